@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/config"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/util"
+	"github.com/urfave/cli/v2"
+)
+
+var ArchiveCommand = &cli.Command{
+	Name:  "archive",
+	Usage: "Maintain the stored thread archives",
+	Flags: util.MergeSlices(
+		config.GetDatabaseCLIFlags(),
+		config.GetRedisCLIFlags(),
+		config.GetLLMCLIFlags(),
+		config.GetIPFSCLIFlags(),
+		config.GetAttestationCLIFlags(),
+		config.GetArchiveEncryptionCLIFlags(),
+	),
+	Subcommands: []*cli.Command{
+		{
+			Name:  "migrate",
+			Usage: fmt.Sprintf("Rewrite archives of older schemas into schema version %d", archive.SchemaVersion),
+			Description: "Every archive written with an older schema is stored again in the current schema under a new CID.\n" +
+				"The thread is pointed at the new CID and the old to new CID lineage is recorded in the database.",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "thread-id",
+					Usage: "Only migrate this thread",
+				},
+				&cli.IntFlag{
+					Name:  "batch-size",
+					Usage: "Number of threads loaded per batch",
+					Value: 100,
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "Only report the archives that would be migrated",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				threadService, cleanup, err := newThreadService(c)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+
+				ctx := service.WithSystemViewer(c.Context)
+				dryRun := c.Bool("dry-run")
+
+				migrate := func(id string) (bool, error) {
+					m, err := threadService.MigrateArchive(ctx, id, dryRun)
+					if err != nil || m == nil {
+						return false, err
+					}
+					if dryRun {
+						fmt.Printf("%s: %s (schema %d) would be migrated to schema %d\n", m.ThreadID, m.OldCID, m.FromVersion, m.ToVersion)
+					} else {
+						fmt.Printf("%s: %s (schema %d) -> %s (schema %d)\n", m.ThreadID, m.OldCID, m.FromVersion, m.NewCID, m.ToVersion)
+					}
+					return true, nil
+				}
+
+				if id := c.String("thread-id"); id != "" {
+					migrated, err := migrate(id)
+					if err != nil {
+						return err
+					}
+					if !migrated {
+						fmt.Println("Archive is already current")
+					}
+					return nil
+				}
+
+				var migrated, failed int
+				after := ""
+				for {
+					ids, err := threadService.ListThreadsToMigrate(ctx, after, c.Int("batch-size"))
+					if err != nil {
+						return err
+					}
+					if len(ids) == 0 {
+						break
+					}
+					for _, id := range ids {
+						ok, err := migrate(id)
+						if err != nil {
+							// Keep going, a single unreadable archive should not stop the run
+							fmt.Printf("%s: failed: %v\n", id, err)
+							failed++
+							continue
+						}
+						if ok {
+							migrated++
+						}
+					}
+					after = ids[len(ids)-1]
+				}
+
+				fmt.Printf("%d archives migrated, %d failed\n", migrated, failed)
+				if failed > 0 {
+					return errors.New("some archives could not be migrated")
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "lineage",
+			Usage: "Show the archives a thread was migrated from",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "thread-id",
+					Usage:    "Thread ID",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				threadService, cleanup, err := newThreadService(c)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+
+				lineage, err := threadService.GetArchiveLineage(c.Context, c.String("thread-id"))
+				if err != nil {
+					return err
+				}
+				if len(lineage) == 0 {
+					fmt.Println("Archive was never migrated")
+					return nil
+				}
+				for _, m := range lineage {
+					fmt.Printf("%s (schema %d) -> %s (schema %d)\n", m.OldCID, m.FromVersion, m.NewCID, m.ToVersion)
+				}
+				return nil
+			},
+		},
+	},
+}
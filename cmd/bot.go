@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -15,12 +16,14 @@ import (
 	"github.com/ipfs-force-community/threadmirror/internal/service/servicefx"
 	"github.com/ipfs-force-community/threadmirror/internal/task/cron/cronfx"
 	"github.com/ipfs-force-community/threadmirror/internal/task/queue/queuefx"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest/attestfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis/redisfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/sql/sqlfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/i18n/i18nfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/ipfs/ipfsfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/jobq/jobqfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring/keyringfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/llm/llmfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/log/logfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/util"
@@ -39,6 +42,8 @@ var BotCommand = &cli.Command{
 		config.GetCronCLIFlags(),
 		config.GetLLMCLIFlags(),
 		config.GetIPFSCLIFlags(),
+		config.GetAttestationCLIFlags(),
+		config.GetArchiveEncryptionCLIFlags(),
 	),
 	Action: func(c *cli.Context) error {
 		commonConfig := config.LoadCommonConfigFromCLI(c)
@@ -48,6 +53,8 @@ var BotCommand = &cli.Command{
 		cronConf := config.LoadCronConfigFromCLI(c)
 		llmConf := config.LoadLLMConfigFromCLI(c)
 		ipfsConf := config.LoadIPFSConfigFromCLI(c)
+		attestConf := config.LoadAttestationConfigFromCLI(c)
+		keyringConf := config.LoadArchiveEncryptionConfigFromCLI(c)
 
 		fxApp := fx.New(
 			// Provide the configuration
@@ -59,7 +66,15 @@ var BotCommand = &cli.Command{
 			}),
 			fx.Supply(llmConf),
 			fx.Supply(ipfsConf),
+			fx.Supply(attestConf),
+			fx.Supply(keyringConf),
 			fx.Supply(botConf),
+			fx.Supply(&xscraper.PoolConfig{
+				FailureThreshold: botConf.ScraperFailureThreshold,
+				BaseCooldown:     botConf.ScraperBaseCooldown,
+				MaxCooldown:      botConf.ScraperMaxCooldown,
+				MaxWait:          botConf.ScraperMaxWait,
+			}),
 			fx.Supply(cronConf),
 			fx.Supply(&logfx.Config{
 				Level:      c.String("log-level"),
@@ -77,10 +92,22 @@ var BotCommand = &cli.Command{
 			jobqfx.ModuleServer,
 			queuefx.Module,
 			cronfx.Module,
-			fx.Provide(func(s *service.BotCookieService) []xscraper.LoginOptions {
+			fx.Provide(func(s *service.BotCookieService) ([]xscraper.LoginOptions, error) {
+				var challengeCode xscraper.ChallengeCodeFunc
+				if botConf.ChallengeCodeCommand != "" {
+					challengeCode = xscraper.CommandChallengeCode(botConf.ChallengeCodeCommand)
+				}
 				loginOpts := make([]xscraper.LoginOptions, len(botConf.Credentials))
 				for i, cred := range botConf.Credentials {
 					credCopy := cred // capture loop variable
+					var realHeader *xscraper.RealHeader
+					if credCopy.RealHeader != "" {
+						h, ok := xscraper.RealHeaderByName(credCopy.RealHeader)
+						if !ok {
+							return nil, fmt.Errorf("unknown real header %q for %s", credCopy.RealHeader, credCopy.Username)
+						}
+						realHeader = &h
+					}
 					loginOpts[i] = xscraper.LoginOptions{
 						Username:          credCopy.Username,
 						Password:          credCopy.Password,
@@ -89,6 +116,10 @@ var BotCommand = &cli.Command{
 						APIKeySecret:      credCopy.APIKeySecret,
 						AccessToken:       credCopy.AccessToken,
 						AccessTokenSecret: credCopy.AccessTokenSecret,
+						Proxy:             credCopy.Proxy,
+						RealHeader:        realHeader,
+						TOTPSecret:        credCopy.TOTPSecret,
+						ChallengeCode:     challengeCode,
 						LoadCookies: func(ctx context.Context) ([]*http.Cookie, error) {
 							return s.LoadCookies(ctx, credCopy.Email, credCopy.Username)
 						},
@@ -97,10 +128,12 @@ var BotCommand = &cli.Command{
 						},
 					}
 				}
-				return loginOpts
+				return loginOpts, nil
 			}),
 			llmfx.Module,
 			ipfsfx.Module,
+			attestfx.Module,
+			keyringfx.Module,
 			xscraperfx.Module,
 			i18nfx.Module(&i18n.LocaleFS),
 			fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
@@ -40,6 +40,8 @@ func main() {
 			ReplyCommand,
 			TakeScreenshotCommand,
 			TweetCommand,
+			ThreadCommand,
+			ArchiveCommand,
 		},
 	}
 
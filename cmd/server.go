@@ -11,6 +11,7 @@ import (
 	"github.com/ipfs-force-community/threadmirror/internal/api/apifx"
 	"github.com/ipfs-force-community/threadmirror/internal/config"
 	"github.com/ipfs-force-community/threadmirror/internal/service/servicefx"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest/attestfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/auth/authfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis/redisfx"
@@ -18,6 +19,7 @@ import (
 	"github.com/ipfs-force-community/threadmirror/pkg/i18n/i18nfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/ipfs/ipfsfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/jobq/jobqfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring/keyringfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/llm/llmfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/log/logfx"
 	"github.com/ipfs-force-community/threadmirror/pkg/util"
@@ -35,6 +37,8 @@ var ServerCommand = &cli.Command{
 		config.GetAuth0CLIFlags(),
 		config.GetLLMCLIFlags(),
 		config.GetIPFSCLIFlags(),
+		config.GetAttestationCLIFlags(),
+		config.GetArchiveEncryptionCLIFlags(),
 	),
 	Action: func(c *cli.Context) error {
 		commonConfig := config.LoadCommonConfigFromCLI(c)
@@ -45,6 +49,8 @@ var ServerCommand = &cli.Command{
 		auth0Conf := config.LoadAuth0ConfigFromCLI(c)
 		llmConf := config.LoadLLMConfigFromCLI(c)
 		ipfsConf := config.LoadIPFSConfigFromCLI(c)
+		attestConf := config.LoadAttestationConfigFromCLI(c)
+		keyringConf := config.LoadArchiveEncryptionConfigFromCLI(c)
 
 		// baseContext, cancel := context.WithCancel(context.Background())
 
@@ -71,6 +77,8 @@ var ServerCommand = &cli.Command{
 			}),
 			fx.Supply(llmConf),
 			fx.Supply(ipfsConf),
+			fx.Supply(attestConf),
+			fx.Supply(keyringConf),
 			logfx.Module,
 			sqlfx.Module,
 			redisfx.Module,
@@ -78,6 +86,8 @@ var ServerCommand = &cli.Command{
 			servicefx.Module,
 			llmfx.Module,
 			ipfsfx.Module,
+			attestfx.Module,
+			keyringfx.Module,
 			xscraperfx.Module,
 			jobqfx.ModuleClient,
 			i18nfx.Module(&i18n.LocaleFS),
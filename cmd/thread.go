@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/config"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest/attestfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/database/redis"
+	"github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs/ipfsfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring/keyringfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/llm/llmfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/log"
+	"github.com/ipfs-force-community/threadmirror/pkg/util"
+	"github.com/urfave/cli/v2"
+)
+
+var ThreadCommand = &cli.Command{
+	Name:  "thread",
+	Usage: "Manage archived threads",
+	Flags: util.MergeSlices(
+		config.GetDatabaseCLIFlags(),
+		config.GetRedisCLIFlags(),
+		config.GetLLMCLIFlags(),
+		config.GetIPFSCLIFlags(),
+		config.GetAttestationCLIFlags(),
+		config.GetArchiveEncryptionCLIFlags(),
+	),
+	Subcommands: []*cli.Command{
+		{
+			Name:  "export-car",
+			Usage: "Export an archived thread and its media as a CAR file",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "id",
+					Usage:    "Thread ID",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "output",
+					Usage: "Output file (defaults to <id>.car, - for stdout)",
+				},
+				&cli.IntFlag{
+					Name:  "car-version",
+					Usage: "CAR format version (1 or 2)",
+					Value: archive.CARv1,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				threadService, cleanup, err := newThreadService(c)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+
+				output := c.String("output")
+				if output == "" {
+					output = c.String("id") + ".car"
+				}
+
+				var w io.Writer = os.Stdout
+				if output != "-" {
+					f, err := os.Create(output)
+					if err != nil {
+						return fmt.Errorf("create output file: %w", err)
+					}
+					defer f.Close() // nolint:errcheck
+					w = f
+				}
+
+				if err := threadService.ExportCAR(service.WithSystemViewer(c.Context), c.String("id"), c.Int("car-version"), w); err != nil {
+					if output != "-" {
+						_ = os.Remove(output)
+					}
+					return err
+				}
+				if output != "-" {
+					fmt.Println("Exported thread to", output)
+				}
+				return nil
+			},
+		},
+		{
+			Name:  "import-car",
+			Usage: "Import a thread CAR file into storage and create its thread record",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "input",
+					Usage:    "CAR file to import",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "thread-id",
+					Usage: "Thread ID to create (a new one is generated if empty)",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				threadService, cleanup, err := newThreadService(c)
+				if err != nil {
+					return err
+				}
+				defer cleanup()
+
+				f, err := os.Open(c.String("input"))
+				if err != nil {
+					return fmt.Errorf("open input file: %w", err)
+				}
+				defer f.Close() // nolint:errcheck
+
+				thread, err := threadService.ImportCAR(service.WithSystemViewer(c.Context), f, c.String("thread-id"))
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Imported thread %s (cid %s, %d tweets)\n", thread.ID, thread.CID, thread.NumTweets)
+				return nil
+			},
+		},
+		{
+			Name:  "attestation-key",
+			Usage: "Print the public key that archive attestations are signed with",
+			Action: func(c *cli.Context) error {
+				signer, err := attestfx.NewSigner(config.LoadAttestationConfigFromCLI(c))
+				if err != nil {
+					return err
+				}
+				if signer == nil {
+					return fmt.Errorf("no attestation private key configured")
+				}
+				pub, err := attest.MarshalPublicKey(signer.PublicKey())
+				if err != nil {
+					return err
+				}
+				fmt.Printf("# key id %s\n%s", signer.KeyID(), pub)
+				return nil
+			},
+		},
+		{
+			Name:  "verify-attestation",
+			Usage: "Verify an archive attestation offline, optionally against an exported thread CAR",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "attestation",
+					Usage:    "Attestation file: the DSSE envelope or the response of GET /thread/{id}/attestation",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     "public-key",
+					Usage:    "PEM file of the ThreadMirror attestation public key",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  "car",
+					Usage: "CAR file of the thread (see export-car) to check against the attested content CID",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				data, err := os.ReadFile(c.String("attestation"))
+				if err != nil {
+					return fmt.Errorf("read attestation: %w", err)
+				}
+				envelope, err := parseAttestation(data)
+				if err != nil {
+					return err
+				}
+				keyData, err := os.ReadFile(c.String("public-key"))
+				if err != nil {
+					return fmt.Errorf("read public key: %w", err)
+				}
+				pub, err := attest.ParsePublicKey(keyData)
+				if err != nil {
+					return err
+				}
+
+				statement, err := attest.Verify(envelope, pub)
+				if err != nil {
+					return err
+				}
+
+				if car := c.String("car"); car != "" {
+					f, err := os.Open(car)
+					if err != nil {
+						return fmt.Errorf("open CAR file: %w", err)
+					}
+					defer f.Close() // nolint:errcheck
+
+					// ReadCAR checks every block against its CID
+					_, root, err := archive.ReadCAR(f)
+					if err != nil {
+						return err
+					}
+					if root.String() != statement.ContentCID {
+						return fmt.Errorf("CAR root %s does not match attested content CID %s", root, statement.ContentCID)
+					}
+				}
+
+				fmt.Println("Attestation is valid")
+				fmt.Println("  thread:          ", statement.ThreadID)
+				fmt.Println("  content cid:     ", statement.ContentCID)
+				fmt.Println("  tweets:          ", strings.Join(statement.TweetIDs, ", "))
+				fmt.Println("  scraped at:      ", statement.ScrapedAt.Format(time.RFC3339))
+				fmt.Println("  scraper account: ", statement.ScraperAccount)
+				fmt.Println("  software version:", statement.SoftwareVersion)
+				if c.String("car") != "" {
+					fmt.Println("  CAR file matches the attested content")
+				}
+				return nil
+			},
+		},
+	},
+}
+
+// parseAttestation reads a DSSE envelope, either bare or wrapped in the
+// response of GET /thread/{id}/attestation
+func parseAttestation(data []byte) (*attest.Envelope, error) {
+	var envelope attest.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid attestation: %w", err)
+	}
+	if envelope.PayloadType != "" {
+		return &envelope, nil
+	}
+
+	var resp struct {
+		Data struct {
+			Envelope *attest.Envelope `json:"envelope"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Data.Envelope == nil {
+		return nil, fmt.Errorf("invalid attestation: no DSSE envelope found")
+	}
+	return resp.Data.Envelope, nil
+}
+
+// newThreadService builds a ThreadService from the thread command flags
+func newThreadService(c *cli.Context) (*service.ThreadService, func(), error) {
+	logger, err := log.New(c.String("log-level"), c.Bool("debug"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbConf := config.LoadDatabaseConfigFromCLI(c)
+	db, err := sql.New(dbConf.Driver, dbConf.DSN, logger.Logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	replicaIndex := service.NewStorageReplicaService(db)
+	storage, err := ipfsfx.NewStorage(config.LoadIPFSConfigFromCLI(c), replicaIndex, logger.Logger)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("failed to create storage: %w", err)
+	}
+
+	llmModel, err := llmfx.NewLLM(config.LoadLLMConfigFromCLI(c))
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("failed to create llm: %w", err)
+	}
+
+	redisConf := config.LoadRedisConfigFromCLI(c)
+	redisClient := redis.NewClient(&redis.RedisConfig{
+		Addr:     redisConf.Addr,
+		Password: redisConf.Password,
+		DB:       redisConf.DB,
+	})
+
+	cleanup := func() {
+		_ = redisClient.Close()
+		_ = db.Close()
+		_ = logger.Close()
+	}
+	signer, err := attestfx.NewSigner(config.LoadAttestationConfigFromCLI(c))
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to create attestation signer: %w", err)
+	}
+
+	kr, err := keyringfx.NewKeyring(config.LoadArchiveEncryptionConfigFromCLI(c))
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to create archive keyring: %w", err)
+	}
+
+	pdpPieces := service.NewPDPPieceService(db, storage, logger.Logger)
+	return service.NewThreadService(db, storage, pdpPieces, signer, kr, llmModel, redisClient, logger.Logger), cleanup, nil
+}
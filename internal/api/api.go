@@ -64,6 +64,7 @@ func NewServer(
 							"error": v1.T(c, "ErrorAuthenticationRequired"),
 						})
 					}),
+					auth.OptionalMiddleware(jwtVerifier),
 				),
 			},
 		},
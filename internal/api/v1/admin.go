@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/auth"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/samber/lo"
+)
+
+var (
+	// Admin module error codes: 15000-15999
+	ErrCodeAdmin = v1errors.NewErrorCode(v1errors.CheckCode(15000), "Admin error")
+
+	// Admin operation errors
+	ErrCodeFailedToGetIntegrityFailures = v1errors.NewErrorCode(15001, "failed to get integrity failures")
+	ErrCodeFailedToGetScraperAccounts   = v1errors.NewErrorCode(15002, "failed to get scraper accounts")
+	ErrCodeScraperNotQuarantined        = v1errors.NewErrorCode(15003, "scraper account is not quarantined")
+	ErrCodeFailedToClearQuarantine      = v1errors.NewErrorCode(15004, "failed to clear scraper quarantine")
+)
+
+// Admin-related methods for V1Handler
+
+// GetAdminIntegrity handles GET /admin/integrity
+func (h *V1Handler) GetAdminIntegrity(c *gin.Context, params GetAdminIntegrityParams) {
+	if !h.isAdmin(c) {
+		_ = c.Error(v1errors.Forbidden(fmt.Errorf("admin access required")))
+		return
+	}
+
+	limit, offset := ExtractPaginationParams(&params)
+
+	verifications, total, err := h.threadService.GetFailedVerifications(c.Request.Context(), limit, offset)
+	if err != nil {
+		_ = c.Error(v1errors.InternalServerError(err).WithCode(ErrCodeFailedToGetIntegrityFailures))
+		return
+	}
+
+	apiVerifications := lo.Map(verifications, func(v service.ThreadVerification, _ int) ThreadVerification {
+		return convertThreadVerification(v)
+	})
+
+	PaginatedJSON(c, apiVerifications, total, limit, offset)
+}
+
+// GetAdminScrapers handles GET /admin/scrapers
+func (h *V1Handler) GetAdminScrapers(c *gin.Context) {
+	if !h.isAdmin(c) {
+		_ = c.Error(v1errors.Forbidden(fmt.Errorf("admin access required")))
+		return
+	}
+
+	accounts, err := h.scraperService.ListScraperAccounts(c.Request.Context())
+	if err != nil {
+		_ = c.Error(v1errors.InternalServerError(err).WithCode(ErrCodeFailedToGetScraperAccounts))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": lo.Map(accounts, func(a service.ScraperAccount, _ int) ScraperAccount {
+			return convertScraperAccount(a)
+		}),
+	})
+}
+
+// DeleteAdminScrapersUsernameQuarantine handles DELETE /admin/scrapers/{username}/quarantine
+func (h *V1Handler) DeleteAdminScrapersUsernameQuarantine(c *gin.Context, username string) {
+	if !h.isAdmin(c) {
+		_ = c.Error(v1errors.Forbidden(fmt.Errorf("admin access required")))
+		return
+	}
+
+	if err := h.scraperService.ClearQuarantine(c.Request.Context(), username); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeScraperNotQuarantined))
+			return
+		}
+		_ = c.Error(v1errors.InternalServerError(err).WithCode(ErrCodeFailedToClearQuarantine))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// isAdmin reports whether the current user is one of the configured admins
+func (h *V1Handler) isAdmin(c *gin.Context) bool {
+	currentUserID := auth.CurrentUserID(c)
+	return currentUserID != "" && slices.Contains(h.serverConfig.AdminUserIDs, currentUserID)
+}
+
+// Admin Helper functions
+
+func convertThreadVerification(v service.ThreadVerification) ThreadVerification {
+	return ThreadVerification{
+		ThreadId:            v.ThreadID,
+		Cid:                 v.CID,
+		Status:              ThreadVerificationStatus(v.Status),
+		FailedCid:           lo.EmptyableToPtr(v.FailedCID),
+		LastError:           lo.EmptyableToPtr(v.LastError),
+		ConsecutiveFailures: v.ConsecutiveFailures,
+		CheckedAt:           v.CheckedAt,
+		RepairQueuedAt:      v.RepairQueuedAt,
+	}
+}
+
+func convertScraperAccount(a service.ScraperAccount) ScraperAccount {
+	failures := a.Failures
+	if failures == nil {
+		failures = map[string]int{}
+	}
+	rateLimits := lo.Map(a.RateLimits, func(l xscraper.EndpointLimit, _ int) ScraperRateLimit {
+		return ScraperRateLimit{
+			Endpoint:  string(l.Endpoint),
+			Limit:     l.Limit,
+			Remaining: l.Remaining,
+			Reset:     l.Reset,
+			UpdatedAt: l.UpdatedAt,
+		}
+	})
+	return ScraperAccount{
+		Username:            a.Username,
+		State:               ScraperAccountState(a.State),
+		Failures:            failures,
+		ConsecutiveFailures: a.ConsecutiveFailures,
+		OpenUntil:           a.OpenUntil,
+		LastError:           lo.EmptyableToPtr(a.LastError),
+		LastErrorAt:         a.LastErrorAt,
+		LastSuccessAt:       a.LastSuccessAt,
+		RateLimits:          rateLimits,
+		QuarantinedAt:       a.QuarantinedAt,
+		QuarantineReason:    lo.EmptyableToPtr(a.QuarantineReason),
+		UpdatedAt:           a.UpdatedAt,
+	}
+}
@@ -20,9 +20,21 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List archive integrity failures
+	// (GET /admin/integrity)
+	GetAdminIntegrity(c *gin.Context, params GetAdminIntegrityParams)
+	// List scraper accounts
+	// (GET /admin/scrapers)
+	GetAdminScrapers(c *gin.Context)
+	// Clear the quarantine of a scraper account
+	// (DELETE /admin/scrapers/{username}/quarantine)
+	DeleteAdminScrapersUsernameQuarantine(c *gin.Context, username string)
 	// Health check
 	// (GET /health)
 	GetHealth(c *gin.Context)
+	// Get archived media
+	// (GET /media/{cid})
+	GetMediaCid(c *gin.Context, cid string)
 	// Get mentions feed
 	// (GET /mentions)
 	GetMentions(c *gin.Context, params GetMentionsParams)
@@ -41,6 +53,21 @@ type ServerInterface interface {
 	// Get thread details
 	// (GET /thread/{id})
 	GetThreadId(c *gin.Context, id string)
+	// Get thread attestation
+	// (GET /thread/{id}/attestation)
+	GetThreadIdAttestation(c *gin.Context, id string)
+	// Export thread as CAR
+	// (GET /thread/{id}/car)
+	GetThreadIdCar(c *gin.Context, id string, params GetThreadIdCarParams)
+	// Get consistency proof
+	// (GET /transparency/proof/consistency)
+	GetTransparencyProofConsistency(c *gin.Context, params GetTransparencyProofConsistencyParams)
+	// Get inclusion proof
+	// (GET /transparency/proof/inclusion)
+	GetTransparencyProofInclusion(c *gin.Context, params GetTransparencyProofInclusionParams)
+	// Get signed tree head
+	// (GET /transparency/sth)
+	GetTransparencySth(c *gin.Context, params GetTransparencySthParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -52,6 +79,83 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// GetAdminIntegrity operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminIntegrity(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAdminIntegrityParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAdminIntegrity(c, params)
+}
+
+// GetAdminScrapers operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminScrapers(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAdminScrapers(c)
+}
+
+// DeleteAdminScrapersUsernameQuarantine operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminScrapersUsernameQuarantine(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "username" -------------
+	var username string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "username", c.Param("username"), &username, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter username: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteAdminScrapersUsernameQuarantine(c, username)
+}
+
 // GetHealth operation middleware
 func (siw *ServerInterfaceWrapper) GetHealth(c *gin.Context) {
 
@@ -65,6 +169,30 @@ func (siw *ServerInterfaceWrapper) GetHealth(c *gin.Context) {
 	siw.Handler.GetHealth(c)
 }
 
+// GetMediaCid operation middleware
+func (siw *ServerInterfaceWrapper) GetMediaCid(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "cid" -------------
+	var cid string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cid", c.Param("cid"), &cid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMediaCid(c, cid)
+}
+
 // GetMentions operation middleware
 func (siw *ServerInterfaceWrapper) GetMentions(c *gin.Context) {
 
@@ -247,6 +375,173 @@ func (siw *ServerInterfaceWrapper) GetThreadId(c *gin.Context) {
 	siw.Handler.GetThreadId(c, id)
 }
 
+// GetThreadIdAttestation operation middleware
+func (siw *ServerInterfaceWrapper) GetThreadIdAttestation(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetThreadIdAttestation(c, id)
+}
+
+// GetThreadIdCar operation middleware
+func (siw *ServerInterfaceWrapper) GetThreadIdCar(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", c.Param("id"), &id, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetThreadIdCarParams
+
+	// ------------- Optional query parameter "version" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "version", c.Request.URL.Query(), &params.Version)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter version: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetThreadIdCar(c, id, params)
+}
+
+// GetTransparencyProofConsistency operation middleware
+func (siw *ServerInterfaceWrapper) GetTransparencyProofConsistency(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTransparencyProofConsistencyParams
+
+	// ------------- Required query parameter "first" -------------
+
+	if paramValue := c.Query("first"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument first is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "first", c.Request.URL.Query(), &params.First)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter first: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "second" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "second", c.Request.URL.Query(), &params.Second)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter second: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTransparencyProofConsistency(c, params)
+}
+
+// GetTransparencyProofInclusion operation middleware
+func (siw *ServerInterfaceWrapper) GetTransparencyProofInclusion(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTransparencyProofInclusionParams
+
+	// ------------- Required query parameter "cid" -------------
+
+	if paramValue := c.Query("cid"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument cid is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "cid", c.Request.URL.Query(), &params.Cid)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "tree_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tree_size", c.Request.URL.Query(), &params.TreeSize)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tree_size: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTransparencyProofInclusion(c, params)
+}
+
+// GetTransparencySth operation middleware
+func (siw *ServerInterfaceWrapper) GetTransparencySth(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTransparencySthParams
+
+	// ------------- Optional query parameter "tree_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tree_size", c.Request.URL.Query(), &params.TreeSize)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tree_size: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTransparencySth(c, params)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -274,77 +569,162 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/admin/integrity", wrapper.GetAdminIntegrity)
+	router.GET(options.BaseURL+"/admin/scrapers", wrapper.GetAdminScrapers)
+	router.DELETE(options.BaseURL+"/admin/scrapers/:username/quarantine", wrapper.DeleteAdminScrapersUsernameQuarantine)
 	router.GET(options.BaseURL+"/health", wrapper.GetHealth)
+	router.GET(options.BaseURL+"/media/:cid", wrapper.GetMediaCid)
 	router.GET(options.BaseURL+"/mentions", wrapper.GetMentions)
 	router.GET(options.BaseURL+"/qrcode", wrapper.GetQrcode)
 	router.GET(options.BaseURL+"/render", wrapper.GetRender)
 	router.GET(options.BaseURL+"/share", wrapper.GetShare)
 	router.POST(options.BaseURL+"/thread/scrape", wrapper.PostThreadScrape)
 	router.GET(options.BaseURL+"/thread/:id", wrapper.GetThreadId)
+	router.GET(options.BaseURL+"/thread/:id/attestation", wrapper.GetThreadIdAttestation)
+	router.GET(options.BaseURL+"/thread/:id/car", wrapper.GetThreadIdCar)
+	router.GET(options.BaseURL+"/transparency/proof/consistency", wrapper.GetTransparencyProofConsistency)
+	router.GET(options.BaseURL+"/transparency/proof/inclusion", wrapper.GetTransparencyProofInclusion)
+	router.GET(options.BaseURL+"/transparency/sth", wrapper.GetTransparencySth)
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9RbbW/bOBL+K4RugUsAb+ym7d2uP13bdNsc2l4au9gDisKgpbHFjUSqJOXGV/i/H8ih",
-	"3izKUpxkr/elTaLRcDjzzAuHo+9BKNJMcOBaBdPvQUYlTUGDtL9d0TW8YynT5pcIVChZppngwTR4T29Z",
-	"mqeE5+kSJBErwjSkimhBJOhc8mAUMEP4NQe5DUYBpykE0yCx7EaBCmNIKfJd0TzRwfR8MgpSZBtMn0zM",
-	"b4y730aB3mbmfcY1rEEGu93Iivev1UqBR74PbbnUDcs6pBLIxStWXY6JR47dKJCgMsEVWKW9pNE1fM1B",
-	"WalCwTVw+yPNsoSF1Ag4/kMZKb/X1vtJwiqYBn8ZVwYZ41M1fi2lcEs1d/mSRkS6xXaj4JJrkJwmM5Ab",
-	"kPjWo8tQLEqUXZUAEo6CD0L/JnIePb4I16BELkMgXGiysmvuRsEnTnMdC8n+A3+CDPXVyM/E/AJcu0Ws",
-	"kZiEyOLW8TJLlTbKpMhAaoYYCkUEbUxbYmKfjYKVkCnViMSn50EbmKMgBaXoupNR8bh8VWnJ+DpARDt5",
-	"p58Dt2BB/qWkF8s/ILTAe0tVrOm6vRHGIxbij00RZppKTSiPCPCIODLCONExEP0NQBMNt8Ylrf8aBp4N",
-	"0ttLfHpunbT6xdFSKenWUFpeLSGc2HYlcvKN6VjkulcfhVxuaz59vIeI0bY2IqayhG4XuUzawlzgQ/Lp",
-	"+h1ZWfMYHi1hRgHcZpRHEPnZvHZPW3xKwOSS+diyaKG09ER68z7JOfuaA2GRAfWKgfSy+EGsbbe8uIFt",
-	"125uYNuzFWSRy2QRa515tvR2Pr+aoXKNqgcoGP/gF8g8IydZLLQYkQ2LQIwI6PDs1MfIa/f3gyXZg7Oz",
-	"e11r7hVcadSA7R782orqcwxu5J3laUrl1hP4WNTe2isM3DWLkZNXlxde3bggv8gkbBh88+nJSkAcIXGE",
-	"Y+VEsjh7B3yt42D6fDLxrSGBaogWVB9gb2jMD5qloDRNs7pdIqrhZ/PE74jdbAe5YYrEC5TzoJjoe/cQ",
-	"lufpwjJRh2owpCgdPpZAI2/KUprq3MPqVS6lsRY+tyxjIG6jJJMiBKWMSKMAuKnUPgcZ8Aj/okJJM/zR",
-	"ZPQENJjVV5QlENVgWnNVK+ECM3pfSTC3xC+QtnrZZ0YkHWLFlpMa4fHfPYTXF2xg0weEhsFKdfs89YPQ",
-	"MDd01yyM5y59Np1VsjA2YXuh6dpjs2v3mJgUmzClSQQrxhlfE8SWNj9Kyteg6pH/kK5bUs1pLbYWGWBP",
-	"eU05B2127itmVlKkC8YjuO3KcPYhOWE8THLFNnBaINVtGCKsNRSsjW28LlBJu818ufS3SneWgthi1nAW",
-	"RMdM4QpWrXWtFm7xUiQGJ5eaJiz0g3+/dhJdm36NydxsGW6P3vKetWparq3d0ovPjFd0zbiNZO9Be+qv",
-	"5G5nWTzIgj9SiYEHT3PqzDp4aKGpJ5XPzZ/3pelXHHIblUdsJ6FPUbNtuhTJ/1u5jlI3q3Xy0+lDFOyN",
-	"GN5WiyeUI+1fVTuYk5P5N6Y1SJIrkKSjUMHOQydXV3MRS+Z5PZNixRJYsJSuwX8SKHk5WmJpBxarKpQA",
-	"fNEjJVJZISuL/ON0WDpzW6uv5NtXt7kuQFPmQfFxafvxS0+X/R+p8iy4P2zhObxi+R/XgsiIFLVeURM+",
-	"VEHYsa05boYqJUJmTEOMF2AenhdbG1TZWE6oxSShywSCqZY59JQ2B+vCRi3YqPuGFIAo/syoCq6E0ueT",
-	"ybXrc7ZdrrPVNMtDY4ZaswluqVF5ha3SZH+IJYmpIksTU77mkNfT5p4t/OW1i7uYkC4vCNxqSUNjFlNV",
-	"WJxg+OtJGMUKh7te+xp6Nvm1W0MR1XRYSHJh7VAD7z0+wERMsRZEZdLE/LclcMuUtXBL3S2Kw7qwct9N",
-	"D7X2d1MH3jTljDb+t+1YaYF4AHICZ+uzEbFdhel4rJHsLBTp2OTVMUJ3/OT86bPnf/v7L7+eNjbrew0S",
-	"wdNc3bRenZQ/3bV50pmgyuD7UDXWA1ZXCkLBI58E+IBsaJKDDay1HOIpX71VWrn1QuaeUsCpoxLLMfaq",
-	"1QbJYxO+efmTQtlDwTcglU2Vi47MXxIUznU4/R1MzvftsxQ9OHsCwsPdQAAV+dGexGOqiYpFnkRkCUWR",
-	"CZE7v0Wgqo6mOj0Wb4czmEnImhWm6zXY64J4NwpiqhZLJqNvVIfxggvtc6PfY9CxSQL2LGz1bpLKy+I9",
-	"gu+VYi2FSIDyzgLIchjY/l5IyJLtQosFhhgvsi4vSpvgwckIysxxM0u2NpSLoFOJ/tVMQOxZy55Fjl1K",
-	"WWVj/dCjcaYIJYng658NsMkHoYGg23o1rhZfc8O6q9hr87b0qLoulnZ7Q3ghYSeXwfstSDs4aUm5SqhG",
-	"JXeywxhBuXHN4pV6CVRjmlC+7sKqeZab6sBd3LXPjkIptky2CwVcMc02B4QqDispW8faCFa945PLGieq",
-	"gDKo4pWguuo5s5/r17M5eXF12eN9RX/ozi1EmxLt/bEnpOK9cu2+eIi/GDAPi24zS9mdS8vOSmEI385N",
-	"uX/wyIUEnYJ3NZRsAVwYZ1S0URqnCtxpw11qHtj273bi9Ud1h3AvVtsutR+hamDorCJe15JQs5qI8VZY",
-	"dd4XNxtfQw94xR35ruO+sutKj2pNwzi1EzoDl8Lr511/Jla2o+ZrMjNOechoQhzJUVt2bUZfT7kogNSB",
-	"AvK4RavSe4ACcunbval+jlr7k/Tu1qZndxnjW86k5uLxcesqkO5Kr/c2pIR3ZX6nh31BO31nVsS3puMs",
-	"hbhJqbxZhCLnB1vjBaXynitWdCMk09DPJ2E3oMYFvZ8bxp9eVrWyws8Hi61ePoaMQRcL7CwMYHJAEBPN",
-	"+1kcGfPru9wXuGWZpnJH+wDoxI89hLXv/g+cn6yL0NDyvdcxqs7Vu8iSiXH9zx4mK5Ek4htI1W+FktQP",
-	"dMmAR4PZYMeyzcZXOtm9DDu1qMUyyWGxAWloou5a0B4hbIMuyYEgfVkUtWtB/92BFewBbjcsn2NuNjqr",
-	"TctxYLF58HrEMhp8NVI0YWEADg5FhWHmY4qUlG2jHS4AB17YNL2s7S77uG8pYK/GrMnbwqovxHySSe/o",
-	"Xe9Q3YDjd9nGO9Ak8dQbnuX3W66NeauqQdbVbKxn/0F3l44YIosJ3x3mjzzd53e61p76YsxBF25xu9dN",
-	"Z9Nj3H/d19HYos0l09uZKfDcXDlQCfJFrmNba9nffiti3T9/nxfT69ah7dNKuFjrDIeWGV+JYhiahjbY",
-	"uBl4E/RmeZYJqR3Wqi76muk4X2ITHVvsY2yJpqyY9t67Ib66xOFTyuma8TUGHxcrlMUKhgUbzJiuXVAg",
-	"S/KShjcGTi+uLjEGKOT85GxyNrEDGBlwmrFgGjw9m5w9NaGI6tiqahwDTVBPa18D51UM4Q1h2BhTIDcs",
-	"BBMZ8bWtFU/mnGPCNd5ks9xlFEyDN6DfIve9uf/zyeROU+ZNN636X8V1pbgx0KhuNMSN9zKsftHQcU+K",
-	"c/muJBpWJ5X6bl8RoLYKgrqEaJo+jyg7AZXoHhdoIWrWMpOR8/nk6T2UfuyA/MhjLvzsoWEx/FOf0YYY",
-	"pF+Fh27oDioz56U6a3EnmH7+MgqKyYRpgJAnoXEcs65tj3x2fw6+mFfH9QOu1+3egCaUZDikBRHOBIpV",
-	"dfS1l7SuXMDIq4iQJMuXCQtLMp9Lvq+e1T9h+uw/Nlck4+oTp91oELH74Gj35UHdv7gdHtjeaQxRextK",
-	"/ZfNe8Nyu90A3Lzbs5hZ69nkSddSpYLGjW9xrN9O+l/yfc5kQVrC0iCqQg9gTeuwWUIC0flVFh/VdGCT",
-	"G0gBxn47+Eco+Xhtm+jufGESmskYa7aB2p2gD48fpWu+76Fxf7gGKjZECwIcl8PSya3e8c1afeq3Cg5Y",
-	"rVYw2w8k/bC1ex1neL9QMSrD1JJxnFbaZ91CS1N7J1cf3pwiYAbYvvYV3b3h0hHTroFHIGtaLpDz8fqV",
-	"+QPiRlqqTtw4JrScvzDFDXk7f//OBDo/ZhZ+zCCrO2NGFq/9mSAx9fw41mnSARL7aABEcM8QVRo7EiHP",
-	"Js/6Xym/SHxkSFVYMNuqIcuZGJGlYiq7A9KF+MYTYbjY21TnRBIyCQq4xgZYc/bNYM0w7ShbZ3a9u8Jr",
-	"XQRGWysbFijKgwJu5BMjAlujqJAmQFY01EKW7oQnKhULTU7cl7rk/LRDJsuh45vjWkG8SgR+NVF8g/y8",
-	"/uXvWfUNMk5m/8nBdFap/l6h9MdwlBLdTUQVfoJQRTdBRI1xKsxWTkJ5/OVjDjkQaocITSbFyT+bzhXd",
-	"mCfldKDzFlNsUheYG0NoZ+TaFgDKMjMnZmMt428WfpKGN4yvz1o+diWUrs/CORcApV+KaPtgnyB3jdvt",
-	"mscE43O7FkDPH02M+nyoB7++YU+c8SQKZ0RXeZJsj0b1r4+2sfpYZ/fGmmOVRQmntkpD+oAVL4rlxfKn",
-	"63fk5IXa8vC05koonmo603cW7Q4e0yI7gwpRA/10KXJzglMZhGzFwmp4u5VrcNXLqD/dWPkvL4rQnVHb",
-	"WXGR+8HrlmPOZMMndoccodyOUcHqx4nIb+wkWlO2Pfqi64g9yM9fjL6xueQz7QVsIBFZWrWgGm3F6Xic",
-	"iJAmsVB6+svkl8mYZmy8eRLsvuz+GwAA//8OBOqelkQAAA==",
+	"H4sIAAAAAAAC/+R9bXPcNtLgX0HNPVVrVdEa2Xm5XefLKbIT67nYUSR511Wp1DwYsmcGKw7BAKCkSVb/",
+	"/QrdAAmS4JB6cda5/WRrAAKNRr+j0fh9lsptKQsojJ69+n1WcsW3YEDhX2d8DT+IrTD2jwx0qkRphCxm",
+	"r2bv+K3YVltWVNslKCZXTBjYamYkU2AqVcySmbAdf61A7WbJrOBbmL2a5ThcMtPpBracxl3xKjezVy+P",
+	"ktmWhp29enFk/xKF+yuZmV1pvxeFgTWo2d1dguD9uFppiMD3vg+XvhLlAFSSRomCFcJxFIHjLpkp0KUs",
+	"NCDSvuXZOfxagUaoUlkYKPC/vCxzkXIL4Pyf2kL5ezDffylYzV7N/te82ZA5ter5G6Wkm6q9ym95xpSb",
+	"7C6ZfSfVUmQZFJ9+5noq9pzxNAWtWQaFgMzCcVoYUAXPL0Bdg6IxPjlEflKmcVYG1DGZvZfmO1kV2acH",
+	"4Ry0rFQKrJCGrXDOu2T2oeCV2UglfoM/AIZwNrs3ldlAYdwkSCxC2V2688SORHus0o24huwdZALnLpUs",
+	"QRlBNJ2KrM9iJ6evLX+ZDTDuPmepLHcJ7UDGVkpu2Xxrh5z/norsblazjzZKFGuLHYeNBTX05qBWZluZ",
+	"glIqAxm72UCB8+LY7IZrlsmbIpc8g8xydZXnfJnD7JVRFUQm1eK3yGQX4jforWglcmCiYMudAT1LZiup",
+	"ttyQBPj6y1lfICSzSuX9wX9UYi0seRLMH85/CAerlOgjByWL269XP+OwCW6FW8Av9Rdy+U9IUQQcGwPa",
+	"4Ga/Ka4hlzGsvr64eMPANX/DgKcbpsW64KZSwIRmvGBvTl5fHLOz5y+/+jpok5azLH5wiFLB8w6BQZHK",
+	"TBRrj8eS7+y2zJIORfnfe7B9yzV8/SWNAxmza4GtJYH/vvjxfYyA3EiXjn4ie+2Ax3lRG/QJ/Ap2ROKx",
+	"z0eBPL54f/iiwdLoTtJsNHZsE90PXCm+630crrde/ay1zhHCuPA4jVAG0IrcsH4XPa30ttFzbzqEvVTx",
+	"ErIFx7lqcs+4gedGbCG2ofSNWvA0lRUB2e8jV+aGK1hcg9KCBGevk9ko4NliADRzA2AWImuTRb9bay/8",
+	"37+P7LCh3WkgCOdroaW/3qSF1chaY9t7IgsttIEi3Z0pKVd9El8Jpdu7MCzBSj/EdLxoSCXp2NHxO7gi",
+	"wOoR/OwDq7RIQCr+EWk2wstbfrtYWrybTcRuldpYRZIL0CyVeQ6p1ShVkYFinGlRrHNguFfsmbMB2cuj",
+	"g9mgbXoUQ6CFIYMyBsEPcA25tpzlwVhCLm+Qz2heIz1kDQhfdCAYBUAUi1xcEUY6au5KlPXcN8Js2Apu",
+	"QDHsnjBeZAiL70GoMRvYzkYs4ciG1bZfV2xkEbWEnRm2tfXsFy+jVLoFrfl6cCDfPCaN3YS+e4zu3nK9",
+	"MXzdX4goMpFGkWy4MohLKDLmulk7IthmuLVk32WxNhWdUutLRH3zR08q2bF6QDiwcSb2zG61rMwoPjxc",
+	"bmkxfJwWaV5ZWTQga3iVCbMoOdH/dCEypEagMGoX2edbnhqWyzXDDojbHPiKbbjeWDvGGs6V5W9rt8R0",
+	"je29sL2j02KrKDK4nSg3RzSOAlh40/O+UjKAJRyprWNIYRC2wsUl4Y7ENnTA7s+ELnO+W0Qt2tfUaE1Z",
+	"tkJ+s2NEkAy3JS8yyOLDvHGtvXH2G8fJTGQLbVRExKOBXRXi1wqYyKxxuhLx7f9c2BeXvLiC3dBqrmA3",
+	"shQaolL5YmNMGVnS28vLs4t7eB+NqRMDCB2yZ+VGGpmwa5GBTBiY9PAgNlB039891A9y+x5iLfEGF/lI",
+	"Idl2yK+PqP2S7p3FuSwuqu2Wkwia4hk7r7XZMfbs5PT1wT7/t1RwLeAmhieEgLmOzHWcawcS0tkPUKyt",
+	"sP3q6Cg2hwJuait8YHjbx/7HmuXa8G0Z7stegz2GAj/sJDbcUucFwbkXTOK9RwBbVNsFDqL3BQ2pR83w",
+	"KGKjEt+6VFVkqJNKKbtb1O79KLdQViqZgrbGJopra1D9PCuhyOgX9Ajov1aD5WAwrLHiIocsINOeq0Oh",
+	"n7HY0SV2Pqa+Xa3VXgZ1nbKLPSat1VGXwjsqq6HNGCG0NqxGd5RTBSL00nbtL+TYUY7bCIKAfWSZyDBa",
+	"hyGrnns7aGG+l0akwD4yvZE3YDWENtD4yzjXlEBUKbUwzn8docU25EtYSQVEVLTwetI+kSrgLri4jyw+",
+	"FPyaC4T3nD4IvOQIaSA+T18fsuOltqSOUbkGowqugedMmMNxRHSIx8EboCe24e+lAQTiXKSbS2cAt/dP",
+	"iXRj9fTC8HWESc9dM7NGci60YRmsRGGRScLE2P8qXqwx9Fer+n1Y7EF1ydejMZ02nJMWexlzR1ZKbhuD",
+	"NWbSYCN7JsiAv4YDT1duwZCRt6BhjRGiKDnV0O7KmPH0XYM77MEwzG1HlsxshKYZEK0hVr0c/FbmVjCc",
+	"Gp6LNC7tut6PHFr0G7Le7JLh9sFL7gYsGiwHc/fwEtvGM74WBaqud2AiBnd+v9M2OmqDuGqSE4/G9JUo",
+	"y4ExjDQ8Yrtd2p+70IwjjkZL6kNAB2EMURcUFDtuYoAd/SpUWgnDlgr4FSiKE1tQOHPxNNbE03oxSw1p",
+	"ZcQ1LKxK9aHhDhGjsrXOpSg006JISdrmXBumKzzyiqIsHJJnGcovnp+1QOh/1Z/cjsGWO5bmXGv2TFmF",
+	"iIiDLGG5TK/sv4U0i1yu15AtRJEQtIt0w/McijUkTJoNqIMA/KVEm0SZcMMbtNvVLcAHbUbVV9N9X4R3",
+	"2jAOp48aSJZQLKrCiDwuChzjp1LmmbwpkF4KZr9iKRHUkB05OvWvFVe8MKKARaNuO5E/uzus3h06ZyIi",
+	"xTMsbaRlRMbN/SbMHoWzhrBiGtJyFTU2ZwFZKUVhdAv+DdcstQvLpipKx+F2BsoxiAWVLVf3ocINczNr",
+	"xhV4GcZw71lDBgnb8Hy1wA9kAdR5CaialFVJfM0t4wTIpH43XBiMRhB9KG6kCmz1NJcal2pHniWzepZZ",
+	"a2P62iuZ3T63gzy/5qrgWysOfu4IOzyeOfETRNp+pHkiLW95vhpu/SkEzLrlZTboFv7DH7FagYEyT0Eq",
+	"VQYUHKaNmepzVRoUZVqMnZvUPf3eB/I0iUvuNgW3VrVHsTRkt4fmnXz4WNM8UURXwySMa48jd0K93LGP",
+	"PcXjR+lP+b3i5eanHxylWQ9RKnZ8dspKbjaJVTcbOwcagK/BcJFHg5kD66EEEc14njtnhXF2I4pM3gy4",
+	"C1uOVvCesXJYGe8ep87X3Ttk1BQJZXJ3kImk1aLhKd90KK7elMYsadbvAR+nKrEuILtUAG8hdqpN7cwo",
+	"ALYJHUXFC11yBUW6s9r7kL3leuPk1AZu6yPmi7fHz19+9fU3LAd+7drdb8+Obo+O2L/+RUHwA4xaFjID",
+	"HXZ4YTvgpv3rX0yJ9cYcHEYItMkY2Ce6Y0kGd8nMLmwxmCqCYoPQ4OdhApWe8gdwsQ2+gl3U/XwLt359",
+	"Hpln//f0Y5M4INboyJXVMhcpC6KELf8bW+OR17M37+rRmlGSJvuk2U3U3rQ2PFALCZpmHlfDUpr6JKIb",
+	"+1JXuZ+O603tvwilDavPAnD/BehoKLeOk00+hn/UaUV4QNGsLIQjIJd6k5OGAqNMZqTia6jPnDoE4c5Q",
+	"XeYOKwWkQIdA16BIJiOVvD5jeMLMiLM7zsEGrGUd1YmXYlvnB6G4p2HwkwfbjcMeyEmj7cKZrN3FXXoZ",
+	"47ZlmcO2oTuL7qgMbtv2HVp3o9CoDlORNU4z5Uslr6FYQCnT2AE4t9IjsIOxH6OP2M1G5FAvBDnLqZlw",
+	"03r0OABYsPzCeub1pEPAvYfbPmg+l+lxAODnCz0QTjvzg7fXzjM0ueTDpkTWi0rk4/VawdpaOpYfcLo+",
+	"8zQAoHg+ZJebgK8409USv8QIAOqdlBdMG5HnbAlMgZVH18R66EHcFOzk9PXhZHkYg7zJOiQkCbN5GgIZ",
+	"cDYsfuroQg9H3zBZ5DsEZUGocs2kogPZg9DpwIeoyjphET9v4v/NaHui/nFPIhCRaPN/aCbptZ1Lac7q",
+	"SaPNx9nAt985qPYcgbgzs2+QszElsEiBJJaVKBYz6If5nbQu5BKso7akBNKE2bXbPo1Q6MqkrhyMnqc4",
+	"ABwy7f8ehs1KD6Or0mf1LP227/y8Fl/ENUMhyzMX846SG+Nb6XDmhqFMIs/n43TeUdRu8yLuVlQD77ZL",
+	"mf/Z0mAI6nYWDPuvg6dIhHFHaY09PGj6k3rjyAJNd+dh1tnG9UljJ5+m+WK/gT1kWSeswHMO6eJflIG+",
+	"5zg6rjSaNG+Xlr1prwaZOmqAPtK1+Iy9gHD9T+AH6DA5dyKmmoTekRykLoGH57CtnNMuwcVM9BDWPbxR",
+	"H0l3REaMvrDvX3T/vJk9u7wRxoBilQbFBnIpfIxpYFSXFsJcgKlPBkquRA4LseVriCcr1WO5vgz7Tsyn",
+	"0akCKBYjUFIvBLKRVv/nYNqJu4+dBTPF1jW8XS66FMnoe0hmwafPjnEJCo9JjglyisfWF8s/Hsmv8QA+",
+	"PGPFaeTFI07xH5faYZUuphgMJs5cxlIUOskVJDGFc2ucJpl6WNBK7rgbFKNNQl00wfiYhZvNNjLPdJ3r",
+	"vKMAS53uTOZJQoK8TtXeMV7sUAh9w+Aa1M59u5J5Lm90YNeIJs/cSPZMFAvsuTByQUbXQmQHgblaq/4W",
+	"QU4zVEPefSczuPRjdRtOWmP/u9OhHKH4dCefFvVEOVGarPBFfZlh72lUGFty0a9Y+LumkzDTRycMk/1d",
+	"RK5FMTLPQBtqOWTf8zKSYk9m2TZhqdwC4yvjKLCXgo+G/uFUpqHNv1QA7y07TGCbEf7mWstUcNOYN0Iz",
+	"miVpLXQyhFP5+VposRS5MDELTYlr654TNZHfjU45fmT9RkSvUEzeFBCe4pE9hxoSh3gAt/29BuzMDzbQ",
+	"7Ke4V6ZcKzuulQnXTYlr4WhYv9Px15nU5uXR0bm7txy5QzOU+HZBp/TB5Qq45ZYdG41Ss/M/5bJx6n+t",
+	"oApzDgauaUV1VCBUG9XBRGHkN+j50/57R3mNosqFm7qyqn//K0btZGoal+HG4NYojveE8EqrBYcsvhHz",
+	"2s+w/3JJd2O+PPrb8MZk3PBpfO8suX33ZN5RA/nlnBK2COM8t//sGNwKjVTW2+Vej/24QLjvh4fgFn0v",
+	"geexFltjH7hb/o36baPI48MH9dDj86a6a6xK71jjjn/TtjJan+4zNDAnD+/OBtYGJvCgvaGf1FaIWAlR",
+	"+yDqBTkGmX/EOxtGugNx9gwO14cJw7z6V/O5oW6HqdzO7QrmJKrmL15+8eVXX//vv/7toEVYsc8gl8W2",
+	"0le9T4/q/01wvLraw+95Lf+7ZmJXpAjNoEjVrqxVn5cvV7BDdU5RYOCZ1WL+AMWVZrBbatd/yE5RgqFJ",
+	"vOHKjxXsceLCCZAxqergf/SomMwICwfd6XADe/F4+FR6blDDTdBt+33N2jLpq/Scpy6bz8n9oss+1Oht",
+	"D8U4W/PS+hgK6q+IjxCDQvs86X5wDdl5kW64iITvzriqs8ZTXshCpDyvJ66D1xji5OnGOQKei3f+6IMm",
+	"8XuJCl5Wup2UThDU6FpKmQNH/23Ny5hyDLSiTwKvFZSjg4RtpTZ4t9MCAymvNHgvLANvRven3JfyHb+G",
+	"bw38kROb7rYQAqmdNpASbNw+S8bb2e11rrnt1sK9/WGNxvXNRmpgJVKQCA7qhbWjrwp5U9w/IZ1iYiGd",
+	"0J4M0/bfQYmVK4oQ8yN0lZvWWTI6UUqYnTvXmBYYbh9XT4snpHssLR9HJhiUhWDNRaGHYsUTEmlP6NCa",
+	"HDXKPVIDWULUZ7E3akSH3zRYDWkSupPCaMhXVibIAuhsMrAa/e3Ce+S2TkqFVVByoRZk5u5PqfOnWsgH",
+	"KdfsxgotpAL6HKmZBnxwXsGQJ74VestNumFiVUfNCslyWaxBYXoJOaQWaSenrxNWFcoKNtRrAg96U1nl",
+	"FNjBQ16esSXH7IBa33B04v1Us2QWDHI/5ROwkTtlo7GHmt81cw51+RDC8oiAORVn8N7XQG5iwKBRYRHm",
+	"5TzJmdoTnqZRQYYYBNTArnleUSQkCGpGLhFET+XqpXuYR0SwQ0cDlhs4ilZ/Cayj6Z0QWGz9defB860S",
+	"YzeB1vqLZnjbVSPm8cbr5DtB7epKE8IdE6PtFq4PmpCccpVN+uDEdkTpvd1WhbOJx7+qe3dC5osBYd04",
+	"QF4s740w7w2hP/bOp78PjJdz6N7RRK7yFICHp1bz6A0KvyX40yTI3NWiDHRzu1ofPJQJx0jDItHT8+iu",
+	"vfGd75LZhuvFUqjsxorHRSFNTLb8YwPojNA1LcT7hmv2rf+O0Xf3MxgnXsWPxMfHbUkEVGiyGNFOlFO0",
+	"YzibdbxG5sJDx4dOpRHZFLkbwThmVVlV/NwSNnsvDVDSdxzjevFrZYce0vX9sbF/96Zoe0hc3pSxqOPg",
+	"KJPX67sOjIR+b84NIXlwOJIRvLCs6T8ZcG1yHkttJ1q1bRVfgy9DE7mxm+eTeO/MdqQrvlos891CQ6GF",
+	"tQ+GV+ENsq1Yb9C6ar6JLQR3M2soa1KoXYHed6H3/M3FJV492M+u/q7jva/DUvWuSqUxGUzVE4OqiJMs",
+	"3ZKnMGn5F9jTGcfTJOgF9hw2YuoUpn1JNdcCbvaer1GHeydvoQXq9zPx+UqtMwNaaYslAy7vy5C+co9r",
+	"DsdFUfLus21XCgb0M2i+nTiDpnvYg0cj7ua9dvGCXBRX5Fr68TtR83CMCc5cJrcuGDQu3sNkkdHe8dyP",
+	"j3/RzNpveF26ue7jkhgWOVdrl71hvdqqsFyZLdDii+b6G5KUo9BEg7v/wBAahbtUxnLg2Xh432WaDMb7",
+	"2iZkxF50TYFmv+GalRJDoRhviQbtlIwphXOZQ9d4dwE4f2XJT9hg+x0QNyr2Tmb1hb9xUrknaQ3Uhtri",
+	"7Iv6tvOEfPb4hbp6Vx8Q4cIhB/fvTWB5tjdjQ3XG9GAFsnbK51S3yVdduxsomDRUU4gbw9PNFmtaT80v",
+	"meqZacwljd0XFwUvUsFz5ro8aMkuwTZW48B7PXqPK/2wSZsgxAQEVCq2euvyPGjuDyq6WrTJXTWY2HTW",
+	"HvfND5tXg3I1hUarc9Tk3Wy/w0MX0EHeOXNWYzfVO88dtdIpxZD+SjfSx4Km51XY0U/wwxiGs4pumi62",
+	"oqicLzgudKDI9HBw81riWRdejtbT89tEEa0xsQFmjW10QAErxPkDL3fxuwlbphtXTKRrI3dLPTo8Ngvx",
+	"0+/dOYfFyEF0tDbF362FVB9R0JzRwNj9bIecLyEfj1FSt8QBN7iuC281d9NQMyEZNvozyj121YAycyua",
+	"oHH2GgwX3kpvT7qU8mrL1dViAP2NWe17DhXquJZKGBgfByujzn3/+GhkRY8OFTjgeuCmdJnvxsdxKQID",
+	"Q1DmyYRB9gBizezxIR7ouYSr7ALc25k2cpMuAQzSD4ZG+0y7J9KIesWXtHhMwLFtFEYmWQo5D3+OyUU8",
+	"SQalx3eh7hondCVQ3E0cho5kItIqG1jLtPieXizzCotYo/syHATBYBvmjeUVsOvwIDUWBIm7VAjY/RL+",
+	"Jz9y0Mr9n1TlbfxiAcL7kEsFg+EcHHFiNGfvzQQcaPKtBH8kBhPobZ/0mUYmQrO656j274RLJt6VaHNz",
+	"ny27/NVDQCciE8Db44mYKOsn9EdQsmMfWSZBB+n23GejVc0Avkbfml+DNZ8UpHJdiN+wra66549xm/SQ",
+	"UkmDhdLt6ipdojk2S2YWRxahSrjGYK6J57y95b2uZ+01nQVg9BovArh6jcdrOA/h7HX4EAJusa7y0XLI",
+	"o4WOJxxDZD27PnJ+2nfBxs2rdg3c5vR0yOIKHaJJl7VcZ8iQE2OXtj7nistxUddb05gG2Ss4e6M96mpX",
+	"W065f4bvptL5faWE2V1Yr9A9TgVcgTquqDD7Ev/6zmuY//7HpX8CC8UotjbAbYwp6cUhUaykf/2DpxSv",
+	"ooe0rKq5qMpSKuNorcnrXAuzqZaU1klJn3M6Gt4K/1RTxxc5O6WC4Lzga59E6ZWkdmlfeO1mVkc9XdYH",
+	"Dcm+5emVJafjs1OSvPRWyOzF4dHhkS8Yx0sxezX74vDo8At8UMVsEFVzO0oxr/Oy7G/r2InWD0KbIP9I",
+	"uwy0aFYX3Van2wjuwCAJMpWooZ2rlLBUKlWVhmK/QTJOwdwVl0P2Y5HvWCPmjWQIPSWVHlKdMvL3T7PZ",
+	"q9n3YI5t+2m9uKT19NvP8fBC02XePA13l0zq7B5qu/ul81zay6Ojez2KFU+Hv8d9mFZmXjS0OJ5h3ynj",
+	"GXn6ov8wF5KJNbJ9IlyLMrRL1FSQYn4j3qK5S2ZfHr0YAqZG47z1whh+9MX4R81jcXfJ7Cvahf1fxJ51",
+	"Qznji6PTGuuk6XqFQT4UhYl/niH5UcKwYzRXVE2P81k6VPvTNn5sCgPW9S5xZHrrJKzV5urZuWTtpTQP",
+	"5qMLD/u/j7g7BVNjMc1REr1o17XDCwh1Rb4/JzF2SvVNI8H5737Vd/OmmiNRpDVOI5HcymB2RVNFsjMx",
+	"pkjilSWLUSqFIoymiq1BwaH6vpQCLfNryKjojiXjUlDxJ3DhTlaVTBY4ChZ38DOWUuZM74r03uRMpneL",
+	"oj84RDS1I/uqonOYWZNM7TjXhXjx7U18hKR+ejOo+NiYPGQuN3zRNY/6SuTL/p40ILM0t7ZM9gfS8JcE",
+	"0f4v6pcpn47oT+xKEesNMQ6VRY4xwgZ4TsZhVAZjMjcTtK/WzxRUC4o+o6swqipc8cSesHxLoz+plGyS",
+	"n7zfKq+sPdxcLJJXoyXxBm4r00uiLso3LfQXPEjXTZolbPkOIYRkj465AXWKRgP6L1Okem+biN6+eATS",
+	"H/r0VhLZLsqxb+0Y/fQUdQzHUbjvUuJeZFZFjc7A2Zq9+vmXkCGJ5OsCep7nHCcQ04VPpA5x3oVRwLet",
+	"OyGUbYoRw9ZLPHgd6pobrg7oPpBzE/xdpBO8wdNjTjwDP0Ffc6+Aj4VEG1DiUj51sbeHC/h9IkKmBsxz",
+	"jRhqU21NIktRUD2Q7ky9HX7XYNVPh/J8gngOnn7+41XAAP19D6Z/+cVTIaU9eCJsTtujFIgjsZJcH8jo",
+	"wQy5as7hkdZceJRiHtqSoquaVI8fJb267f9/N7TzpNS/wQWtt+Kh9tATGSuWohrqAYrh16TpcyqQOn9V",
+	"/s3IAdosLEkBGSD4Kgbj7KdzTON15yn+QH4triG4lRCjx5+US//dKwgvgztnmL5BdcJ80NLNPvDkfHiV",
+	"6CnlIq51XlKG86MEYRt7z87ef3/wQEn4SQTbOeAF+gbLnnJ+Oj+xPxDdKOw1SDduEF7XPcC3r99evvvB",
+	"Cro4zSziNEND3ZtmlP/sjyQSA7dmvjHbfIBIsGkCidCaIWsw9qfWlY4aGlqwywooy20xURZmxwwS1mv3",
+	"Hr27z+GYSEGpQEPRFH8M7q1aWrODDvhOFzjffclr7QUjOmx2iPrY+ukILomBkQEayjrlObAVT41UNTvR",
+	"WYbeyPCR5YMBmHCEWTh/XcHhZeCVrXJJb8j5R5K/Cp8rPnxRkzM9W/QHC9OLBvWPEqWfB6PU1N2mKM8n",
+	"RKrEJkRRLrSGlpPUJhasgQoYx5pBVpNSxR1U55rjaXZdlcdxizU2uRPMrYIkh+wcDQCNg4mCdsvyG5Kf",
+	"4umVKNb96NeZ1CasQeNYALT5Vma7e1mH46cQ/TI3d21f1fLcXY9AX34yMMJyUBH6jdV2cpfV3cNNqyrP",
+	"dw+m6r99soWF5ZSGF9YuZ+RNOL3TBrZPaPESWFFa/nD+A3t2rHdFehCwEoGn28z0+75AgTWqM6z9hKdM",
+	"DfXzpcQotS4hFSuRNjUlerqGZj3NxtUNwo/xhIjX/0md/qk+2fRKWVNcKLdiQrD+vNx804GtQzFz3i5T",
+	"PUg9wUstw1WrW+EfmgXrGy1F4YpqGk8cCaMLf3VBNZ34ilFGbCHpv6fk70GenL4mFSBX5sZqGhc8TerH",
+	"BpZQJ4AxuVrlogAqqvQ/YW6BhwW77p4HS/mfw330Hxb2/o9ghXDB9+GHkLQ+R57grY0cFa7zlKtx0z7C",
+	"AvUVhU5klONzGZCvnhO14nHcyfE5e+br2hxnmQKtsYGf48cHPqA6SJ8nXP2xdNmz9O0aSMUEBxsxK75p",
+	"jdjxL+qkwxfJy18i2eP344frIjsUZZ4dum18lOWOK7T78Gc22N/cllKZwLM9OT4fZoSg0twcq+fOU1lo",
+	"oY39ZZAvzqyuoCoZnIK+euPfPBt47owKAJQKVuKWTilzbu0iWUDCxCEc0nCFrJNKbrhmCrbymirkKbhR",
+	"1pQqrMW2xGsERZRhgomxxu9JsKARDroQv9ULAK5yAaq7vA3ZUTG69/Vwh3lswtta+0AijEUA+qZu1gYT",
+	"zMSKya3F1hCsVFlndj/g/nhVFeydq9c8RVMFX9EDMn/6Y520t6KAowOCH2Zr9wz4HnswZOrg6NEaZkK7",
+	"kqX+jtg0np/Enqc1YCPMedzRvyeNousQd/pYVeeZjj8Ns4VP5H3e/FZvxj24rf4m5LXPhXFEB7jpbKP3",
+	"5MbQ+ageeOmTlyUU2XOsE1s/6NjRhs/OvzthX//t65f4RD3P8555eXL6WlNGWPjSD8+19M/9iIKdnn13",
+	"4coL+zcWD8e47gKNwkl68D+O/juPu05LpeyQwefFAF0i3cMB7ZH8nQG6QfDzL3Y3KEsqRjGvAWl02+RS",
+	"tS4FvJrPc5nyfCO1efXXo78ezXkp5tcvZne/3P2/AAAA//+uBx2WmZwAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs/go-cid"
+)
+
+var (
+	// Media module error codes: 17000-17999
+	ErrCodeMedia = v1errors.NewErrorCode(v1errors.CheckCode(17000), "Media error")
+
+	// Media errors
+	ErrCodeMediaNotFound = v1errors.NewErrorCode(17001, "media not found")
+)
+
+// GetMediaCid implements the /media/{cid} endpoint. Only media archived with a
+// thread the caller can view is served.
+func (h *V1Handler) GetMediaCid(c *gin.Context, cidStr string) {
+	if _, err := cid.Parse(cidStr); err != nil {
+		HandleBadRequestError(c, fmt.Errorf("invalid cid: %w", err))
+		return
+	}
+
+	reader, err := h.threadService.GetMedia(viewerContext(c), cidStr)
+	if err != nil {
+		if errors.Is(err, service.ErrMediaNotFound) {
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeMediaNotFound))
+			return
+		}
+		HandleInternalServerError(c, err)
+		return
+	}
+	defer reader.Close() // nolint:errcheck
+
+	// Archived media is content addressed, sniff the type from the first bytes
+	br := bufio.NewReaderSize(reader, 512)
+	head, _ := br.Peek(512)
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, -1, http.DetectContentType(head), br, nil)
+}
@@ -5,12 +5,14 @@ import (
 	v1 "github.com/ipfs-force-community/threadmirror/internal/api/v1"
 )
 
-func Authentication(m gin.HandlerFunc) func(c *gin.Context) {
+// Authentication runs m on routes that require authentication and optional on
+// public ones, so that public routes can still tell who is calling
+func Authentication(m, optional gin.HandlerFunc) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		if _, ok := c.Get(v1.BearerAuthScopes); ok {
 			m(c)
 		} else {
-			c.Next()
+			optional(c)
 		}
 	}
 }
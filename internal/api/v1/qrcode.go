@@ -14,6 +14,17 @@ func (h *V1Handler) GetQrcode(c *gin.Context, params GetQrcodeParams) {
 		HandleBadRequestError(c, fmt.Errorf("thread_id parameter is required"))
 		return
 	}
+	// Do not reveal that a private thread exists to anyone but its owner
+	hidden, err := h.threadService.IsThreadHidden(viewerContext(c), params.GetThreadId())
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+	if hidden {
+		HandleNotFoundError(c, fmt.Errorf("thread not found"))
+		return
+	}
+
 	img, err := comm.GenQrcode(h.commonConfig.ThreadURLTemplate, params.GetThreadId())
 	if err != nil {
 		HandleInternalServerError(c, err)
@@ -1,12 +1,14 @@
 package v1
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
 	"github.com/ipfs-force-community/threadmirror/internal/comm"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
 )
 
 // GetRender implements the /render endpoint.
@@ -16,8 +18,12 @@ func (h *V1Handler) GetRender(c *gin.Context, params GetRenderParams) {
 		return
 	}
 
-	thread, err := h.threadService.GetThreadByID(c.Request.Context(), params.GetThreadId())
+	thread, err := h.threadService.GetThreadByID(viewerContext(c), params.GetThreadId())
 	if err != nil {
+		if errors.Is(err, service.ErrThreadNotFound) || errors.Is(err, service.ErrInvalidThreadID) {
+			_ = c.Error(v1errors.NotFound(err).WithCode(v1errors.ErrCodeNotFound))
+			return
+		}
 		_ = c.Error(v1errors.InternalServerError(err).WithCode(v1errors.ErrCodeInternalError))
 		return
 	}
@@ -28,6 +34,7 @@ func (h *V1Handler) GetRender(c *gin.Context, params GetRenderParams) {
 
 	c.Render(http.StatusOK, &render{
 		threadURLTemplate: h.commonConfig.ThreadURLTemplate,
+		mediaURLTemplate:  h.commonConfig.MediaURLTemplate,
 		threadID:          params.GetThreadId(),
 		data:              thread,
 		logger:            h.logger,
@@ -36,13 +43,14 @@ func (h *V1Handler) GetRender(c *gin.Context, params GetRenderParams) {
 
 type render struct {
 	threadURLTemplate string
+	mediaURLTemplate  string
 	threadID          string
 	data              any
 	logger            *slog.Logger
 }
 
 func (r *render) Render(w http.ResponseWriter) error {
-	html, err := comm.RenderThread(r.threadURLTemplate, r.threadID, r.data, r.logger)
+	html, err := comm.RenderThread(r.threadURLTemplate, r.mediaURLTemplate, r.threadID, r.data, r.logger)
 	if err != nil {
 		return err
 	}
@@ -1,11 +1,13 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ipfs-force-community/threadmirror/internal/comm"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
 	"github.com/ipfs-force-community/threadmirror/pkg/util"
 )
 
@@ -18,8 +20,12 @@ func (h *V1Handler) GetShare(c *gin.Context, params GetShareParams) {
 	}
 
 	// Fetch thread detail
-	thread, err := h.threadService.GetThreadByID(c.Request.Context(), threadID)
+	thread, err := h.threadService.GetThreadByID(viewerContext(c), threadID)
 	if err != nil {
+		if errors.Is(err, service.ErrThreadNotFound) || errors.Is(err, service.ErrInvalidThreadID) {
+			HandleNotFoundError(c, err)
+			return
+		}
 		HandleInternalServerError(c, err)
 		return
 	}
@@ -29,7 +35,7 @@ func (h *V1Handler) GetShare(c *gin.Context, params GetShareParams) {
 	}
 
 	// Render thread to HTML
-	html, err := comm.RenderThread(h.commonConfig.ThreadURLTemplate, threadID, thread, h.logger)
+	html, err := comm.RenderThread(h.commonConfig.ThreadURLTemplate, h.commonConfig.MediaURLTemplate, threadID, thread, h.logger)
 	if err != nil {
 		HandleInternalServerError(c, err)
 		return
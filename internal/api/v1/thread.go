@@ -8,8 +8,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
 	"github.com/ipfs-force-community/threadmirror/internal/service"
 	"github.com/ipfs-force-community/threadmirror/internal/task/queue"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
 	"github.com/ipfs-force-community/threadmirror/pkg/auth"
 	"github.com/ipfs-force-community/threadmirror/pkg/util"
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
@@ -17,11 +19,17 @@ import (
 )
 
 // Thread access errors
-var ErrCodeThreadNotFound = v1errors.NewErrorCode(14001, "thread not found")
+var (
+	ErrCodeThreadNotFound    = v1errors.NewErrorCode(14001, "thread not found")
+	ErrCodeThreadNotArchived = v1errors.NewErrorCode(14002, "thread has not been archived yet")
+	ErrCodeCARUnsupported    = v1errors.NewErrorCode(14003, "CAR export is not supported by the storage backend")
+	ErrCodeNoAttestation     = v1errors.NewErrorCode(14004, "thread has no attestation")
+	ErrCodePrivateDisabled   = v1errors.NewErrorCode(14005, "private archives are not enabled")
+)
 
 // GetMentionsId handles GET /mentions/{id}
 func (h *V1Handler) GetThreadId(c *gin.Context, id string) {
-	thread, err := h.threadService.GetThreadByID(c.Request.Context(), id)
+	thread, err := h.threadService.GetThreadByID(viewerContext(c), id)
 	if err != nil {
 		if errors.Is(err, service.ErrThreadNotFound) {
 			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeThreadNotFound))
@@ -38,10 +46,12 @@ func (h *V1Handler) GetThreadId(c *gin.Context, id string) {
 
 func (h *V1Handler) convertThreadDetailToAPI(thread *service.ThreadDetail) ThreadDetail {
 	var apiTweets []Tweet
-	if len(thread.Tweets) > 0 {
-		apiTweets = lo.Map(thread.Tweets, func(tweet *xscraper.Tweet, _ int) Tweet {
+	var apiTree *[]ThreadTreeNode
+	if thread.Tree != nil && thread.Tree.Len() > 0 {
+		apiTweets = lo.Map(thread.Tree.Tweets(), func(tweet *xscraper.Tweet, _ int) Tweet {
 			return h.convertXScraperTweetToAPI(tweet)
 		})
+		apiTree = lo.ToPtr(convertThreadTreeToAPI(thread.Tree))
 	}
 
 	// Convert author if available
@@ -56,7 +66,11 @@ func (h *V1Handler) convertThreadDetailToAPI(thread *service.ThreadDetail) Threa
 	}
 
 	var status ThreadDetailStatus
-	if thread.RetryCount >= h.commonConfig.ThreadMaxRetries-1 {
+	var failureReason *UnavailableReason
+	if thread.FailureReason != "" {
+		status = ThreadDetailStatusFailed
+		failureReason = lo.ToPtr(UnavailableReason(thread.FailureReason))
+	} else if thread.RetryCount >= h.commonConfig.ThreadMaxRetries-1 {
 		status = ThreadDetailStatusFailed
 	} else {
 		status = ThreadDetailStatus(thread.Status)
@@ -69,11 +83,109 @@ func (h *V1Handler) convertThreadDetailToAPI(thread *service.ThreadDetail) Threa
 		NumTweets:      thread.NumTweets,
 		CreatedAt:      thread.CreatedAt,
 		Tweets:         &apiTweets,
+		Tree:           apiTree,
 		Status:         status,
 		Author:         apiAuthor,
+		Visibility:     ThreadDetailVisibility(thread.Visibility),
+		Mode:           lo.ToPtr(ThreadDetailMode(thread.Mode)),
+		Conversation:   convertConversationOptionsToAPI(thread.Conversation),
+		StorageProof:   convertStorageProof(thread.PDP),
+		FailureReason:  failureReason,
+		MissingTweets:  convertMissingTweetsToAPI(thread.MissingTweets),
+	}
+}
+
+// convertMissingTweetsToAPI converts the tweets of a thread X did not serve to API MissingTweets
+func convertMissingTweetsToAPI(missing []xscraper.Tombstone) *[]MissingTweet {
+	if len(missing) == 0 {
+		return nil
+	}
+	return lo.ToPtr(lo.Map(missing, func(tombstone xscraper.Tombstone, _ int) MissingTweet {
+		return MissingTweet{
+			TweetId:  lo.EmptyableToPtr(tombstone.TweetID),
+			Reason:   UnavailableReason(tombstone.Reason),
+			Message:  lo.EmptyableToPtr(tombstone.Message),
+			Position: tombstone.Position,
+		}
+	}))
+}
+
+// convertThreadTreeToAPI flattens a reply tree into API ThreadTreeNodes, depth
+// first. Replies to a gap point at the gap, so clients can rebuild the tree.
+func convertThreadTreeToAPI(tree *xscraper.ThreadTree) []ThreadTreeNode {
+	gaps := lo.SliceToMap(tree.Gaps, func(gap *xscraper.ThreadNode) (string, bool) { return gap.ID, true })
+	return lo.Map(tree.Nodes(), func(node *xscraper.ThreadNode, _ int) ThreadTreeNode {
+		var parentID *string
+		switch {
+		case node.Parent != nil:
+			parentID = &node.Parent.ID
+		case !node.IsGap() && gaps[node.Tweet.InReplyToStatusID]:
+			parentID = &node.Tweet.InReplyToStatusID
+		}
+		return ThreadTreeNode{
+			Id:          node.ID,
+			ParentId:    parentID,
+			AuthorChain: node.AuthorChain,
+			Gap:         node.IsGap(),
+		}
+	})
+}
+
+// convertConversationOptionsToAPI converts the limits of a conversation to API ConversationOptions
+func convertConversationOptionsToAPI(opts *xscraper.ConversationOptions) *ConversationOptions {
+	if opts == nil {
+		return nil
+	}
+	return &ConversationOptions{
+		MaxDepth:   &opts.MaxDepth,
+		MaxBreadth: &opts.MaxBreadth,
+		MinLikes:   &opts.MinLikes,
 	}
 }
 
+// conversationOptionsFromAPI returns the limits requested for a conversation,
+// nil if the request is for a thread
+func conversationOptionsFromAPI(req PostThreadScrapeJSONRequestBody) *xscraper.ConversationOptions {
+	if req.Mode == nil || *req.Mode != ThreadModeConversation {
+		return nil
+	}
+	var opts xscraper.ConversationOptions
+	if o := req.Conversation; o != nil {
+		opts.MaxDepth = lo.FromPtr(o.MaxDepth)
+		opts.MaxBreadth = lo.FromPtr(o.MaxBreadth)
+		opts.MinLikes = lo.FromPtr(o.MinLikes)
+	}
+	return &opts
+}
+
+// convertStorageProof converts the PDP status of a thread archive to API StorageProof
+func convertStorageProof(piece *service.PDPPiece) *StorageProof {
+	if piece == nil {
+		return nil
+	}
+
+	proof := &StorageProof{
+		Status:       StorageProofStatus(piece.ProofStatus()),
+		State:        StorageProofState(piece.State),
+		SubrootIndex: piece.SubrootIndex,
+	}
+	if piece.RootCID != "" {
+		proof.RootCid = &piece.RootCID
+	}
+	if p := piece.Proof; p != nil {
+		proof.ProofSetId = &p.ProofSetID
+		proof.RootId = p.RootID
+		proof.LastProvenEpoch = p.LastProvenEpoch
+		proof.NextChallengeEpoch = p.NextChallengeEpoch
+		proof.Failures = p.Failures
+		proof.CheckedAt = p.CheckedAt
+		if p.LastError != "" {
+			proof.LastError = &p.LastError
+		}
+	}
+	return proof
+}
+
 // convertXScraperTweetToAPI converts xscraper.Tweet to API Tweet type
 func (h *V1Handler) convertXScraperTweetToAPI(tweet *xscraper.Tweet) Tweet {
 	if tweet == nil {
@@ -123,6 +235,121 @@ func (h *V1Handler) convertXScraperTweetToAPI(tweet *xscraper.Tweet) Tweet {
 		Views:             &tweet.Views,
 		IsNoteTweet:       tweet.IsNoteTweet,
 		Richtext:          richtext,
+		ArchivedMedia:     convertArchivedMedia(tweet.ArchivedMedia),
+		Poll:              convertTweetPoll(tweet.Poll),
+		Card:              convertTweetCard(tweet.Card),
+		Space:             convertTweetSpace(tweet.Space),
+		Community:         convertTweetCommunity(tweet.Community),
+	}
+}
+
+// GetThreadIdCar handles GET /thread/{id}/car
+func (h *V1Handler) GetThreadIdCar(c *gin.Context, id string, params GetThreadIdCarParams) {
+	version := archive.CARv1
+	if params.GetVersion() != nil {
+		version = int(*params.GetVersion())
+	}
+
+	w := &carResponseWriter{c: c, filename: id + ".car"}
+	err := h.threadService.ExportCAR(viewerContext(c), id, version, w)
+	if err != nil {
+		if w.started {
+			// Headers are already sent, all we can do is cut the stream short
+			h.logger.Error("failed to stream thread car", "thread_id", id, "error", err)
+			c.Abort()
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrThreadNotFound), errors.Is(err, service.ErrInvalidThreadID):
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeThreadNotFound))
+		case errors.Is(err, service.ErrThreadNotArchived):
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeThreadNotArchived))
+		case errors.Is(err, service.ErrCARUnsupported):
+			_ = c.Error(v1errors.BadRequest(err).WithCode(ErrCodeCARUnsupported))
+		case errors.Is(err, service.ErrInvalidInput):
+			HandleBadRequestError(c, err)
+		default:
+			HandleInternalServerError(c, err)
+		}
+	}
+}
+
+// carResponseWriter sends the CAR response headers on the first write, so
+// errors raised before any block is written can still become JSON errors
+type carResponseWriter struct {
+	c        *gin.Context
+	filename string
+	started  bool
+}
+
+func (w *carResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", "application/vnd.ipld.car")
+		w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// GetThreadIdAttestation handles GET /thread/{id}/attestation
+func (h *V1Handler) GetThreadIdAttestation(c *gin.Context, id string) {
+	attestation, err := h.threadService.GetThreadAttestation(viewerContext(c), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrThreadNotFound), errors.Is(err, service.ErrInvalidThreadID):
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeThreadNotFound))
+		case errors.Is(err, service.ErrThreadNotArchived):
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeThreadNotArchived))
+		case errors.Is(err, service.ErrAttestationNotFound):
+			_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeNoAttestation))
+		default:
+			HandleInternalServerError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": convertThreadAttestation(attestation),
+	})
+}
+
+func convertAttestationEnvelope(e *attest.Envelope) AttestationEnvelope {
+	envelope := AttestationEnvelope{
+		PayloadType: e.PayloadType,
+		Payload:     e.Payload,
+	}
+	for _, sig := range e.Signatures {
+		envelope.Signatures = append(envelope.Signatures, struct {
+			Keyid string `json:"keyid"`
+			Sig   string `json:"sig"`
+		}{Keyid: sig.KeyID, Sig: sig.Sig})
+	}
+	return envelope
+}
+
+func convertThreadAttestation(a *service.ThreadAttestation) ThreadAttestation {
+	var publicKey *string
+	if a.PublicKey != "" {
+		publicKey = &a.PublicKey
+	}
+
+	return ThreadAttestation{
+		ThreadId:       a.ThreadID,
+		ContentCid:     a.ContentCID,
+		AttestationCid: a.AttestationCID,
+		KeyId:          a.KeyID,
+		PublicKey:      publicKey,
+		Envelope:       convertAttestationEnvelope(a.Envelope),
+		Statement: AttestationStatement{
+			Type:            a.Statement.Type,
+			ThreadId:        a.Statement.ThreadID,
+			TweetIds:        a.Statement.TweetIDs,
+			ScrapedAt:       a.Statement.ScrapedAt,
+			ScraperAccount:  a.Statement.ScraperAccount,
+			ContentCid:      a.Statement.ContentCID,
+			SoftwareVersion: a.Statement.SoftwareVersion,
+		},
 	}
 }
 
@@ -148,6 +375,16 @@ func (h *V1Handler) PostThreadScrape(c *gin.Context) {
 		return
 	}
 
+	conversation := conversationOptionsFromAPI(req)
+	if req.Visibility != nil && *req.Visibility == ThreadVisibilityPrivate {
+		h.scrapePrivateThread(c, currentUserID, tweetID, conversation)
+		return
+	}
+	if conversation != nil {
+		h.scrapeConversation(c, currentUserID, tweetID, *conversation)
+		return
+	}
+
 	// Create mention record and pending thread (will check for user-specific duplicates)
 	_, err = h.mentionService.CreateMention(c.Request.Context(), currentUserID, tweetID, nil, time.Now())
 	if err != nil {
@@ -175,8 +412,92 @@ func (h *V1Handler) PostThreadScrape(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusAccepted, gin.H{
-		"job_id":   jobID,
-		"tweet_id": tweetID,
-		"message":  "Thread scraping job has been queued and mention created",
+		"job_id":    jobID,
+		"tweet_id":  tweetID,
+		"thread_id": tweetID,
+		"message":   "Thread scraping job has been queued and mention created",
+	})
+}
+
+// scrapeConversation records userID's request for the conversation under
+// tweetID and queues its scrape
+func (h *V1Handler) scrapeConversation(c *gin.Context, userID, tweetID string, opts xscraper.ConversationOptions) {
+	ctx := c.Request.Context()
+
+	thread, err := h.threadService.GetOrCreateConversationThread(ctx, tweetID, opts)
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	_, err = h.mentionService.CreateMention(ctx, userID, thread.ID, nil, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrMentionAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{
+				"message": "You already have a mention for this conversation",
+			})
+			return
+		}
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	job, err := queue.NewThreadScrapeJobInto(thread.ID, tweetID)
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	jobID, err := h.jobQueueClient.Enqueue(ctx, job)
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id":    jobID,
+		"tweet_id":  tweetID,
+		"thread_id": thread.ID,
+		"message":   "Conversation scraping job has been queued and mention created",
+	})
+}
+
+// scrapePrivateThread creates an encrypted private archive of tweetID (or the
+// conversation under it) owned by userID and queues its scrape
+func (h *V1Handler) scrapePrivateThread(c *gin.Context, userID, tweetID string, conversation *xscraper.ConversationOptions) {
+	ctx := c.Request.Context()
+
+	thread, err := h.threadService.CreatePrivateThread(ctx, userID, tweetID, conversation)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrThreadAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"message": "You already have a private archive of this thread",
+			})
+		case errors.Is(err, service.ErrPrivateDisabled):
+			_ = c.Error(v1errors.BadRequest(err).WithCode(ErrCodePrivateDisabled))
+		default:
+			HandleInternalServerError(c, err)
+		}
+		return
+	}
+
+	job, err := queue.NewThreadScrapeJobInto(thread.ID, tweetID)
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	jobID, err := h.jobQueueClient.Enqueue(ctx, job)
+	if err != nil {
+		HandleInternalServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job_id":    jobID,
+		"tweet_id":  tweetID,
+		"thread_id": thread.ID,
+		"message":   "Private thread scraping job has been queued and mention created",
 	})
 }
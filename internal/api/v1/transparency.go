@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+)
+
+var (
+	// Transparency log module error codes: 16000-16999
+	ErrCodeTransparency = v1errors.NewErrorCode(v1errors.CheckCode(16000), "Transparency log error")
+
+	// Transparency log errors
+	ErrCodeTreeHeadNotFound = v1errors.NewErrorCode(16001, "tree head not found")
+	ErrCodeNotInLog         = v1errors.NewErrorCode(16002, "CID is not in the transparency log")
+)
+
+// GetTransparencySth handles GET /transparency/sth
+func (h *V1Handler) GetTransparencySth(c *gin.Context, params GetTransparencySthParams) {
+	head, err := h.logService.GetTreeHead(c.Request.Context(), params.GetTreeSize())
+	if err != nil {
+		h.handleTransparencyError(c, err)
+		return
+	}
+
+	var publicKey *string
+	if head.PublicKey != "" {
+		publicKey = &head.PublicKey
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": SignedTreeHead{
+			TreeSize:  head.TreeSize,
+			RootHash:  head.RootHash,
+			Timestamp: head.Timestamp,
+			HeadCid:   head.HeadCID,
+			KeyId:     head.KeyID,
+			PublicKey: publicKey,
+			Envelope:  convertAttestationEnvelope(head.Envelope),
+		},
+	})
+}
+
+// GetTransparencyProofInclusion handles GET /transparency/proof/inclusion
+func (h *V1Handler) GetTransparencyProofInclusion(c *gin.Context, params GetTransparencyProofInclusionParams) {
+	proof, err := h.logService.GetInclusionProof(c.Request.Context(), params.GetCid(), params.GetTreeSize())
+	if err != nil {
+		h.handleTransparencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": InclusionProof{
+			LeafIndex: proof.LeafIndex,
+			TreeSize:  proof.TreeSize,
+			ThreadId:  proof.Entry.ThreadID,
+			Cid:       proof.Entry.CID,
+			Entry:     proof.RawEntry,
+			LeafHash:  proof.LeafHash,
+			AuditPath: proof.AuditPath,
+		},
+	})
+}
+
+// GetTransparencyProofConsistency handles GET /transparency/proof/consistency
+func (h *V1Handler) GetTransparencyProofConsistency(c *gin.Context, params GetTransparencyProofConsistencyParams) {
+	proof, err := h.logService.GetConsistencyProof(c.Request.Context(), params.GetFirst(), params.GetSecond())
+	if err != nil {
+		h.handleTransparencyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": ConsistencyProof{
+			First:  proof.First,
+			Second: proof.Second,
+			Proof:  proof.Proof,
+		},
+	})
+}
+
+func (h *V1Handler) handleTransparencyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTreeHeadNotFound):
+		_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeTreeHeadNotFound))
+	case errors.Is(err, service.ErrNotInLog):
+		_ = c.Error(v1errors.NotFound(err).WithCode(ErrCodeNotInLog))
+	case errors.Is(err, service.ErrInvalidInput):
+		HandleBadRequestError(c, err)
+	default:
+		HandleInternalServerError(c, err)
+	}
+}
@@ -25,6 +25,35 @@ const (
 	Italic NoteTweetRichTextTagRichtextTypes = "Italic"
 )
 
+// Defines values for ScraperAccountState.
+const (
+	ScraperAccountStateClosed      ScraperAccountState = "closed"
+	ScraperAccountStateHalfOpen    ScraperAccountState = "half_open"
+	ScraperAccountStateOpen        ScraperAccountState = "open"
+	ScraperAccountStateQuarantined ScraperAccountState = "quarantined"
+)
+
+// Defines values for StorageProofState.
+const (
+	StorageProofStateFailed      StorageProofState = "failed"
+	StorageProofStateRootAdded   StorageProofState = "root_added"
+	StorageProofStateRootPending StorageProofState = "root_pending"
+	StorageProofStateUploaded    StorageProofState = "uploaded"
+)
+
+// Defines values for StorageProofStatus.
+const (
+	StorageProofStatusFailing StorageProofStatus = "failing"
+	StorageProofStatusPending StorageProofStatus = "pending"
+	StorageProofStatusProving StorageProofStatus = "proving"
+)
+
+// Defines values for ThreadDetailMode.
+const (
+	ThreadDetailModeConversation ThreadDetailMode = "conversation"
+	ThreadDetailModeThread       ThreadDetailMode = "thread"
+)
+
 // Defines values for ThreadDetailStatus.
 const (
 	ThreadDetailStatusCompleted ThreadDetailStatus = "completed"
@@ -33,6 +62,104 @@ const (
 	ThreadDetailStatusScraping  ThreadDetailStatus = "scraping"
 )
 
+// Defines values for ThreadDetailVisibility.
+const (
+	ThreadDetailVisibilityPrivate ThreadDetailVisibility = "private"
+	ThreadDetailVisibilityPublic  ThreadDetailVisibility = "public"
+)
+
+// Defines values for ThreadScrapePostRequestMode.
+const (
+	ThreadModeConversation ThreadScrapePostRequestMode = "conversation"
+	ThreadModeThread       ThreadScrapePostRequestMode = "thread"
+)
+
+// Defines values for ThreadScrapePostRequestVisibility.
+const (
+	ThreadVisibilityPrivate ThreadScrapePostRequestVisibility = "private"
+	ThreadVisibilityPublic  ThreadScrapePostRequestVisibility = "public"
+)
+
+// Defines values for ThreadVerificationStatus.
+const (
+	ThreadVerificationStatusMismatch    ThreadVerificationStatus = "mismatch"
+	ThreadVerificationStatusPass        ThreadVerificationStatus = "pass"
+	ThreadVerificationStatusUnreachable ThreadVerificationStatus = "unreachable"
+)
+
+// Defines values for UnavailableReason.
+const (
+	UnavailableReasonAgeRestricted UnavailableReason = "age_restricted"
+	UnavailableReasonDeleted       UnavailableReason = "deleted"
+	UnavailableReasonProtected     UnavailableReason = "protected"
+	UnavailableReasonSuspended     UnavailableReason = "suspended"
+	UnavailableReasonUnavailable   UnavailableReason = "unavailable"
+)
+
+// Defines values for GetThreadIdCarParamsVersion.
+const (
+	N1 GetThreadIdCarParamsVersion = 1
+	N2 GetThreadIdCarParamsVersion = 2
+)
+
+// ArchivedMedia defines model for ArchivedMedia.
+type ArchivedMedia struct {
+	// Cid CID of the archived copy, served from /media/{cid}
+	Cid string `json:"cid"`
+
+	// ContentType Content type reported when the media was downloaded
+	ContentType *string `json:"content_type"`
+
+	// Size Size of the archived file in bytes
+	Size int64 `json:"size"`
+
+	// Url Original media URL
+	Url string `json:"url"`
+}
+
+// AttestationEnvelope DSSE envelope; each signature is an ECDSA P-256 signature over the DSSE pre-authentication encoding of the payload
+type AttestationEnvelope struct {
+	// Payload Base64 encoded statement JSON
+	Payload     string `json:"payload"`
+	PayloadType string `json:"payloadType"`
+	Signatures  []struct {
+		Keyid string `json:"keyid"`
+
+		// Sig Base64 encoded ASN.1 signature
+		Sig string `json:"sig"`
+	} `json:"signatures"`
+}
+
+// AttestationStatement Decoded payload of the envelope
+type AttestationStatement struct {
+	ContentCid      string    `json:"content_cid"`
+	ScrapedAt       time.Time `json:"scraped_at"`
+	ScraperAccount  string    `json:"scraper_account"`
+	SoftwareVersion string    `json:"software_version"`
+	ThreadId        string    `json:"thread_id"`
+	TweetIds        []string  `json:"tweet_ids"`
+	Type            string    `json:"type"`
+}
+
+// ConsistencyProof defines model for ConsistencyProof.
+type ConsistencyProof struct {
+	First  int64    `json:"first"`
+	Proof  []string `json:"proof"`
+	Second int64    `json:"second"`
+}
+
+// ConversationOptions defines model for ConversationOptions.
+type ConversationOptions struct {
+	// MaxBreadth Most replies collected under a single tweet (default 20)
+	MaxBreadth *int `json:"max_breadth,omitempty"`
+
+	// MaxDepth Levels of replies below the tweet to collect (default 3)
+	MaxDepth *int `json:"max_depth,omitempty"`
+
+	// MinLikes Skip replies with fewer likes, and the replies under them
+	MinLikes *int `json:"min_likes,omitempty"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// Code Error code
@@ -51,6 +178,19 @@ type Hashtag struct {
 	Text string `json:"text"`
 }
 
+// InclusionProof defines model for InclusionProof.
+type InclusionProof struct {
+	AuditPath []string `json:"audit_path"`
+	Cid       string   `json:"cid"`
+
+	// Entry Exact log entry the leaf hash is computed over
+	Entry     string `json:"entry"`
+	LeafHash  string `json:"leaf_hash"`
+	LeafIndex int64  `json:"leaf_index"`
+	ThreadId  string `json:"thread_id"`
+	TreeSize  int64  `json:"tree_size"`
+}
+
 // Media defines model for Media.
 type Media struct {
 	// DisplayUrl Display URL for media
@@ -109,6 +249,21 @@ type MentionSummary struct {
 // MentionSummaryStatus Current status of the mention processing
 type MentionSummaryStatus string
 
+// MissingTweet A tweet of the thread X did not serve
+type MissingTweet struct {
+	// Message Notice X showed instead of the tweet
+	Message *string `json:"message"`
+
+	// Position Number of tweets of the thread before the missing tweet
+	Position int `json:"position"`
+
+	// Reason Why X does not serve a tweet; unavailable when X gave no recognizable reason
+	Reason UnavailableReason `json:"reason"`
+
+	// TweetId Tweet ID. Absent when X did not reveal it.
+	TweetId *string `json:"tweet_id"`
+}
+
 // NoteTweetRichText defines model for NoteTweetRichText.
 type NoteTweetRichText struct {
 	// RichtextTags Richtext tag list defining formatting ranges
@@ -142,6 +297,116 @@ type PaginationMeta struct {
 	Total int `json:"total"`
 }
 
+// ScraperAccount Circuit breaker state of a scraper account
+type ScraperAccount struct {
+	// ConsecutiveFailures Failed logins since the last success
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Failures Failures by class (rate_limited, locked, not_logged_in, login_challenge, other) since the bot started
+	Failures      map[string]int `json:"failures"`
+	LastError     *string        `json:"last_error"`
+	LastErrorAt   *time.Time     `json:"last_error_at"`
+	LastSuccessAt *time.Time     `json:"last_success_at"`
+
+	// OpenUntil End of the cooldown of an open circuit
+	OpenUntil *time.Time `json:"open_until"`
+
+	// QuarantineReason Login challenge the account was stopped at
+	QuarantineReason *string    `json:"quarantine_reason"`
+	QuarantinedAt    *time.Time `json:"quarantined_at"`
+
+	// RateLimits Rate limits of the endpoints the account has called
+	RateLimits []ScraperRateLimit `json:"rate_limits"`
+
+	// State open accounts are skipped until open_until, half_open ones are being tried again, quarantined ones wait for an operator
+	State ScraperAccountState `json:"state"`
+
+	// UpdatedAt When the bot last recorded the state
+	UpdatedAt time.Time `json:"updated_at"`
+	Username  string    `json:"username"`
+}
+
+// ScraperAccountState open accounts are skipped until open_until, half_open ones are being tried again, quarantined ones wait for an operator
+type ScraperAccountState string
+
+// ScraperRateLimit Rate limit of an X endpoint for a scraper account, as last reported by X
+type ScraperRateLimit struct {
+	// Endpoint GraphQL operation or API path, such as TweetDetail
+	Endpoint string `json:"endpoint"`
+
+	// Limit Requests allowed in a window
+	Limit int `json:"limit"`
+
+	// Remaining Requests left in the current window
+	Remaining int `json:"remaining"`
+
+	// Reset End of the current window
+	Reset     time.Time `json:"reset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SignedTreeHead Signed tree head of the transparency log. Hashes are hex encoded SHA-256; leaves are SHA-256(0x00 || entry) and nodes SHA-256(0x01 || left || right).
+type SignedTreeHead struct {
+	// Envelope DSSE envelope; each signature is an ECDSA P-256 signature over the DSSE pre-authentication encoding of the payload
+	Envelope AttestationEnvelope `json:"envelope"`
+
+	// HeadCid CID the signed envelope is stored under
+	HeadCid string `json:"head_cid"`
+
+	// KeyId Hex SHA-256 of the PKIX encoded signing public key
+	KeyId string `json:"key_id"`
+
+	// PublicKey PEM encoded public key, when the tree head was signed with the current key
+	PublicKey *string `json:"public_key"`
+
+	// RootHash Merkle tree hash of the first tree_size entries
+	RootHash  string    `json:"root_hash"`
+	Timestamp time.Time `json:"timestamp"`
+	TreeSize  int64     `json:"tree_size"`
+}
+
+// StorageProof How the archive piece is covered by the PDP proof set
+type StorageProof struct {
+	// CheckedAt Time of the last proof check
+	CheckedAt *time.Time `json:"checked_at"`
+
+	// Failures Consecutive proof checks that found a problem with the root
+	Failures int `json:"failures"`
+
+	// LastError Problem found by the last proof check
+	LastError *string `json:"last_error"`
+
+	// LastProvenEpoch Latest challenge epoch proven while the root was in the proof set
+	LastProvenEpoch *int64 `json:"last_proven_epoch"`
+
+	// NextChallengeEpoch Next challenge epoch of the proof set
+	NextChallengeEpoch *int64 `json:"next_challenge_epoch"`
+
+	// ProofSetId Proof set the root was added to
+	ProofSetId *int64 `json:"proof_set_id"`
+
+	// RootCid Aggregate PDP root the archive piece was added under. The piece is a subroot of it and can still be retrieved by its own CID.
+	RootCid *string `json:"root_cid"`
+
+	// RootId ID of the root within the proof set
+	RootId *int64 `json:"root_id"`
+
+	// State PDP state of the archive piece; only root_added archives are covered by proofs
+	State StorageProofState `json:"state"`
+
+	// Status Summary; proving once a proof covering the root has been observed, failing while the last proof check found a problem
+	Status StorageProofStatus `json:"status"`
+
+	// SubrootIndex Position of the archive piece among the subroots of root_cid
+	SubrootIndex *int `json:"subroot_index"`
+}
+
+// StorageProofState PDP state of the archive piece; only root_added archives are covered by proofs
+type StorageProofState string
+
+// StorageProofStatus Summary; proving once a proof covering the root has been observed, failing while the last proof check found a problem
+type StorageProofStatus string
+
 // Symbol defines model for Symbol.
 type Symbol struct {
 	// Indices Start and end indices in the tweet text
@@ -151,6 +416,28 @@ type Symbol struct {
 	Text string `json:"text"`
 }
 
+// ThreadAttestation Signed provenance attestation of an archived thread
+type ThreadAttestation struct {
+	// AttestationCid CID the envelope is stored under, next to the content
+	AttestationCid string `json:"attestation_cid"`
+
+	// ContentCid Archived content the attestation covers
+	ContentCid string `json:"content_cid"`
+
+	// Envelope DSSE envelope; each signature is an ECDSA P-256 signature over the DSSE pre-authentication encoding of the payload
+	Envelope AttestationEnvelope `json:"envelope"`
+
+	// KeyId Hex SHA-256 of the PKIX encoded signing public key
+	KeyId string `json:"key_id"`
+
+	// PublicKey PEM encoded public key, when the attestation was signed with the current key
+	PublicKey *string `json:"public_key"`
+
+	// Statement Decoded payload of the envelope
+	Statement AttestationStatement `json:"statement"`
+	ThreadId  string               `json:"thread_id"`
+}
+
 // ThreadAuthor defines model for ThreadAuthor.
 type ThreadAuthor struct {
 	// Id Author's unique identifier (Twitter user ID)
@@ -174,32 +461,60 @@ type ThreadDetail struct {
 	Cid string `json:"cid"`
 
 	// ContentPreview Thread content preview/summary
-	ContentPreview string `json:"content_preview"`
+	ContentPreview string               `json:"content_preview"`
+	Conversation   *ConversationOptions `json:"conversation,omitempty"`
 
 	// CreatedAt Thread creation timestamp
 	CreatedAt time.Time `json:"created_at"`
 
+	// FailureReason Why X does not serve a tweet; unavailable when X gave no recognizable reason
+	FailureReason *UnavailableReason `json:"failure_reason,omitempty"`
+
 	// Id Thread unique identifier
 	Id string `json:"id"`
 
+	// MissingTweets Tweets of the thread X did not serve when it was archived
+	MissingTweets *[]MissingTweet `json:"missing_tweets"`
+
+	// Mode A conversation holds the reply tree under the tweet, with replies by any user; every reply follows the tweet it replies to (in_reply_to_status_id)
+	Mode *ThreadDetailMode `json:"mode,omitempty"`
+
 	// NumTweets Number of tweets in the thread
 	NumTweets int `json:"num_tweets"`
 
 	// Status Current status of the thread scraping process
 	Status ThreadDetailStatus `json:"status"`
 
-	// Tweets Tweets associated with this Thread
+	// StorageProof How the archive piece is covered by the PDP proof set
+	StorageProof *StorageProof `json:"storage_proof,omitempty"`
+
+	// Tree Reply tree of the tweets, depth first with replies oldest first. Gaps, and the replies to them, come after the replies under the root.
+	Tree *[]ThreadTreeNode `json:"tree"`
+
+	// Tweets Tweets associated with this Thread, oldest first
 	Tweets *[]Tweet `json:"tweets"`
+
+	// Visibility Private threads are only visible to their owner
+	Visibility ThreadDetailVisibility `json:"visibility"`
 }
 
+// ThreadDetailMode A conversation holds the reply tree under the tweet, with replies by any user; every reply follows the tweet it replies to (in_reply_to_status_id)
+type ThreadDetailMode string
+
 // ThreadDetailStatus Current status of the thread scraping process
 type ThreadDetailStatus string
 
+// ThreadDetailVisibility Private threads are only visible to their owner
+type ThreadDetailVisibility string
+
 // ThreadScrapePost200Response defines model for ThreadScrapePost200Response.
 type ThreadScrapePost200Response struct {
 	// Message Success message
 	Message string `json:"message"`
 
+	// ThreadId Thread the tweet is archived into; a private archive gets its own thread
+	ThreadId *string `json:"thread_id,omitempty"`
+
 	// TweetId Twitter tweet ID extracted from the URL
 	TweetId string `json:"tweet_id"`
 }
@@ -214,10 +529,64 @@ type ThreadScrapePost409Response struct {
 
 // ThreadScrapePostRequest defines model for ThreadScrapePostRequest.
 type ThreadScrapePostRequest struct {
+	Conversation *ConversationOptions `json:"conversation,omitempty"`
+
+	// Mode thread archives the author's thread up to the tweet; conversation archives the reply tree under the tweet, including replies by other users
+	Mode *ThreadScrapePostRequestMode `json:"mode,omitempty"`
+
 	// Url Twitter/X URL to scrape (e.g., https://twitter.com/user/status/123456789)
 	Url string `json:"url"`
+
+	// Visibility A private archive is encrypted with its own key and only readable by the requesting user. It is not shared with other users, attested or added to the transparency log, and its media is not archived.
+	Visibility *ThreadScrapePostRequestVisibility `json:"visibility,omitempty"`
 }
 
+// ThreadScrapePostRequestMode thread archives the author's thread up to the tweet; conversation archives the reply tree under the tweet, including replies by other users
+type ThreadScrapePostRequestMode string
+
+// ThreadScrapePostRequestVisibility A private archive is encrypted with its own key and only readable by the requesting user. It is not shared with other users, attested or added to the transparency log, and its media is not archived.
+type ThreadScrapePostRequestVisibility string
+
+// ThreadTreeNode Place of a tweet in the reply tree of a thread, or a gap where a tweet replied to is missing
+type ThreadTreeNode struct {
+	// AuthorChain Part of the canonical thread, the root and each following reply by its author to the previous tweet of the chain
+	AuthorChain bool `json:"author_chain"`
+
+	// Gap The tweet is missing from the archive, most likely because it was deleted
+	Gap bool `json:"gap"`
+
+	// Id Tweet ID
+	Id string `json:"id"`
+
+	// ParentId ID of the tweet replied to, the ID of a gap for a reply to a missing tweet. Absent for the root and for gaps, whose place in the tree is unknown.
+	ParentId *string `json:"parent_id"`
+}
+
+// ThreadVerification Result of the last integrity check of an archived thread
+type ThreadVerification struct {
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Cid Thread CID the check ran against
+	Cid string `json:"cid"`
+
+	// ConsecutiveFailures Checks failed in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// FailedCid Content that failed the check, the thread itself or one of its archived media
+	FailedCid *string `json:"failed_cid"`
+	LastError *string `json:"last_error"`
+
+	// RepairQueuedAt When the failing replicas were last queued for repair
+	RepairQueuedAt *time.Time `json:"repair_queued_at"`
+
+	// Status mismatch if content no longer hashes to its CID, unreachable if it could not be read back
+	Status   ThreadVerificationStatus `json:"status"`
+	ThreadId string                   `json:"thread_id"`
+}
+
+// ThreadVerificationStatus mismatch if content no longer hashes to its CID, unreachable if it could not be read back
+type ThreadVerificationStatus string
+
 // Timestamp defines model for Timestamp.
 type Timestamp struct {
 	// Indices Start and end indices in the text
@@ -232,7 +601,15 @@ type Timestamp struct {
 
 // Tweet defines model for Tweet.
 type Tweet struct {
-	Author *TweetUser `json:"author,omitempty"`
+	// ArchivedMedia Archived copies of the tweet's photos and videos
+	ArchivedMedia *[]ArchivedMedia `json:"archived_media"`
+	Author        *TweetUser       `json:"author,omitempty"`
+
+	// Card Preview X shows for a link in a tweet
+	Card *TweetCard `json:"card,omitempty"`
+
+	// Community Community the tweet was posted in
+	Community *TweetCommunity `json:"community,omitempty"`
 
 	// ConversationId Conversation thread identifier
 	ConversationId string `json:"conversation_id"`
@@ -274,6 +651,9 @@ type Tweet struct {
 	// Lang Tweet language code
 	Lang string `json:"lang"`
 
+	// Poll Poll attached to a tweet
+	Poll *TweetPoll `json:"poll,omitempty"`
+
 	// PossiblySensitive Whether content might be sensitive
 	PossiblySensitive bool   `json:"possibly_sensitive"`
 	QuotedTweet       *Tweet `json:"quoted_tweet,omitempty"`
@@ -283,8 +663,11 @@ type Tweet struct {
 	Richtext NoteTweetRichText `json:"richtext"`
 
 	// Source Source application
-	Source *string    `json:"source"`
-	Stats  TweetStats `json:"stats"`
+	Source *string `json:"source"`
+
+	// Space Audio Space shared in a tweet
+	Space *TweetSpace `json:"space,omitempty"`
+	Stats TweetStats  `json:"stats"`
 
 	// Text Tweet text content
 	Text string `json:"text"`
@@ -293,6 +676,31 @@ type Tweet struct {
 	Views *int `json:"views"`
 }
 
+// TweetCard Preview X shows for a link in a tweet
+type TweetCard struct {
+	Description *string `json:"description"`
+	Domain      *string `json:"domain"`
+	ImageUrl    *string `json:"image_url"`
+
+	// Name X's card type, such as summary_large_image or unified_card
+	Name  string  `json:"name"`
+	Title *string `json:"title"`
+
+	// Url Where the card leads
+	Url string `json:"url"`
+}
+
+// TweetCommunity Community the tweet was posted in
+type TweetCommunity struct {
+	// AuthorRole Role of the tweet's author in the community, such as Member or Moderator
+	AuthorRole  *string `json:"author_role"`
+	Description *string `json:"description"`
+	Id          string  `json:"id"`
+	MemberCount *int    `json:"member_count"`
+	Name        string  `json:"name"`
+	Url         *string `json:"url"`
+}
+
 // TweetEntities defines model for TweetEntities.
 type TweetEntities struct {
 	// Hashtags Hashtags in the tweet
@@ -314,6 +722,32 @@ type TweetEntities struct {
 	UserMentions []UserMention `json:"user_mentions"`
 }
 
+// TweetPoll Poll attached to a tweet
+type TweetPoll struct {
+	Choices         []TweetPollChoice `json:"choices"`
+	DurationMinutes *int              `json:"duration_minutes"`
+
+	// EndsAt When voting closes
+	EndsAt time.Time `json:"ends_at"`
+
+	// Final The poll has ended and its counts no longer change
+	Final bool `json:"final"`
+}
+
+// TweetPollChoice defines model for TweetPollChoice.
+type TweetPollChoice struct {
+	// Count Votes for the choice
+	Count    int     `json:"count"`
+	ImageUrl *string `json:"image_url"`
+	Label    string  `json:"label"`
+}
+
+// TweetSpace Audio Space shared in a tweet
+type TweetSpace struct {
+	Id  string `json:"id"`
+	Url string `json:"url"`
+}
+
 // TweetStats defines model for TweetStats.
 type TweetStats struct {
 	// BookmarkCount Number of bookmarks
@@ -358,6 +792,9 @@ type TweetUser struct {
 	// Name User display name
 	Name string `json:"name"`
 
+	// ProfileImageCid CID of the archived profile image
+	ProfileImageCid *string `json:"profile_image_cid"`
+
 	// ProfileImageUrl User profile image URL
 	ProfileImageUrl string `json:"profile_image_url"`
 
@@ -374,6 +811,9 @@ type TweetUser struct {
 	Verified bool `json:"verified"`
 }
 
+// UnavailableReason Why X does not serve a tweet; unavailable when X gave no recognizable reason
+type UnavailableReason string
+
 // Url defines model for Url.
 type Url struct {
 	DisplayUrl  string  `json:"display_url"`
@@ -406,6 +846,9 @@ type PageOffset = int
 // BadRequest defines model for BadRequest.
 type BadRequest = Error
 
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
 // InternalServerError defines model for InternalServerError.
 type InternalServerError = Error
 
@@ -415,6 +858,18 @@ type NotFound = Error
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = Error
 
+// GetAdminIntegrityParams defines parameters for GetAdminIntegrity.
+type GetAdminIntegrityParams struct {
+	// Limit Maximum number of items to return
+	Limit *PageLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of items to skip
+	Offset *PageOffset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+func (p *GetAdminIntegrityParams) GetLimit() *PageLimit   { return p.Limit }
+func (p *GetAdminIntegrityParams) GetOffset() *PageOffset { return p.Offset }
+
 // GetMentionsParams defines parameters for GetMentions.
 type GetMentionsParams struct {
 	// Limit Maximum number of items to return
@@ -455,5 +910,48 @@ type GetShareParams struct {
 func (p *GetShareParams) GetThreadId() string { return p.ThreadId }
 func (p *GetShareParams) GetScale() *float32  { return p.Scale }
 
+// GetThreadIdCarParams defines parameters for GetThreadIdCar.
+type GetThreadIdCarParams struct {
+	// Version CAR format version
+	Version *GetThreadIdCarParamsVersion `form:"version,omitempty" json:"version,omitempty"`
+}
+
+func (p *GetThreadIdCarParams) GetVersion() *GetThreadIdCarParamsVersion { return p.Version }
+
+// GetThreadIdCarParamsVersion defines parameters for GetThreadIdCar.
+type GetThreadIdCarParamsVersion int
+
+// GetTransparencyProofConsistencyParams defines parameters for GetTransparencyProofConsistency.
+type GetTransparencyProofConsistencyParams struct {
+	// First Size of the earlier published tree head
+	First int64 `form:"first" json:"first"`
+
+	// Second Size of the later published tree head; the latest one if omitted
+	Second *int64 `form:"second,omitempty" json:"second,omitempty"`
+}
+
+func (p *GetTransparencyProofConsistencyParams) GetFirst() int64   { return p.First }
+func (p *GetTransparencyProofConsistencyParams) GetSecond() *int64 { return p.Second }
+
+// GetTransparencyProofInclusionParams defines parameters for GetTransparencyProofInclusion.
+type GetTransparencyProofInclusionParams struct {
+	// Cid Archived thread CID
+	Cid string `form:"cid" json:"cid"`
+
+	// TreeSize Size of a published tree head; the latest one if omitted
+	TreeSize *int64 `form:"tree_size,omitempty" json:"tree_size,omitempty"`
+}
+
+func (p *GetTransparencyProofInclusionParams) GetCid() string      { return p.Cid }
+func (p *GetTransparencyProofInclusionParams) GetTreeSize() *int64 { return p.TreeSize }
+
+// GetTransparencySthParams defines parameters for GetTransparencySth.
+type GetTransparencySthParams struct {
+	// TreeSize Size of a published tree head; the latest one if omitted
+	TreeSize *int64 `form:"tree_size,omitempty" json:"tree_size,omitempty"`
+}
+
+func (p *GetTransparencySthParams) GetTreeSize() *int64 { return p.TreeSize }
+
 // PostThreadScrapeJSONRequestBody defines body for PostThreadScrape for application/json ContentType.
 type PostThreadScrapeJSONRequestBody = ThreadScrapePostRequest
@@ -1,10 +1,13 @@
 package v1
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	v1errors "github.com/ipfs-force-community/threadmirror/internal/api/v1/errors"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/auth"
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
 	"github.com/samber/lo"
@@ -43,6 +46,12 @@ func PaginatedJSON(c *gin.Context, data any, total int64, limit, offset int) {
 	})
 }
 
+// viewerContext returns the request context carrying the calling user, which
+// decides whether private threads can be read
+func viewerContext(c *gin.Context) context.Context {
+	return service.WithViewer(c.Request.Context(), auth.CurrentUserID(c))
+}
+
 func ParseStringUUID(c *gin.Context, id string, errCode v1errors.ErrorCode) (string, bool) {
 	if id == "" {
 		_ = c.Error(v1errors.NotFound(nil).WithCode(errCode))
@@ -134,6 +143,11 @@ func convertTweetAuthor(author *xscraper.User) *TweetUser {
 		return nil
 	}
 
+	var profileImageCID *string
+	if author.ProfileImageCID != "" {
+		profileImageCID = &author.ProfileImageCID
+	}
+
 	return &TweetUser{
 		Id:              author.ID,
 		RestId:          author.RestID,
@@ -147,6 +161,81 @@ func convertTweetAuthor(author *xscraper.User) *TweetUser {
 		CreatedAt:       author.CreatedAt,
 		Verified:        author.Verified,
 		IsBlueVerified:  author.IsBlueVerified,
+		ProfileImageCid: profileImageCID,
+	}
+}
+
+// convertArchivedMedia converts xscraper archived media records to API ArchivedMedia
+func convertArchivedMedia(archived []xscraper.ArchivedMedia) *[]ArchivedMedia {
+	if len(archived) == 0 {
+		return nil
+	}
+
+	media := lo.Map(archived, func(m xscraper.ArchivedMedia, _ int) ArchivedMedia {
+		var contentType *string
+		if m.ContentType != "" {
+			contentType = &m.ContentType
+		}
+		return ArchivedMedia{
+			Url:         m.URL,
+			Cid:         m.CID,
+			ContentType: contentType,
+			Size:        m.Size,
+		}
+	})
+	return &media
+}
+
+// convertTweetPoll converts the poll of a tweet to API TweetPoll
+func convertTweetPoll(poll *xscraper.Poll) *TweetPoll {
+	if poll == nil {
+		return nil
+	}
+	return &TweetPoll{
+		Choices: lo.Map(poll.Choices, func(c xscraper.PollChoice, _ int) TweetPollChoice {
+			return TweetPollChoice{Label: c.Label, Count: c.Count, ImageUrl: lo.EmptyableToPtr(c.ImageURL)}
+		}),
+		EndsAt:          poll.EndsAt,
+		DurationMinutes: lo.EmptyableToPtr(poll.DurationMinutes),
+		Final:           poll.Final,
+	}
+}
+
+// convertTweetCard converts the link card of a tweet to API TweetCard
+func convertTweetCard(card *xscraper.LinkCard) *TweetCard {
+	if card == nil {
+		return nil
+	}
+	return &TweetCard{
+		Name:        card.Name,
+		Url:         card.URL,
+		Title:       lo.EmptyableToPtr(card.Title),
+		Description: lo.EmptyableToPtr(card.Description),
+		Domain:      lo.EmptyableToPtr(card.Domain),
+		ImageUrl:    lo.EmptyableToPtr(card.ImageURL),
+	}
+}
+
+// convertTweetSpace converts the Space of a tweet to API TweetSpace
+func convertTweetSpace(space *xscraper.Space) *TweetSpace {
+	if space == nil {
+		return nil
+	}
+	return &TweetSpace{Id: space.ID, Url: space.URL}
+}
+
+// convertTweetCommunity converts the community of a tweet to API TweetCommunity
+func convertTweetCommunity(community *xscraper.Community) *TweetCommunity {
+	if community == nil {
+		return nil
+	}
+	return &TweetCommunity{
+		Id:          community.ID,
+		Name:        community.Name,
+		Description: lo.EmptyableToPtr(community.Description),
+		MemberCount: lo.EmptyableToPtr(community.MemberCount),
+		Url:         lo.EmptyableToPtr(community.URL),
+		AuthorRole:  lo.EmptyableToPtr(community.AuthorRole),
 	}
 }
 
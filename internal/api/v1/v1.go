@@ -16,6 +16,8 @@ type V1Handler struct {
 	logger         *slog.Logger
 	mentionService *service.MentionService
 	threadService  *service.ThreadService
+	logService     *service.TransparencyLogService
+	scraperService *service.ScraperAccountService
 	commonConfig   *config.CommonConfig
 	serverConfig   *config.ServerConfig
 	jobQueueClient jobq.JobQueueClient
@@ -24,6 +26,8 @@ type V1Handler struct {
 func NewV1Handler(
 	mentionService *service.MentionService,
 	threadService *service.ThreadService,
+	logService *service.TransparencyLogService,
+	scraperService *service.ScraperAccountService,
 	logger *slog.Logger,
 	commonConfig *config.CommonConfig,
 	serverConfig *config.ServerConfig,
@@ -32,6 +36,8 @@ func NewV1Handler(
 	return &V1Handler{
 		mentionService: mentionService,
 		threadService:  threadService,
+		logService:     logService,
+		scraperService: scraperService,
 		commonConfig:   commonConfig,
 		serverConfig:   serverConfig,
 		jobQueueClient: jobQueueClient,
@@ -0,0 +1,290 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+)
+
+// ThreadDocument is a thread stored as a single canonical JSON file, for
+// backends that do not accept raw blocks and for private archives. SchemaV1
+// archives are a bare JSON array of tweets.
+type ThreadDocument struct {
+	// Version is the schema version the document was written with
+	Version int        `json:"version"`
+	Type    string     `json:"type"`
+	Tweets  []*tweetV3 `json:"tweets"`
+	// Stats is only kept in the document by private archives, which are
+	// encrypted and never share a CID anyway
+	Stats *Stats `json:"stats,omitempty"`
+}
+
+// Stats holds the engagement counters of a scrape. They change every time a
+// thread is scraped, so they are kept out of the archived content and stored
+// next to it instead.
+type Stats struct {
+	Tweets map[string]TweetStats `json:"tweets,omitempty"`
+	Users  map[string]UserStats  `json:"users,omitempty"`
+}
+
+// TweetStats holds the volatile fields of a tweet
+type TweetStats struct {
+	xscraper.TweetStats
+	Views int `json:"views,omitempty"`
+	// PollCounts are the votes of the choices of a poll that is not final
+	PollCounts []int `json:"poll_counts,omitempty"`
+	// CommunityMemberCount is the member count of the community the tweet was
+	// posted in
+	CommunityMemberCount int `json:"community_member_count,omitempty"`
+}
+
+// UserStats holds the volatile fields of a user
+type UserStats struct {
+	FollowersCount int `json:"followers_count"`
+	FriendsCount   int `json:"friends_count"`
+	StatusesCount  int `json:"statuses_count"`
+}
+
+// SplitStats returns a copy of tweets without engagement counters and with
+// timestamps in UTC, which is what gets archived, and the counters themselves.
+// The same content always yields the same archived tweets, however often it is
+// scraped.
+func SplitStats(tweets []*xscraper.Tweet) ([]*xscraper.Tweet, *Stats) {
+	stats := &Stats{
+		Tweets: make(map[string]TweetStats),
+		Users:  make(map[string]UserStats),
+	}
+	// Authors are shared between tweets; keep them shared in the copy
+	users := make(map[*xscraper.User]*xscraper.User)
+
+	var split func(tweet *xscraper.Tweet) *xscraper.Tweet
+	split = func(tweet *xscraper.Tweet) *xscraper.Tweet {
+		if tweet == nil {
+			return nil
+		}
+		content := *tweet
+		tweetStats := TweetStats{TweetStats: tweet.Stats, Views: tweet.Views}
+		content.Stats = xscraper.TweetStats{}
+		content.Views = 0
+		content.CreatedAt = tweet.CreatedAt.UTC()
+		// Votes keep coming in until the poll is final
+		if tweet.Poll != nil && !tweet.Poll.Final {
+			poll := *tweet.Poll
+			poll.EndsAt = poll.EndsAt.UTC()
+			poll.Choices = make([]xscraper.PollChoice, len(tweet.Poll.Choices))
+			for i, c := range tweet.Poll.Choices {
+				tweetStats.PollCounts = append(tweetStats.PollCounts, c.Count)
+				c.Count = 0
+				poll.Choices[i] = c
+			}
+			content.Poll = &poll
+		}
+		if tweet.Community != nil {
+			community := *tweet.Community
+			tweetStats.CommunityMemberCount = community.MemberCount
+			community.MemberCount = 0
+			content.Community = &community
+		}
+		stats.Tweets[tweet.RestID] = tweetStats
+
+		if tweet.Author != nil {
+			author, ok := users[tweet.Author]
+			if !ok {
+				u := *tweet.Author
+				stats.Users[u.RestID] = UserStats{
+					FollowersCount: u.FollowersCount,
+					FriendsCount:   u.FriendsCount,
+					StatusesCount:  u.StatusesCount,
+				}
+				u.FollowersCount, u.FriendsCount, u.StatusesCount = 0, 0, 0
+				u.CreatedAt = u.CreatedAt.UTC()
+				author = &u
+				users[tweet.Author] = author
+			}
+			content.Author = author
+		}
+		content.QuotedTweet = split(tweet.QuotedTweet)
+		return &content
+	}
+
+	content := make([]*xscraper.Tweet, 0, len(tweets))
+	for _, tweet := range tweets {
+		content = append(content, split(tweet))
+	}
+	return content, stats
+}
+
+// Apply sets the engagement counters recorded in s on tweets
+func (s *Stats) Apply(tweets []*xscraper.Tweet) {
+	if s == nil {
+		return
+	}
+
+	var apply func(tweet *xscraper.Tweet)
+	apply = func(tweet *xscraper.Tweet) {
+		if tweet == nil {
+			return
+		}
+		if ts, ok := s.Tweets[tweet.RestID]; ok {
+			tweet.Stats = ts.TweetStats
+			tweet.Views = ts.Views
+			if tweet.Poll != nil && len(ts.PollCounts) == len(tweet.Poll.Choices) {
+				for i, count := range ts.PollCounts {
+					tweet.Poll.Choices[i].Count = count
+				}
+			}
+			if tweet.Community != nil {
+				tweet.Community.MemberCount = ts.CommunityMemberCount
+			}
+		}
+		if tweet.Author != nil {
+			if us, ok := s.Users[tweet.Author.RestID]; ok {
+				tweet.Author.FollowersCount = us.FollowersCount
+				tweet.Author.FriendsCount = us.FriendsCount
+				tweet.Author.StatusesCount = us.StatusesCount
+			}
+		}
+		apply(tweet.QuotedTweet)
+	}
+	for _, tweet := range tweets {
+		apply(tweet)
+	}
+}
+
+// MarshalCanonical encodes v as canonical JSON: object keys are sorted at every
+// level, HTML characters are not escaped and there is no trailing newline, so
+// equal values always encode to the same bytes.
+func MarshalCanonical(v any) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Struct fields keep their declaration order; going through a generic value
+	// turns every object into a map, which encoding/json writes in key order
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// EncodeJSON encodes the content of tweets as a canonical thread document.
+// Engagement counters are left out, see SplitStats.
+func EncodeJSON(tweets []*xscraper.Tweet) ([]byte, error) {
+	return encodeJSON(ThreadType, tweets, false)
+}
+
+// EncodeJSONWithStats encodes tweets as a canonical thread document that keeps
+// its engagement counters, for archives that are encrypted before storage
+func EncodeJSONWithStats(tweets []*xscraper.Tweet) ([]byte, error) {
+	return encodeJSON(ThreadType, tweets, true)
+}
+
+// EncodeConversationJSON is EncodeJSON for a conversation. Replies point to
+// their parent by in_reply_to_status_id.
+func EncodeConversationJSON(tweets []*xscraper.Tweet) ([]byte, error) {
+	return encodeJSON(ConversationType, tweets, false)
+}
+
+// EncodeConversationJSONWithStats is EncodeJSONWithStats for a conversation
+func EncodeConversationJSONWithStats(tweets []*xscraper.Tweet) ([]byte, error) {
+	return encodeJSON(ConversationType, tweets, true)
+}
+
+func encodeJSON(typ string, tweets []*xscraper.Tweet, withStats bool) ([]byte, error) {
+	content, stats := SplitStats(tweets)
+	doc := ThreadDocument{
+		Version: SchemaVersion,
+		Type:    typ,
+		Tweets:  make([]*tweetV3, 0, len(content)),
+	}
+	if withStats {
+		doc.Stats = stats
+	}
+	for _, tweet := range content {
+		archived, err := newTweetV3(tweet)
+		if err != nil {
+			return nil, err
+		}
+		doc.Tweets = append(doc.Tweets, archived)
+	}
+	return MarshalCanonical(doc)
+}
+
+// DecodeJSON decodes a thread document, or a SchemaV1 JSON array of tweets
+func DecodeJSON(data []byte) ([]*xscraper.Tweet, error) {
+	doc, err := readDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(doc)
+}
+
+// JSONSchemaVersion returns the schema version of a thread document
+func JSONSchemaVersion(data []byte) (int, error) {
+	doc, err := readDocument(data)
+	if err != nil {
+		return 0, err
+	}
+	return doc.Version, nil
+}
+
+// JSONType returns the type of a thread document, ThreadType or
+// ConversationType
+func JSONType(data []byte) (string, error) {
+	doc, err := readDocument(data)
+	if err != nil {
+		return "", err
+	}
+	return doc.Type, nil
+}
+
+func readDocument(data []byte) (*ThreadDocument, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		doc := &ThreadDocument{Version: SchemaV1, Type: ThreadType}
+		if err := json.Unmarshal(data, &doc.Tweets); err != nil {
+			return nil, fmt.Errorf("unmarshal tweets: %w", err)
+		}
+		return doc, nil
+	}
+
+	var doc ThreadDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal thread document: %w", err)
+	}
+	if !isRootType(doc.Type) {
+		return nil, fmt.Errorf("not a thread document: type %q", doc.Type)
+	}
+	if err := checkSchemaVersion(doc.Version); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// decodeDocument decodes a document of any supported schema. Schemas 1 to 3
+// share the document layout, and their tweets all read as tweetV3.
+func decodeDocument(doc *ThreadDocument) ([]*xscraper.Tweet, error) {
+	tweets := make([]*xscraper.Tweet, 0, len(doc.Tweets))
+	for _, archived := range doc.Tweets {
+		tweet, err := archived.tweet()
+		if err != nil {
+			return nil, err
+		}
+		tweets = append(tweets, tweet)
+	}
+	doc.Stats.Apply(tweets)
+	return tweets, nil
+}
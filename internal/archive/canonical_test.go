@@ -0,0 +1,108 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	type node struct {
+		Zebra string         `json:"zebra"`
+		Alpha map[string]any `json:"alpha"`
+		Big   int64          `json:"big"`
+	}
+	data, err := MarshalCanonical(node{
+		Zebra: "<b>",
+		Alpha: map[string]any{"y": 1, "x": []any{map[string]any{"d": true, "c": nil}}},
+		Big:   1 << 60,
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"alpha":{"x":[{"c":null,"d":true}],"y":1},"big":1152921504606846976,"zebra":"<b>"}`, string(data))
+}
+
+func TestEncodeJSONIsDeterministic(t *testing.T) {
+	first, err := EncodeJSON(testTweets())
+	require.NoError(t, err)
+
+	// New engagement counters and another time zone do not change the document
+	tweets := testTweets()
+	tweets[0].Stats.FavoriteCount = 1000
+	tweets[0].Views = 5000
+	tweets[0].Author.FollowersCount = 10
+	tweets[1].CreatedAt = tweets[1].CreatedAt.In(time.FixedZone("", 8*3600))
+	second, err := EncodeJSON(tweets)
+	require.NoError(t, err)
+	require.Equal(t, string(first), string(second))
+
+	tweets[0].Text = "edited"
+	third, err := EncodeJSON(tweets)
+	require.NoError(t, err)
+	require.NotEqual(t, string(first), string(third))
+
+	decoded, err := DecodeJSON(first)
+	require.NoError(t, err)
+	content, _ := SplitStats(testTweets())
+	require.Equal(t, content, decoded)
+}
+
+func TestEncodeJSONSortsEntityMaps(t *testing.T) {
+	tweets := testTweets()
+	tweets[0].Entities.Hashtags = []generated.Hashtag{{"text": "go", "indices": []any{0, 3}}}
+	first, err := EncodeJSON(tweets)
+	require.NoError(t, err)
+	require.Contains(t, string(first), `{"indices":[0,3],"text":"go"}`)
+}
+
+func TestDecodeJSONLegacyArray(t *testing.T) {
+	tweets, err := DecodeJSON([]byte(` [{"id":"1","rest_id":"1","text":"legacy","stats":{"favorite_count":2}}]`))
+	require.NoError(t, err)
+	require.Len(t, tweets, 1)
+	require.Equal(t, "legacy", tweets[0].Text)
+	require.Equal(t, 2, tweets[0].Stats.FavoriteCount)
+
+	_, err = DecodeJSON([]byte(`{"version":99,"type":"threadmirror/thread","tweets":[]}`))
+	require.ErrorContains(t, err, "unsupported")
+}
+
+func TestStatsApply(t *testing.T) {
+	tweets := testTweets()
+	tweets[1].QuotedTweet.Stats.QuoteCount = 4
+	tweets[0].Author.StatusesCount = 9
+
+	content, stats := SplitStats(tweets)
+	require.Zero(t, content[1].QuotedTweet.Stats.QuoteCount)
+	require.Zero(t, content[0].Author.StatusesCount)
+	// Votes of a poll that is not final are counters too
+	require.Zero(t, content[0].Poll.Votes())
+	// The input is left alone
+	require.Equal(t, 4, tweets[1].QuotedTweet.Stats.QuoteCount)
+	require.Equal(t, 7, tweets[0].Poll.Votes())
+
+	stats.Apply(content)
+	require.Equal(t, tweets, content)
+
+	// Final counts are content
+	tweets[0].Poll.Final = true
+	content, _ = SplitStats(tweets)
+	require.Equal(t, 7, content[0].Poll.Votes())
+}
+
+func TestCommunityMemberCountIsStats(t *testing.T) {
+	first, err := EncodeJSON(testTweets())
+	require.NoError(t, err)
+
+	tweets := testTweets()
+	tweets[0].Community.MemberCount = 4242
+	second, err := EncodeJSON(tweets)
+	require.NoError(t, err)
+	require.Equal(t, string(first), string(second))
+
+	content, stats := SplitStats(tweets)
+	require.Zero(t, content[0].Community.MemberCount)
+	require.Equal(t, 4242, tweets[0].Community.MemberCount)
+	stats.Apply(content)
+	require.Equal(t, 4242, content[0].Community.MemberCount)
+}
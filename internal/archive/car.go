@@ -0,0 +1,270 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/exchange/offline"
+	"github.com/ipfs/boxo/files"
+	"github.com/ipfs/boxo/ipld/merkledag"
+	unixfile "github.com/ipfs/boxo/ipld/unixfs/file"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	carv2 "github.com/ipld/go-car/v2"
+	carstorage "github.com/ipld/go-car/v2/storage"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	cidlink "github.com/ipld/go-ipld-prime/linking/cid"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+)
+
+// Supported CAR versions
+const (
+	CARv1 = 1
+	CARv2 = 2
+)
+
+// WriteCAR writes every block reachable from root, plus the blocks reachable
+// from extra (e.g. media referenced by a legacy JSON archive), to w as a CAR
+// with root as its only root. CARv1 is streamed; CARv2 is staged in a
+// temporary file because its header and index are written last.
+func WriteCAR(ctx context.Context, w io.Writer, bs ipfs.BlockStorage, version int, root cid.Cid, extra ...cid.Cid) error {
+	switch version {
+	case CARv1:
+		car, err := carstorage.NewWritable(w, []cid.Cid{root}, carv2.WriteAsCarV1(true))
+		if err != nil {
+			return fmt.Errorf("create car writer: %w", err)
+		}
+		return walkBlocks(ctx, bs, append([]cid.Cid{root}, extra...), func(block blocks.Block) error {
+			return car.Put(ctx, block.Cid().KeyString(), block.RawData())
+		})
+	case CARv2:
+		tmp, err := os.CreateTemp("", "threadmirror-*.car")
+		if err != nil {
+			return fmt.Errorf("create temp file: %w", err)
+		}
+		defer os.Remove(tmp.Name()) // nolint:errcheck
+		defer tmp.Close()           // nolint:errcheck
+
+		car, err := carstorage.NewWritable(tmp, []cid.Cid{root})
+		if err != nil {
+			return fmt.Errorf("create car writer: %w", err)
+		}
+		err = walkBlocks(ctx, bs, append([]cid.Cid{root}, extra...), func(block blocks.Block) error {
+			return car.Put(ctx, block.Cid().KeyString(), block.RawData())
+		})
+		if err != nil {
+			return err
+		}
+		if err := car.Finalize(); err != nil {
+			return fmt.Errorf("finalize car: %w", err)
+		}
+
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("seek temp file: %w", err)
+		}
+		if _, err := io.Copy(w, tmp); err != nil {
+			return fmt.Errorf("copy car: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported CAR version %d", version)
+	}
+}
+
+// walkBlocks visits every block reachable from roots once, parents first
+func walkBlocks(ctx context.Context, bs ipfs.BlockStorage, roots []cid.Cid, visit func(blocks.Block) error) error {
+	seen := make(map[cid.Cid]struct{})
+	queue := append([]cid.Cid{}, roots...)
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+
+		block, err := bs.GetBlock(ctx, c)
+		if err != nil {
+			return fmt.Errorf("get block %s: %w", c, err)
+		}
+		if err := visit(block); err != nil {
+			return fmt.Errorf("write block %s: %w", c, err)
+		}
+
+		links, err := blockLinks(block)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, links...)
+	}
+	return nil
+}
+
+// blockLinks returns the CIDs a block links to
+func blockLinks(block blocks.Block) ([]cid.Cid, error) {
+	var decode func(datamodel.NodeAssembler, io.Reader) error
+	switch block.Cid().Prefix().Codec {
+	case cid.Raw:
+		return nil, nil
+	case cid.DagProtobuf:
+		node, err := merkledag.DecodeProtobufBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("decode dag-pb block %s: %w", block.Cid(), err)
+		}
+		links := make([]cid.Cid, 0, len(node.Links()))
+		for _, l := range node.Links() {
+			links = append(links, l.Cid)
+		}
+		return links, nil
+	case cid.DagCBOR:
+		decode = dagcbor.Decode
+	case cid.DagJSON:
+		decode = dagjson.Decode
+	default:
+		return nil, fmt.Errorf("unsupported codec 0x%x in block %s", block.Cid().Prefix().Codec, block.Cid())
+	}
+
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := decode(nb, bytes.NewReader(block.RawData())); err != nil {
+		return nil, fmt.Errorf("decode block %s: %w", block.Cid(), err)
+	}
+
+	var links []cid.Cid
+	collectLinks(nb.Build(), &links)
+	return links, nil
+}
+
+func collectLinks(n datamodel.Node, links *[]cid.Cid) {
+	switch n.Kind() {
+	case datamodel.Kind_Link:
+		if l, err := n.AsLink(); err == nil {
+			if cl, ok := l.(cidlink.Link); ok {
+				*links = append(*links, cl.Cid)
+			}
+		}
+	case datamodel.Kind_Map:
+		it := n.MapIterator()
+		for !it.Done() {
+			_, v, err := it.Next()
+			if err != nil {
+				return
+			}
+			collectLinks(v, links)
+		}
+	case datamodel.Kind_List:
+		it := n.ListIterator()
+		for !it.Done() {
+			_, v, err := it.Next()
+			if err != nil {
+				return
+			}
+			collectLinks(v, links)
+		}
+	}
+}
+
+// ReadCAR reads a CARv1 or CARv2 stream into memory, verifying every block,
+// and returns the blocks together with the CAR's single root
+func ReadCAR(r io.Reader) (*MemoryBlocks, cid.Cid, error) {
+	br, err := carv2.NewBlockReader(r)
+	if err != nil {
+		return nil, cid.Undef, fmt.Errorf("open car: %w", err)
+	}
+	if len(br.Roots) != 1 {
+		return nil, cid.Undef, fmt.Errorf("expected exactly one root in car, got %d", len(br.Roots))
+	}
+
+	mem := NewMemoryBlocks()
+	for {
+		block, err := br.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, cid.Undef, fmt.Errorf("read car block: %w", err)
+		}
+		if err := mem.PutBlock(context.Background(), block); err != nil {
+			return nil, cid.Undef, fmt.Errorf("store car block: %w", err)
+		}
+	}
+	return mem, br.Roots[0], nil
+}
+
+// MemoryBlocks is an in-memory ipfs.BlockStorage, used to stage the blocks
+// of an imported CAR
+type MemoryBlocks struct {
+	bstore blockstore.Blockstore
+	// cids keeps the full CIDs in insertion order, the blockstore only keys by multihash
+	cids []cid.Cid
+}
+
+var _ ipfs.BlockStorage = (*MemoryBlocks)(nil)
+
+func NewMemoryBlocks() *MemoryBlocks {
+	return &MemoryBlocks{
+		bstore: blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore())),
+	}
+}
+
+func (m *MemoryBlocks) PutBlock(ctx context.Context, block blocks.Block) error {
+	has, err := m.bstore.Has(ctx, block.Cid())
+	if err != nil {
+		return err
+	}
+	if has {
+		return nil
+	}
+	if err := m.bstore.Put(ctx, block); err != nil {
+		return err
+	}
+	m.cids = append(m.cids, block.Cid())
+	return nil
+}
+
+func (m *MemoryBlocks) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return m.bstore.Get(ctx, c)
+}
+
+// AllBlocks calls fn for every staged block
+func (m *MemoryBlocks) AllBlocks(ctx context.Context, fn func(blocks.Block) error) error {
+	for _, c := range m.cids {
+		block, err := m.bstore.Get(ctx, c)
+		if err != nil {
+			return err
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile opens the UnixFS file (or raw block) rooted at c
+func (m *MemoryBlocks) ReadFile(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	dserv := merkledag.NewDAGService(blockservice.New(m.bstore, offline.Exchange(m.bstore)))
+	node, err := dserv.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("get file root %s: %w", c, err)
+	}
+
+	fnode, err := unixfile.NewUnixfsFile(ctx, dserv, node)
+	if err != nil {
+		return nil, fmt.Errorf("open unixfs file %s: %w", c, err)
+	}
+	f, ok := fnode.(files.File)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a file", c)
+	}
+	return f, nil
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCARRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	for _, version := range []int{CARv1, CARv2} {
+		bs := newMemBlockStorage()
+		mediaCID, err := cid.V1Builder{Codec: cid.Raw, MhType: multihash.SHA2_256}.Sum([]byte("jpeg"))
+		require.NoError(t, err)
+		media, err := blocks.NewBlockWithCid([]byte("jpeg"), mediaCID)
+		require.NoError(t, err)
+		require.NoError(t, bs.PutBlock(ctx, media))
+
+		tweets := testTweets()
+		tweets[0].ArchivedMedia[0].CID = media.Cid().String()
+		tweets[0].Author.ProfileImageCID = media.Cid().String()
+
+		root, err := Encode(ctx, bs, tweets)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteCAR(ctx, &buf, bs, version, root))
+
+		mem, gotRoot, err := ReadCAR(&buf)
+		require.NoError(t, err)
+		require.Equal(t, root, gotRoot)
+		require.Len(t, mem.cids, len(bs.blocks), "CARv%d should contain every reachable block", version)
+
+		got, err := Decode(ctx, mem, gotRoot)
+		require.NoError(t, err)
+		content, _ := SplitStats(tweets)
+		require.Equal(t, content, got)
+
+		f, err := mem.ReadFile(ctx, media.Cid())
+		require.NoError(t, err)
+		data := new(bytes.Buffer)
+		_, err = data.ReadFrom(f)
+		require.NoError(t, err)
+		require.Equal(t, "jpeg", data.String())
+	}
+}
@@ -0,0 +1,463 @@
+// Package archive defines how archived threads are laid out in IPFS.
+//
+// A thread is stored as a small IPLD DAG of DAG-CBOR blocks:
+//
+//	thread root  {version, type, conversation_id, num_tweets, tweets: [Link]}
+//	  └─ tweet   {version, tweet: {...}, author: Link, quoted_tweet: Link, media: [{url, cid: Link, ...}]}
+//	       ├─ user   {version, user: {...}, profile_image: Link}
+//	       └─ media  archived UnixFS files (see service.MediaArchiver)
+//
+// Because every tweet and author is its own block, a changed tweet only
+// produces new blocks for itself and the root, and threads quoting the same
+// tweet share its block. Blocks may also be DAG-JSON encoded; both codecs
+// are accepted when decoding.
+//
+// Since SchemaV2 the archive only holds content: engagement counters are
+// split off (see SplitStats) and timestamps are in UTC, so scraping an
+// unchanged thread again yields the same CID. DAG-CBOR sorts map keys, and
+// backends without raw blocks get the same content as a canonical JSON
+// document (see EncodeJSON).
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/codec/dagjson"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+	"github.com/multiformats/go-multihash"
+)
+
+// Root node types
+const (
+	// ThreadType identifies a thread root node
+	ThreadType = "threadmirror/thread"
+	// ConversationType identifies the root node of a conversation
+	ConversationType = "threadmirror/conversation"
+)
+
+// blockPrefix is used to compute the CID of every block written by Encode
+var blockPrefix = cid.Prefix{
+	Version:  1,
+	Codec:    cid.DagCBOR,
+	MhType:   multihash.SHA2_256,
+	MhLength: -1,
+}
+
+// Link is a CID that serialises as a DAG-JSON link ({"/": "<cid>"})
+type Link struct {
+	cid.Cid
+}
+
+func (l Link) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"/": l.String()})
+}
+
+func (l *Link) UnmarshalJSON(data []byte) error {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid link: %w", err)
+	}
+	c, err := cid.Parse(raw["/"])
+	if err != nil {
+		return fmt.Errorf("invalid link cid: %w", err)
+	}
+	l.Cid = c
+	return nil
+}
+
+// ThreadNode is the root block of an archived thread
+type ThreadNode struct {
+	// Version is the schema version the DAG was written with
+	Version        int    `json:"version"`
+	Type           string `json:"type"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	NumTweets      int    `json:"num_tweets"`
+	Tweets         []Link `json:"tweets"`
+}
+
+// TweetNode holds a single tweet; its author, quoted tweet and media are links
+type TweetNode struct {
+	Version     int         `json:"version"`
+	Tweet       tweetV3     `json:"tweet"`
+	Author      *Link       `json:"author,omitempty"`
+	QuotedTweet *Link       `json:"quoted_tweet,omitempty"`
+	Media       []MediaNode `json:"media,omitempty"`
+	// Parent links to the tweet replied to, in conversations only
+	Parent *Link `json:"parent,omitempty"`
+}
+
+// MediaNode links to an archived media file
+type MediaNode struct {
+	URL         string `json:"url"`
+	CID         Link   `json:"cid"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+}
+
+// UserNode holds a tweet author
+type UserNode struct {
+	Version      int    `json:"version"`
+	User         userV1 `json:"user"`
+	ProfileImage *Link  `json:"profile_image,omitempty"`
+}
+
+// IsDAG reports whether c points to a DAG-encoded thread root rather than a
+// legacy JSON blob (which is stored as a UnixFS file)
+func IsDAG(c cid.Cid) bool {
+	codec := c.Prefix().Codec
+	return codec == cid.DagCBOR || codec == cid.DagJSON
+}
+
+// Encode writes the content of tweets as a thread DAG to bs and returns the
+// root CID. Engagement counters are left out, see SplitStats. The blocks are
+// written together if bs is an ipfs.DAGStorage.
+func Encode(ctx context.Context, bs ipfs.BlockStorage, tweets []*xscraper.Tweet) (cid.Cid, error) {
+	return encode(ctx, bs, ThreadType, tweets)
+}
+
+// EncodeConversation writes the content of a conversation as a DAG to bs and
+// returns the root CID. tweets start with the root, and every reply must come
+// after the tweet it replies to for its node to link to it.
+func EncodeConversation(ctx context.Context, bs ipfs.BlockStorage, tweets []*xscraper.Tweet) (cid.Cid, error) {
+	return encode(ctx, bs, ConversationType, tweets)
+}
+
+func encode(ctx context.Context, bs ipfs.BlockStorage, typ string, tweets []*xscraper.Tweet) (cid.Cid, error) {
+	e := &encoder{bs: bs, written: make(map[cid.Cid]struct{})}
+	dag, batch := bs.(ipfs.DAGStorage)
+	if batch {
+		e.pending = []blocks.Block{}
+	}
+	tweets, _ = SplitStats(tweets)
+
+	root := ThreadNode{
+		Version:   SchemaVersion,
+		Type:      typ,
+		NumTweets: len(tweets),
+		Tweets:    make([]Link, 0, len(tweets)),
+	}
+	if len(tweets) > 0 && tweets[0] != nil {
+		root.ConversationID = tweets[0].ConversationID
+	}
+	nodes := make(map[string]cid.Cid, len(tweets))
+	for _, tweet := range tweets {
+		var parent *Link
+		if typ == ConversationType && tweet != nil {
+			if c, ok := nodes[tweet.InReplyToStatusID]; ok {
+				parent = &Link{c}
+			}
+		}
+		c, err := e.putTweet(ctx, tweet, parent)
+		if err != nil {
+			return cid.Undef, err
+		}
+		nodes[tweet.RestID] = c
+		root.Tweets = append(root.Tweets, Link{c})
+	}
+
+	c, err := e.put(ctx, root)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("put thread root: %w", err)
+	}
+	if batch {
+		if err := dag.PutDAG(ctx, c, e.pending); err != nil {
+			return cid.Undef, fmt.Errorf("put thread DAG: %w", err)
+		}
+	}
+	return c, nil
+}
+
+type encoder struct {
+	bs      ipfs.BlockStorage
+	written map[cid.Cid]struct{}
+	// pending collects the blocks instead of writing them when non-nil
+	pending []blocks.Block
+}
+
+func (e *encoder) putTweet(ctx context.Context, tweet *xscraper.Tweet, parent *Link) (cid.Cid, error) {
+	if tweet == nil {
+		return cid.Undef, fmt.Errorf("nil tweet")
+	}
+
+	body := *tweet
+	body.Author = nil
+	body.QuotedTweet = nil
+	body.ArchivedMedia = nil
+	archived, err := newTweetV3(&body)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("tweet %s: %w", tweet.RestID, err)
+	}
+	node := TweetNode{Version: SchemaVersion, Tweet: *archived, Parent: parent}
+
+	if tweet.Author != nil {
+		c, err := e.putUser(ctx, tweet.Author)
+		if err != nil {
+			return cid.Undef, err
+		}
+		node.Author = &Link{c}
+	}
+
+	if tweet.QuotedTweet != nil {
+		c, err := e.putTweet(ctx, tweet.QuotedTweet, nil)
+		if err != nil {
+			return cid.Undef, err
+		}
+		node.QuotedTweet = &Link{c}
+	}
+
+	for _, m := range tweet.ArchivedMedia {
+		c, err := cid.Parse(m.CID)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("invalid media CID %q: %w", m.CID, err)
+		}
+		node.Media = append(node.Media, MediaNode{
+			URL:         m.URL,
+			CID:         Link{c},
+			ContentType: m.ContentType,
+			Size:        m.Size,
+		})
+	}
+
+	c, err := e.put(ctx, node)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("put tweet %s: %w", tweet.RestID, err)
+	}
+	return c, nil
+}
+
+func (e *encoder) putUser(ctx context.Context, user *xscraper.User) (cid.Cid, error) {
+	body := *user
+	body.ProfileImageCID = ""
+	node := UserNode{Version: SchemaVersion, User: *newUserV1(&body)}
+
+	if user.ProfileImageCID != "" {
+		c, err := cid.Parse(user.ProfileImageCID)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("invalid profile image CID %q: %w", user.ProfileImageCID, err)
+		}
+		node.ProfileImage = &Link{c}
+	}
+
+	c, err := e.put(ctx, node)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("put user %s: %w", user.RestID, err)
+	}
+	return c, nil
+}
+
+// put encodes v as a DAG-CBOR block and stores it unless it was already
+// written during this run
+func (e *encoder) put(ctx context.Context, v any) (cid.Cid, error) {
+	block, err := EncodeBlock(v)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if _, ok := e.written[block.Cid()]; ok {
+		return block.Cid(), nil
+	}
+	if e.pending != nil {
+		e.pending = append(e.pending, block)
+	} else if err := e.bs.PutBlock(ctx, block); err != nil {
+		return cid.Undef, err
+	}
+	e.written[block.Cid()] = struct{}{}
+	return block.Cid(), nil
+}
+
+// EncodeBlock encodes v as a DAG-CBOR block. v is first marshalled with
+// encoding/json, so Link fields become IPLD links.
+func EncodeBlock(v any) (blocks.Block, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal node: %w", err)
+	}
+
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := dagjson.Decode(nb, bytes.NewReader(js)); err != nil {
+		return nil, fmt.Errorf("decode node: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := dagcbor.Encode(nb.Build(), &buf); err != nil {
+		return nil, fmt.Errorf("encode dag-cbor: %w", err)
+	}
+
+	c, err := blockPrefix.Sum(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("compute cid: %w", err)
+	}
+	return blocks.NewBlockWithCid(buf.Bytes(), c)
+}
+
+// Decode reads the thread DAG rooted at root back into tweets
+func Decode(ctx context.Context, bs ipfs.BlockStorage, root cid.Cid) ([]*xscraper.Tweet, error) {
+	node, err := getThreadNode(ctx, bs, root)
+	if err != nil {
+		return nil, err
+	}
+	return decodeThreadNode(ctx, bs, node)
+}
+
+// DAGSchemaVersion returns the schema version of the thread DAG rooted at root
+func DAGSchemaVersion(ctx context.Context, bs ipfs.BlockStorage, root cid.Cid) (int, error) {
+	node, err := getThreadNode(ctx, bs, root)
+	if err != nil {
+		return 0, err
+	}
+	return node.Version, nil
+}
+
+// DAGType returns the type of the DAG rooted at root, ThreadType or
+// ConversationType
+func DAGType(ctx context.Context, bs ipfs.BlockStorage, root cid.Cid) (string, error) {
+	node, err := getThreadNode(ctx, bs, root)
+	if err != nil {
+		return "", err
+	}
+	return node.Type, nil
+}
+
+func getThreadNode(ctx context.Context, bs ipfs.BlockStorage, root cid.Cid) (*ThreadNode, error) {
+	var node ThreadNode
+	if err := getNode(ctx, bs, root, &node); err != nil {
+		return nil, fmt.Errorf("get thread root: %w", err)
+	}
+	if !isRootType(node.Type) {
+		return nil, fmt.Errorf("not a thread root: type %q", node.Type)
+	}
+	if err := checkSchemaVersion(node.Version); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+func isRootType(typ string) bool {
+	return typ == ThreadType || typ == ConversationType
+}
+
+// decodeThreadNode decodes a DAG of any supported schema. Schemas 1 to 3 share
+// the node layout, and their tweets all read as tweetV3.
+func decodeThreadNode(ctx context.Context, bs ipfs.BlockStorage, node *ThreadNode) ([]*xscraper.Tweet, error) {
+	d := &decoder{bs: bs, users: make(map[cid.Cid]*xscraper.User)}
+	tweets := make([]*xscraper.Tweet, 0, len(node.Tweets))
+	for _, link := range node.Tweets {
+		tweet, err := d.getTweet(ctx, link.Cid)
+		if err != nil {
+			return nil, err
+		}
+		tweets = append(tweets, tweet)
+	}
+	return tweets, nil
+}
+
+type decoder struct {
+	bs    ipfs.BlockStorage
+	users map[cid.Cid]*xscraper.User
+}
+
+func (d *decoder) getTweet(ctx context.Context, c cid.Cid) (*xscraper.Tweet, error) {
+	var node TweetNode
+	if err := getNode(ctx, d.bs, c, &node); err != nil {
+		return nil, fmt.Errorf("get tweet %s: %w", c, err)
+	}
+
+	tweet, err := node.Tweet.tweet()
+	if err != nil {
+		return nil, err
+	}
+	if node.Author != nil {
+		author, err := d.getUser(ctx, node.Author.Cid)
+		if err != nil {
+			return nil, err
+		}
+		tweet.Author = author
+	}
+
+	if node.QuotedTweet != nil {
+		quoted, err := d.getTweet(ctx, node.QuotedTweet.Cid)
+		if err != nil {
+			return nil, err
+		}
+		tweet.QuotedTweet = quoted
+	}
+
+	for _, m := range node.Media {
+		tweet.ArchivedMedia = append(tweet.ArchivedMedia, xscraper.ArchivedMedia{
+			URL:         m.URL,
+			CID:         m.CID.String(),
+			ContentType: m.ContentType,
+			Size:        m.Size,
+		})
+	}
+	return tweet, nil
+}
+
+func (d *decoder) getUser(ctx context.Context, c cid.Cid) (*xscraper.User, error) {
+	if user, ok := d.users[c]; ok {
+		return user, nil
+	}
+
+	var node UserNode
+	if err := getNode(ctx, d.bs, c, &node); err != nil {
+		return nil, fmt.Errorf("get user %s: %w", c, err)
+	}
+
+	user := node.User.user()
+	if node.ProfileImage != nil {
+		user.ProfileImageCID = node.ProfileImage.String()
+	}
+	d.users[c] = user
+	return user, nil
+}
+
+// getNode fetches a DAG-CBOR or DAG-JSON block, verifies it against its CID
+// and unmarshals it into v
+func getNode(ctx context.Context, bs ipfs.BlockStorage, c cid.Cid, v any) error {
+	block, err := bs.GetBlock(ctx, c)
+	if err != nil {
+		return err
+	}
+	return DecodeBlock(block, v)
+}
+
+// DecodeBlock verifies block against its CID and unmarshals it into v
+func DecodeBlock(block blocks.Block, v any) error {
+	c := block.Cid()
+	sum, err := c.Prefix().Sum(block.RawData())
+	if err != nil {
+		return fmt.Errorf("compute cid: %w", err)
+	}
+	if !sum.Equals(c) {
+		return fmt.Errorf("block data does not match cid %s", c)
+	}
+
+	nb := basicnode.Prototype.Any.NewBuilder()
+	switch c.Prefix().Codec {
+	case cid.DagCBOR:
+		err = dagcbor.Decode(nb, bytes.NewReader(block.RawData()))
+	case cid.DagJSON:
+		err = dagjson.Decode(nb, bytes.NewReader(block.RawData()))
+	default:
+		return fmt.Errorf("unsupported codec 0x%x", c.Prefix().Codec)
+	}
+	if err != nil {
+		return fmt.Errorf("decode block %s: %w", c, err)
+	}
+
+	var buf bytes.Buffer
+	if err := dagjson.Encode(nb.Build(), &buf); err != nil {
+		return fmt.Errorf("encode dag-json: %w", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), v); err != nil {
+		return fmt.Errorf("unmarshal node: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+)
+
+// memBlockStorage is an in-memory ipfs.BlockStorage
+type memBlockStorage struct {
+	blocks map[cid.Cid]blocks.Block
+	puts   int
+}
+
+func newMemBlockStorage() *memBlockStorage {
+	return &memBlockStorage{blocks: make(map[cid.Cid]blocks.Block)}
+}
+
+func (m *memBlockStorage) PutBlock(_ context.Context, block blocks.Block) error {
+	m.blocks[block.Cid()] = block
+	m.puts++
+	return nil
+}
+
+func (m *memBlockStorage) GetBlock(_ context.Context, c cid.Cid) (blocks.Block, error) {
+	block, ok := m.blocks[c]
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", c)
+	}
+	return block, nil
+}
+
+func testTweets() []*xscraper.Tweet {
+	author := &xscraper.User{
+		ID:              "VXNlcjox",
+		RestID:          "1",
+		Name:            "Alice",
+		ScreenName:      "alice",
+		ProfileImageURL: "https://pbs.twimg.com/profile_images/1/a.jpg",
+		ProfileImageCID: "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+		CreatedAt:       time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	quoted := &xscraper.Tweet{
+		ID:        "100",
+		RestID:    "100",
+		Text:      "quoted",
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Author:    author,
+		Card: &xscraper.LinkCard{
+			Name:     "summary_large_image",
+			URL:      "https://example.com/post",
+			Title:    "A post",
+			Domain:   "example.com",
+			ImageURL: "https://pbs.twimg.com/card_img/1/a.jpg",
+		},
+	}
+	return []*xscraper.Tweet{
+		{
+			ID:             "200",
+			RestID:         "200",
+			Text:           "first",
+			CreatedAt:      time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			Author:         author,
+			ConversationID: "200",
+			Stats:          xscraper.TweetStats{FavoriteCount: 3},
+			ArchivedMedia: []xscraper.ArchivedMedia{{
+				URL:         "https://pbs.twimg.com/media/x.jpg",
+				CID:         "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku",
+				ContentType: "image/jpeg",
+				Size:        4,
+			}},
+			Poll: &xscraper.Poll{
+				Choices: []xscraper.PollChoice{{Label: "yes", Count: 5}, {Label: "no", Count: 2}},
+				EndsAt:  time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+			},
+			Community: &xscraper.Community{ID: "300", Name: "Archivists", MemberCount: 42},
+		},
+		{
+			ID:             "201",
+			RestID:         "201",
+			Text:           "second",
+			CreatedAt:      time.Date(2024, 2, 1, 0, 1, 0, 0, time.UTC),
+			Author:         author,
+			ConversationID: "200",
+			QuotedTweet:    quoted,
+			IsQuoteStatus:  true,
+			Space:          &xscraper.Space{ID: "1ZkJzbdvLgyJv", URL: "https://x.com/i/spaces/1ZkJzbdvLgyJv"},
+		},
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+	tweets := testTweets()
+
+	root, err := Encode(ctx, bs, tweets)
+	require.NoError(t, err)
+	require.True(t, IsDAG(root))
+	require.Equal(t, uint64(cid.DagCBOR), root.Prefix().Codec)
+
+	// root + 3 tweets + 1 shared author
+	require.Len(t, bs.blocks, 5)
+
+	// Engagement counters are not archived
+	decoded, err := Decode(ctx, bs, root)
+	require.NoError(t, err)
+	content, stats := SplitStats(tweets)
+	require.Equal(t, content, decoded)
+
+	stats.Apply(decoded)
+	require.Equal(t, tweets, decoded)
+}
+
+// memDAGStorage is a memBlockStorage recording the DAGs written with PutDAG
+type memDAGStorage struct {
+	*memBlockStorage
+	roots []cid.Cid
+}
+
+func (m *memDAGStorage) PutDAG(ctx context.Context, root cid.Cid, dag []blocks.Block) error {
+	m.roots = append(m.roots, root)
+	for _, block := range dag {
+		if err := m.PutBlock(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestEncodePutsDAGTogether(t *testing.T) {
+	ctx := context.Background()
+	bs := &memDAGStorage{memBlockStorage: newMemBlockStorage()}
+
+	root, err := Encode(ctx, bs, testTweets())
+	require.NoError(t, err)
+	require.Equal(t, []cid.Cid{root}, bs.roots)
+	require.Len(t, bs.blocks, 5)
+
+	_, err = Decode(ctx, bs, root)
+	require.NoError(t, err)
+}
+
+func TestEncodeIsDeterministic(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+
+	first, err := Encode(ctx, bs, testTweets())
+	require.NoError(t, err)
+
+	// New engagement counters do not change the archive
+	tweets := testTweets()
+	tweets[1].Stats.FavoriteCount = 42
+	tweets[1].Views = 1000
+	tweets[1].Author.FollowersCount = 7
+	same, err := Encode(ctx, bs, tweets)
+	require.NoError(t, err)
+	require.Equal(t, first, same)
+	require.Len(t, bs.blocks, 5)
+
+	// Changing one tweet only adds new blocks for that tweet and the root
+	tweets = testTweets()
+	tweets[1].Text = "second, edited"
+	second, err := Encode(ctx, bs, tweets)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+	require.Len(t, bs.blocks, 7)
+
+	again, err := Encode(ctx, bs, testTweets())
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+}
+
+func TestDecodeRejectsTamperedBlock(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+
+	root, err := Encode(ctx, bs, testTweets())
+	require.NoError(t, err)
+
+	data := bs.blocks[root].RawData()
+	bs.blocks[root] = &tamperedBlock{Block: blocks.NewBlock(data[:len(data)-1]), cid: root}
+
+	_, err = Decode(ctx, bs, root)
+	require.ErrorContains(t, err, "does not match cid")
+}
+
+func TestIsDAG(t *testing.T) {
+	legacy, err := cid.Parse("QmPZ9gcCEpqKTo6aq61g2nXGUhM4iCL3ewB6LDXZCtioEB")
+	require.NoError(t, err)
+	require.False(t, IsDAG(legacy))
+}
+
+type tamperedBlock struct {
+	blocks.Block
+	cid cid.Cid
+}
+
+func (b *tamperedBlock) Cid() cid.Cid { return b.cid }
+
+func TestVerifyDAG(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+
+	root, err := Encode(ctx, bs, testTweets())
+	require.NoError(t, err)
+
+	// The shared media file and avatar are returned once, for content checks
+	files, err := VerifyDAG(ctx, bs, root)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku", files[0].String())
+
+	var node ThreadNode
+	require.NoError(t, DecodeBlock(bs.blocks[root], &node))
+	tweet := node.Tweets[0].Cid
+	data := bs.blocks[tweet].RawData()
+	bs.blocks[tweet] = &tamperedBlock{Block: blocks.NewBlock(data[:len(data)-1]), cid: tweet}
+
+	_, err = VerifyDAG(ctx, bs, root)
+	var verifyErr *ipfs.VerifyError
+	require.ErrorAs(t, err, &verifyErr)
+	require.Equal(t, tweet, verifyErr.CID)
+	require.ErrorIs(t, err, ipfs.ErrCIDMismatch)
+
+	delete(bs.blocks, tweet)
+	_, err = VerifyDAG(ctx, bs, root)
+	require.ErrorIs(t, err, ipfs.ErrContentUnreachable)
+}
+
+func TestEncodeConversation(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+
+	tweets := testTweets()[:1]
+	tweets = append(tweets,
+		&xscraper.Tweet{ID: "300", RestID: "300", Text: "reply", ConversationID: "200", IsReply: true, InReplyToStatusID: "200"},
+		&xscraper.Tweet{ID: "301", RestID: "301", Text: "nested", ConversationID: "200", IsReply: true, InReplyToStatusID: "300"},
+	)
+
+	root, err := EncodeConversation(ctx, bs, tweets)
+	require.NoError(t, err)
+	typ, err := DAGType(ctx, bs, root)
+	require.NoError(t, err)
+	require.Equal(t, ConversationType, typ)
+
+	// Every reply links to the node of the tweet it replies to
+	var node ThreadNode
+	require.NoError(t, DecodeBlock(bs.blocks[root], &node))
+	require.Len(t, node.Tweets, 3)
+	for i, parent := range []*Link{nil, &node.Tweets[0], &node.Tweets[1]} {
+		var tweet TweetNode
+		require.NoError(t, DecodeBlock(bs.blocks[node.Tweets[i].Cid], &tweet))
+		require.Equal(t, parent, tweet.Parent)
+	}
+
+	decoded, err := Decode(ctx, bs, root)
+	require.NoError(t, err)
+	content, _ := SplitStats(tweets)
+	require.Equal(t, content, decoded)
+
+	// A thread of the same tweets is a different archive
+	thread, err := Encode(ctx, bs, tweets)
+	require.NoError(t, err)
+	require.NotEqual(t, root, thread)
+}
@@ -0,0 +1,345 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+)
+
+// Archive schema versions. Every archive records the version it was written
+// with, and tweets are read through frozen copies of xscraper.Tweet (tweetV1,
+// tweetV3), so changes to xscraper.Tweet do not change how existing archives
+// read. Writing a new shape means adding a new version and archived type
+// rather than editing an old one; `threadmirror archive migrate` rewrites old
+// archives into the newest version.
+const (
+	// SchemaV1 is a bare JSON array of tweets, or a DAG. Tweets include their
+	// engagement counters.
+	SchemaV1 = 1
+	// SchemaV2 keeps engagement counters out of the content and normalises
+	// timestamps to UTC (see SplitStats). Conversations (ConversationType)
+	// were added to it; they were never written with SchemaV1.
+	SchemaV2 = 2
+	// SchemaV3 adds polls, link cards, Spaces and communities to tweets
+	// (tweetV3). The vote counts of polls that are not final are engagement
+	// counters and kept out of the content too.
+	SchemaV3 = 3
+
+	// SchemaVersion is the version written by Encode and EncodeJSON
+	SchemaVersion = SchemaV3
+)
+
+// checkSchemaVersion rejects versions this build cannot decode
+func checkSchemaVersion(v int) error {
+	if v < SchemaV1 || v > SchemaVersion {
+		return fmt.Errorf("unsupported archive schema version %d", v)
+	}
+	return nil
+}
+
+// tweetV1 is a tweet as archived by schemas 1 and 2. It is a frozen copy of
+// xscraper.Tweet at the time; entities and rich text are X's own GraphQL shapes
+// and are decoded with the generated types.
+type tweetV1 struct {
+	ID                string          `json:"id"`
+	RestID            string          `json:"rest_id"`
+	Text              string          `json:"text"`
+	CreatedAt         time.Time       `json:"created_at"`
+	Author            *userV1         `json:"author,omitempty"`
+	Entities          json.RawMessage `json:"entities"`
+	Stats             tweetStatsV1    `json:"stats"`
+	IsRetweet         bool            `json:"is_retweet"`
+	IsReply           bool            `json:"is_reply"`
+	IsQuoteStatus     bool            `json:"is_quote_status"`
+	ConversationID    string          `json:"conversation_id"`
+	InReplyToStatusID string          `json:"in_reply_to_status_id,omitempty"`
+	InReplyToUserID   string          `json:"in_reply_to_user_id,omitempty"`
+	QuotedTweet       *tweetV1        `json:"quoted_tweet,omitempty"`
+	HasBirdwatchNotes bool            `json:"has_birdwatch_notes"`
+	Lang              string          `json:"lang"`
+	Source            string          `json:"source,omitempty"`
+	PossiblySensitive bool            `json:"possibly_sensitive"`
+	IsTranslatable    bool            `json:"is_translatable"`
+	Views             int             `json:"views,omitempty"`
+	IsNoteTweet       bool            `json:"is_note_tweet"`
+	RichText          json.RawMessage `json:"richtext,omitempty"`
+	DisplayTextRange  []int           `json:"display_text_range,omitempty"`
+	ArchivedMedia     []mediaV1       `json:"archived_media,omitempty"`
+}
+
+type tweetStatsV1 struct {
+	ReplyCount    int `json:"reply_count"`
+	RetweetCount  int `json:"retweet_count"`
+	FavoriteCount int `json:"favorite_count"`
+	QuoteCount    int `json:"quote_count"`
+	BookmarkCount int `json:"bookmark_count"`
+	ViewCount     int `json:"view_count,omitempty"`
+}
+
+type mediaV1 struct {
+	URL         string `json:"url"`
+	CID         string `json:"cid"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+}
+
+// userV1 is a user as archived by schemas 1 and 2
+type userV1 struct {
+	ID              string    `json:"id"`
+	RestID          string    `json:"rest_id"`
+	Name            string    `json:"name"`
+	ScreenName      string    `json:"screen_name"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	Description     string    `json:"description"`
+	FollowersCount  int       `json:"followers_count"`
+	FriendsCount    int       `json:"friends_count"`
+	StatusesCount   int       `json:"statuses_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	Verified        bool      `json:"verified"`
+	IsBlueVerified  bool      `json:"is_blue_verified"`
+	ProfileImageCID string    `json:"profile_image_cid,omitempty"`
+}
+
+// newTweetV1 converts a tweet to its archived shape
+func newTweetV1(t *xscraper.Tweet) (*tweetV1, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	entities, err := json.Marshal(t.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("marshal entities: %w", err)
+	}
+	var richText json.RawMessage
+	if t.RichText != nil {
+		if richText, err = json.Marshal(t.RichText); err != nil {
+			return nil, fmt.Errorf("marshal rich text: %w", err)
+		}
+	}
+	quoted, err := newTweetV1(t.QuotedTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &tweetV1{
+		ID:                t.ID,
+		RestID:            t.RestID,
+		Text:              t.Text,
+		CreatedAt:         t.CreatedAt,
+		Author:            newUserV1(t.Author),
+		Entities:          entities,
+		Stats:             tweetStatsV1(t.Stats),
+		IsRetweet:         t.IsRetweet,
+		IsReply:           t.IsReply,
+		IsQuoteStatus:     t.IsQuoteStatus,
+		ConversationID:    t.ConversationID,
+		InReplyToStatusID: t.InReplyToStatusID,
+		InReplyToUserID:   t.InReplyToUserID,
+		QuotedTweet:       quoted,
+		HasBirdwatchNotes: t.HasBirdwatchNotes,
+		Lang:              t.Lang,
+		Source:            t.Source,
+		PossiblySensitive: t.PossiblySensitive,
+		IsTranslatable:    t.IsTranslatable,
+		Views:             t.Views,
+		IsNoteTweet:       t.IsNoteTweet,
+		RichText:          richText,
+		DisplayTextRange:  t.DisplayTextRange,
+	}
+	for _, m := range t.ArchivedMedia {
+		v.ArchivedMedia = append(v.ArchivedMedia, mediaV1(m))
+	}
+	return v, nil
+}
+
+// tweetV3 is a tweet as archived by schema 3: tweetV1 with the polls, link
+// cards, Spaces and communities of xscraper.Tweet. Tweets of schemas 1 and 2
+// read as a tweetV3 without them.
+type tweetV3 struct {
+	tweetV1
+	QuotedTweet *tweetV3     `json:"quoted_tweet,omitempty"`
+	Poll        *pollV3      `json:"poll,omitempty"`
+	Card        *cardV3      `json:"card,omitempty"`
+	Space       *spaceV3     `json:"space,omitempty"`
+	Community   *communityV3 `json:"community,omitempty"`
+}
+
+type pollV3 struct {
+	Choices         []pollChoiceV3 `json:"choices"`
+	EndsAt          time.Time      `json:"ends_at"`
+	DurationMinutes int            `json:"duration_minutes,omitempty"`
+	Final           bool           `json:"final"`
+}
+
+type pollChoiceV3 struct {
+	Label    string `json:"label"`
+	Count    int    `json:"count"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type cardV3 struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+type spaceV3 struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+type communityV3 struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MemberCount int    `json:"member_count,omitempty"`
+	URL         string `json:"url,omitempty"`
+	AuthorRole  string `json:"author_role,omitempty"`
+}
+
+// newTweetV3 converts a tweet to its archived shape
+func newTweetV3(t *xscraper.Tweet) (*tweetV3, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	body := *t
+	body.QuotedTweet = nil
+	base, err := newTweetV1(&body)
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := newTweetV3(t.QuotedTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &tweetV3{tweetV1: *base, QuotedTweet: quoted}
+	if p := t.Poll; p != nil {
+		v.Poll = &pollV3{EndsAt: p.EndsAt, DurationMinutes: p.DurationMinutes, Final: p.Final}
+		for _, c := range p.Choices {
+			v.Poll.Choices = append(v.Poll.Choices, pollChoiceV3(c))
+		}
+	}
+	if t.Card != nil {
+		v.Card = (*cardV3)(t.Card)
+	}
+	if t.Space != nil {
+		v.Space = (*spaceV3)(t.Space)
+	}
+	if t.Community != nil {
+		v.Community = (*communityV3)(t.Community)
+	}
+	return v, nil
+}
+
+// tweet converts an archived tweet back to an xscraper.Tweet
+func (v *tweetV3) tweet() (*xscraper.Tweet, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	t, err := v.tweetV1.tweet()
+	if err != nil {
+		return nil, err
+	}
+	if t.QuotedTweet, err = v.QuotedTweet.tweet(); err != nil {
+		return nil, err
+	}
+	if p := v.Poll; p != nil {
+		t.Poll = &xscraper.Poll{EndsAt: p.EndsAt, DurationMinutes: p.DurationMinutes, Final: p.Final}
+		for _, c := range p.Choices {
+			t.Poll.Choices = append(t.Poll.Choices, xscraper.PollChoice(c))
+		}
+	}
+	if v.Card != nil {
+		card := xscraper.LinkCard(*v.Card)
+		t.Card = &card
+	}
+	if v.Space != nil {
+		space := xscraper.Space(*v.Space)
+		t.Space = &space
+	}
+	if v.Community != nil {
+		community := xscraper.Community(*v.Community)
+		t.Community = &community
+	}
+	return t, nil
+}
+
+func newUserV1(u *xscraper.User) *userV1 {
+	if u == nil {
+		return nil
+	}
+	v := userV1(*u)
+	return &v
+}
+
+// tweet converts an archived tweet back to an xscraper.Tweet
+func (v *tweetV1) tweet() (*xscraper.Tweet, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	var entities generated.Entities
+	if len(v.Entities) > 0 {
+		if err := json.Unmarshal(v.Entities, &entities); err != nil {
+			return nil, fmt.Errorf("unmarshal entities of tweet %s: %w", v.RestID, err)
+		}
+	}
+	var richText *generated.NoteTweetResultRichText
+	if len(v.RichText) > 0 && string(v.RichText) != "null" {
+		richText = new(generated.NoteTweetResultRichText)
+		if err := json.Unmarshal(v.RichText, richText); err != nil {
+			return nil, fmt.Errorf("unmarshal rich text of tweet %s: %w", v.RestID, err)
+		}
+	}
+	quoted, err := v.QuotedTweet.tweet()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &xscraper.Tweet{
+		ID:                v.ID,
+		RestID:            v.RestID,
+		Text:              v.Text,
+		CreatedAt:         v.CreatedAt,
+		Author:            v.Author.user(),
+		Entities:          entities,
+		Stats:             xscraper.TweetStats(v.Stats),
+		IsRetweet:         v.IsRetweet,
+		IsReply:           v.IsReply,
+		IsQuoteStatus:     v.IsQuoteStatus,
+		ConversationID:    v.ConversationID,
+		InReplyToStatusID: v.InReplyToStatusID,
+		InReplyToUserID:   v.InReplyToUserID,
+		QuotedTweet:       quoted,
+		HasBirdwatchNotes: v.HasBirdwatchNotes,
+		Lang:              v.Lang,
+		Source:            v.Source,
+		PossiblySensitive: v.PossiblySensitive,
+		IsTranslatable:    v.IsTranslatable,
+		Views:             v.Views,
+		IsNoteTweet:       v.IsNoteTweet,
+		RichText:          richText,
+		DisplayTextRange:  v.DisplayTextRange,
+	}
+	for _, m := range v.ArchivedMedia {
+		t.ArchivedMedia = append(t.ArchivedMedia, xscraper.ArchivedMedia(m))
+	}
+	return t, nil
+}
+
+func (v *userV1) user() *xscraper.User {
+	if v == nil {
+		return nil
+	}
+	u := xscraper.User(*v)
+	return &u
+}
@@ -0,0 +1,87 @@
+package archive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaVersion(t *testing.T) {
+	current, err := EncodeJSON(testTweets())
+	require.NoError(t, err)
+	require.Contains(t, string(current), `"version":3`)
+
+	for data, want := range map[string]int{
+		string(current):     SchemaVersion,
+		`[{"rest_id":"1"}]`: SchemaV1,
+		`{"version":2,"type":"threadmirror/thread","tweets":[]}`: SchemaV2,
+	} {
+		got, err := JSONSchemaVersion([]byte(data))
+		require.NoError(t, err)
+		require.Equal(t, want, got, data)
+	}
+
+	_, err = JSONSchemaVersion([]byte(`{"version":4,"type":"threadmirror/thread","tweets":[]}`))
+	require.ErrorContains(t, err, "unsupported archive schema version 4")
+}
+
+func TestDecodeDAGSchemaV1(t *testing.T) {
+	ctx := context.Background()
+	bs := newMemBlockStorage()
+
+	// A schema 1 DAG: tweets carry their counters
+	tweet, err := EncodeBlock(map[string]any{
+		"version": 1,
+		"tweet": map[string]any{
+			"id":         "300",
+			"rest_id":    "300",
+			"text":       "old",
+			"created_at": "2023-05-01T10:00:00Z",
+			"entities":   map[string]any{},
+			"stats":      map[string]any{"favorite_count": 5},
+			"views":      12,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, bs.PutBlock(ctx, tweet))
+	root, err := EncodeBlock(map[string]any{
+		"version":    1,
+		"type":       ThreadType,
+		"num_tweets": 1,
+		"tweets":     []any{map[string]string{"/": tweet.Cid().String()}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, bs.PutBlock(ctx, root))
+
+	version, err := DAGSchemaVersion(ctx, bs, root.Cid())
+	require.NoError(t, err)
+	require.Equal(t, SchemaV1, version)
+
+	tweets, err := Decode(ctx, bs, root.Cid())
+	require.NoError(t, err)
+	require.Len(t, tweets, 1)
+	require.Equal(t, "old", tweets[0].Text)
+	require.Equal(t, 5, tweets[0].Stats.FavoriteCount)
+	require.Equal(t, 12, tweets[0].Views)
+
+	// Rewriting it yields a current archive of the same content
+	migrated, err := Encode(ctx, bs, tweets)
+	require.NoError(t, err)
+	version, err = DAGSchemaVersion(ctx, bs, migrated)
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion, version)
+	decoded, err := Decode(ctx, bs, migrated)
+	require.NoError(t, err)
+	require.Equal(t, "old", decoded[0].Text)
+	require.Zero(t, decoded[0].Stats.FavoriteCount)
+}
+
+func TestEncodeJSONWithStats(t *testing.T) {
+	data, err := EncodeJSONWithStats(testTweets())
+	require.NoError(t, err)
+
+	tweets, err := DecodeJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, tweets[0].Stats.FavoriteCount)
+}
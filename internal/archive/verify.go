@@ -0,0 +1,48 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs/go-cid"
+)
+
+// VerifyDAG checks that every block of the thread DAG at root still hashes to
+// its CID and returns the CIDs of the files the DAG links to (archived media),
+// which are not part of the DAG and have to be checked with ipfs.VerifyContent.
+// Failures are returned as a *ipfs.VerifyError.
+func VerifyDAG(ctx context.Context, bs ipfs.BlockStorage, root cid.Cid) ([]cid.Cid, error) {
+	var files []cid.Cid
+	seen := make(map[cid.Cid]struct{})
+	queue := []cid.Cid{root}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+
+		if !IsDAG(c) {
+			files = append(files, c)
+			continue
+		}
+
+		block, err := bs.GetBlock(ctx, c)
+		if err != nil {
+			return nil, &ipfs.VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ipfs.ErrContentUnreachable, err)}
+		}
+		if err := ipfs.VerifyBlock(block); err != nil {
+			return nil, err
+		}
+
+		links, err := blockLinks(block)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, links...)
+	}
+	return files, nil
+}
@@ -14,9 +14,36 @@ import (
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
 )
 
-func RenderThread(threadURLTemplate, threadID string, data any, logger *slog.Logger) (template.HTML, error) {
+// RenderThread renders a thread as HTML. Media and avatars that have been archived
+// are referenced through mediaURLTemplate (formatted with the CID) instead of X's CDN.
+func RenderThread(threadURLTemplate, mediaURLTemplate, threadID string, data any, logger *slog.Logger) (template.HTML, error) {
+	archivedURL := func(cid string) string {
+		if cid == "" || mediaURLTemplate == "" {
+			return ""
+		}
+		return fmt.Sprintf(mediaURLTemplate, cid)
+	}
 	funcMap := template.FuncMap{
+		"mediaSrc": func(tweet *xscraper.Tweet, url string) string {
+			if archived := archivedURL(tweet.ArchivedCID(url)); archived != "" {
+				return archived
+			}
+			return url
+		},
+		"avatarSrc": func(user *xscraper.User) string {
+			if archived := archivedURL(user.ProfileImageCID); archived != "" {
+				return archived
+			}
+			return user.ProfileImageURL
+		},
 		"linkify": linkifyTweetText,
+		"pollPercent": func(poll *xscraper.Poll, count int) int {
+			votes := poll.Votes()
+			if votes == 0 {
+				return 0
+			}
+			return (count*100 + votes/2) / votes
+		},
 		"displayText": func(tweet *xscraper.Tweet) string {
 			return tweet.GetDisplayableText()
 		},
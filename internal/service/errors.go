@@ -13,6 +13,9 @@ var (
 	ErrInvalidThreadID      = errors.New("invalid thread ID")
 	ErrThreadStatusInvalid  = errors.New("invalid thread status")
 	ErrOptimisticLockFailed = errors.New("optimistic lock failed - resource was modified")
+	ErrThreadNotArchived    = errors.New("thread has not been archived yet")
+	ErrAttestationNotFound  = errors.New("thread has no attestation")
+	ErrPrivateDisabled      = errors.New("private archives are not enabled")
 
 	// Mention-related errors
 	ErrMentionNotFound      = errors.New("mention not found")
@@ -36,9 +39,12 @@ var (
 	ErrThreadEmpty       = errors.New("thread contains no tweets")
 
 	// IPFS-related errors
-	ErrIPFSStoreFailed = errors.New("failed to store content in IPFS")
-	ErrIPFSLoadFailed  = errors.New("failed to load content from IPFS")
-	ErrInvalidCID      = errors.New("invalid IPFS CID")
+	ErrIPFSStoreFailed  = errors.New("failed to store content in IPFS")
+	ErrIPFSLoadFailed   = errors.New("failed to load content from IPFS")
+	ErrInvalidCID       = errors.New("invalid IPFS CID")
+	ErrMediaNotFound    = errors.New("media not found")
+	ErrCARUnsupported   = errors.New("storage backend does not support CAR export")
+	ErrPDPRootAddFailed = errors.New("failed to add piece as PDP root")
 
 	// LLM-related errors
 	ErrLLMGenerationFailed = errors.New("LLM generation failed")
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/google/uuid"
+)
+
+// parseID parses the ID of a thread or mention. Records created for a tweet,
+// such as the thread of a mentioned tweet and the mention itself, are keyed by
+// the tweet ID, which is mapped to a name-based UUID; other records have
+// random UUIDs.
+func parseID(id string) (uuid.UUID, error) {
+	if isTweetID(id) {
+		return tweetUUID(id), nil
+	}
+	return uuid.Parse(id)
+}
+
+// tweetUUID returns the UUID of the records keyed by tweetID
+func tweetUUID(tweetID string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://x.com/i/status/"+tweetID))
+}
+
+// isTweetID reports whether id is a tweet ID rather than a UUID
+func isTweetID(id string) bool {
+	if id == "" || len(id) > 20 {
+		return false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
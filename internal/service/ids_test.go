@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseID(t *testing.T) {
+	// The mention pipeline passes tweet IDs as thread and mention IDs
+	const tweetID = "1920620861285093468"
+	_, err := uuid.Parse(tweetID)
+	require.Error(t, err)
+
+	id, err := parseID(tweetID)
+	require.NoError(t, err)
+	require.Equal(t, tweetUUID(tweetID), id)
+	require.Equal(t, uuid.Version(5), id.Version())
+
+	again, err := parseID(tweetID)
+	require.NoError(t, err)
+	require.Equal(t, id, again)
+	other, err := parseID("1920620861285093469")
+	require.NoError(t, err)
+	require.NotEqual(t, id, other)
+
+	random := uuid.New()
+	parsed, err := parseID(random.String())
+	require.NoError(t, err)
+	require.Equal(t, random, parsed)
+
+	for _, invalid := range []string{"", "thread456", "123456789012345678901"} {
+		_, err := parseID(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+)
+
+const (
+	// maxArchivedMediaSize caps a single downloaded media file
+	maxArchivedMediaSize = 512 << 20
+	mediaDownloadTimeout = 5 * time.Minute
+)
+
+// MediaArchiver downloads tweet media and avatars and adds them to storage,
+// so archived threads no longer depend on pbs.twimg.com / video.twimg.com.
+type MediaArchiver struct {
+	storage ipfs.Storage
+	client  *http.Client
+	logger  *slog.Logger
+	maxSize int64
+}
+
+func NewMediaArchiver(storage ipfs.Storage, logger *slog.Logger) *MediaArchiver {
+	return &MediaArchiver{
+		storage: storage,
+		client:  &http.Client{Timeout: mediaDownloadTimeout},
+		logger:  logger,
+		maxSize: maxArchivedMediaSize,
+	}
+}
+
+// ArchiveTweets archives the media of every tweet (including quoted tweets) and
+// the profile images of their authors, recording the resulting CIDs on the tweets.
+// Media that cannot be downloaded is logged and skipped so that a single broken
+// link does not fail the whole thread.
+func (a *MediaArchiver) ArchiveTweets(ctx context.Context, tweets []*xscraper.Tweet) {
+	archived := make(map[string]xscraper.ArchivedMedia)
+
+	var archiveTweet func(tweet *xscraper.Tweet)
+	archiveTweet = func(tweet *xscraper.Tweet) {
+		if tweet == nil {
+			return
+		}
+
+		tweet.ArchivedMedia = nil
+		for _, url := range tweet.MediaURLs() {
+			media, ok := a.archiveOnce(ctx, archived, url)
+			if ok {
+				tweet.ArchivedMedia = append(tweet.ArchivedMedia, media)
+			}
+		}
+
+		if tweet.Author != nil && tweet.Author.ProfileImageURL != "" {
+			if media, ok := a.archiveOnce(ctx, archived, tweet.Author.ProfileImageURL); ok {
+				tweet.Author.ProfileImageCID = media.CID
+			}
+		}
+
+		archiveTweet(tweet.QuotedTweet)
+	}
+
+	for _, tweet := range tweets {
+		archiveTweet(tweet)
+	}
+}
+
+// archiveOnce archives url unless it was already archived during this run
+func (a *MediaArchiver) archiveOnce(ctx context.Context, archived map[string]xscraper.ArchivedMedia, url string) (xscraper.ArchivedMedia, bool) {
+	if media, ok := archived[url]; ok {
+		return media, true
+	}
+
+	media, err := a.Archive(ctx, url)
+	if err != nil {
+		a.logger.Warn("failed to archive media", "url", url, "error", err)
+		return xscraper.ArchivedMedia{}, false
+	}
+	archived[url] = media
+	return media, true
+}
+
+// Archive downloads a single media file and adds it to storage
+func (a *MediaArchiver) Archive(ctx context.Context, url string) (xscraper.ArchivedMedia, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("download media: %w", err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("download media: unexpected status %d", resp.StatusCode)
+	}
+
+	// Storage.Add needs an io.ReadSeeker, spool videos to disk instead of memory
+	tmp, err := os.CreateTemp("", "threadmirror-media-*")
+	if err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+	defer tmp.Close()           // nolint:errcheck
+
+	size, err := io.Copy(tmp, io.LimitReader(resp.Body, a.maxSize+1))
+	if err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("read media: %w", err)
+	}
+	if size > a.maxSize {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("media exceeds %d bytes", a.maxSize)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("seek temp file: %w", err)
+	}
+
+	c, err := a.storage.Add(ctx, tmp)
+	if err != nil {
+		return xscraper.ArchivedMedia{}, fmt.Errorf("add media to IPFS: %w", err)
+	}
+
+	return xscraper.ArchivedMedia{
+		URL:         url,
+		CID:         c.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        size,
+	}, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// memStorage is an in-memory ipfs.Storage addressing content as raw CIDs
+type memStorage struct {
+	mu   sync.Mutex
+	data map[cid.Cid][]byte
+}
+
+func (m *memStorage) Add(_ context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: multihash.SHA2_256, MhLength: -1}.Sum(data)
+	if err != nil {
+		return cid.Undef, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[c] = data
+	return c, nil
+}
+
+func (m *memStorage) Get(_ context.Context, c cid.Cid) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.data[c]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", c)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// mediaServer serves the body registered for a path and counts the requests
+// for every path
+type mediaServer struct {
+	mu       sync.Mutex
+	files    map[string]string
+	requests map[string]int
+}
+
+func (s *mediaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.URL.Path]++
+	body, ok := s.files[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = io.WriteString(w, body)
+}
+
+func newTestMediaArchiver(t *testing.T, files map[string]string) (*MediaArchiver, *mediaServer, *httptest.Server) {
+	media := &mediaServer{files: files, requests: make(map[string]int)}
+	srv := httptest.NewServer(media)
+	t.Cleanup(srv.Close)
+
+	storage := &memStorage{data: make(map[cid.Cid][]byte)}
+	archiver := NewMediaArchiver(storage, slog.New(slog.DiscardHandler))
+	archiver.client = srv.Client()
+	return archiver, media, srv
+}
+
+func TestMediaArchiverArchiveTweets(t *testing.T) {
+	archiver, media, srv := newTestMediaArchiver(t, map[string]string{
+		"/photo.jpg":  "photo",
+		"/thumb.jpg":  "thumb",
+		"/low.mp4":    "low",
+		"/high.mp4":   "high",
+		"/avatar.jpg": "avatar",
+		"/quoted.jpg": "quoted",
+		"/video.m3u8": "playlist",
+		"/toobig.jpg": strings.Repeat("x", 64),
+	})
+	archiver.maxSize = 16
+
+	author := &xscraper.User{RestID: "1", ProfileImageURL: srv.URL + "/avatar.jpg"}
+	tweets := []*xscraper.Tweet{
+		{
+			RestID: "1",
+			Author: author,
+			Entities: generated.Entities{Media: &[]generated.Media{
+				{MediaUrlHttps: srv.URL + "/photo.jpg"},
+				{MediaUrlHttps: srv.URL + "/missing.jpg"},
+				{MediaUrlHttps: srv.URL + "/toobig.jpg"},
+			}},
+		},
+		{
+			RestID: "2",
+			Author: author,
+			Entities: generated.Entities{Media: &[]generated.Media{{
+				MediaUrlHttps: srv.URL + "/thumb.jpg",
+				VideoInfo: &generated.MediaVideoInfo{Variants: []generated.MediaVideoInfoVariant{
+					{ContentType: "application/x-mpegURL", Url: srv.URL + "/video.m3u8"},
+					{ContentType: "video/mp4", Url: srv.URL + "/low.mp4", Bitrate: lo.ToPtr(256000)},
+					{ContentType: "video/mp4", Url: srv.URL + "/high.mp4", Bitrate: lo.ToPtr(2176000)},
+				}},
+			}}},
+			QuotedTweet: &xscraper.Tweet{
+				RestID: "3",
+				Author: &xscraper.User{RestID: "2", ProfileImageURL: srv.URL + "/avatar.jpg"},
+				Entities: generated.Entities{Media: &[]generated.Media{
+					{MediaUrlHttps: srv.URL + "/quoted.jpg"},
+				}},
+			},
+		},
+	}
+
+	archiver.ArchiveTweets(context.Background(), tweets)
+
+	archivedURLs := func(tweet *xscraper.Tweet) []string {
+		return lo.Map(tweet.ArchivedMedia, func(m xscraper.ArchivedMedia, _ int) string {
+			return strings.TrimPrefix(m.URL, srv.URL)
+		})
+	}
+
+	// Missing and oversized media are skipped without failing the rest
+	require.Equal(t, []string{"/photo.jpg"}, archivedURLs(tweets[0]))
+	require.Equal(t, 1, media.requests["/missing.jpg"])
+	require.Equal(t, 1, media.requests["/toobig.jpg"])
+
+	// Every mp4 variant is archived, the HLS playlist is not
+	require.Equal(t, []string{"/thumb.jpg", "/low.mp4", "/high.mp4"}, archivedURLs(tweets[1]))
+	require.Zero(t, media.requests["/video.m3u8"])
+	require.Equal(t, []string{"/quoted.jpg"}, archivedURLs(tweets[1].QuotedTweet))
+
+	// The avatar is downloaded once and recorded on every author
+	require.Equal(t, 1, media.requests["/avatar.jpg"])
+	require.NotEmpty(t, author.ProfileImageCID)
+	require.Equal(t, author.ProfileImageCID, tweets[1].QuotedTweet.Author.ProfileImageCID)
+
+	// The recorded CIDs resolve to the downloaded bytes
+	photo := tweets[0].ArchivedMedia[0]
+	require.Equal(t, int64(len("photo")), photo.Size)
+	require.Equal(t, "application/octet-stream", photo.ContentType)
+	c, err := cid.Parse(photo.CID)
+	require.NoError(t, err)
+	reader, err := archiver.storage.Get(context.Background(), c)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "photo", string(data))
+}
+
+func TestMediaArchiverSizeCap(t *testing.T) {
+	archiver, _, srv := newTestMediaArchiver(t, map[string]string{
+		"/exact.jpg": strings.Repeat("x", 16),
+		"/over.jpg":  strings.Repeat("x", 17),
+	})
+	archiver.maxSize = 16
+
+	media, err := archiver.Archive(context.Background(), srv.URL+"/exact.jpg")
+	require.NoError(t, err)
+	require.Equal(t, int64(16), media.Size)
+
+	_, err = archiver.Archive(context.Background(), srv.URL+"/over.jpg")
+	require.ErrorContains(t, err, "exceeds 16 bytes")
+}
@@ -66,7 +66,7 @@ func (s *MentionService) CreateMention(
 	mentionID *string,
 	mentionCreateAt time.Time,
 ) (*MentionSummary, error) {
-	threadUUID, err := uuid.Parse(threadID)
+	threadUUID, err := parseID(threadID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid thread ID: %w", err)
 	}
@@ -94,14 +94,20 @@ func (s *MentionService) CreateMention(
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				// Create new thread in pending status
-				thread, err = queries.CreateThread(ctx, sqlc_generated.CreateThreadParams{
+				params := sqlc_generated.CreateThreadParams{
 					ID:        threadUUID,
 					Summary:   "",
 					Cid:       "",
 					NumTweets: 0,
 					Status:    "pending",
+					Mode:      ThreadModeThread,
 					// Author fields will be filled when scraping completes
-				})
+				}
+				// Remember the tweet of threads keyed by it, to scrape them again
+				if isTweetID(threadID) {
+					params.TweetID = &threadID
+				}
+				thread, err = queries.CreateThread(ctx, params)
 				if err != nil {
 					return fmt.Errorf("failed to create pending thread: %w", err)
 				}
@@ -113,7 +119,7 @@ func (s *MentionService) CreateMention(
 		// Create mention
 		var mentionUUID uuid.UUID
 		if mentionID != nil {
-			mentionUUID, err = uuid.Parse(*mentionID)
+			mentionUUID, err = parseID(*mentionID)
 			if err != nil {
 				return fmt.Errorf("invalid mention ID: %w", err)
 			}
@@ -267,7 +273,7 @@ func (s *MentionService) GetMentionsByUser(
 
 // GetMentionByID retrieves a mention by ID with thread data
 func (s *MentionService) GetMentionByID(ctx context.Context, id string) (*MentionSummary, error) {
-	mentionUUID, err := uuid.Parse(id)
+	mentionUUID, err := parseID(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid mention ID: %w", err)
 	}
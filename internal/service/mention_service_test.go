@@ -72,6 +72,19 @@ var _ = Describe("MentionService", func() {
 			_, err = mentionService.CreateMention(ctx, userID, threadID, nil, mentionCreateAt)
 			Expect(err).To(Equal(service.ErrMentionAlreadyExists))
 		})
+
+		It("should create a mention of a tweet", func() {
+			tweetID := "1920620861285093468"
+			mentionID := "1920620861285093470"
+
+			created, err := mentionService.CreateMention(ctx, "user123", tweetID, &mentionID, time.Now())
+			Expect(err).ToNot(HaveOccurred())
+
+			mention, err := mentionService.GetMentionByID(ctx, mentionID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mention.ID).To(Equal(created.ID))
+			Expect(mention.ThreadID).To(Equal(created.ThreadID))
+		})
 	})
 
 	Describe("GetMentions", func() {
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	dbsql "github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PDP piece states, see supabase/schemas/pdp_piece.sql
+const (
+	PDPPieceUploaded    = "uploaded"
+	PDPPieceRootPending = "root_pending"
+	PDPPieceRootAdded   = "root_added"
+	PDPPieceFailed      = "failed"
+)
+
+// PDP root states
+const (
+	PDPRootPending = "pending"
+	PDPRootAdded   = "added"
+	PDPRootFailed  = "failed"
+)
+
+// maxPiecesPerAggregateRun caps how many uploaded pieces one AggregatePieces call looks at
+const maxPiecesPerAggregateRun = 1000
+
+// PDPPiece describes how an archived piece is covered by the proof set
+type PDPPiece struct {
+	State string
+	// RootCID is the aggregate root the piece is a subroot of, empty until aggregated
+	RootCID string
+	// SubrootIndex is the position of the piece within RootCID
+	SubrootIndex *int
+	// Proof is the proving status of RootCID, nil until the root is added
+	Proof *PDPRootProof
+}
+
+// PDPRootProof is the last observed proving status of an added root
+type PDPRootProof struct {
+	ProofSetID         int64
+	RootID             *int64
+	LastProvenEpoch    *int64
+	NextChallengeEpoch *int64
+	// Failures counts consecutive proof checks that found a problem
+	Failures  int
+	LastError string
+	CheckedAt *time.Time
+}
+
+// Summary statuses of PDPPiece.ProofStatus
+const (
+	PDPProofPending = "pending"
+	PDPProofProving = "proving"
+	PDPProofFailing = "failing"
+)
+
+// ProofStatus summarizes whether the piece is actually being proven
+func (p *PDPPiece) ProofStatus() string {
+	switch {
+	case p.State == PDPPieceFailed:
+		return PDPProofFailing
+	case p.Proof == nil:
+		return PDPProofPending
+	case p.Proof.Failures > 0:
+		return PDPProofFailing
+	case p.Proof.LastProvenEpoch != nil:
+		return PDPProofProving
+	default:
+		return PDPProofPending
+	}
+}
+
+// PDPPieceService tracks pieces uploaded to a PDP backend until they are added
+// to the proof set. Uploads are recorded first, then batched into aggregate roots
+// whose AddRoots calls are driven by the job queue, so a failed call or a restart
+// never loses a root.
+type PDPPieceService struct {
+	db       *dbsql.DB
+	pieces   ipfs.PieceStorage    // nil if the backend does not store pieces
+	proofSet ipfs.ProofSetStorage // nil if the backend has no proof set
+	// replicated and replica locate the PDP service within a replicated
+	// backend, whose CIDs are those of its first replica
+	replicated ipfs.ReplicatedStorage
+	replica    string
+	logger     *slog.Logger
+}
+
+func NewPDPPieceService(db *dbsql.DB, storage ipfs.Storage, logger *slog.Logger) *PDPPieceService {
+	s := &PDPPieceService{db: db, logger: logger.With("service", "pdp_piece")}
+	// With the replicated backend, the PDP service is one of the replicas
+	if proofSet, ok := ipfs.FindStorage[ipfs.ProofSetStorage](storage); ok {
+		if pieces, ok := proofSet.(ipfs.PieceStorage); ok {
+			s.pieces, s.proofSet = pieces, proofSet
+		}
+	}
+	if replicated, ok := storage.(ipfs.ReplicatedStorage); ok && s.proofSet != nil {
+		for _, replica := range replicated.Replicas() {
+			if proofSet, ok := ipfs.FindStorage[ipfs.ProofSetStorage](replica.Storage); ok && proofSet == s.proofSet {
+				s.replicated, s.replica = replicated, replica.Name
+				break
+			}
+		}
+	}
+	return s
+}
+
+// PieceCID returns the piece CID archived content is stored under on the PDP
+// backend, which is the content CID itself unless the PDP service is one of
+// several replicas. It returns "" if the replica is missing the content.
+func (s *PDPPieceService) PieceCID(ctx context.Context, contentCID string) (string, error) {
+	if s.replicated == nil {
+		return contentCID, nil
+	}
+	c, err := cid.Parse(contentCID)
+	if err != nil {
+		return "", fmt.Errorf("parse CID %s: %w", contentCID, err)
+	}
+	pieceCID, err := s.replicated.ReplicaCID(ctx, c, s.replica)
+	if err != nil {
+		return "", fmt.Errorf("resolve piece CID of %s: %w", contentCID, err)
+	}
+	if !pieceCID.Defined() {
+		return "", nil
+	}
+	return pieceCID.String(), nil
+}
+
+// Enabled reports whether the storage backend needs root registration
+func (s *PDPPieceService) Enabled() bool {
+	return s.proofSet != nil
+}
+
+// Storage returns storage whose Add records every piece uploaded to the PDP
+// backend, directly or as a replica, or storage itself if the backend needs no
+// root registration
+func (s *PDPPieceService) Storage(storage ipfs.Storage) ipfs.Storage {
+	if !s.Enabled() {
+		return storage
+	}
+	if replicated, ok := storage.(ipfs.ReplicatedStorage); ok {
+		return replicated.WithReplicas(func(replica ipfs.Replica) ipfs.Storage {
+			if proofSet, ok := replica.Storage.(ipfs.ProofSetStorage); ok && proofSet == s.proofSet {
+				return &pieceRecordingStorage{Storage: replica.Storage, service: s}
+			}
+			return replica.Storage
+		})
+	}
+	return &pieceRecordingStorage{Storage: storage, service: s}
+}
+
+// RecordUpload records a freshly uploaded piece as 'uploaded'
+func (s *PDPPieceService) RecordUpload(ctx context.Context, piece ipfs.Piece) error {
+	err := s.db.QueriesFromContext(ctx).CreatePDPPiece(ctx, sqlc_generated.CreatePDPPieceParams{
+		PieceCid:   piece.CID.String(),
+		PaddedSize: int64(piece.PaddedSize),
+	})
+	if err != nil {
+		return fmt.Errorf("record pdp piece %s: %w", piece.CID, err)
+	}
+	return nil
+}
+
+// AggregatePieces groups pieces that have been uploaded for at least settleDelay
+// (the PDP service needs a moment to process a new piece) into aggregate roots
+// according to policy, and returns the CIDs of the new roots. The pieces move to
+// 'root_pending' and remember their root and subroot position.
+func (s *PDPPieceService) AggregatePieces(ctx context.Context, settleDelay time.Duration, policy ipfs.AggregatePolicy) ([]string, error) {
+	tx, err := s.db.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // nolint:errcheck
+
+	queries := s.db.QueriesFromContext(ctx).WithTx(tx)
+
+	rows, err := queries.ListSettledPDPPieces(ctx, sqlc_generated.ListSettledPDPPiecesParams{
+		SettledBefore: time.Now().Add(-settleDelay),
+		Limit:         maxPiecesPerAggregateRun,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list settled pdp pieces: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	pending := make([]ipfs.PendingPiece, 0, len(rows))
+	for _, row := range rows {
+		c, err := cid.Parse(row.PieceCid)
+		if err != nil {
+			return nil, fmt.Errorf("parse piece CID %s: %w", row.PieceCid, err)
+		}
+		pending = append(pending, ipfs.PendingPiece{
+			Piece:      ipfs.Piece{CID: c, PaddedSize: uint64(row.PaddedSize)},
+			UploadedAt: row.CreatedAt,
+		})
+	}
+
+	var roots []string
+	for _, batch := range ipfs.PlanAggregates(pending, policy, time.Now()) {
+		subroots := make([]ipfs.Piece, len(batch))
+		for i, p := range batch {
+			subroots[i] = p.Piece
+		}
+		ipfs.SortPiecesForAggregate(subroots)
+
+		root, err := ipfs.AggregatePieces(subroots)
+		if err != nil {
+			return nil, fmt.Errorf("aggregate pieces: %w", err)
+		}
+		rootCID := root.CID.String()
+
+		err = queries.CreatePDPRoot(ctx, sqlc_generated.CreatePDPRootParams{
+			RootCid:     rootCID,
+			PaddedSize:  int64(root.PaddedSize),
+			NumSubroots: int32(len(subroots)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create pdp root: %w", err)
+		}
+		for i, p := range subroots {
+			index := int32(i)
+			err = queries.AssignPDPPieceToRoot(ctx, sqlc_generated.AssignPDPPieceToRootParams{
+				PieceCid:     p.CID.String(),
+				RootCid:      &rootCID,
+				SubrootIndex: &index,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("assign pdp piece to root: %w", err)
+			}
+		}
+		roots = append(roots, rootCID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return roots, nil
+}
+
+// ClaimStaleRoots returns up to limit roots that have been 'pending' for staleAfter
+// without progress, e.g. because the queued job was lost, and touches them so the
+// next run does not pick them up again right away
+func (s *PDPPieceService) ClaimStaleRoots(ctx context.Context, staleAfter time.Duration, limit int) ([]string, error) {
+	rows, err := s.db.QueriesFromContext(ctx).ClaimStalePDPRoots(ctx, sqlc_generated.ClaimStalePDPRootsParams{
+		StaleBefore: time.Now().Add(-staleAfter),
+		Limit:       int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim stale pdp roots: %w", err)
+	}
+	roots := make([]string, len(rows))
+	for i, row := range rows {
+		roots[i] = row.RootCid
+	}
+	return roots, nil
+}
+
+// AddRoot adds the aggregate root with its subroots to the proof set. A failed
+// attempt is recorded and returned so the job can be retried; once maxAttempts is
+// reached the root and its pieces are marked failed and ErrPDPRootAddFailed is returned.
+func (s *PDPPieceService) AddRoot(ctx context.Context, rootCID string, maxAttempts int) error {
+	if !s.Enabled() {
+		return fmt.Errorf("storage backend has no proof set")
+	}
+
+	queries := s.db.QueriesFromContext(ctx)
+	root, err := queries.GetPDPRoot(ctx, sqlc_generated.GetPDPRootParams{RootCid: rootCID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get pdp root: %w", err)
+	}
+	switch root.State {
+	case PDPRootAdded:
+		return nil
+	case PDPRootFailed:
+		return ErrPDPRootAddFailed
+	}
+
+	subroots, err := queries.ListPDPRootSubroots(ctx, sqlc_generated.ListPDPRootSubrootsParams{RootCid: &rootCID})
+	if err != nil {
+		return fmt.Errorf("list pdp root subroots: %w", err)
+	}
+	if len(subroots) != int(root.NumSubroots) {
+		return fmt.Errorf("pdp root %s has %d subroots, expected %d", rootCID, len(subroots), root.NumSubroots)
+	}
+	subrootCIDs := make([]string, len(subroots))
+	for i, p := range subroots {
+		subrootCIDs[i] = p.PieceCid
+	}
+
+	addErr := s.proofSet.AddRoots(ctx, "", []string{ipfs.RootInput(rootCID, subrootCIDs...)})
+	if addErr == nil {
+		if err := s.setRootState(ctx, rootCID, PDPRootAdded); err != nil {
+			return err
+		}
+		s.logger.Info("pdp root added", "root_cid", rootCID, "subroots", len(subroots))
+		return nil
+	}
+
+	lastError := addErr.Error()
+	root, err = queries.RecordPDPRootFailure(ctx, sqlc_generated.RecordPDPRootFailureParams{
+		RootCid:     rootCID,
+		LastError:   &lastError,
+		MaxAttempts: int32(maxAttempts),
+	})
+	if err != nil {
+		return fmt.Errorf("record pdp root failure: %w", err)
+	}
+	if root.State == PDPRootFailed {
+		if err := s.setRootState(ctx, rootCID, PDPRootFailed); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w after %d attempts: %w", ErrPDPRootAddFailed, root.Attempts, addErr)
+	}
+	return fmt.Errorf("add root (attempt %d/%d): %w", root.Attempts, maxAttempts, addErr)
+}
+
+// setRootState moves a root to added or failed together with its pieces
+func (s *PDPPieceService) setRootState(ctx context.Context, rootCID string, state string) error {
+	tx, err := s.db.Pool().Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // nolint:errcheck
+
+	queries := s.db.QueriesFromContext(ctx).WithTx(tx)
+
+	pieceState := PDPPieceFailed
+	if state == PDPRootAdded {
+		pieceState = PDPPieceRootAdded
+		proofSetID := int64(s.proofSet.ProofSetID())
+		err := queries.MarkPDPRootAdded(ctx, sqlc_generated.MarkPDPRootAddedParams{RootCid: rootCID, ProofSetID: &proofSetID})
+		if err != nil {
+			return fmt.Errorf("mark pdp root added: %w", err)
+		}
+	}
+	err = queries.SetPDPRootPiecesState(ctx, sqlc_generated.SetPDPRootPiecesStateParams{RootCid: &rootCID, State: pieceState})
+	if err != nil {
+		return fmt.Errorf("update pdp root pieces: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetPiece returns how a piece is covered by the proof set, or nil if it is not tracked
+func (s *PDPPieceService) GetPiece(ctx context.Context, pieceCID string) (*PDPPiece, error) {
+	piece, err := s.db.QueriesFromContext(ctx).GetPDPPiece(ctx, sqlc_generated.GetPDPPieceParams{PieceCid: pieceCID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get pdp piece: %w", err)
+	}
+
+	info := &PDPPiece{State: piece.State, RootCID: getStringValue(piece.RootCid)}
+	if piece.SubrootIndex != nil {
+		index := int(*piece.SubrootIndex)
+		info.SubrootIndex = &index
+	}
+	if piece.State != PDPPieceRootAdded || info.RootCID == "" {
+		return info, nil
+	}
+
+	queries := s.db.QueriesFromContext(ctx)
+	root, err := queries.GetPDPRoot(ctx, sqlc_generated.GetPDPRootParams{RootCid: info.RootCID})
+	if err != nil {
+		return nil, fmt.Errorf("get pdp root: %w", err)
+	}
+	if root.ProofSetID == nil {
+		return info, nil
+	}
+	info.Proof = &PDPRootProof{
+		ProofSetID:      *root.ProofSetID,
+		RootID:          root.RootID,
+		LastProvenEpoch: root.LastProvenEpoch,
+		Failures:        int(root.ProofFailures),
+		LastError:       getStringValue(root.LastProofError),
+		CheckedAt:       root.ProofCheckedAt,
+	}
+
+	proofSet, err := queries.GetPDPProofSet(ctx, sqlc_generated.GetPDPProofSetParams{ID: *root.ProofSetID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return info, nil
+		}
+		return nil, fmt.Errorf("get pdp proof set: %w", err)
+	}
+	info.Proof.NextChallengeEpoch = proofSet.NextChallengeEpoch
+	// The PDP service being unreachable is worth reporting too
+	if info.Proof.LastError == "" {
+		info.Proof.LastError = getStringValue(proofSet.LastError)
+	}
+	return info, nil
+}
+
+// pieceRecordingStorage records every piece added through it with its PDPPieceService
+type pieceRecordingStorage struct {
+	ipfs.Storage
+	service *PDPPieceService
+}
+
+func (s *pieceRecordingStorage) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	piece, err := s.service.pieces.AddPiece(ctx, content)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if err := s.service.RecordUpload(ctx, piece); err != nil {
+		return cid.Undef, err
+	}
+	return piece.CID, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/jackc/pgx/v5"
+)
+
+// PDPProofCheckResult summarizes a CheckProofs run
+type PDPProofCheckResult struct {
+	Roots   int
+	Proven  int
+	Failing int
+	// LastProvenEpoch is the latest challenge epoch the proof set was proven for
+	LastProvenEpoch *int64
+}
+
+// CheckProofs polls the proof set and records, per added root, its root ID, the
+// last proven epoch and any failures.
+//
+// PDP services do not necessarily report proven epochs, so a proof is inferred
+// when the proof set's next challenge epoch moves forward: the previous challenge
+// must have been proven. A root only counts as proven by a challenge if it was
+// already in the proof set at the previous check.
+//
+// Roots missing from the proof set landingGrace after being added, and roots of a
+// proof set without any proof for stallAfter, are recorded as failures.
+func (s *PDPPieceService) CheckProofs(ctx context.Context, stallAfter, landingGrace time.Duration) (*PDPProofCheckResult, error) {
+	if !s.Enabled() {
+		return &PDPProofCheckResult{}, nil
+	}
+
+	proofSetID := int64(s.proofSet.ProofSetID())
+	queries := s.db.QueriesFromContext(ctx)
+
+	status, err := s.proofSet.GetProofSet(ctx)
+	if err != nil {
+		lastError := err.Error()
+		if recErr := queries.RecordPDPProofSetError(ctx, sqlc_generated.RecordPDPProofSetErrorParams{ID: proofSetID, LastError: &lastError}); recErr != nil {
+			s.logger.Error("failed to record proof set error", "error", recErr)
+		}
+		return nil, err
+	}
+
+	var prev *sqlc_generated.PdpProofSet
+	if row, err := queries.GetPDPProofSet(ctx, sqlc_generated.GetPDPProofSetParams{ID: proofSetID}); err == nil {
+		prev = &row
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get pdp proof set: %w", err)
+	}
+
+	now := time.Now()
+	lastProven, lastProvenAt, advanced := provenEpoch(prev, status, now)
+	err = queries.UpsertPDPProofSet(ctx, sqlc_generated.UpsertPDPProofSetParams{
+		ID:                 proofSetID,
+		NextChallengeEpoch: status.NextChallengeEpoch,
+		LastProvenEpoch:    lastProven,
+		LastProvenAt:       lastProvenAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update pdp proof set: %w", err)
+	}
+
+	// Without any proof so far, the stall timer starts at the first check
+	stalled := false
+	if prev != nil {
+		since := prev.CreatedAt
+		if lastProvenAt != nil {
+			since = *lastProvenAt
+		}
+		stalled = now.Sub(since) > stallAfter
+	}
+
+	rootIDs := make(map[string]uint64, len(status.Roots))
+	for _, r := range status.Roots {
+		rootIDs[r.RootCID] = r.RootID
+	}
+
+	roots, err := queries.ListAddedPDPRoots(ctx, sqlc_generated.ListAddedPDPRootsParams{ProofSetID: &proofSetID})
+	if err != nil {
+		return nil, fmt.Errorf("list added pdp roots: %w", err)
+	}
+
+	result := &PDPProofCheckResult{Roots: len(roots), LastProvenEpoch: lastProven}
+	for _, root := range roots {
+		params := sqlc_generated.UpdatePDPRootProofStatusParams{
+			RootCid:         root.RootCid,
+			RootID:          root.RootID,
+			LastProvenEpoch: root.LastProvenEpoch,
+		}
+
+		var failure string
+		id, found := rootIDs[root.RootCid]
+		switch {
+		case !found:
+			if root.AddedAt != nil && now.Sub(*root.AddedAt) > landingGrace {
+				failure = "root not found in proof set"
+			}
+		case root.RootID == nil:
+			// First time the root shows up, check that all of its subroots made it
+			rootID := int64(id)
+			params.RootID = &rootID
+			failure = s.verifyRoot(ctx, id, int(root.NumSubroots))
+		case advanced:
+			params.LastProvenEpoch = lastProven
+		case stalled:
+			failure = fmt.Sprintf("no proof observed for the proof set in %s", stallAfter)
+		}
+
+		if failure != "" {
+			params.ProofFailures = root.ProofFailures + 1
+			params.LastProofError = &failure
+			result.Failing++
+			s.logger.Warn("pdp root proof check failed", "root_cid", root.RootCid, "failures", params.ProofFailures, "error", failure)
+		}
+		if params.LastProvenEpoch != nil && failure == "" {
+			result.Proven++
+		}
+
+		if err := queries.UpdatePDPRootProofStatus(ctx, params); err != nil {
+			return nil, fmt.Errorf("update pdp root proof status: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// verifyRoot checks a root of the proof set against the number of subroots it was
+// added with, and returns a failure description or ""
+func (s *PDPPieceService) verifyRoot(ctx context.Context, rootID uint64, numSubroots int) string {
+	root, err := s.proofSet.GetRoot(ctx, rootID)
+	if err != nil {
+		return err.Error()
+	}
+	if len(root.Subroots) != numSubroots {
+		return fmt.Sprintf("root has %d subroots in proof set, expected %d", len(root.Subroots), numSubroots)
+	}
+	return ""
+}
+
+// provenEpoch returns the last proven epoch of the proof set, when that proof was
+// first observed, and whether it advanced since prev
+func provenEpoch(prev *sqlc_generated.PdpProofSet, status *ipfs.ProofSetStatus, now time.Time) (epoch *int64, at *time.Time, advanced bool) {
+	if prev != nil {
+		epoch, at = prev.LastProvenEpoch, prev.LastProvenAt
+	}
+
+	switch {
+	case status.LastProvenEpoch != nil:
+		if epoch == nil || *status.LastProvenEpoch > *epoch {
+			return status.LastProvenEpoch, &now, true
+		}
+	case prev != nil && prev.NextChallengeEpoch != nil && status.NextChallengeEpoch != nil:
+		if *status.NextChallengeEpoch > *prev.NextChallengeEpoch {
+			return prev.NextChallengeEpoch, &now, true
+		}
+	}
+	return epoch, at, false
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	dbsql "github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/samber/lo"
+)
+
+// ScraperAccount is the last recorded circuit breaker state of a scraper account
+type ScraperAccount struct {
+	Username string
+	// State is closed, open, half_open or quarantined
+	State               string
+	Failures            map[string]int
+	ConsecutiveFailures int
+	OpenUntil           *time.Time
+	LastError           string
+	LastErrorAt         *time.Time
+	LastSuccessAt       *time.Time
+	// RateLimits are the limits of the endpoints the account has called
+	RateLimits          []xscraper.EndpointLimit
+	QuarantinedAt       *time.Time
+	QuarantineReason    string
+	QuarantineClearedAt *time.Time
+	// UpdatedAt is when the bot last recorded the state
+	UpdatedAt time.Time
+}
+
+// ScraperAccountService records the state of the bot's scraper pool and the
+// quarantines operators clear
+type ScraperAccountService struct {
+	db     *dbsql.DB
+	logger *slog.Logger
+}
+
+func NewScraperAccountService(db *dbsql.DB, logger *slog.Logger) *ScraperAccountService {
+	return &ScraperAccountService{db: db, logger: logger.With("service", "scraper_account")}
+}
+
+// SyncPool reconciles the quarantines of pool with the database and records
+// the state of its accounts. Quarantines cleared by an operator since the pool
+// quarantined an account are cleared in the pool; quarantines recorded before
+// a restart are restored into it.
+func (s *ScraperAccountService) SyncPool(ctx context.Context, pool *xscraper.ScraperPool) error {
+	queries := s.db.QueriesFromContext(ctx)
+	rows, err := queries.ListScraperAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("list scraper accounts: %w", err)
+	}
+	recorded := lo.KeyBy(rows, func(row sqlc_generated.ScraperAccount) string { return row.Username })
+
+	for _, status := range pool.Snapshot() {
+		row, ok := recorded[status.Username]
+		if !ok {
+			continue
+		}
+		switch {
+		case status.State == xscraper.CircuitQuarantined:
+			if row.QuarantineClearedAt != nil && row.QuarantineClearedAt.After(status.QuarantinedAt) {
+				pool.ClearQuarantine(status.Username)
+				s.logger.Info("scraper account quarantine cleared", "username", status.Username)
+			}
+		case row.QuarantinedAt != nil:
+			pool.Quarantine(status.Username, getStringValue(row.QuarantineReason), *row.QuarantinedAt)
+			s.logger.Info("scraper account quarantine restored", "username", status.Username)
+		}
+	}
+
+	for _, status := range pool.Snapshot() {
+		failures, err := json.Marshal(status.Failures)
+		if err != nil {
+			return fmt.Errorf("marshal failures: %w", err)
+		}
+		rateLimits, err := json.Marshal(status.RateLimits)
+		if err != nil {
+			return fmt.Errorf("marshal rate limits: %w", err)
+		}
+		err = queries.UpsertScraperAccount(ctx, sqlc_generated.UpsertScraperAccountParams{
+			Username:            status.Username,
+			State:               string(status.State),
+			Failures:            failures,
+			ConsecutiveFailures: int32(status.ConsecutiveFailures),
+			OpenUntil:           timePtr(status.OpenUntil),
+			LastError:           lo.EmptyableToPtr(status.LastError),
+			LastErrorAt:         timePtr(status.LastErrorAt),
+			LastSuccessAt:       timePtr(status.LastSuccessAt),
+			RateLimits:          rateLimits,
+			QuarantinedAt:       timePtr(status.QuarantinedAt),
+			QuarantineReason:    lo.EmptyableToPtr(status.QuarantineReason),
+		})
+		if err != nil {
+			return fmt.Errorf("record scraper account %s: %w", status.Username, err)
+		}
+	}
+	return nil
+}
+
+// ListScraperAccounts returns the last recorded state of every scraper account
+func (s *ScraperAccountService) ListScraperAccounts(ctx context.Context) ([]ScraperAccount, error) {
+	rows, err := s.db.QueriesFromContext(ctx).ListScraperAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list scraper accounts: %w", err)
+	}
+	accounts := make([]ScraperAccount, len(rows))
+	for i, row := range rows {
+		var failures map[string]int
+		if len(row.Failures) > 0 {
+			if err := json.Unmarshal(row.Failures, &failures); err != nil {
+				s.logger.Warn("failed to unmarshal scraper account failures", "username", row.Username, "error", err)
+			}
+		}
+		var rateLimits []xscraper.EndpointLimit
+		if len(row.RateLimits) > 0 {
+			if err := json.Unmarshal(row.RateLimits, &rateLimits); err != nil {
+				s.logger.Warn("failed to unmarshal scraper account rate limits", "username", row.Username, "error", err)
+			}
+		}
+		accounts[i] = ScraperAccount{
+			Username:            row.Username,
+			State:               row.State,
+			Failures:            failures,
+			ConsecutiveFailures: int(row.ConsecutiveFailures),
+			OpenUntil:           row.OpenUntil,
+			LastError:           getStringValue(row.LastError),
+			LastErrorAt:         row.LastErrorAt,
+			LastSuccessAt:       row.LastSuccessAt,
+			RateLimits:          rateLimits,
+			QuarantinedAt:       row.QuarantinedAt,
+			QuarantineReason:    getStringValue(row.QuarantineReason),
+			QuarantineClearedAt: row.QuarantineClearedAt,
+			UpdatedAt:           row.UpdatedAt,
+		}
+	}
+	return accounts, nil
+}
+
+// ClearQuarantine clears the quarantine of a scraper account. The bot puts
+// the account back into use on its next sync. It returns ErrNotFound if the
+// account is not quarantined.
+func (s *ScraperAccountService) ClearQuarantine(ctx context.Context, username string) error {
+	n, err := s.db.QueriesFromContext(ctx).ClearScraperQuarantine(ctx, sqlc_generated.ClearScraperQuarantineParams{Username: username})
+	if err != nil {
+		return fmt.Errorf("clear scraper quarantine: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	s.logger.Info("scraper account quarantine cleared by operator", "username", username)
+	return nil
+}
+
+// timePtr returns nil for the zero time
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
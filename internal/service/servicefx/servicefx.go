@@ -2,6 +2,7 @@ package servicefx
 
 import (
 	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
 	"go.uber.org/fx"
 )
 
@@ -9,5 +10,10 @@ var Module = fx.Module("service",
 	fx.Provide(service.NewMentionService),
 	fx.Provide(service.NewProcessedMarkService),
 	fx.Provide(service.NewBotCookieService),
+	fx.Provide(service.NewPDPPieceService),
+	fx.Provide(service.NewStorageReplicaService),
+	fx.Provide(func(s *service.StorageReplicaService) ipfs.ReplicaIndex { return s }),
 	fx.Provide(service.NewThreadService),
+	fx.Provide(service.NewTransparencyLogService),
+	fx.Provide(service.NewScraperAccountService),
 )
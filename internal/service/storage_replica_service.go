@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	dbsql "github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs/go-cid"
+)
+
+// StorageReplicaService is the database backed ipfs.ReplicaIndex of the
+// replicated storage backend
+type StorageReplicaService struct {
+	db *dbsql.DB
+}
+
+var _ ipfs.ReplicaIndex = (*StorageReplicaService)(nil)
+
+func NewStorageReplicaService(db *dbsql.DB) *StorageReplicaService {
+	return &StorageReplicaService{db: db}
+}
+
+func (s *StorageReplicaService) GetReplicas(ctx context.Context, c cid.Cid) ([]ipfs.ReplicaEntry, error) {
+	rows, err := s.db.QueriesFromContext(ctx).ListStorageReplicas(ctx, sqlc_generated.ListStorageReplicasParams{Cid: c.String()})
+	if err != nil {
+		return nil, fmt.Errorf("list storage replicas: %w", err)
+	}
+	return toReplicaEntries(rows)
+}
+
+func (s *StorageReplicaService) SetReplica(ctx context.Context, entry ipfs.ReplicaEntry) error {
+	params := sqlc_generated.UpsertStorageReplicaParams{
+		Cid:            entry.CID.String(),
+		Replica:        entry.Replica,
+		IsBlock:        entry.Block,
+		RepairAttempts: int32(entry.Attempts),
+	}
+	if !entry.Missing() {
+		replicaCID := entry.ReplicaCID.String()
+		params.ReplicaCid = &replicaCID
+	}
+	if entry.Root.Defined() {
+		root := entry.Root.String()
+		params.DagRoot = &root
+	}
+	if entry.LastError != "" {
+		params.LastError = &entry.LastError
+	}
+	if err := s.db.QueriesFromContext(ctx).UpsertStorageReplica(ctx, params); err != nil {
+		return fmt.Errorf("upsert storage replica: %w", err)
+	}
+	return nil
+}
+
+func (s *StorageReplicaService) DeleteReplica(ctx context.Context, c cid.Cid, replica string) error {
+	err := s.db.QueriesFromContext(ctx).DeleteStorageReplica(ctx, sqlc_generated.DeleteStorageReplicaParams{
+		Cid:     c.String(),
+		Replica: replica,
+	})
+	if err != nil {
+		return fmt.Errorf("delete storage replica: %w", err)
+	}
+	return nil
+}
+
+func (s *StorageReplicaService) ListMissingReplicas(ctx context.Context, limit int) ([]ipfs.ReplicaEntry, error) {
+	rows, err := s.db.QueriesFromContext(ctx).ListMissingStorageReplicas(ctx, sqlc_generated.ListMissingStorageReplicasParams{
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list missing storage replicas: %w", err)
+	}
+	return toReplicaEntries(rows)
+}
+
+func toReplicaEntries(rows []sqlc_generated.StorageReplica) ([]ipfs.ReplicaEntry, error) {
+	entries := make([]ipfs.ReplicaEntry, len(rows))
+	for i, row := range rows {
+		c, err := cid.Parse(row.Cid)
+		if err != nil {
+			return nil, fmt.Errorf("parse CID %s: %w", row.Cid, err)
+		}
+		entries[i] = ipfs.ReplicaEntry{
+			CID:       c,
+			Replica:   row.Replica,
+			Block:     row.IsBlock,
+			LastError: getStringValue(row.LastError),
+			Attempts:  int(row.RepairAttempts),
+		}
+		if row.ReplicaCid != nil {
+			if entries[i].ReplicaCID, err = cid.Parse(*row.ReplicaCid); err != nil {
+				return nil, fmt.Errorf("parse replica CID %s: %w", *row.ReplicaCid, err)
+			}
+		}
+		if row.DagRoot != nil {
+			if entries[i].Root, err = cid.Parse(*row.DagRoot); err != nil {
+				return nil, fmt.Errorf("parse DAG root %s: %w", *row.DagRoot, err)
+			}
+		}
+	}
+	return entries, nil
+}
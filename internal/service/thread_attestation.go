@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
+	"github.com/ipfs-force-community/threadmirror/pkg/util"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+// ScrapeProvenance describes how a thread was captured
+type ScrapeProvenance struct {
+	ScrapedAt time.Time
+	// ScraperAccount is the X account the thread was scraped with
+	ScraperAccount string
+}
+
+// ThreadAttestation is the signed provenance attestation of an archived thread
+type ThreadAttestation struct {
+	ThreadID       string
+	ContentCID     string
+	AttestationCID string
+	KeyID          string
+	Envelope       *attest.Envelope
+	Statement      *attest.Statement
+	// PublicKey is the PEM of the signing key, if it is the current one
+	PublicKey string
+}
+
+// attestThread signs the provenance of a freshly archived thread and stores the
+// attestation next to its content. It does nothing without a signing key.
+func (s *ThreadService) attestThread(ctx context.Context, threadID uuid.UUID, tweets []*xscraper.Tweet, content cid.Cid, provenance ScrapeProvenance) error {
+	if s.signer == nil {
+		return nil
+	}
+
+	envelope, err := s.signer.Sign(attest.Statement{
+		ThreadID:        threadID.String(),
+		TweetIDs:        lo.Map(tweets, func(t *xscraper.Tweet, _ int) string { return t.RestID }),
+		ScrapedAt:       provenance.ScrapedAt.UTC(),
+		ScraperAccount:  provenance.ScraperAccount,
+		ContentCID:      content.String(),
+		SoftwareVersion: util.Version(),
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestation: %w", err)
+	}
+	attestationCID, err := s.storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to add attestation to IPFS: %w", err)
+	}
+
+	err = s.db.QueriesFromContext(ctx).UpsertThreadAttestation(ctx, sqlc_generated.UpsertThreadAttestationParams{
+		ThreadID:       threadID,
+		ContentCid:     content.String(),
+		AttestationCid: attestationCID.String(),
+		KeyID:          s.signer.KeyID(),
+		ScrapedAt:      provenance.ScrapedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("record attestation: %w", err)
+	}
+	return nil
+}
+
+// GetThreadAttestation returns the attestation of the current archive of a thread
+func (s *ThreadService) GetThreadAttestation(ctx context.Context, id string) (*ThreadAttestation, error) {
+	threadID, err := parseID(id)
+	if err != nil {
+		return nil, ErrInvalidThreadID
+	}
+
+	queries := s.db.QueriesFromContext(ctx)
+	thread, err := queries.GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: threadID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrThreadNotFound
+		}
+		return nil, fmt.Errorf("get thread: %w", err)
+	}
+	if !canView(ctx, thread) {
+		return nil, ErrThreadNotFound
+	}
+	if thread.Status != "completed" || thread.Cid == "" {
+		return nil, ErrThreadNotArchived
+	}
+
+	row, err := queries.GetThreadAttestation(ctx, sqlc_generated.GetThreadAttestationParams{
+		ThreadID:   threadID,
+		ContentCid: thread.Cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAttestationNotFound
+		}
+		return nil, fmt.Errorf("get attestation: %w", err)
+	}
+
+	envelope, err := s.loadAttestation(ctx, row.AttestationCid)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := envelope.Statement()
+	if err != nil {
+		return nil, err
+	}
+
+	attestation := &ThreadAttestation{
+		ThreadID:       threadID.String(),
+		ContentCID:     row.ContentCid,
+		AttestationCID: row.AttestationCid,
+		KeyID:          row.KeyID,
+		Envelope:       envelope,
+		Statement:      statement,
+	}
+	if s.signer != nil && s.signer.KeyID() == row.KeyID {
+		if attestation.PublicKey, err = attest.MarshalPublicKey(s.signer.PublicKey()); err != nil {
+			return nil, err
+		}
+	}
+	return attestation, nil
+}
+
+// loadAttestation reads a stored attestation envelope
+func (s *ThreadService) loadAttestation(ctx context.Context, cidStr string) (*attest.Envelope, error) {
+	c, err := cid.Parse(cidStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attestation CID: %w", err)
+	}
+	reader, err := s.storage.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attestation from IPFS: %w", err)
+	}
+	defer reader.Close() // nolint:errcheck
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation: %w", err)
+	}
+	var envelope attest.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attestation: %w", err)
+	}
+	return &envelope, nil
+}
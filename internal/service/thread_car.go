@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportCAR writes the archived thread, including its archived media, to w as a
+// CAR file of the given version (archive.CARv1 or archive.CARv2)
+func (s *ThreadService) ExportCAR(ctx context.Context, id string, version int, w io.Writer) error {
+	if version != archive.CARv1 && version != archive.CARv2 {
+		return fmt.Errorf("%w: unsupported CAR version %d", ErrInvalidInput, version)
+	}
+
+	bs, ok := s.storage.(ipfs.BlockStorage)
+	if !ok {
+		return ErrCARUnsupported
+	}
+
+	threadID, err := parseID(id)
+	if err != nil {
+		return ErrInvalidThreadID
+	}
+	thread, err := s.db.QueriesFromContext(ctx).GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: threadID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrThreadNotFound
+		}
+		return fmt.Errorf("get thread: %w", err)
+	}
+	if !canView(ctx, thread) {
+		return ErrThreadNotFound
+	}
+	if thread.Status != "completed" || thread.Cid == "" {
+		return ErrThreadNotArchived
+	}
+
+	root, err := cid.Parse(thread.Cid)
+	if err != nil {
+		return fmt.Errorf("failed to parse CID: %w", err)
+	}
+
+	// The DAG layout links its media; a legacy JSON blob only mentions the CIDs.
+	// A private archive is a single encrypted file without archived media.
+	var extra []cid.Cid
+	if !archive.IsDAG(root) && thread.Visibility != ThreadVisibilityPrivate {
+		tweets, err := s.loadTweetsFromJSON(ctx, root)
+		if err != nil {
+			return err
+		}
+		extra = archivedMediaCIDs(tweets)
+	}
+
+	if err := archive.WriteCAR(ctx, w, bs, version, root, extra...); err != nil {
+		return fmt.Errorf("write car: %w", err)
+	}
+	return nil
+}
+
+// ImportCAR loads a thread CAR into the configured storage and creates a completed
+// thread row for it. If threadID is empty a new ID is generated.
+//
+// Backends that accept raw blocks receive the CAR blocks unchanged, so the thread
+// keeps its CID. Other backends get the tweets and media re-added as files, which
+// yields new CIDs.
+func (s *ThreadService) ImportCAR(ctx context.Context, r io.Reader, threadID string) (*ThreadDetail, error) {
+	threadUUID := uuid.New()
+	if threadID != "" {
+		var err error
+		threadUUID, err = uuid.Parse(threadID)
+		if err != nil {
+			return nil, ErrInvalidThreadID
+		}
+	}
+
+	mem, root, err := archive.ReadCAR(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	var tweets []*xscraper.Tweet
+	var typ string
+	if archive.IsDAG(root) {
+		tweets, err = archive.Decode(ctx, mem, root)
+		if err != nil {
+			return nil, fmt.Errorf("decode thread DAG: %w", err)
+		}
+		if typ, err = archive.DAGType(ctx, mem, root); err != nil {
+			return nil, fmt.Errorf("decode thread DAG: %w", err)
+		}
+	} else {
+		tweets, typ, err = readTweetsFile(ctx, mem, root)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mode := threadMode(typ)
+	if len(tweets) == 0 {
+		return nil, ErrThreadEmpty
+	}
+
+	var rootCID cid.Cid
+	if bs, ok := s.storage.(ipfs.BlockStorage); ok {
+		if dag, ok := bs.(ipfs.DAGStorage); ok {
+			var all []blocks.Block
+			err = mem.AllBlocks(ctx, func(block blocks.Block) error {
+				all = append(all, block)
+				return nil
+			})
+			if err == nil {
+				err = dag.PutDAG(ctx, root, all)
+			}
+		} else {
+			err = mem.AllBlocks(ctx, func(block blocks.Block) error {
+				return bs.PutBlock(ctx, block)
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("put car blocks: %w", err)
+		}
+		rootCID = root
+	} else {
+		s.readdArchivedMedia(ctx, mem, tweets)
+		rootCID, err = s.storeTweets(ctx, tweets, mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	summary, err := s.generateTweetsSummary(ctx, tweets)
+	if err != nil {
+		s.logger.Warn("failed to generate summary for imported thread", "error", err)
+		summary = ""
+	}
+
+	authorID, authorName, authorScreenName, authorProfileImageURL := threadAuthorFields(tweets, mode)
+	_, err = s.db.QueriesFromContext(ctx).CreateThread(ctx, sqlc_generated.CreateThreadParams{
+		ID:                    threadUUID,
+		Summary:               summary,
+		Cid:                   rootCID.String(),
+		NumTweets:             int32(len(tweets)),
+		Status:                "completed",
+		Version:               1,
+		AuthorID:              authorID,
+		AuthorName:            authorName,
+		AuthorScreenName:      authorScreenName,
+		AuthorProfileImageUrl: authorProfileImageURL,
+		Mode:                  mode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create thread: %w", err)
+	}
+	if err := s.recordThreadMedia(ctx, threadUUID, archivedMediaCIDs(tweets)); err != nil {
+		s.logger.Error("failed to record thread media", "threadID", threadUUID, "error", err)
+	}
+
+	s.logger.Info("thread imported from car", "threadID", threadUUID, "cid", rootCID)
+	return s.GetThreadByID(ctx, threadUUID.String())
+}
+
+// readdArchivedMedia adds the archived media files found in mem to storage and
+// rewrites the tweets to point at the new CIDs. Media missing from the CAR is dropped.
+func (s *ThreadService) readdArchivedMedia(ctx context.Context, mem *archive.MemoryBlocks, tweets []*xscraper.Tweet) {
+	readded := make(map[string]string)
+	readd := func(oldCID string) string {
+		if newCID, ok := readded[oldCID]; ok {
+			return newCID
+		}
+		newCID, err := s.readdFile(ctx, mem, oldCID)
+		if err != nil {
+			s.logger.Warn("failed to re-add archived media", "cid", oldCID, "error", err)
+		}
+		readded[oldCID] = newCID
+		// Authors are shared between tweets, don't re-add an already rewritten CID
+		if newCID != "" {
+			readded[newCID] = newCID
+		}
+		return newCID
+	}
+
+	var walk func(tweet *xscraper.Tweet)
+	walk = func(tweet *xscraper.Tweet) {
+		if tweet == nil {
+			return
+		}
+		media := tweet.ArchivedMedia[:0]
+		for _, m := range tweet.ArchivedMedia {
+			if m.CID = readd(m.CID); m.CID != "" {
+				media = append(media, m)
+			}
+		}
+		tweet.ArchivedMedia = media
+		if tweet.Author != nil && tweet.Author.ProfileImageCID != "" {
+			tweet.Author.ProfileImageCID = readd(tweet.Author.ProfileImageCID)
+		}
+		walk(tweet.QuotedTweet)
+	}
+	for _, tweet := range tweets {
+		walk(tweet)
+	}
+}
+
+func (s *ThreadService) readdFile(ctx context.Context, mem *archive.MemoryBlocks, cidStr string) (string, error) {
+	c, err := cid.Parse(cidStr)
+	if err != nil {
+		return "", err
+	}
+	f, err := mem.ReadFile(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // nolint:errcheck
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	newCID, err := s.storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return newCID.String(), nil
+}
+
+// readTweetsFile decodes a JSON thread document stored as a UnixFS file in mem,
+// and returns its type
+func readTweetsFile(ctx context.Context, mem *archive.MemoryBlocks, root cid.Cid) ([]*xscraper.Tweet, string, error) {
+	f, err := mem.ReadFile(ctx, root)
+	if err != nil {
+		return nil, "", fmt.Errorf("read thread file: %w", err)
+	}
+	defer f.Close() // nolint:errcheck
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("read thread file: %w", err)
+	}
+	tweets, err := archive.DecodeJSON(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal tweets: %w", err)
+	}
+	typ, err := archive.JSONType(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal tweets: %w", err)
+	}
+	return tweets, typ, nil
+}
+
+// archivedMediaCIDs lists the CIDs of all archived media and avatars in tweets
+func archivedMediaCIDs(tweets []*xscraper.Tweet) []cid.Cid {
+	var cids []cid.Cid
+	add := func(s string) {
+		if c, err := cid.Parse(s); err == nil {
+			cids = append(cids, c)
+		}
+	}
+
+	var walk func(tweet *xscraper.Tweet)
+	walk = func(tweet *xscraper.Tweet) {
+		if tweet == nil {
+			return
+		}
+		for _, m := range tweet.ArchivedMedia {
+			add(m.CID)
+		}
+		if tweet.Author != nil && tweet.Author.ProfileImageCID != "" {
+			add(tweet.Author.ProfileImageCID)
+		}
+		walk(tweet.QuotedTweet)
+	}
+	for _, tweet := range tweets {
+		walk(tweet)
+	}
+	return cids
+}
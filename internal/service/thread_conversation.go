@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/jackc/pgx/v5"
+)
+
+// Thread mode, see supabase/schemas/thread.sql
+const (
+	// ThreadModeThread archives the author's thread up to a tweet
+	ThreadModeThread = "thread"
+	// ThreadModeConversation archives the reply tree under a tweet, including
+	// replies by other users
+	ThreadModeConversation = "conversation"
+)
+
+// GetOrCreateConversationThread returns the public conversation thread of
+// tweetID collected within opts, creating it pending if there is none. Requests
+// for the same tweet share a thread only if their normalized limits match, so
+// every archive holds the limits it was asked for. Mentions of it are recorded
+// with MentionService.CreateMention.
+func (s *ThreadService) GetOrCreateConversationThread(ctx context.Context, tweetID string, opts xscraper.ConversationOptions) (*ThreadDetail, error) {
+	options, err := json.Marshal(opts.Normalize())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal conversation options: %w", err)
+	}
+
+	var thread sqlc_generated.Thread
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		queries := s.db.QueriesFromContext(ctx)
+
+		thread, err = queries.GetConversationThreadByTweetAndOptions(ctx, sqlc_generated.GetConversationThreadByTweetAndOptionsParams{
+			TweetID:             &tweetID,
+			ConversationOptions: options,
+		})
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("check existing conversation thread: %w", err)
+		}
+
+		thread, err = queries.CreateConversationThread(ctx, sqlc_generated.CreateConversationThreadParams{
+			ID:                  uuid.New(),
+			TweetID:             &tweetID,
+			ConversationOptions: options,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create conversation thread: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThreadDetail{
+		ID:           thread.ID.String(),
+		CreatedAt:    thread.CreatedAt,
+		Status:       thread.Status,
+		Version:      int(thread.Version),
+		Visibility:   thread.Visibility,
+		Mode:         thread.Mode,
+		Conversation: conversationOptions(thread),
+	}, nil
+}
+
+// conversationOptions returns the limits of a conversation thread, nil for
+// threads in thread mode
+func conversationOptions(thread sqlc_generated.Thread) *xscraper.ConversationOptions {
+	if thread.Mode != ThreadModeConversation {
+		return nil
+	}
+	opts := xscraper.DefaultConversationOptions
+	if len(thread.ConversationOptions) > 0 {
+		// Unreadable options fall back to the defaults rather than failing the scrape
+		_ = json.Unmarshal(thread.ConversationOptions, &opts)
+	}
+	return &opts
+}
+
+// archiveType returns the archive root type of a thread mode
+func archiveType(mode string) string {
+	if mode == ThreadModeConversation {
+		return archive.ConversationType
+	}
+	return archive.ThreadType
+}
+
+// threadMode returns the thread mode of an archive root type
+func threadMode(typ string) string {
+	if typ == archive.ConversationType {
+		return ThreadModeConversation
+	}
+	return ThreadModeThread
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+// ArchiveMigration records an archive rewritten into a newer schema
+type ArchiveMigration struct {
+	ThreadID    string
+	OldCID      string
+	NewCID      string
+	FromVersion int
+	ToVersion   int
+}
+
+// ListThreadsToMigrate returns the IDs of up to limit archived threads after
+// afterID (empty for the first page), in ID order
+func (s *ThreadService) ListThreadsToMigrate(ctx context.Context, afterID string, limit int) ([]string, error) {
+	after := uuid.Nil
+	if afterID != "" {
+		var err error
+		if after, err = uuid.Parse(afterID); err != nil {
+			return nil, ErrInvalidThreadID
+		}
+	}
+
+	threads, err := s.db.QueriesFromContext(ctx).ListThreadsToMigrate(ctx, sqlc_generated.ListThreadsToMigrateParams{
+		AfterID: after,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list threads to migrate: %w", err)
+	}
+	return lo.Map(threads, func(t sqlc_generated.Thread, _ int) string { return t.ID.String() }), nil
+}
+
+// MigrateArchive rewrites the archive of a thread into the current schema as a
+// new CID, points the thread at it and records the lineage from the old CID.
+// It returns nil if the archive is already current. With dryRun the migration
+// is only reported.
+func (s *ThreadService) MigrateArchive(ctx context.Context, id string, dryRun bool) (*ArchiveMigration, error) {
+	threadID, err := parseID(id)
+	if err != nil {
+		return nil, ErrInvalidThreadID
+	}
+	thread, err := s.db.QueriesFromContext(ctx).GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: threadID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrThreadNotFound
+		}
+		return nil, fmt.Errorf("get thread: %w", err)
+	}
+	if !canView(ctx, thread) {
+		return nil, ErrThreadNotFound
+	}
+	if thread.Status != "completed" || thread.Cid == "" {
+		return nil, ErrThreadNotArchived
+	}
+
+	from, err := s.archiveSchemaVersion(ctx, thread)
+	if err != nil {
+		return nil, err
+	}
+	if from == archive.SchemaVersion {
+		return nil, nil
+	}
+
+	migration := &ArchiveMigration{
+		ThreadID:    id,
+		OldCID:      thread.Cid,
+		FromVersion: from,
+		ToVersion:   archive.SchemaVersion,
+	}
+	if dryRun {
+		return migration, nil
+	}
+
+	newCID, stats, err := s.rewriteArchive(ctx, thread)
+	if err != nil {
+		return nil, err
+	}
+	migration.NewCID = newCID.String()
+
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		queries := s.db.QueriesFromContext(ctx)
+		n, err := queries.UpdateThreadArchive(ctx, sqlc_generated.UpdateThreadArchiveParams{
+			ID:              threadID,
+			Cid:             migration.NewCID,
+			Stats:           stats,
+			ExpectedVersion: thread.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("update thread: %w", err)
+		}
+		if n == 0 {
+			return ErrOptimisticLockFailed
+		}
+
+		err = queries.CreateArchiveLineage(ctx, sqlc_generated.CreateArchiveLineageParams{
+			ThreadID:    threadID,
+			OldCid:      migration.OldCID,
+			NewCid:      migration.NewCID,
+			FromVersion: int32(migration.FromVersion),
+			ToVersion:   int32(migration.ToVersion),
+		})
+		if err != nil {
+			return fmt.Errorf("record lineage: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.Visibility != ThreadVisibilityPrivate {
+		if err := s.reattestThread(ctx, thread, newCID); err != nil {
+			s.logger.Error("failed to attest migrated thread", "threadID", id, "error", err)
+		}
+	}
+
+	s.logger.Info("thread archive migrated", "threadID", id, "old_cid", migration.OldCID, "new_cid", migration.NewCID,
+		"from_version", migration.FromVersion, "to_version", migration.ToVersion)
+	return migration, nil
+}
+
+// GetArchiveLineage returns the migrations of a thread's archive, oldest first
+func (s *ThreadService) GetArchiveLineage(ctx context.Context, id string) ([]ArchiveMigration, error) {
+	threadID, err := parseID(id)
+	if err != nil {
+		return nil, ErrInvalidThreadID
+	}
+	rows, err := s.db.QueriesFromContext(ctx).ListArchiveLineage(ctx, sqlc_generated.ListArchiveLineageParams{ThreadID: threadID})
+	if err != nil {
+		return nil, fmt.Errorf("list archive lineage: %w", err)
+	}
+	return lo.Map(rows, func(r sqlc_generated.ArchiveLineage, _ int) ArchiveMigration {
+		return ArchiveMigration{
+			ThreadID:    r.ThreadID.String(),
+			OldCID:      r.OldCid,
+			NewCID:      r.NewCid,
+			FromVersion: int(r.FromVersion),
+			ToVersion:   int(r.ToVersion),
+		}
+	}), nil
+}
+
+// archiveSchemaVersion returns the schema version the archive of thread was
+// written with
+func (s *ThreadService) archiveSchemaVersion(ctx context.Context, thread sqlc_generated.Thread) (int, error) {
+	if thread.Visibility == ThreadVisibilityPrivate {
+		plaintext, err := s.openPrivateArchive(ctx, thread)
+		if err != nil {
+			return 0, err
+		}
+		return archive.JSONSchemaVersion(plaintext)
+	}
+
+	c, err := cid.Parse(thread.Cid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse CID: %w", err)
+	}
+	if archive.IsDAG(c) {
+		bs, ok := s.storage.(ipfs.BlockStorage)
+		if !ok {
+			return 0, fmt.Errorf("storage backend does not support IPLD blocks, cannot load %s", c)
+		}
+		return archive.DAGSchemaVersion(ctx, bs, c)
+	}
+	data, err := s.readArchiveFile(ctx, c)
+	if err != nil {
+		return 0, err
+	}
+	return archive.JSONSchemaVersion(data)
+}
+
+// rewriteArchive stores the archive of thread in the current schema. It
+// returns the new CID and the engagement counters to keep in the thread row.
+func (s *ThreadService) rewriteArchive(ctx context.Context, thread sqlc_generated.Thread) (cid.Cid, []byte, error) {
+	if thread.Visibility == ThreadVisibilityPrivate {
+		tweets, err := s.loadPrivateTweets(ctx, thread)
+		if err != nil {
+			return cid.Undef, nil, err
+		}
+		dataKey, err := s.threadDataKey(ctx, thread.ID)
+		if err != nil {
+			return cid.Undef, nil, err
+		}
+		c, err := s.storeSealedTweets(ctx, tweets, thread.Mode, dataKey)
+		return c, nil, err
+	}
+
+	// Read the archive itself rather than the cache, which holds decoded tweets
+	c, err := cid.Parse(thread.Cid)
+	if err != nil {
+		return cid.Undef, nil, fmt.Errorf("failed to parse CID: %w", err)
+	}
+	var tweets []*xscraper.Tweet
+	if archive.IsDAG(c) {
+		tweets, err = s.loadTweetsFromDAG(ctx, c)
+	} else {
+		tweets, err = s.loadTweetsFromJSON(ctx, c)
+	}
+	if err != nil {
+		return cid.Undef, nil, err
+	}
+
+	// Older schemas kept the engagement counters in the archive; keep them
+	// in the thread row unless a later scrape already put newer ones there
+	stats := thread.Stats
+	if len(stats) == 0 {
+		_, split := archive.SplitStats(tweets)
+		if stats, err = json.Marshal(split); err != nil {
+			return cid.Undef, nil, fmt.Errorf("failed to marshal stats: %w", err)
+		}
+	}
+
+	newCID, err := s.storeTweets(ctx, tweets, thread.Mode)
+	if err != nil {
+		return cid.Undef, nil, err
+	}
+	return newCID, stats, nil
+}
+
+// reattestThread attests a rewritten archive with the provenance of the
+// attestation of the archive it replaces, if there was one
+func (s *ThreadService) reattestThread(ctx context.Context, thread sqlc_generated.Thread, content cid.Cid) error {
+	if s.signer == nil {
+		return nil
+	}
+	row, err := s.db.QueriesFromContext(ctx).GetThreadAttestation(ctx, sqlc_generated.GetThreadAttestationParams{
+		ThreadID:   thread.ID,
+		ContentCid: thread.Cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("get attestation: %w", err)
+	}
+	envelope, err := s.loadAttestation(ctx, row.AttestationCid)
+	if err != nil {
+		return err
+	}
+	statement, err := envelope.Statement()
+	if err != nil {
+		return err
+	}
+
+	tweets := lo.Map(statement.TweetIDs, func(id string, _ int) *xscraper.Tweet { return &xscraper.Tweet{RestID: id} })
+	return s.attestThread(ctx, thread.ID, tweets, content, ScrapeProvenance{
+		ScrapedAt:      statement.ScrapedAt,
+		ScraperAccount: statement.ScraperAccount,
+	})
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreatePrivateThread creates a pending private thread of tweetID owned by
+// ownerID, together with its data key wrapped for the owner and the owner's
+// mention of it. Private threads get their own ID, so they never collide with
+// the public thread of the same tweet. With conversation set the thread
+// archives the conversation under tweetID within those limits.
+func (s *ThreadService) CreatePrivateThread(ctx context.Context, ownerID, tweetID string, conversation *xscraper.ConversationOptions) (*ThreadDetail, error) {
+	if s.keyring == nil {
+		return nil, ErrPrivateDisabled
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: private threads need an owner", ErrInvalidInput)
+	}
+
+	mode := ThreadModeThread
+	var options []byte
+	if conversation != nil {
+		mode = ThreadModeConversation
+		var err error
+		if options, err = json.Marshal(conversation.Normalize()); err != nil {
+			return nil, fmt.Errorf("failed to marshal conversation options: %w", err)
+		}
+	}
+
+	dataKey, err := keyring.NewDataKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := s.keyring.WrapKey(ownerID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var thread sqlc_generated.Thread
+	err = s.db.RunInTx(ctx, func(ctx context.Context) error {
+		queries := s.db.QueriesFromContext(ctx)
+
+		_, err := queries.GetPrivateThreadByOwnerAndTweet(ctx, sqlc_generated.GetPrivateThreadByOwnerAndTweetParams{
+			OwnerID: &ownerID,
+			TweetID: &tweetID,
+			Mode:    mode,
+		})
+		if err == nil {
+			return ErrThreadAlreadyExists
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("check existing private thread: %w", err)
+		}
+
+		thread, err = queries.CreatePrivateThread(ctx, sqlc_generated.CreatePrivateThreadParams{
+			ID:                  uuid.New(),
+			OwnerID:             &ownerID,
+			TweetID:             &tweetID,
+			Mode:                mode,
+			ConversationOptions: options,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create private thread: %w", err)
+		}
+
+		err = queries.CreateThreadKey(ctx, sqlc_generated.CreateThreadKeyParams{
+			ThreadID:   thread.ID,
+			OwnerID:    ownerID,
+			WrappedKey: wrapped,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store thread key: %w", err)
+		}
+
+		_, err = queries.CreateMention(ctx, sqlc_generated.CreateMentionParams{
+			ID:              uuid.New(),
+			UserID:          ownerID,
+			ThreadID:        thread.ID,
+			MentionCreateAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create mention: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ThreadDetail{
+		ID:           thread.ID.String(),
+		CreatedAt:    thread.CreatedAt,
+		Status:       thread.Status,
+		Version:      int(thread.Version),
+		Visibility:   thread.Visibility,
+		Mode:         thread.Mode,
+		Conversation: conversationOptions(thread),
+	}, nil
+}
+
+// IsThreadHidden reports whether the thread exists but may not be read by the
+// viewer of ctx
+func (s *ThreadService) IsThreadHidden(ctx context.Context, id string) (bool, error) {
+	threadID, err := parseID(id)
+	if err != nil {
+		return false, nil
+	}
+	thread, err := s.db.QueriesFromContext(ctx).GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: threadID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get thread: %w", err)
+	}
+	return !canView(ctx, thread), nil
+}
+
+// threadDataKey unwraps the data key of a private thread
+func (s *ThreadService) threadDataKey(ctx context.Context, threadID uuid.UUID) ([]byte, error) {
+	if s.keyring == nil {
+		return nil, ErrPrivateDisabled
+	}
+	row, err := s.db.QueriesFromContext(ctx).GetThreadKey(ctx, sqlc_generated.GetThreadKeyParams{ThreadID: threadID})
+	if err != nil {
+		return nil, fmt.Errorf("get thread key: %w", err)
+	}
+	return s.keyring.UnwrapKey(row.OwnerID, row.WrappedKey)
+}
+
+// storeSealedTweets encrypts tweets with dataKey and stores them as a single
+// file. Sealing uses a random nonce, so the engagement counters stay with the
+// content rather than in the thread row.
+func (s *ThreadService) storeSealedTweets(ctx context.Context, tweets []*xscraper.Tweet, mode string, dataKey []byte) (cid.Cid, error) {
+	encode := archive.EncodeJSONWithStats
+	if mode == ThreadModeConversation {
+		encode = archive.EncodeConversationJSONWithStats
+	}
+	jsonTweets, err := encode(tweets)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to marshal tweets: %w", err)
+	}
+	sealed, err := keyring.Seal(dataKey, jsonTweets)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to encrypt tweets: %w", err)
+	}
+
+	c, err := s.storage.Add(ctx, bytes.NewReader(sealed))
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to add tweets to IPFS: %w", err)
+	}
+	return c, nil
+}
+
+// loadPrivateTweets loads and decrypts the tweets of a private thread. They
+// are not cached, so plaintext never leaves the process.
+func (s *ThreadService) loadPrivateTweets(ctx context.Context, thread sqlc_generated.Thread) ([]*xscraper.Tweet, error) {
+	plaintext, err := s.openPrivateArchive(ctx, thread)
+	if err != nil {
+		return nil, err
+	}
+	tweets, err := archive.DecodeJSON(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tweets: %w", err)
+	}
+	return tweets, nil
+}
+
+// openPrivateArchive loads and decrypts the thread document of a private thread
+func (s *ThreadService) openPrivateArchive(ctx context.Context, thread sqlc_generated.Thread) ([]byte, error) {
+	dataKey, err := s.threadDataKey(ctx, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := cid.Parse(thread.Cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CID: %w", err)
+	}
+
+	reader, err := s.storage.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content from IPFS: %w", err)
+	}
+	defer reader.Close() // nolint:errcheck
+
+	sealed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	plaintext, err := keyring.Open(dataKey, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt tweets: %w", err)
+	}
+	return plaintext, nil
+}
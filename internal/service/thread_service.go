@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,11 +16,14 @@ import (
 	"github.com/eko/gocache/lib/v4/store"
 	redis_store "github.com/eko/gocache/store/redis/v4"
 	"github.com/google/uuid"
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
 	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis"
 	dbsql "github.com/ipfs-force-community/threadmirror/pkg/database/sql"
-	
+
 	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring"
 	"github.com/ipfs-force-community/threadmirror/pkg/llm"
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
 	"github.com/ipfs/go-cid"
@@ -27,18 +32,32 @@ import (
 )
 
 type ThreadDetail struct {
-	ID             string            `json:"id"`
-	CID            string            `json:"cid"`
-	ContentPreview string            `json:"content_preview"`
-	NumTweets      int               `json:"numTweets"`
-	Tweets         []*xscraper.Tweet `json:"tweets,omitempty"`
-	CreatedAt      time.Time         `json:"created_at"`
+	ID             string               `json:"id"`
+	CID            string               `json:"cid"`
+	ContentPreview string               `json:"content_preview"`
+	NumTweets      int                  `json:"numTweets"`
+	Tree           *xscraper.ThreadTree `json:"tree,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
 
 	// New fields for status and author
 	Status     string        `json:"status"`
 	RetryCount int           `json:"retry_count"`
 	Version    int           `json:"version"`
 	Author     *ThreadAuthor `json:"author,omitempty"`
+	// Visibility is public, or private for encrypted archives only their owner can read
+	Visibility string `json:"visibility"`
+	// Mode is thread, or conversation for the reply tree under a tweet
+	Mode string `json:"mode"`
+	// Conversation holds the limits of a conversation, nil in thread mode
+	Conversation *xscraper.ConversationOptions `json:"conversation,omitempty"`
+
+	// PDP describes how the archive is covered by PDP proofs (nil if not stored on PDP)
+	PDP *PDPPiece `json:"pdp,omitempty"`
+
+	// MissingTweets are the tweets of the thread X did not serve when it was scraped
+	MissingTweets []xscraper.Tombstone `json:"missing_tweets,omitempty"`
+	// FailureReason is why a failed thread can never be scraped, empty otherwise
+	FailureReason xscraper.UnavailableReason `json:"failure_reason,omitempty"`
 }
 
 // TweetSlice is a helper type that implements encoding.BinaryMarshaler and
@@ -56,21 +75,37 @@ func (ts *TweetSlice) UnmarshalBinary(data []byte) error {
 }
 
 type ThreadService struct {
-	db      *dbsql.DB
-	storage ipfs.Storage
-	cache   cache.CacheInterface[TweetSlice]
-	llm     llm.Model
-	logger  *slog.Logger
+	db            *dbsql.DB
+	storage       ipfs.Storage
+	mediaArchiver *MediaArchiver
+	pdpPieces     *PDPPieceService
+	signer        *attest.Signer   // nil if attestations are disabled
+	keyring       *keyring.Keyring // nil if private archives are disabled
+	cache         cache.CacheInterface[TweetSlice]
+	llm           llm.Model
+	logger        *slog.Logger
 }
 
-func NewThreadService(db *dbsql.DB, storage ipfs.Storage, llmModel llm.Model, redisClientWrapper *redis.Client, logger *slog.Logger) *ThreadService {
+func NewThreadService(db *dbsql.DB, storage ipfs.Storage, pdpPieces *PDPPieceService, signer *attest.Signer, keyring *keyring.Keyring, llmModel llm.Model, redisClientWrapper *redis.Client, logger *slog.Logger) *ThreadService {
 	redisStore := redis_store.NewRedis(redisClientWrapper.Client)
 	cacheManager := cache.New[TweetSlice](redisStore)
-	return &ThreadService{db: db, storage: storage, cache: cacheManager, llm: llmModel, logger: logger}
+	// Every piece uploaded to PDP must be recorded so it ends up in the proof set
+	storage = pdpPieces.Storage(storage)
+	return &ThreadService{
+		db:            db,
+		storage:       storage,
+		mediaArchiver: NewMediaArchiver(storage, logger),
+		pdpPieces:     pdpPieces,
+		signer:        signer,
+		keyring:       keyring,
+		cache:         cacheManager,
+		llm:           llmModel,
+		logger:        logger,
+	}
 }
 
 func (s *ThreadService) GetThreadByID(ctx context.Context, id string) (*ThreadDetail, error) {
-	threadID, err := uuid.Parse(id)
+	threadID, err := parseID(id)
 	if err != nil {
 		return nil, fmt.Errorf("invalid thread ID: %w", err)
 	}
@@ -82,14 +117,31 @@ func (s *ThreadService) GetThreadByID(ctx context.Context, id string) (*ThreadDe
 		}
 		return nil, fmt.Errorf("get thread: %w", err)
 	}
+	// Private threads do not exist for anyone but their owner
+	if !canView(ctx, thread) {
+		return nil, ErrThreadNotFound
+	}
 
 	// Load tweets from IPFS (only if completed and has CID)
-	var tweets []*xscraper.Tweet
+	var tree *xscraper.ThreadTree
 	if thread.Status == "completed" && thread.Cid != "" {
-		tweets, err = s.loadTweetsFromIPFS(ctx, thread.Cid)
+		var tweets []*xscraper.Tweet
+		if thread.Visibility == ThreadVisibilityPrivate {
+			tweets, err = s.loadPrivateTweets(ctx, thread)
+		} else {
+			tweets, err = s.loadTweetsFromIPFS(ctx, thread.Cid)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("load from ipfs %s: %w", thread.Cid, err)
 		}
+		// Private threads keep no author columns, the owner gets it from the content
+		if thread.Visibility == ThreadVisibilityPrivate && len(tweets) > 0 {
+			thread.AuthorID, thread.AuthorName, thread.AuthorScreenName, thread.AuthorProfileImageUrl = threadAuthorFields(tweets, thread.Mode)
+		}
+		if err := applyThreadStats(tweets, thread.Stats); err != nil {
+			s.logger.Warn("failed to apply thread stats", "threadID", id, "error", err)
+		}
+		tree = xscraper.NewThreadTree(tweets)
 	}
 
 	// Build author info if available
@@ -103,17 +155,44 @@ func (s *ThreadService) GetThreadByID(ctx context.Context, id string) (*ThreadDe
 		}
 	}
 
+	var missing []xscraper.Tombstone
+	if len(thread.MissingTweets) > 0 {
+		if err := json.Unmarshal(thread.MissingTweets, &missing); err != nil {
+			s.logger.Warn("failed to unmarshal missing tweets", "threadID", id, "error", err)
+		}
+	}
+
+	var pdpPiece *PDPPiece
+	if thread.Cid != "" && s.pdpPieces.Enabled() {
+		pieceCID, err := s.pdpPieces.PieceCID(ctx, thread.Cid)
+		if err != nil {
+			return nil, err
+		}
+		if pieceCID != "" {
+			pdpPiece, err = s.pdpPieces.GetPiece(ctx, pieceCID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &ThreadDetail{
 		ID:             thread.ID.String(),
 		CID:            thread.Cid,
 		ContentPreview: thread.Summary,
 		NumTweets:      int(thread.NumTweets),
-		Tweets:         tweets,
+		Tree:           tree,
 		CreatedAt:      thread.CreatedAt,
 		Status:         thread.Status,
 		RetryCount:     int(thread.RetryCount),
 		Version:        int(thread.Version),
 		Author:         author,
+		Visibility:     thread.Visibility,
+		Mode:           thread.Mode,
+		Conversation:   conversationOptions(thread),
+		PDP:            pdpPiece,
+		MissingTweets:  missing,
+		FailureReason:  xscraper.UnavailableReason(getStringValue(thread.FailureReason)),
 	}, nil
 }
 
@@ -144,6 +223,57 @@ func (s *ThreadService) loadTweetsFromIPFS(ctx context.Context, cidStr string) (
 		return nil, fmt.Errorf("failed to parse CID: %w", err)
 	}
 
+	var tweets []*xscraper.Tweet
+	if archive.IsDAG(c) {
+		tweets, err = s.loadTweetsFromDAG(ctx, c)
+	} else {
+		tweets, err = s.loadTweetsFromJSON(ctx, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the result for future requests
+	if s.cache != nil {
+		// Permanent cache (no TTL), rely on Redis allkeys-lru eviction
+		key := cacheKeyForThread(cidStr)
+		err = s.cache.Set(ctx, key, TweetSlice(tweets))
+		if err != nil {
+			s.logger.Error("failed to set cache", "error", err)
+		}
+	}
+	return tweets, nil
+}
+
+// loadTweetsFromDAG loads tweets stored in the per-tweet DAG layout
+func (s *ThreadService) loadTweetsFromDAG(ctx context.Context, c cid.Cid) ([]*xscraper.Tweet, error) {
+	bs, ok := s.storage.(ipfs.BlockStorage)
+	if !ok {
+		return nil, fmt.Errorf("storage backend does not support IPLD blocks, cannot load %s", c)
+	}
+
+	tweets, err := archive.Decode(ctx, bs, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode thread DAG: %w", err)
+	}
+	return tweets, nil
+}
+
+// loadTweetsFromJSON loads tweets stored as a single JSON thread document
+func (s *ThreadService) loadTweetsFromJSON(ctx context.Context, c cid.Cid) ([]*xscraper.Tweet, error) {
+	data, err := s.readArchiveFile(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	tweets, err := archive.DecodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tweets: %w", err)
+	}
+	return tweets, nil
+}
+
+// readArchiveFile reads a thread stored as a single file
+func (s *ThreadService) readArchiveFile(ctx context.Context, c cid.Cid) ([]byte, error) {
 	// Get content from IPFS
 	reader, err := s.storage.Get(ctx, c)
 	if err != nil {
@@ -158,23 +288,62 @@ func (s *ThreadService) loadTweetsFromIPFS(ctx context.Context, cidStr string) (
 		return nil, fmt.Errorf("failed to read content: %w", err)
 	}
 
-	// Unmarshal tweets
-	var tweets []*xscraper.Tweet
-	err = json.Unmarshal(buffer.Bytes(), &tweets)
+	return buffer.Bytes(), nil
+}
+
+// applyThreadStats sets the engagement counters stored with a thread on its
+// archived tweets
+func applyThreadStats(tweets []*xscraper.Tweet, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var stats archive.Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return err
+	}
+	stats.Apply(tweets)
+	return nil
+}
+
+// GetMedia opens an archived media file by its CID. Only media archived with a
+// thread the viewer of ctx may read is served, anything else is ErrMediaNotFound,
+// so storage never serves content that was not archived here.
+func (s *ThreadService) GetMedia(ctx context.Context, cidStr string) (io.ReadCloser, error) {
+	c, err := cid.Parse(cidStr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tweets: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCID, err)
 	}
 
-	// Cache the result for future requests
-	if s.cache != nil {
-		// Permanent cache (no TTL), rely on Redis allkeys-lru eviction
-		key := cacheKeyForThread(cidStr)
-		err = s.cache.Set(ctx, key, TweetSlice(tweets))
-		if err != nil {
-			s.logger.Error("failed to set cache", "error", err)
-		}
+	threads, err := s.db.QueriesFromContext(ctx).ListThreadsByMedia(ctx, sqlc_generated.ListThreadsByMediaParams{Cid: c.String()})
+	if err != nil {
+		return nil, fmt.Errorf("list threads with media: %w", err)
 	}
-	return tweets, nil
+	if !slices.ContainsFunc(threads, func(thread sqlc_generated.Thread) bool { return canView(ctx, thread) }) {
+		return nil, ErrMediaNotFound
+	}
+
+	reader, err := s.storage.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media from IPFS: %w", err)
+	}
+	return reader, nil
+}
+
+// recordThreadMedia records the archived media and avatars of tweets as
+// belonging to the thread, so that GetMedia serves them
+func (s *ThreadService) recordThreadMedia(ctx context.Context, threadID uuid.UUID, media []cid.Cid) error {
+	if len(media) == 0 {
+		return nil
+	}
+	cids := make([]string, len(media))
+	for i, c := range media {
+		cids[i] = c.String()
+	}
+	err := s.db.QueriesFromContext(ctx).AddThreadMedia(ctx, sqlc_generated.AddThreadMediaParams{ThreadID: threadID, Cids: cids})
+	if err != nil {
+		return fmt.Errorf("record thread media: %w", err)
+	}
+	return nil
 }
 
 // cacheKeyForThread returns a namespaced cache key for storing tweets by CID
@@ -184,7 +353,7 @@ func cacheKeyForThread(cid string) string {
 
 // UpdateThreadStatus updates thread status with optimistic locking
 func (s *ThreadService) UpdateThreadStatus(ctx context.Context, threadID string, status string, version int) error {
-	threadUUID, err := uuid.Parse(threadID)
+	threadUUID, err := parseID(threadID)
 	if err != nil {
 		return fmt.Errorf("invalid thread ID: %w", err)
 	}
@@ -202,6 +371,28 @@ func (s *ThreadService) UpdateThreadStatus(ctx context.Context, threadID string,
 	return nil
 }
 
+// MarkThreadUnavailable fails a thread whose tweet X will never serve, so that
+// it is not retried and its viewers learn why
+func (s *ThreadService) MarkThreadUnavailable(ctx context.Context, threadID string, reason xscraper.UnavailableReason, version int) error {
+	threadUUID, err := parseID(threadID)
+	if err != nil {
+		return fmt.Errorf("invalid thread ID: %w", err)
+	}
+
+	failureReason := string(reason)
+	err = s.db.QueriesFromContext(ctx).UpdateThreadUnavailable(ctx, sqlc_generated.UpdateThreadUnavailableParams{
+		ThreadID:       threadUUID,
+		FailureReason:  &failureReason,
+		CurrentVersion: int32(version),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark thread unavailable: %w", err)
+	}
+
+	s.logger.Info("thread marked unavailable", "threadID", threadID, "reason", reason, "version", version+1)
+	return nil
+}
+
 // GetStuckScrapingThreadsForRetry gets threads that have been in 'scraping' status for too long and increments their retry count
 func (s *ThreadService) GetStuckScrapingThreadsForRetry(ctx context.Context, stuckDuration time.Duration, maxRetries int) ([]sqlc_generated.Thread, error) {
 	cutoffTime := time.Now().Add(-stuckDuration)
@@ -337,47 +528,79 @@ func (s *ThreadService) GetFailedThreadsForRetry(ctx context.Context, retryDelay
 	return threads, nil
 }
 
-// UpdateThreadWithScrapedData updates thread with complete scraped data including summary generation and IPFS upload
+// UpdateThreadWithScrapedData updates thread with complete scraped data including summary generation and IPFS upload,
+// and attests the provenance of the archive. missing records the tweets of the thread X did not serve.
 func (s *ThreadService) UpdateThreadWithScrapedData(
 	ctx context.Context,
 	threadID string,
 	tweets []*xscraper.Tweet,
+	missing []xscraper.Tombstone,
 	version int,
+	provenance ScrapeProvenance,
 ) error {
 	if len(tweets) == 0 {
 		return fmt.Errorf("no tweets provided")
 	}
 
-	threadUUID, err := uuid.Parse(threadID)
+	threadUUID, err := parseID(threadID)
 	if err != nil {
 		return fmt.Errorf("invalid thread ID: %w", err)
 	}
 
-	// Generate summary using the same logic as MentionService
-	summary, err := s.generateTweetsSummary(ctx, tweets)
+	thread, err := s.db.QueriesFromContext(ctx).GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: threadUUID})
 	if err != nil {
-		return fmt.Errorf("failed to generate AI summary: %w", err)
+		return fmt.Errorf("get thread: %w", err)
 	}
+	private := thread.Visibility == ThreadVisibilityPrivate
 
-	// Marshal and upload tweets to IPFS
-	jsonTweets, err := json.Marshal(tweets)
-	if err != nil {
-		return fmt.Errorf("failed to marshal tweets: %w", err)
-	}
+	var cid cid.Cid
+	var stats []byte
+	var summary string
+	var authorID, authorName, authorScreenName, authorProfileImageURL *string
+	if private {
+		// The summary and author columns stay empty: they are stored in plaintext,
+		// and summarising would hand the tweets to the LLM provider
+		// Media stays on X's CDN: archived copies would be readable by anyone with the CID
+		dataKey, err := s.threadDataKey(ctx, threadUUID)
+		if err != nil {
+			return err
+		}
+		cid, err = s.storeSealedTweets(ctx, tweets, thread.Mode, dataKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Generate summary using the same logic as MentionService
+		summary, err = s.generateTweetsSummary(ctx, tweets)
+		if err != nil {
+			return fmt.Errorf("failed to generate AI summary: %w", err)
+		}
+		authorID, authorName, authorScreenName, authorProfileImageURL = threadAuthorFields(tweets, thread.Mode)
 
-	cid, err := s.storage.Add(ctx, bytes.NewReader(jsonTweets))
-	if err != nil {
-		return fmt.Errorf("failed to add tweets to IPFS: %w", err)
+		// Copy media and avatars into storage so the archive does not depend on X's CDN
+		s.mediaArchiver.ArchiveTweets(ctx, tweets)
+
+		cid, err = s.storeTweets(ctx, tweets, thread.Mode)
+		if err != nil {
+			return err
+		}
+
+		_, threadStats := archive.SplitStats(tweets)
+		stats, err = json.Marshal(threadStats)
+		if err != nil {
+			return fmt.Errorf("failed to marshal stats: %w", err)
+		}
+		if thread.Cid == cid.String() {
+			s.logger.Info("thread content unchanged since last scrape", "threadID", threadID, "cid", thread.Cid)
+		}
 	}
 
-	// Set author information from last tweet (which is the thread starter)
-	var authorID, authorName, authorScreenName, authorProfileImageURL *string
-	author := tweets[len(tweets)-1].Author
-	if author != nil {
-		authorID = &author.RestID
-		authorName = &author.Name
-		authorScreenName = &author.ScreenName
-		authorProfileImageURL = &author.ProfileImageURL
+	var missingTweets []byte
+	if len(missing) > 0 {
+		missingTweets, err = json.Marshal(missing)
+		if err != nil {
+			return fmt.Errorf("failed to marshal missing tweets: %w", err)
+		}
 	}
 
 	// Update thread with scraped data using optimistic locking
@@ -393,15 +616,77 @@ func (s *ThreadService) UpdateThreadWithScrapedData(
 		AuthorName:            authorName,
 		AuthorScreenName:      authorScreenName,
 		AuthorProfileImageUrl: authorProfileImageURL,
+		Stats:                 stats,
+		MissingTweets:         missingTweets,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update thread: %w", err)
 	}
 
 	s.logger.Info("thread updated successfully", "threadID", threadID, "version", version)
+
+	// Attestations are public and would reveal what a private archive holds
+	if private {
+		return nil
+	}
+
+	// Media left unrecorded is picked up by the next integrity check
+	if err := s.recordThreadMedia(ctx, threadUUID, archivedMediaCIDs(tweets)); err != nil {
+		s.logger.Error("failed to record thread media", "threadID", threadID, "error", err)
+	}
+
+	// The archive is complete either way; a missing attestation only loses provenance
+	if err := s.attestThread(ctx, threadUUID, tweets, cid, provenance); err != nil {
+		s.logger.Error("failed to attest thread", "threadID", threadID, "error", err)
+	}
 	return nil
 }
 
+// storeTweets stores tweets as a thread (or conversation, depending on mode) DAG
+// when the backend supports raw blocks, and falls back to a single JSON file otherwise
+func (s *ThreadService) storeTweets(ctx context.Context, tweets []*xscraper.Tweet, mode string) (cid.Cid, error) {
+	conversation := mode == ThreadModeConversation
+	if bs, ok := s.storage.(ipfs.BlockStorage); ok {
+		encode := archive.Encode
+		if conversation {
+			encode = archive.EncodeConversation
+		}
+		c, err := encode(ctx, bs, tweets)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("failed to add thread DAG to IPFS: %w", err)
+		}
+		return c, nil
+	}
+
+	encode := archive.EncodeJSON
+	if conversation {
+		encode = archive.EncodeConversationJSON
+	}
+	jsonTweets, err := encode(tweets)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to marshal tweets: %w", err)
+	}
+
+	c, err := s.storage.Add(ctx, bytes.NewReader(jsonTweets))
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to add tweets to IPFS: %w", err)
+	}
+	return c, nil
+}
+
+// threadAuthorFields returns the thread author columns, taken from the last tweet
+// (which is the thread starter), or the root of a conversation
+func threadAuthorFields(tweets []*xscraper.Tweet, mode string) (id, name, screenName, profileImageURL *string) {
+	author := tweets[len(tweets)-1].Author
+	if mode == ThreadModeConversation {
+		author = tweets[0].Author
+	}
+	if author == nil {
+		return nil, nil, nil, nil
+	}
+	return &author.RestID, &author.Name, &author.ScreenName, &author.ProfileImageURL
+}
+
 // generateTweetsSummary generates AI summary for tweets using LLM
 func (s *ThreadService) generateTweetsSummary(ctx context.Context, tweets []*xscraper.Tweet) (string, error) {
 	if len(tweets) == 0 {
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"os"
@@ -10,10 +11,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/google/uuid"
 	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
 	"github.com/ipfs-force-community/threadmirror/internal/testsuit"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/redis"
 	"github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
 )
 
 var _ = Describe("ThreadService", func() {
@@ -37,6 +42,9 @@ var _ = Describe("ThreadService", func() {
 		threadService = service.NewThreadService(
 			db,
 			&testsuit.MockIPFSStorage{},
+			service.NewPDPPieceService(db, &testsuit.MockIPFSStorage{}, slog.Default()),
+			nil,
+			nil,
 			&testsuit.MockLLM{},
 			redisClient,
 			slog.New(slog.NewTextHandler(os.Stdout, nil)),
@@ -66,6 +74,109 @@ var _ = Describe("ThreadService", func() {
 		})
 	})
 
+	Describe("CreatePrivateThread", func() {
+		It("should fail when private archives are not enabled", func() {
+			_, err := threadService.CreatePrivateThread(ctx, "user-1", "1234567890", nil)
+			Expect(err).To(MatchError(service.ErrPrivateDisabled))
+		})
+
+		It("should only show the thread to its owner", func() {
+			kr, err := keyring.New(bytes.Repeat([]byte{1}, keyring.KeySize))
+			Expect(err).ToNot(HaveOccurred())
+			privateService := service.NewThreadService(
+				db,
+				&testsuit.MockIPFSStorage{},
+				service.NewPDPPieceService(db, &testsuit.MockIPFSStorage{}, slog.Default()),
+				nil,
+				kr,
+				&testsuit.MockLLM{},
+				redisClient,
+				slog.Default(),
+			)
+
+			thread, err := privateService.CreatePrivateThread(ctx, "user-1", "1234567890", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(thread.Visibility).To(Equal(service.ThreadVisibilityPrivate))
+
+			_, err = privateService.CreatePrivateThread(ctx, "user-1", "1234567890", nil)
+			Expect(err).To(MatchError(service.ErrThreadAlreadyExists))
+
+			owned, err := privateService.GetThreadByID(service.WithViewer(ctx, "user-1"), thread.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(owned.ID).To(Equal(thread.ID))
+
+			_, err = privateService.GetThreadByID(service.WithViewer(ctx, "user-2"), thread.ID)
+			Expect(err).To(MatchError(service.ErrThreadNotFound))
+			_, err = privateService.GetThreadByID(ctx, thread.ID)
+			Expect(err).To(MatchError(service.ErrThreadNotFound))
+
+			hidden, err := privateService.IsThreadHidden(ctx, thread.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hidden).To(BeTrue())
+
+			// The conversation of the same tweet is a separate archive
+			conversation, err := privateService.CreatePrivateThread(ctx, "user-1", "1234567890", &xscraper.ConversationOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conversation.ID).ToNot(Equal(thread.ID))
+			Expect(conversation.Mode).To(Equal(service.ThreadModeConversation))
+		})
+
+		It("should keep the content out of the thread row", func() {
+			kr, err := keyring.New(bytes.Repeat([]byte{1}, keyring.KeySize))
+			Expect(err).ToNot(HaveOccurred())
+			privateService := service.NewThreadService(
+				db,
+				&testsuit.MockIPFSStorage{},
+				service.NewPDPPieceService(db, &testsuit.MockIPFSStorage{}, slog.Default()),
+				nil,
+				kr,
+				&testsuit.MockLLM{},
+				redisClient,
+				slog.Default(),
+			)
+
+			thread, err := privateService.CreatePrivateThread(ctx, "user-1", "1234567890", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			tweets := []*xscraper.Tweet{{
+				RestID: "1234567890",
+				Text:   "for my eyes only",
+				Author: &xscraper.User{RestID: "42", Name: "Alice", ScreenName: "alice"},
+			}}
+			err = privateService.UpdateThreadWithScrapedData(ctx, thread.ID, tweets, nil, thread.Version, service.ScrapeProvenance{})
+			Expect(err).ToNot(HaveOccurred())
+
+			row, err := db.QueriesFromContext(ctx).GetThreadByID(ctx, sqlc_generated.GetThreadByIDParams{ThreadID: uuid.MustParse(thread.ID)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(row.Status).To(Equal("completed"))
+			Expect(row.Summary).To(BeEmpty())
+			Expect(row.AuthorID).To(BeNil())
+			Expect(row.AuthorName).To(BeNil())
+			Expect(row.AuthorScreenName).To(BeNil())
+			Expect(row.AuthorProfileImageUrl).To(BeNil())
+		})
+	})
+
+	Describe("GetOrCreateConversationThread", func() {
+		It("should share one conversation per tweet and limits", func() {
+			opts := xscraper.ConversationOptions{MaxDepth: 50, MinLikes: 5}
+			first, err := threadService.GetOrCreateConversationThread(ctx, "1234567890", opts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.Mode).To(Equal(service.ThreadModeConversation))
+			Expect(*first.Conversation).To(Equal(opts.Normalize()))
+
+			same, err := threadService.GetOrCreateConversationThread(ctx, "1234567890", opts.Normalize())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(same.ID).To(Equal(first.ID))
+
+			// Other limits get an archive of their own
+			defaults, err := threadService.GetOrCreateConversationThread(ctx, "1234567890", xscraper.ConversationOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(defaults.ID).ToNot(Equal(first.ID))
+			Expect(*defaults.Conversation).To(Equal(xscraper.DefaultConversationOptions))
+		})
+	})
+
 	Describe("UpdateThreadStatus", func() {
 		It("should return error for non-existent thread", func() {
 			err := threadService.UpdateThreadStatus(ctx, "nonexistent-id", "completed", 1)
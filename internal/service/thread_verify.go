@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/archive"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs/go-cid"
+	"github.com/samber/lo"
+)
+
+// Integrity check outcomes of an archived thread
+const (
+	ThreadVerificationPass        = "pass"
+	ThreadVerificationMismatch    = "mismatch"
+	ThreadVerificationUnreachable = "unreachable"
+)
+
+// ThreadVerification is the result of the last integrity check of a thread
+type ThreadVerification struct {
+	ThreadID string `json:"thread_id"`
+	CID      string `json:"cid"`
+	Status   string `json:"status"`
+	// FailedCID is the thread itself or one of its archived media
+	FailedCID           string     `json:"failed_cid,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CheckedAt           time.Time  `json:"checked_at"`
+	RepairQueuedAt      *time.Time `json:"repair_queued_at,omitempty"`
+}
+
+// ThreadVerifyResult summarizes a VerifyThreads run
+type ThreadVerifyResult struct {
+	Checked      int
+	Passed       int
+	Mismatched   int
+	Unreachable  int
+	RepairQueued int
+}
+
+// VerifyThreads checks up to limit completed threads that were not checked
+// within recheckAfter, least recently checked first. The content of each thread
+// and of its archived media is read back from storage and its CID recomputed
+// (CommP for pieces, UnixFS for Kubo files, the block hash for DAG blocks).
+//
+// The media of public threads that pass is recorded for the thread, which
+// also serves media archived before media was recorded.
+//
+// With queueRepair, the replicas of the replicated storage backend holding a
+// failed copy are marked missing so that storage repair copies the content
+// over from an intact replica.
+func (s *ThreadService) VerifyThreads(ctx context.Context, limit int, recheckAfter time.Duration, queueRepair bool) (*ThreadVerifyResult, error) {
+	queries := s.db.QueriesFromContext(ctx)
+	threads, err := queries.ListThreadsToVerify(ctx, sqlc_generated.ListThreadsToVerifyParams{
+		CheckedBefore: time.Now().Add(-recheckAfter),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list threads to verify: %w", err)
+	}
+
+	result := &ThreadVerifyResult{}
+	for _, thread := range threads {
+		logger := s.logger.With("thread_id", thread.ID, "cid", thread.Cid)
+
+		params := sqlc_generated.UpsertThreadVerificationParams{
+			ThreadID: thread.ID,
+			Cid:      thread.Cid,
+			Status:   ThreadVerificationPass,
+		}
+
+		media, err := s.verifyArchive(ctx, thread.Cid, thread.Visibility == ThreadVisibilityPrivate)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Checked++
+		if err == nil {
+			if err := s.recordThreadMedia(ctx, thread.ID, media); err != nil {
+				logger.Warn("failed to record thread media", "error", err)
+			}
+		}
+
+		if err != nil {
+			failedCID := thread.Cid
+			params.Status = ThreadVerificationUnreachable
+			var verifyErr *ipfs.VerifyError
+			if errors.As(err, &verifyErr) {
+				failedCID = verifyErr.CID.String()
+				if errors.Is(err, ipfs.ErrCIDMismatch) {
+					params.Status = ThreadVerificationMismatch
+				}
+				if queueRepair && s.queueRepair(ctx, verifyErr.CID) {
+					params.RepairQueuedAt = lo.ToPtr(time.Now())
+					result.RepairQueued++
+				}
+			}
+			params.FailedCid = &failedCID
+			params.LastError = lo.ToPtr(err.Error())
+			logger.Warn("thread failed verification", "status", params.Status, "failed_cid", failedCID, "error", err)
+		}
+
+		switch params.Status {
+		case ThreadVerificationPass:
+			result.Passed++
+		case ThreadVerificationMismatch:
+			result.Mismatched++
+		default:
+			result.Unreachable++
+		}
+
+		if err := queries.UpsertThreadVerification(ctx, params); err != nil {
+			return result, fmt.Errorf("record verification of thread %s: %w", thread.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// verifyArchive checks the archived thread at cidStr and its archived media,
+// and returns the CIDs of the media. A private archive is a single encrypted
+// file, checked without decrypting it.
+func (s *ThreadService) verifyArchive(ctx context.Context, cidStr string, private bool) ([]cid.Cid, error) {
+	root, err := cid.Parse(cidStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CID: %w", err)
+	}
+
+	// The DAG layout links its media; a legacy JSON blob only mentions the CIDs
+	var files []cid.Cid
+	if archive.IsDAG(root) {
+		bs, ok := s.storage.(ipfs.BlockStorage)
+		if !ok {
+			return nil, fmt.Errorf("storage backend does not support IPLD blocks, cannot verify %s", root)
+		}
+		if files, err = archive.VerifyDAG(ctx, bs, root); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := ipfs.VerifyContent(ctx, s.storage, root); err != nil {
+			return nil, err
+		}
+		if private {
+			return nil, nil
+		}
+		tweets, err := s.loadTweetsFromJSON(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		files = archivedMediaCIDs(tweets)
+	}
+
+	for _, c := range files {
+		if !ipfs.CanRecompute(c) {
+			s.logger.Debug("skipping media with unverifiable CID", "cid", c)
+			continue
+		}
+		if err := ipfs.VerifyContent(ctx, s.storage, c); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// queueRepair marks the replicas holding a failed copy of c as missing, and
+// reports whether any were marked
+func (s *ThreadService) queueRepair(ctx context.Context, c cid.Cid) bool {
+	replicated, ok := s.storage.(ipfs.ReplicatedStorage)
+	if !ok {
+		return false
+	}
+	marked, err := replicated.CheckReplicas(ctx, c, archive.IsDAG(c))
+	if err != nil {
+		s.logger.Warn("failed to queue repair", "cid", c, "error", err)
+		return false
+	}
+	return len(marked) > 0
+}
+
+// GetFailedVerifications returns the threads whose last integrity check failed,
+// most recently checked first
+func (s *ThreadService) GetFailedVerifications(ctx context.Context, limit, offset int) ([]ThreadVerification, int64, error) {
+	queries := s.db.QueriesFromContext(ctx)
+	rows, err := queries.ListFailedThreadVerifications(ctx, sqlc_generated.ListFailedThreadVerificationsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list failed thread verifications: %w", err)
+	}
+	total, err := queries.CountFailedThreadVerifications(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count failed thread verifications: %w", err)
+	}
+
+	verifications := lo.Map(rows, func(row sqlc_generated.ThreadVerification, _ int) ThreadVerification {
+		return ThreadVerification{
+			ThreadID:            row.ThreadID.String(),
+			CID:                 row.Cid,
+			Status:              row.Status,
+			FailedCID:           getStringValue(row.FailedCid),
+			LastError:           getStringValue(row.LastError),
+			ConsecutiveFailures: int(row.ConsecutiveFailures),
+			CheckedAt:           row.CheckedAt,
+			RepairQueuedAt:      row.RepairQueuedAt,
+		}
+	})
+	return verifications, total, nil
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
+	dbsql "github.com/ipfs-force-community/threadmirror/pkg/database/sql"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/merkle"
+	"github.com/ipfs/go-cid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
+)
+
+// TreeHeadPayloadType is the DSSE payload type of a signed tree head
+const TreeHeadPayloadType = "application/vnd.threadmirror.tree-head+json"
+
+var (
+	// ErrTreeHeadNotFound means no tree head of the requested size was published
+	ErrTreeHeadNotFound = errors.New("tree head not found")
+	// ErrNotInLog means the CID is not included in the transparency log
+	ErrNotInLog = errors.New("CID is not in the transparency log")
+)
+
+// LogEntry is an entry of the transparency log. The leaf hash covers its JSON
+// encoding, which is returned with inclusion proofs.
+type LogEntry struct {
+	ThreadID string `json:"thread_id"`
+	CID      string `json:"cid"`
+}
+
+// TreeHead is the payload of a signed tree head
+type TreeHead struct {
+	TreeSize int64 `json:"tree_size"`
+	// RootHash is the hex RFC 6962 Merkle tree hash of the first TreeSize entries
+	RootHash  string    `json:"root_hash"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SignedTreeHead is a published tree head
+type SignedTreeHead struct {
+	TreeHead
+	// HeadCID is the CID the signed envelope is stored under
+	HeadCID  string
+	KeyID    string
+	Envelope *attest.Envelope
+	// PublicKey is the PEM of the signing key, if it is the current one
+	PublicKey string
+}
+
+// InclusionProof proves that an entry is part of a published tree
+type InclusionProof struct {
+	LeafIndex int64
+	TreeSize  int64
+	Entry     LogEntry
+	// RawEntry is the exact JSON the leaf hash was computed over
+	RawEntry  string
+	LeafHash  string
+	AuditPath []string
+}
+
+// ConsistencyProof proves that a published tree is a prefix of a later one
+type ConsistencyProof struct {
+	First  int64
+	Second int64
+	Proof  []string
+}
+
+// TransparencyLogService keeps an append-only Merkle log of archived thread CIDs
+type TransparencyLogService struct {
+	db      *dbsql.DB
+	storage ipfs.Storage
+	signer  *attest.Signer
+	logger  *slog.Logger
+}
+
+// NewTransparencyLogService creates a new transparency log service. Tree heads
+// are only published with a signer.
+func NewTransparencyLogService(db *dbsql.DB, storage ipfs.Storage, signer *attest.Signer, logger *slog.Logger) *TransparencyLogService {
+	return &TransparencyLogService{
+		db:      db,
+		storage: storage,
+		signer:  signer,
+		logger:  logger.With("service", "transparency_log"),
+	}
+}
+
+// Sequence appends the current CIDs of up to limit completed threads that are
+// not in the log yet, and returns how many entries were appended
+func (s *TransparencyLogService) Sequence(ctx context.Context, limit int) (int, error) {
+	tx, err := s.db.Pool().Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // nolint:errcheck
+
+	queries := s.db.QueriesFromContext(ctx).WithTx(tx)
+	if err := queries.LockTransparencyLog(ctx); err != nil {
+		return 0, fmt.Errorf("lock transparency log: %w", err)
+	}
+
+	threads, err := queries.ListThreadsToSequence(ctx, sqlc_generated.ListThreadsToSequenceParams{Limit: int32(limit)})
+	if err != nil {
+		return 0, fmt.Errorf("list threads to sequence: %w", err)
+	}
+	if len(threads) == 0 {
+		return 0, nil
+	}
+
+	size, err := queries.GetTransparencyLogSize(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get transparency log size: %w", err)
+	}
+	for i, thread := range threads {
+		entry, err := json.Marshal(LogEntry{ThreadID: thread.ID.String(), CID: thread.Cid})
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal log entry: %w", err)
+		}
+		err = queries.InsertTransparencyLogLeaf(ctx, sqlc_generated.InsertTransparencyLogLeafParams{
+			LeafIndex: size + int64(i),
+			ThreadID:  thread.ID,
+			Cid:       thread.Cid,
+			Entry:     entry,
+			LeafHash:  merkle.LeafHash(entry),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("append %s of thread %s: %w", thread.Cid, thread.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return len(threads), nil
+}
+
+// PublishTreeHead signs the current tree head, stores it in IPFS and records
+// it. It returns nil if the log did not grow since the last tree head, or
+// without a signer.
+func (s *TransparencyLogService) PublishTreeHead(ctx context.Context) (*SignedTreeHead, error) {
+	if s.signer == nil {
+		return nil, nil
+	}
+
+	queries := s.db.QueriesFromContext(ctx)
+	size, err := queries.GetTransparencyLogSize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get transparency log size: %w", err)
+	}
+	latest, err := queries.GetLatestTransparencyLogHead(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get latest tree head: %w", err)
+	}
+	if err == nil && latest.TreeSize >= size {
+		return nil, nil
+	}
+
+	leaves, err := s.leafHashes(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+	root := merkle.RootHash(leaves)
+	head := TreeHead{
+		TreeSize:  size,
+		RootHash:  hex.EncodeToString(root),
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+	}
+	payload, err := json.Marshal(head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tree head: %w", err)
+	}
+	envelope, err := s.signer.SignPayload(TreeHeadPayloadType, payload)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tree head envelope: %w", err)
+	}
+	headCID, err := s.storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to add tree head to IPFS: %w", err)
+	}
+
+	err = queries.InsertTransparencyLogHead(ctx, sqlc_generated.InsertTransparencyLogHeadParams{
+		TreeSize: head.TreeSize,
+		RootHash: root,
+		HeadCid:  headCID.String(),
+		KeyID:    s.signer.KeyID(),
+		SignedAt: head.Timestamp,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record tree head: %w", err)
+	}
+
+	return &SignedTreeHead{
+		TreeHead: head,
+		HeadCID:  headCID.String(),
+		KeyID:    s.signer.KeyID(),
+		Envelope: envelope,
+	}, nil
+}
+
+// GetTreeHead returns the published tree head of treeSize, or the latest one
+// if treeSize is nil
+func (s *TransparencyLogService) GetTreeHead(ctx context.Context, treeSize *int64) (*SignedTreeHead, error) {
+	row, err := s.getHead(ctx, treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := s.loadEnvelope(ctx, row.HeadCid)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := envelope.DecodePayload()
+	if err != nil {
+		return nil, err
+	}
+	var head TreeHead
+	if err := json.Unmarshal(payload, &head); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tree head: %w", err)
+	}
+
+	sth := &SignedTreeHead{
+		TreeHead: head,
+		HeadCID:  row.HeadCid,
+		KeyID:    row.KeyID,
+		Envelope: envelope,
+	}
+	if s.signer != nil && s.signer.KeyID() == row.KeyID {
+		if sth.PublicKey, err = attest.MarshalPublicKey(s.signer.PublicKey()); err != nil {
+			return nil, err
+		}
+	}
+	return sth, nil
+}
+
+// GetInclusionProof proves that content CID c is included in the published
+// tree of treeSize, or the latest one if treeSize is nil
+func (s *TransparencyLogService) GetInclusionProof(ctx context.Context, c string, treeSize *int64) (*InclusionProof, error) {
+	head, err := s.getHead(ctx, treeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := s.db.QueriesFromContext(ctx).GetTransparencyLogLeafByCID(ctx, sqlc_generated.GetTransparencyLogLeafByCIDParams{Cid: c})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotInLog
+		}
+		return nil, fmt.Errorf("get transparency log leaf: %w", err)
+	}
+	if leaf.LeafIndex >= head.TreeSize {
+		return nil, fmt.Errorf("%w: not included in the tree of size %d yet", ErrNotInLog, head.TreeSize)
+	}
+
+	leaves, err := s.leafHashes(ctx, head.TreeSize)
+	if err != nil {
+		return nil, err
+	}
+	path, err := merkle.InclusionProof(int(leaf.LeafIndex), leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InclusionProof{
+		LeafIndex: leaf.LeafIndex,
+		TreeSize:  head.TreeSize,
+		Entry:     LogEntry{ThreadID: leaf.ThreadID.String(), CID: leaf.Cid},
+		RawEntry:  string(leaf.Entry),
+		LeafHash:  hex.EncodeToString(leaf.LeafHash),
+		AuditPath: lo.Map(path, func(h []byte, _ int) string { return hex.EncodeToString(h) }),
+	}, nil
+}
+
+// GetConsistencyProof proves that the published tree of size first is a prefix
+// of the published tree of size second, or of the latest one if second is nil
+func (s *TransparencyLogService) GetConsistencyProof(ctx context.Context, first int64, second *int64) (*ConsistencyProof, error) {
+	to, err := s.getHead(ctx, second)
+	if err != nil {
+		return nil, err
+	}
+	if first > to.TreeSize {
+		return nil, fmt.Errorf("%w: first tree size %d is larger than %d", ErrInvalidInput, first, to.TreeSize)
+	}
+	if _, err := s.getHead(ctx, &first); err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.leafHashes(ctx, to.TreeSize)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := merkle.ConsistencyProof(int(first), leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsistencyProof{
+		First:  first,
+		Second: to.TreeSize,
+		Proof:  lo.Map(proof, func(h []byte, _ int) string { return hex.EncodeToString(h) }),
+	}, nil
+}
+
+// getHead returns the recorded tree head of treeSize, or the latest one
+func (s *TransparencyLogService) getHead(ctx context.Context, treeSize *int64) (sqlc_generated.TransparencyLogHead, error) {
+	queries := s.db.QueriesFromContext(ctx)
+	var (
+		head sqlc_generated.TransparencyLogHead
+		err  error
+	)
+	if treeSize == nil {
+		head, err = queries.GetLatestTransparencyLogHead(ctx)
+	} else {
+		head, err = queries.GetTransparencyLogHead(ctx, sqlc_generated.GetTransparencyLogHeadParams{TreeSize: *treeSize})
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return head, ErrTreeHeadNotFound
+		}
+		return head, fmt.Errorf("get tree head: %w", err)
+	}
+	return head, nil
+}
+
+// leafHashes returns the leaf hashes of the first treeSize entries
+func (s *TransparencyLogService) leafHashes(ctx context.Context, treeSize int64) ([][]byte, error) {
+	leaves, err := s.db.QueriesFromContext(ctx).ListTransparencyLogLeafHashes(ctx, sqlc_generated.ListTransparencyLogLeafHashesParams{TreeSize: treeSize})
+	if err != nil {
+		return nil, fmt.Errorf("list transparency log leaves: %w", err)
+	}
+	if int64(len(leaves)) != treeSize {
+		return nil, fmt.Errorf("transparency log has %d of %d leaves", len(leaves), treeSize)
+	}
+	return leaves, nil
+}
+
+// loadEnvelope reads a stored tree head envelope
+func (s *TransparencyLogService) loadEnvelope(ctx context.Context, cidStr string) (*attest.Envelope, error) {
+	c, err := cid.Parse(cidStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tree head CID: %w", err)
+	}
+	reader, err := s.storage.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree head from IPFS: %w", err)
+	}
+	defer reader.Close() // nolint:errcheck
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree head: %w", err)
+	}
+	var envelope attest.Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tree head: %w", err)
+	}
+	return &envelope, nil
+}
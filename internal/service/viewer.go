@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
+)
+
+// Thread visibility, see supabase/schemas/thread.sql
+const (
+	ThreadVisibilityPublic  = "public"
+	ThreadVisibilityPrivate = "private"
+)
+
+type viewerKey struct{}
+
+type viewer struct {
+	userID string
+	system bool
+}
+
+// WithViewer returns a context that reads threads on behalf of userID. An
+// empty userID is an anonymous caller, which is also the default.
+func WithViewer(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer{userID: userID})
+}
+
+// WithSystemViewer returns a context for internal callers, such as queue jobs
+// and the CLI, that may read every thread
+func WithSystemViewer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer{system: true})
+}
+
+// canView reports whether the viewer of ctx may read thread. Private threads
+// are only readable by their owner.
+func canView(ctx context.Context, thread sqlc_generated.Thread) bool {
+	if thread.Visibility != ThreadVisibilityPrivate {
+		return true
+	}
+	v, _ := ctx.Value(viewerKey{}).(viewer)
+	return v.system || (v.userID != "" && thread.OwnerID != nil && *thread.OwnerID == v.userID)
+}
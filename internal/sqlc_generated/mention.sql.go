@@ -80,7 +80,7 @@ func (q *Queries) CreateMention(ctx context.Context, arg CreateMentionParams) (M
 
 const getMentionByID = `-- name: GetMentionByID :one
 
-SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.created_at, t.updated_at FROM mention m
+SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.visibility, t.owner_id, t.tweet_id, t.mode, t.conversation_options, t.stats, t.missing_tweets, t.failure_reason, t.created_at, t.updated_at FROM mention m
 JOIN thread t ON m.thread_id = t.id
 WHERE m.id = $1
 `
@@ -107,6 +107,14 @@ type GetMentionByIDRow struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Visibility            string    `json:"visibility"`
+	OwnerID               *string   `json:"owner_id"`
+	TweetID               *string   `json:"tweet_id"`
+	Mode                  string    `json:"mode"`
+	ConversationOptions   []byte    `json:"conversation_options"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
+	FailureReason         *string   `json:"failure_reason"`
 	CreatedAt_2           time.Time `json:"created_at_2"`
 	UpdatedAt_2           time.Time `json:"updated_at_2"`
 }
@@ -133,6 +141,14 @@ func (q *Queries) GetMentionByID(ctx context.Context, arg GetMentionByIDParams)
 		&i.AuthorName,
 		&i.AuthorScreenName,
 		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
 		&i.CreatedAt_2,
 		&i.UpdatedAt_2,
 	)
@@ -140,7 +156,7 @@ func (q *Queries) GetMentionByID(ctx context.Context, arg GetMentionByIDParams)
 }
 
 const getMentionByUserIDAndThreadID = `-- name: GetMentionByUserIDAndThreadID :one
-SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.created_at, t.updated_at FROM mention m
+SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.visibility, t.owner_id, t.tweet_id, t.mode, t.conversation_options, t.stats, t.missing_tweets, t.failure_reason, t.created_at, t.updated_at FROM mention m
 JOIN thread t ON m.thread_id = t.id
 WHERE m.user_id = $1 AND m.thread_id = $2
 `
@@ -168,6 +184,14 @@ type GetMentionByUserIDAndThreadIDRow struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Visibility            string    `json:"visibility"`
+	OwnerID               *string   `json:"owner_id"`
+	TweetID               *string   `json:"tweet_id"`
+	Mode                  string    `json:"mode"`
+	ConversationOptions   []byte    `json:"conversation_options"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
+	FailureReason         *string   `json:"failure_reason"`
 	CreatedAt_2           time.Time `json:"created_at_2"`
 	UpdatedAt_2           time.Time `json:"updated_at_2"`
 }
@@ -193,6 +217,14 @@ func (q *Queries) GetMentionByUserIDAndThreadID(ctx context.Context, arg GetMent
 		&i.AuthorName,
 		&i.AuthorScreenName,
 		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
 		&i.CreatedAt_2,
 		&i.UpdatedAt_2,
 	)
@@ -200,7 +232,7 @@ func (q *Queries) GetMentionByUserIDAndThreadID(ctx context.Context, arg GetMent
 }
 
 const getMentions = `-- name: GetMentions :many
-SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.created_at, t.updated_at FROM mention m
+SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.visibility, t.owner_id, t.tweet_id, t.mode, t.conversation_options, t.stats, t.missing_tweets, t.failure_reason, t.created_at, t.updated_at FROM mention m
 JOIN thread t ON m.thread_id = t.id
 WHERE ($1::text IS NULL OR m.user_id = $1)
 ORDER BY m.created_at DESC
@@ -231,6 +263,14 @@ type GetMentionsRow struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Visibility            string    `json:"visibility"`
+	OwnerID               *string   `json:"owner_id"`
+	TweetID               *string   `json:"tweet_id"`
+	Mode                  string    `json:"mode"`
+	ConversationOptions   []byte    `json:"conversation_options"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
+	FailureReason         *string   `json:"failure_reason"`
 	CreatedAt_2           time.Time `json:"created_at_2"`
 	UpdatedAt_2           time.Time `json:"updated_at_2"`
 }
@@ -262,6 +302,14 @@ func (q *Queries) GetMentions(ctx context.Context, arg GetMentionsParams) ([]Get
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
 		); err != nil {
@@ -276,7 +324,7 @@ func (q *Queries) GetMentions(ctx context.Context, arg GetMentionsParams) ([]Get
 }
 
 const getMentionsByUser = `-- name: GetMentionsByUser :many
-SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.created_at, t.updated_at FROM mention m
+SELECT m.id, m.user_id, m.thread_id, m.mention_create_at, m.created_at, m.updated_at, t.id, t.summary, t.cid, t.num_tweets, t.status, t.retry_count, t.version, t.author_id, t.author_name, t.author_screen_name, t.author_profile_image_url, t.visibility, t.owner_id, t.tweet_id, t.mode, t.conversation_options, t.stats, t.missing_tweets, t.failure_reason, t.created_at, t.updated_at FROM mention m
 JOIN thread t ON m.thread_id = t.id
 WHERE m.user_id = $1
 ORDER BY m.created_at DESC
@@ -307,6 +355,14 @@ type GetMentionsByUserRow struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Visibility            string    `json:"visibility"`
+	OwnerID               *string   `json:"owner_id"`
+	TweetID               *string   `json:"tweet_id"`
+	Mode                  string    `json:"mode"`
+	ConversationOptions   []byte    `json:"conversation_options"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
+	FailureReason         *string   `json:"failure_reason"`
 	CreatedAt_2           time.Time `json:"created_at_2"`
 	UpdatedAt_2           time.Time `json:"updated_at_2"`
 }
@@ -338,6 +394,14 @@ func (q *Queries) GetMentionsByUser(ctx context.Context, arg GetMentionsByUserPa
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt_2,
 			&i.UpdatedAt_2,
 		); err != nil {
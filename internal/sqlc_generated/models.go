@@ -12,6 +12,179 @@ import (
 	"github.com/google/uuid"
 )
 
+type PdpPieceState string
+
+const (
+	PdpPieceStateUploaded    PdpPieceState = "uploaded"
+	PdpPieceStateRootPending PdpPieceState = "root_pending"
+	PdpPieceStateRootAdded   PdpPieceState = "root_added"
+	PdpPieceStateFailed      PdpPieceState = "failed"
+)
+
+func (e *PdpPieceState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PdpPieceState(s)
+	case string:
+		*e = PdpPieceState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PdpPieceState: %T", src)
+	}
+	return nil
+}
+
+type NullPdpPieceState struct {
+	PdpPieceState PdpPieceState `json:"pdp_piece_state"`
+	Valid         bool          `json:"valid"` // Valid is true if PdpPieceState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPdpPieceState) Scan(value interface{}) error {
+	if value == nil {
+		ns.PdpPieceState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PdpPieceState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPdpPieceState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PdpPieceState), nil
+}
+
+type PdpRootState string
+
+const (
+	PdpRootStatePending PdpRootState = "pending"
+	PdpRootStateAdded   PdpRootState = "added"
+	PdpRootStateFailed  PdpRootState = "failed"
+)
+
+func (e *PdpRootState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PdpRootState(s)
+	case string:
+		*e = PdpRootState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PdpRootState: %T", src)
+	}
+	return nil
+}
+
+type NullPdpRootState struct {
+	PdpRootState PdpRootState `json:"pdp_root_state"`
+	Valid        bool         `json:"valid"` // Valid is true if PdpRootState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPdpRootState) Scan(value interface{}) error {
+	if value == nil {
+		ns.PdpRootState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PdpRootState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPdpRootState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PdpRootState), nil
+}
+
+type ScraperCircuitState string
+
+const (
+	ScraperCircuitStateClosed      ScraperCircuitState = "closed"
+	ScraperCircuitStateOpen        ScraperCircuitState = "open"
+	ScraperCircuitStateHalfOpen    ScraperCircuitState = "half_open"
+	ScraperCircuitStateQuarantined ScraperCircuitState = "quarantined"
+)
+
+func (e *ScraperCircuitState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScraperCircuitState(s)
+	case string:
+		*e = ScraperCircuitState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScraperCircuitState: %T", src)
+	}
+	return nil
+}
+
+type NullScraperCircuitState struct {
+	ScraperCircuitState ScraperCircuitState `json:"scraper_circuit_state"`
+	Valid               bool                `json:"valid"` // Valid is true if ScraperCircuitState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScraperCircuitState) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScraperCircuitState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScraperCircuitState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScraperCircuitState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScraperCircuitState), nil
+}
+
+type ThreadMode string
+
+const (
+	ThreadModeThread       ThreadMode = "thread"
+	ThreadModeConversation ThreadMode = "conversation"
+)
+
+func (e *ThreadMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ThreadMode(s)
+	case string:
+		*e = ThreadMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ThreadMode: %T", src)
+	}
+	return nil
+}
+
+type NullThreadMode struct {
+	ThreadMode ThreadMode `json:"thread_mode"`
+	Valid      bool       `json:"valid"` // Valid is true if ThreadMode is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullThreadMode) Scan(value interface{}) error {
+	if value == nil {
+		ns.ThreadMode, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ThreadMode.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullThreadMode) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ThreadMode), nil
+}
+
 type ThreadStatus string
 
 const (
@@ -56,6 +229,101 @@ func (ns NullThreadStatus) Value() (driver.Value, error) {
 	return string(ns.ThreadStatus), nil
 }
 
+type ThreadVerificationStatus string
+
+const (
+	ThreadVerificationStatusPass        ThreadVerificationStatus = "pass"
+	ThreadVerificationStatusMismatch    ThreadVerificationStatus = "mismatch"
+	ThreadVerificationStatusUnreachable ThreadVerificationStatus = "unreachable"
+)
+
+func (e *ThreadVerificationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ThreadVerificationStatus(s)
+	case string:
+		*e = ThreadVerificationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ThreadVerificationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullThreadVerificationStatus struct {
+	ThreadVerificationStatus ThreadVerificationStatus `json:"thread_verification_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if ThreadVerificationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullThreadVerificationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ThreadVerificationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ThreadVerificationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullThreadVerificationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ThreadVerificationStatus), nil
+}
+
+type ThreadVisibility string
+
+const (
+	ThreadVisibilityPublic  ThreadVisibility = "public"
+	ThreadVisibilityPrivate ThreadVisibility = "private"
+)
+
+func (e *ThreadVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ThreadVisibility(s)
+	case string:
+		*e = ThreadVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ThreadVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullThreadVisibility struct {
+	ThreadVisibility ThreadVisibility `json:"thread_visibility"`
+	Valid            bool             `json:"valid"` // Valid is true if ThreadVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullThreadVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.ThreadVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ThreadVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullThreadVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ThreadVisibility), nil
+}
+
+type ArchiveLineage struct {
+	ID          int64     `json:"id"`
+	ThreadID    uuid.UUID `json:"thread_id"`
+	OldCid      string    `json:"old_cid"`
+	NewCid      string    `json:"new_cid"`
+	FromVersion int32     `json:"from_version"`
+	ToVersion   int32     `json:"to_version"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type BotCookie struct {
 	ID          int32      `json:"id"`
 	Email       string     `json:"email"`
@@ -75,6 +343,45 @@ type Mention struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+type PdpPiece struct {
+	PieceCid     string    `json:"piece_cid"`
+	PaddedSize   int64     `json:"padded_size"`
+	State        string    `json:"state"`
+	RootCid      *string   `json:"root_cid"`
+	SubrootIndex *int32    `json:"subroot_index"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type PdpProofSet struct {
+	ID                 int64      `json:"id"`
+	NextChallengeEpoch *int64     `json:"next_challenge_epoch"`
+	LastProvenEpoch    *int64     `json:"last_proven_epoch"`
+	LastProvenAt       *time.Time `json:"last_proven_at"`
+	LastError          *string    `json:"last_error"`
+	CheckedAt          time.Time  `json:"checked_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+type PdpRoot struct {
+	RootCid         string     `json:"root_cid"`
+	PaddedSize      int64      `json:"padded_size"`
+	NumSubroots     int32      `json:"num_subroots"`
+	State           string     `json:"state"`
+	Attempts        int32      `json:"attempts"`
+	LastError       *string    `json:"last_error"`
+	AddedAt         *time.Time `json:"added_at"`
+	ProofSetID      *int64     `json:"proof_set_id"`
+	RootID          *int64     `json:"root_id"`
+	LastProvenEpoch *int64     `json:"last_proven_epoch"`
+	ProofFailures   int32      `json:"proof_failures"`
+	LastProofError  *string    `json:"last_proof_error"`
+	ProofCheckedAt  *time.Time `json:"proof_checked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
 type ProcessedMark struct {
 	Key       string    `json:"key"`
 	Type      string    `json:"type"`
@@ -82,6 +389,35 @@ type ProcessedMark struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type ScraperAccount struct {
+	Username            string     `json:"username"`
+	State               string     `json:"state"`
+	Failures            []byte     `json:"failures"`
+	ConsecutiveFailures int32      `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until"`
+	LastError           *string    `json:"last_error"`
+	LastErrorAt         *time.Time `json:"last_error_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+	RateLimits          []byte     `json:"rate_limits"`
+	QuarantinedAt       *time.Time `json:"quarantined_at"`
+	QuarantineReason    *string    `json:"quarantine_reason"`
+	QuarantineClearedAt *time.Time `json:"quarantine_cleared_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type StorageReplica struct {
+	Cid            string    `json:"cid"`
+	Replica        string    `json:"replica"`
+	ReplicaCid     *string   `json:"replica_cid"`
+	IsBlock        bool      `json:"is_block"`
+	DagRoot        *string   `json:"dag_root"`
+	LastError      *string   `json:"last_error"`
+	RepairAttempts int32     `json:"repair_attempts"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
 type Thread struct {
 	ID                    uuid.UUID `json:"id"`
 	Summary               string    `json:"summary"`
@@ -94,6 +430,68 @@ type Thread struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Visibility            string    `json:"visibility"`
+	OwnerID               *string   `json:"owner_id"`
+	TweetID               *string   `json:"tweet_id"`
+	Mode                  string    `json:"mode"`
+	ConversationOptions   []byte    `json:"conversation_options"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
+	FailureReason         *string   `json:"failure_reason"`
 	CreatedAt             time.Time `json:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at"`
 }
+
+type ThreadAttestation struct {
+	ThreadID       uuid.UUID `json:"thread_id"`
+	ContentCid     string    `json:"content_cid"`
+	AttestationCid string    `json:"attestation_cid"`
+	KeyID          string    `json:"key_id"`
+	ScrapedAt      time.Time `json:"scraped_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ThreadKey struct {
+	ThreadID   uuid.UUID `json:"thread_id"`
+	OwnerID    string    `json:"owner_id"`
+	WrappedKey []byte    `json:"wrapped_key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ThreadMedium struct {
+	ThreadID  uuid.UUID `json:"thread_id"`
+	Cid       string    `json:"cid"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ThreadVerification struct {
+	ThreadID            uuid.UUID  `json:"thread_id"`
+	Cid                 string     `json:"cid"`
+	Status              string     `json:"status"`
+	FailedCid           *string    `json:"failed_cid"`
+	LastError           *string    `json:"last_error"`
+	ConsecutiveFailures int32      `json:"consecutive_failures"`
+	CheckedAt           time.Time  `json:"checked_at"`
+	RepairQueuedAt      *time.Time `json:"repair_queued_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type TransparencyLogHead struct {
+	TreeSize  int64     `json:"tree_size"`
+	RootHash  []byte    `json:"root_hash"`
+	HeadCid   string    `json:"head_cid"`
+	KeyID     string    `json:"key_id"`
+	SignedAt  time.Time `json:"signed_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TransparencyLogLeaf struct {
+	LeafIndex int64     `json:"leaf_index"`
+	ThreadID  uuid.UUID `json:"thread_id"`
+	Cid       string    `json:"cid"`
+	Entry     []byte    `json:"entry"`
+	LeafHash  []byte    `json:"leaf_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
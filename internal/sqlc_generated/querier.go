@@ -9,39 +9,114 @@ import (
 )
 
 type Querier interface {
+	// Thread media queries
+	AddThreadMedia(ctx context.Context, arg AddThreadMediaParams) error
+	AssignPDPPieceToRoot(ctx context.Context, arg AssignPDPPieceToRootParams) error
+	ClaimStalePDPRoots(ctx context.Context, arg ClaimStalePDPRootsParams) ([]PdpRoot, error)
+	ClearScraperQuarantine(ctx context.Context, arg ClearScraperQuarantineParams) (int64, error)
 	CountBotCookies(ctx context.Context) (int64, error)
+	CountFailedThreadVerifications(ctx context.Context) (int64, error)
 	CountMentions(ctx context.Context, arg CountMentionsParams) (int64, error)
 	CountMentionsByUser(ctx context.Context, arg CountMentionsByUserParams) (int64, error)
+	// Archive lineage queries
+	CreateArchiveLineage(ctx context.Context, arg CreateArchiveLineageParams) error
 	CreateBotCookie(ctx context.Context, arg CreateBotCookieParams) (BotCookie, error)
+	CreateConversationThread(ctx context.Context, arg CreateConversationThreadParams) (Thread, error)
 	CreateMention(ctx context.Context, arg CreateMentionParams) (Mention, error)
+	// A piece whose root failed before is re-uploaded on the next archive, give it a fresh start
+	CreatePDPPiece(ctx context.Context, arg CreatePDPPieceParams) error
+	CreatePDPRoot(ctx context.Context, arg CreatePDPRootParams) error
+	CreatePrivateThread(ctx context.Context, arg CreatePrivateThreadParams) (Thread, error)
 	CreateProcessedMark(ctx context.Context, arg CreateProcessedMarkParams) (ProcessedMark, error)
 	CreateThread(ctx context.Context, arg CreateThreadParams) (Thread, error)
+	// Thread key queries
+	CreateThreadKey(ctx context.Context, arg CreateThreadKeyParams) error
 	DeleteOldProcessedMarks(ctx context.Context, arg DeleteOldProcessedMarksParams) error
 	DeleteProcessedMark(ctx context.Context, arg DeleteProcessedMarkParams) error
+	DeleteStorageReplica(ctx context.Context, arg DeleteStorageReplicaParams) error
 	GetBotCookieByEmailAndUsername(ctx context.Context, arg GetBotCookieByEmailAndUsernameParams) (BotCookie, error)
 	// BotCookie queries
 	GetBotCookieByID(ctx context.Context, arg GetBotCookieByIDParams) (BotCookie, error)
+	GetConversationThreadByTweetAndOptions(ctx context.Context, arg GetConversationThreadByTweetAndOptionsParams) (Thread, error)
 	GetFailedThreadsForRetry(ctx context.Context, arg GetFailedThreadsForRetryParams) ([]Thread, error)
+	GetLatestTransparencyLogHead(ctx context.Context) (TransparencyLogHead, error)
 	// Mention queries
 	GetMentionByID(ctx context.Context, arg GetMentionByIDParams) (GetMentionByIDRow, error)
 	GetMentionByUserIDAndThreadID(ctx context.Context, arg GetMentionByUserIDAndThreadIDParams) (GetMentionByUserIDAndThreadIDRow, error)
 	GetMentions(ctx context.Context, arg GetMentionsParams) ([]GetMentionsRow, error)
 	GetMentionsByUser(ctx context.Context, arg GetMentionsByUserParams) ([]GetMentionsByUserRow, error)
 	GetOldPendingThreads(ctx context.Context, arg GetOldPendingThreadsParams) ([]Thread, error)
+	// PDP piece queries
+	GetPDPPiece(ctx context.Context, arg GetPDPPieceParams) (PdpPiece, error)
+	// PDP proof set queries
+	GetPDPProofSet(ctx context.Context, arg GetPDPProofSetParams) (PdpProofSet, error)
+	// PDP root queries
+	GetPDPRoot(ctx context.Context, arg GetPDPRootParams) (PdpRoot, error)
+	GetPrivateThreadByOwnerAndTweet(ctx context.Context, arg GetPrivateThreadByOwnerAndTweetParams) (Thread, error)
 	// ProcessedMark queries
 	GetProcessedMark(ctx context.Context, arg GetProcessedMarkParams) (ProcessedMark, error)
 	GetStuckScrapingThreads(ctx context.Context, arg GetStuckScrapingThreadsParams) ([]Thread, error)
+	GetThreadAttestation(ctx context.Context, arg GetThreadAttestationParams) (ThreadAttestation, error)
 	// Thread queries
 	GetThreadByID(ctx context.Context, arg GetThreadByIDParams) (Thread, error)
+	GetThreadKey(ctx context.Context, arg GetThreadKeyParams) (ThreadKey, error)
 	GetThreadsByIDs(ctx context.Context, arg GetThreadsByIDsParams) ([]Thread, error)
+	GetTransparencyLogHead(ctx context.Context, arg GetTransparencyLogHeadParams) (TransparencyLogHead, error)
+	// First entry of a CID; the same content may be logged for several threads
+	GetTransparencyLogLeafByCID(ctx context.Context, arg GetTransparencyLogLeafByCIDParams) (TransparencyLogLeaf, error)
+	GetTransparencyLogSize(ctx context.Context) (int64, error)
 	IncrementThreadRetryCount(ctx context.Context, arg IncrementThreadRetryCountParams) error
+	InsertTransparencyLogHead(ctx context.Context, arg InsertTransparencyLogHeadParams) error
+	InsertTransparencyLogLeaf(ctx context.Context, arg InsertTransparencyLogLeafParams) error
+	ListAddedPDPRoots(ctx context.Context, arg ListAddedPDPRootsParams) ([]PdpRoot, error)
+	// Migrations of a thread's archive, oldest first
+	ListArchiveLineage(ctx context.Context, arg ListArchiveLineageParams) ([]ArchiveLineage, error)
 	ListBotCookies(ctx context.Context, arg ListBotCookiesParams) ([]BotCookie, error)
+	ListFailedThreadVerifications(ctx context.Context, arg ListFailedThreadVerificationsParams) ([]ThreadVerification, error)
+	ListMissingStorageReplicas(ctx context.Context, arg ListMissingStorageReplicasParams) ([]StorageReplica, error)
+	ListPDPRootSubroots(ctx context.Context, arg ListPDPRootSubrootsParams) ([]PdpPiece, error)
+	// Scraper account queries
+	ListScraperAccounts(ctx context.Context) ([]ScraperAccount, error)
+	ListSettledPDPPieces(ctx context.Context, arg ListSettledPDPPiecesParams) ([]PdpPiece, error)
+	// Storage replica queries
+	ListStorageReplicas(ctx context.Context, arg ListStorageReplicasParams) ([]StorageReplica, error)
+	ListThreadsByMedia(ctx context.Context, arg ListThreadsByMediaParams) ([]Thread, error)
+	// Completed threads after after_id, in ID order, for paging through every archive
+	ListThreadsToMigrate(ctx context.Context, arg ListThreadsToMigrateParams) ([]Thread, error)
+	// Completed public threads whose current CID is not in the log yet, oldest
+	// first. Private archives stay out of the public log.
+	ListThreadsToSequence(ctx context.Context, arg ListThreadsToSequenceParams) ([]ListThreadsToSequenceRow, error)
+	// Thread verification queries
+	// Completed threads that were never checked come first, then the ones checked
+	// longest ago
+	ListThreadsToVerify(ctx context.Context, arg ListThreadsToVerifyParams) ([]ListThreadsToVerifyRow, error)
+	ListTransparencyLogLeafHashes(ctx context.Context, arg ListTransparencyLogLeafHashesParams) ([][]byte, error)
+	// Transparency log queries
+	// Serializes appends for the rest of the transaction
+	LockTransparencyLog(ctx context.Context) error
+	MarkPDPRootAdded(ctx context.Context, arg MarkPDPRootAddedParams) error
+	RecordPDPProofSetError(ctx context.Context, arg RecordPDPProofSetErrorParams) error
+	RecordPDPRootFailure(ctx context.Context, arg RecordPDPRootFailureParams) (PdpRoot, error)
+	SetPDPRootPiecesState(ctx context.Context, arg SetPDPRootPiecesStateParams) error
 	SoftDeleteBotCookie(ctx context.Context, arg SoftDeleteBotCookieParams) error
 	UpdateBotCookie(ctx context.Context, arg UpdateBotCookieParams) error
 	UpdateMention(ctx context.Context, arg UpdateMentionParams) error
+	UpdatePDPRootProofStatus(ctx context.Context, arg UpdatePDPRootProofStatusParams) error
+	// Points a thread at a rewritten archive of the same content
+	UpdateThreadArchive(ctx context.Context, arg UpdateThreadArchiveParams) (int64, error)
 	UpdateThreadComplete(ctx context.Context, arg UpdateThreadCompleteParams) error
 	UpdateThreadStatus(ctx context.Context, arg UpdateThreadStatusParams) error
+	// Fails a thread whose tweet X will never serve
+	UpdateThreadUnavailable(ctx context.Context, arg UpdateThreadUnavailableParams) error
+	UpsertPDPProofSet(ctx context.Context, arg UpsertPDPProofSetParams) error
 	UpsertProcessedMark(ctx context.Context, arg UpsertProcessedMarkParams) (ProcessedMark, error)
+	// Quarantine columns are written as the pool has them; clearing them is left
+	// to ClearScraperQuarantine
+	UpsertScraperAccount(ctx context.Context, arg UpsertScraperAccountParams) error
+	UpsertStorageReplica(ctx context.Context, arg UpsertStorageReplicaParams) error
+	// Thread attestation queries
+	UpsertThreadAttestation(ctx context.Context, arg UpsertThreadAttestationParams) error
+	UpsertThreadVerification(ctx context.Context, arg UpsertThreadVerificationParams) error
 }
 
 var _ Querier = (*Queries)(nil)
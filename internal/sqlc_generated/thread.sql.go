@@ -12,14 +12,104 @@ import (
 	"github.com/google/uuid"
 )
 
+const createConversationThread = `-- name: CreateConversationThread :one
+INSERT INTO thread (id, summary, cid, status, tweet_id, mode, conversation_options)
+VALUES ($1, '', '', 'pending', $2, 'conversation', $3)
+RETURNING id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at
+`
+
+type CreateConversationThreadParams struct {
+	ID                  uuid.UUID `json:"id"`
+	TweetID             *string   `json:"tweet_id"`
+	ConversationOptions []byte    `json:"conversation_options"`
+}
+
+func (q *Queries) CreateConversationThread(ctx context.Context, arg CreateConversationThreadParams) (Thread, error) {
+	row := q.db.QueryRow(ctx, createConversationThread, arg.ID, arg.TweetID, arg.ConversationOptions)
+	var i Thread
+	err := row.Scan(
+		&i.ID,
+		&i.Summary,
+		&i.Cid,
+		&i.NumTweets,
+		&i.Status,
+		&i.RetryCount,
+		&i.Version,
+		&i.AuthorID,
+		&i.AuthorName,
+		&i.AuthorScreenName,
+		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createPrivateThread = `-- name: CreatePrivateThread :one
+INSERT INTO thread (id, summary, cid, status, visibility, owner_id, tweet_id, mode, conversation_options)
+VALUES ($1, '', '', 'pending', 'private', $2, $3, $4, $5)
+RETURNING id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at
+`
+
+type CreatePrivateThreadParams struct {
+	ID                  uuid.UUID `json:"id"`
+	OwnerID             *string   `json:"owner_id"`
+	TweetID             *string   `json:"tweet_id"`
+	Mode                string    `json:"mode"`
+	ConversationOptions []byte    `json:"conversation_options"`
+}
+
+func (q *Queries) CreatePrivateThread(ctx context.Context, arg CreatePrivateThreadParams) (Thread, error) {
+	row := q.db.QueryRow(ctx, createPrivateThread,
+		arg.ID,
+		arg.OwnerID,
+		arg.TweetID,
+		arg.Mode,
+		arg.ConversationOptions,
+	)
+	var i Thread
+	err := row.Scan(
+		&i.ID,
+		&i.Summary,
+		&i.Cid,
+		&i.NumTweets,
+		&i.Status,
+		&i.RetryCount,
+		&i.Version,
+		&i.AuthorID,
+		&i.AuthorName,
+		&i.AuthorScreenName,
+		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createThread = `-- name: CreateThread :one
 INSERT INTO thread (
     id, summary, cid, num_tweets, status, retry_count, version,
-    author_id, author_name, author_screen_name, author_profile_image_url
+    author_id, author_name, author_screen_name, author_profile_image_url, mode, tweet_id
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7,
-    $8, $9, $10, $11
-) RETURNING id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at
+    $8, $9, $10, $11, $12, $13
+) RETURNING id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at
 `
 
 type CreateThreadParams struct {
@@ -34,6 +124,8 @@ type CreateThreadParams struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Mode                  string    `json:"mode"`
+	TweetID               *string   `json:"tweet_id"`
 }
 
 func (q *Queries) CreateThread(ctx context.Context, arg CreateThreadParams) (Thread, error) {
@@ -49,7 +141,50 @@ func (q *Queries) CreateThread(ctx context.Context, arg CreateThreadParams) (Thr
 		arg.AuthorName,
 		arg.AuthorScreenName,
 		arg.AuthorProfileImageUrl,
+		arg.Mode,
+		arg.TweetID,
+	)
+	var i Thread
+	err := row.Scan(
+		&i.ID,
+		&i.Summary,
+		&i.Cid,
+		&i.NumTweets,
+		&i.Status,
+		&i.RetryCount,
+		&i.Version,
+		&i.AuthorID,
+		&i.AuthorName,
+		&i.AuthorScreenName,
+		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
+	return i, err
+}
+
+const getConversationThreadByTweetAndOptions = `-- name: GetConversationThreadByTweetAndOptions :one
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread
+WHERE visibility = 'public' AND mode = 'conversation' AND tweet_id = $1
+  AND conversation_options = $2
+LIMIT 1
+`
+
+type GetConversationThreadByTweetAndOptionsParams struct {
+	TweetID             *string `json:"tweet_id"`
+	ConversationOptions []byte  `json:"conversation_options"`
+}
+
+func (q *Queries) GetConversationThreadByTweetAndOptions(ctx context.Context, arg GetConversationThreadByTweetAndOptionsParams) (Thread, error) {
+	row := q.db.QueryRow(ctx, getConversationThreadByTweetAndOptions, arg.TweetID, arg.ConversationOptions)
 	var i Thread
 	err := row.Scan(
 		&i.ID,
@@ -63,6 +198,14 @@ func (q *Queries) CreateThread(ctx context.Context, arg CreateThreadParams) (Thr
 		&i.AuthorName,
 		&i.AuthorScreenName,
 		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -70,8 +213,9 @@ func (q *Queries) CreateThread(ctx context.Context, arg CreateThreadParams) (Thr
 }
 
 const getFailedThreadsForRetry = `-- name: GetFailedThreadsForRetry :many
-SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at FROM thread 
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread 
 WHERE status = 'failed' 
+  AND failure_reason IS NULL
   AND updated_at < $1 
   AND retry_count < $2
 FOR UPDATE
@@ -103,6 +247,14 @@ func (q *Queries) GetFailedThreadsForRetry(ctx context.Context, arg GetFailedThr
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -117,7 +269,7 @@ func (q *Queries) GetFailedThreadsForRetry(ctx context.Context, arg GetFailedThr
 }
 
 const getOldPendingThreads = `-- name: GetOldPendingThreads :many
-SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at FROM thread 
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread 
 WHERE status = 'pending' 
   AND created_at < $1 
   AND retry_count < $2
@@ -150,6 +302,14 @@ func (q *Queries) GetOldPendingThreads(ctx context.Context, arg GetOldPendingThr
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -163,8 +323,49 @@ func (q *Queries) GetOldPendingThreads(ctx context.Context, arg GetOldPendingThr
 	return items, nil
 }
 
+const getPrivateThreadByOwnerAndTweet = `-- name: GetPrivateThreadByOwnerAndTweet :one
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread
+WHERE visibility = 'private' AND owner_id = $1 AND tweet_id = $2 AND mode = $3
+LIMIT 1
+`
+
+type GetPrivateThreadByOwnerAndTweetParams struct {
+	OwnerID *string `json:"owner_id"`
+	TweetID *string `json:"tweet_id"`
+	Mode    string  `json:"mode"`
+}
+
+func (q *Queries) GetPrivateThreadByOwnerAndTweet(ctx context.Context, arg GetPrivateThreadByOwnerAndTweetParams) (Thread, error) {
+	row := q.db.QueryRow(ctx, getPrivateThreadByOwnerAndTweet, arg.OwnerID, arg.TweetID, arg.Mode)
+	var i Thread
+	err := row.Scan(
+		&i.ID,
+		&i.Summary,
+		&i.Cid,
+		&i.NumTweets,
+		&i.Status,
+		&i.RetryCount,
+		&i.Version,
+		&i.AuthorID,
+		&i.AuthorName,
+		&i.AuthorScreenName,
+		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getStuckScrapingThreads = `-- name: GetStuckScrapingThreads :many
-SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at FROM thread 
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread 
 WHERE status = 'scraping' 
   AND updated_at < $1 
   AND retry_count < $2
@@ -197,6 +398,14 @@ func (q *Queries) GetStuckScrapingThreads(ctx context.Context, arg GetStuckScrap
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -212,7 +421,7 @@ func (q *Queries) GetStuckScrapingThreads(ctx context.Context, arg GetStuckScrap
 
 const getThreadByID = `-- name: GetThreadByID :one
 
-SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at FROM thread WHERE id = $1
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread WHERE id = $1
 `
 
 type GetThreadByIDParams struct {
@@ -235,6 +444,14 @@ func (q *Queries) GetThreadByID(ctx context.Context, arg GetThreadByIDParams) (T
 		&i.AuthorName,
 		&i.AuthorScreenName,
 		&i.AuthorProfileImageUrl,
+		&i.Visibility,
+		&i.OwnerID,
+		&i.TweetID,
+		&i.Mode,
+		&i.ConversationOptions,
+		&i.Stats,
+		&i.MissingTweets,
+		&i.FailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -242,7 +459,7 @@ func (q *Queries) GetThreadByID(ctx context.Context, arg GetThreadByIDParams) (T
 }
 
 const getThreadsByIDs = `-- name: GetThreadsByIDs :many
-SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, created_at, updated_at FROM thread WHERE id = ANY($1::uuid[])
+SELECT id, summary, cid, num_tweets, status, retry_count, version, author_id, author_name, author_screen_name, author_profile_image_url, visibility, owner_id, tweet_id, mode, conversation_options, stats, missing_tweets, failure_reason, created_at, updated_at FROM thread WHERE id = ANY($1::uuid[])
 `
 
 type GetThreadsByIDsParams struct {
@@ -270,6 +487,14 @@ func (q *Queries) GetThreadsByIDs(ctx context.Context, arg GetThreadsByIDsParams
 			&i.AuthorName,
 			&i.AuthorScreenName,
 			&i.AuthorProfileImageUrl,
+			&i.Visibility,
+			&i.OwnerID,
+			&i.TweetID,
+			&i.Mode,
+			&i.ConversationOptions,
+			&i.Stats,
+			&i.MissingTweets,
+			&i.FailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -299,6 +524,36 @@ func (q *Queries) IncrementThreadRetryCount(ctx context.Context, arg IncrementTh
 	return err
 }
 
+const updateThreadArchive = `-- name: UpdateThreadArchive :execrows
+UPDATE thread SET
+    cid = $1,
+    stats = $2,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $3 AND version = $4
+`
+
+type UpdateThreadArchiveParams struct {
+	Cid             string    `json:"cid"`
+	Stats           []byte    `json:"stats"`
+	ID              uuid.UUID `json:"id"`
+	ExpectedVersion int32     `json:"expected_version"`
+}
+
+// Points a thread at a rewritten archive of the same content
+func (q *Queries) UpdateThreadArchive(ctx context.Context, arg UpdateThreadArchiveParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateThreadArchive,
+		arg.Cid,
+		arg.Stats,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateThreadComplete = `-- name: UpdateThreadComplete :exec
 UPDATE thread SET
     summary = $1,
@@ -311,8 +566,11 @@ UPDATE thread SET
     author_name = $7,
     author_screen_name = $8,
     author_profile_image_url = $9,
+    stats = $10,
+    missing_tweets = $11,
+    failure_reason = NULL,
     updated_at = NOW()
-WHERE id = $10 AND version = $11
+WHERE id = $12 AND version = $13
 `
 
 type UpdateThreadCompleteParams struct {
@@ -325,6 +583,8 @@ type UpdateThreadCompleteParams struct {
 	AuthorName            *string   `json:"author_name"`
 	AuthorScreenName      *string   `json:"author_screen_name"`
 	AuthorProfileImageUrl *string   `json:"author_profile_image_url"`
+	Stats                 []byte    `json:"stats"`
+	MissingTweets         []byte    `json:"missing_tweets"`
 	ID                    uuid.UUID `json:"id"`
 	ExpectedVersion       int32     `json:"expected_version"`
 }
@@ -340,6 +600,8 @@ func (q *Queries) UpdateThreadComplete(ctx context.Context, arg UpdateThreadComp
 		arg.AuthorName,
 		arg.AuthorScreenName,
 		arg.AuthorProfileImageUrl,
+		arg.Stats,
+		arg.MissingTweets,
 		arg.ID,
 		arg.ExpectedVersion,
 	)
@@ -364,3 +626,24 @@ func (q *Queries) UpdateThreadStatus(ctx context.Context, arg UpdateThreadStatus
 	_, err := q.db.Exec(ctx, updateThreadStatus, arg.Status, arg.ThreadID, arg.CurrentVersion)
 	return err
 }
+
+const updateThreadUnavailable = `-- name: UpdateThreadUnavailable :exec
+UPDATE thread SET
+    status = 'failed',
+    failure_reason = $1,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = $2 AND version = $3
+`
+
+type UpdateThreadUnavailableParams struct {
+	FailureReason  *string   `json:"failure_reason"`
+	ThreadID       uuid.UUID `json:"thread_id"`
+	CurrentVersion int32     `json:"current_version"`
+}
+
+// Fails a thread whose tweet X will never serve
+func (q *Queries) UpdateThreadUnavailable(ctx context.Context, arg UpdateThreadUnavailableParams) error {
+	_, err := q.db.Exec(ctx, updateThreadUnavailable, arg.FailureReason, arg.ThreadID, arg.CurrentVersion)
+	return err
+}
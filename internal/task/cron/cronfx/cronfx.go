@@ -9,6 +9,7 @@ import (
 	"github.com/ipfs-force-community/threadmirror/internal/config"
 	"github.com/ipfs-force-community/threadmirror/internal/service"
 	"github.com/ipfs-force-community/threadmirror/internal/task/cron"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
 	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
 	"go.uber.org/fx"
@@ -19,6 +20,12 @@ var Module = fx.Module("cron",
 	fx.Provide(newCronScheduler),
 	fx.Provide(newThreadStatusCleanupHandler),
 	fx.Provide(newMentionCheckHandler),
+	fx.Provide(newPDPRootHandler),
+	fx.Provide(newPDPProofCheckHandler),
+	fx.Provide(newStorageRepairHandler),
+	fx.Provide(newThreadVerifyHandler),
+	fx.Provide(newTransparencyLogHandler),
+	fx.Provide(cron.NewScraperPoolSyncHandler),
 	fx.Invoke(registerCronLifecycle),
 )
 
@@ -52,7 +59,7 @@ func newThreadStatusCleanupHandler(
 // newMentionCheckHandler creates a mention check handler
 func newMentionCheckHandler(
 	logger *slog.Logger,
-	scrapers []*xscraper.XScraper,
+	scraperPool *xscraper.ScraperPool,
 	jobQueueClient jobq.JobQueueClient,
 	cronConfig *config.CronConfig,
 ) *cron.MentionCheckHandler {
@@ -63,18 +70,120 @@ func newMentionCheckHandler(
 
 	return cron.NewMentionCheckHandler(
 		logger,
-		scrapers,
+		scraperPool,
 		jobQueueClient,
 		mentionConfig,
 	)
 }
 
+// newPDPRootHandler creates a PDP root handler
+func newPDPRootHandler(
+	logger *slog.Logger,
+	pdpPieces *service.PDPPieceService,
+	jobQueueClient jobq.JobQueueClient,
+	cronConfig *config.CronConfig,
+) *cron.PDPRootHandler {
+	pdpRootConfig := cron.PDPRootConfig{
+		SettleDelaySeconds:  cronConfig.PDPRoot.SettleDelaySeconds,
+		StaleMinutes:        cronConfig.PDPRoot.StaleMinutes,
+		BatchMaxSizeMiB:     cronConfig.PDPRoot.BatchMaxSizeMiB,
+		BatchMaxSubroots:    cronConfig.PDPRoot.BatchMaxSubroots,
+		BatchMaxWaitMinutes: cronConfig.PDPRoot.BatchMaxWaitMinutes,
+	}
+
+	return cron.NewPDPRootHandler(
+		logger,
+		pdpPieces,
+		jobQueueClient,
+		pdpRootConfig,
+	)
+}
+
+// newPDPProofCheckHandler creates a PDP proof check handler
+func newPDPProofCheckHandler(
+	logger *slog.Logger,
+	pdpPieces *service.PDPPieceService,
+	cronConfig *config.CronConfig,
+) *cron.PDPProofCheckHandler {
+	proofCheckConfig := cron.PDPProofCheckConfig{
+		StallHours:          cronConfig.PDPProofCheck.StallHours,
+		LandingGraceMinutes: cronConfig.PDPProofCheck.LandingGraceMinutes,
+	}
+
+	return cron.NewPDPProofCheckHandler(
+		logger,
+		pdpPieces,
+		proofCheckConfig,
+	)
+}
+
+// newStorageRepairHandler creates a storage repair handler
+func newStorageRepairHandler(
+	logger *slog.Logger,
+	storage ipfs.Storage,
+	pdpPieces *service.PDPPieceService,
+	cronConfig *config.CronConfig,
+) *cron.StorageRepairHandler {
+	repairConfig := cron.StorageRepairConfig{
+		BatchSize: cronConfig.StorageRepair.BatchSize,
+	}
+
+	// Pieces repaired onto a PDP replica need root registration too
+	return cron.NewStorageRepairHandler(
+		logger,
+		pdpPieces.Storage(storage),
+		repairConfig,
+	)
+}
+
+// newThreadVerifyHandler creates a thread verify handler
+func newThreadVerifyHandler(
+	logger *slog.Logger,
+	threadService *service.ThreadService,
+	cronConfig *config.CronConfig,
+) *cron.ThreadVerifyHandler {
+	verifyConfig := cron.ThreadVerifyConfig{
+		BatchSize:    cronConfig.ThreadVerify.BatchSize,
+		RecheckHours: cronConfig.ThreadVerify.RecheckHours,
+		QueueRepair:  cronConfig.ThreadVerify.QueueRepair,
+	}
+
+	return cron.NewThreadVerifyHandler(
+		logger,
+		threadService,
+		verifyConfig,
+	)
+}
+
+// newTransparencyLogHandler creates a transparency log handler
+func newTransparencyLogHandler(
+	logger *slog.Logger,
+	logService *service.TransparencyLogService,
+	cronConfig *config.CronConfig,
+) *cron.TransparencyLogHandler {
+	logConfig := cron.TransparencyLogConfig{
+		BatchSize: cronConfig.TransparencyLog.BatchSize,
+	}
+
+	return cron.NewTransparencyLogHandler(
+		logger,
+		logService,
+		logConfig,
+	)
+}
+
 // registerCronLifecycle registers cron jobs and manages their lifecycle
 func registerCronLifecycle(
 	lc fx.Lifecycle,
 	scheduler gocron.Scheduler,
 	threadStatusCleanup *cron.ThreadStatusCleanupHandler,
 	mentionCheck *cron.MentionCheckHandler,
+	pdpRoot *cron.PDPRootHandler,
+	pdpProofCheck *cron.PDPProofCheckHandler,
+	storageRepair *cron.StorageRepairHandler,
+	threadVerify *cron.ThreadVerifyHandler,
+	transparencyLog *cron.TransparencyLogHandler,
+	scraperPoolSync *cron.ScraperPoolSyncHandler,
 	cronConfig *config.CronConfig,
 	logger *slog.Logger,
 ) {
@@ -128,6 +237,133 @@ func registerCronLifecycle(
 				)
 			}
 
+			// Schedule PDP root registration
+			if cronConfig.PDPRoot.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.PDPRoot.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+						defer cancel()
+
+						if err := pdpRoot.Execute(ctx); err != nil {
+							logger.Error("PDP root registration failed", "error", err)
+						}
+					}),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled PDP root registration", "interval_minutes", intervalMinutes)
+			}
+
+			// Schedule PDP proof check
+			if cronConfig.PDPProofCheck.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.PDPProofCheck.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+						defer cancel()
+
+						if err := pdpProofCheck.Execute(ctx); err != nil {
+							logger.Error("PDP proof check failed", "error", err)
+						}
+					}),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled PDP proof check", "interval_minutes", intervalMinutes)
+			}
+
+			// Schedule storage replica repair
+			if cronConfig.StorageRepair.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.StorageRepair.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+						defer cancel()
+
+						if err := storageRepair.Execute(ctx); err != nil {
+							logger.Error("Storage replica repair failed", "error", err)
+						}
+					}),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled storage replica repair", "interval_minutes", intervalMinutes)
+			}
+
+			// Schedule archive integrity verification
+			if cronConfig.ThreadVerify.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.ThreadVerify.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+						defer cancel()
+
+						if err := threadVerify.Execute(ctx); err != nil {
+							logger.Error("Thread verification failed", "error", err)
+						}
+					}),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled thread verification", "interval_minutes", intervalMinutes)
+			}
+
+			// Schedule transparency log sequencing and tree head publishing
+			if cronConfig.TransparencyLog.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.TransparencyLog.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+						defer cancel()
+
+						if err := transparencyLog.Execute(ctx); err != nil {
+							logger.Error("Transparency log update failed", "error", err)
+						}
+					}),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled transparency log", "interval_minutes", intervalMinutes)
+			}
+
+			// Schedule scraper pool sync, right away so quarantines survive restarts
+			if cronConfig.ScraperPoolSync.EnabledIntervalMinutes > 0 {
+				intervalMinutes := cronConfig.ScraperPoolSync.EnabledIntervalMinutes
+
+				_, err := scheduler.NewJob(
+					gocron.DurationJob(time.Duration(intervalMinutes)*time.Minute),
+					gocron.NewTask(func() {
+						ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+						defer cancel()
+
+						if err := scraperPoolSync.Execute(ctx); err != nil {
+							logger.Error("Scraper pool sync failed", "error", err)
+						}
+					}),
+					gocron.WithStartAt(gocron.WithStartImmediately()),
+				)
+				if err != nil {
+					return err
+				}
+				logger.Info("Scheduled scraper pool sync", "interval_minutes", intervalMinutes)
+			}
+
 			// Start the scheduler
 			scheduler.Start()
 			logger.Info("Cron scheduler started")
@@ -14,7 +14,7 @@ import (
 // MentionCheckHandler handles checking for new mentions and processing them
 type MentionCheckHandler struct {
 	logger         *slog.Logger
-	scrapers       []*xscraper.XScraper
+	scraperPool    *xscraper.ScraperPool
 	jobQueueClient jobq.JobQueueClient
 
 	// Lower-cased prefix of author screen names to exclude from processing
@@ -33,19 +33,19 @@ type MentionCheckConfig struct {
 // NewMentionCheckHandler creates a new mention check handler
 func NewMentionCheckHandler(
 	logger *slog.Logger,
-	scrapers []*xscraper.XScraper,
+	scraperPool *xscraper.ScraperPool,
 	jobQueueClient jobq.JobQueueClient,
 	config MentionCheckConfig,
 ) *MentionCheckHandler {
 	// If mentionUsername is empty, use the first scraper's username as fallback
 	mentionUsername := config.MentionUsername
-	if mentionUsername == "" && len(scrapers) > 0 {
+	if scrapers := scraperPool.Scrapers(); mentionUsername == "" && len(scrapers) > 0 {
 		mentionUsername = scrapers[0].LoginOpts.Username
 	}
 
 	return &MentionCheckHandler{
 		logger:                          logger.With("cron_handler", "mention_check"),
-		scrapers:                        scrapers,
+		scraperPool:                     scraperPool,
 		jobQueueClient:                  jobQueueClient,
 		excludeMentionAuthorPrefixLower: strings.ToLower(config.ExcludeMentionAuthorPrefix),
 		mentionUsername:                 mentionUsername,
@@ -59,8 +59,7 @@ func (h *MentionCheckHandler) Execute(ctx context.Context) error {
 		"exclude_prefix", h.excludeMentionAuthorPrefixLower,
 	)
 
-	pool := xscraper.NewScraperPool(h.scrapers)
-	mentions, err := xscraper.TryWithResult(pool, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
+	mentions, err := xscraper.TryWithResult(h.scraperPool, xscraper.EndpointSearchTimeline, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
 		filter := func(tweet *xscraper.Tweet) bool {
 			if h.excludeMentionAuthorPrefixLower == "" {
 				return true
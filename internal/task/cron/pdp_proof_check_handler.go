@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+)
+
+// PDPProofCheckHandler polls the PDP proof set and records per root whether it
+// is being proven
+type PDPProofCheckHandler struct {
+	logger    *slog.Logger
+	pdpPieces *service.PDPPieceService
+
+	// Configuration
+	stallAfter   time.Duration // How long without a proof before roots count as failing
+	landingGrace time.Duration // How long an added root may be missing from the proof set
+}
+
+// PDPProofCheckConfig holds configuration for the PDP proof check handler
+type PDPProofCheckConfig struct {
+	StallHours          int `mapstructure:"stall_hours" default:"48"`
+	LandingGraceMinutes int `mapstructure:"landing_grace_minutes" default:"60"`
+}
+
+// NewPDPProofCheckHandler creates a new PDP proof check handler
+func NewPDPProofCheckHandler(
+	logger *slog.Logger,
+	pdpPieces *service.PDPPieceService,
+	config PDPProofCheckConfig,
+) *PDPProofCheckHandler {
+	// Apply defaults if not set
+	if config.StallHours <= 0 {
+		config.StallHours = 48
+	}
+	if config.LandingGraceMinutes <= 0 {
+		config.LandingGraceMinutes = 60
+	}
+
+	return &PDPProofCheckHandler{
+		logger:       logger.With("cron_handler", "pdp_proof_check"),
+		pdpPieces:    pdpPieces,
+		stallAfter:   time.Duration(config.StallHours) * time.Hour,
+		landingGrace: time.Duration(config.LandingGraceMinutes) * time.Minute,
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *PDPProofCheckHandler) Execute(ctx context.Context) error {
+	if !h.pdpPieces.Enabled() {
+		return nil
+	}
+
+	result, err := h.pdpPieces.CheckProofs(ctx, h.stallAfter, h.landingGrace)
+	if err != nil {
+		return fmt.Errorf("check pdp proofs: %w", err)
+	}
+
+	h.logger.Info("Checked PDP proofs",
+		"roots", result.Roots,
+		"proven", result.Proven,
+		"failing", result.Failing,
+		"last_proven_epoch", result.LastProvenEpoch,
+	)
+	return nil
+}
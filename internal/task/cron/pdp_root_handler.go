@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/internal/task/queue"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
+)
+
+// PDPRootHandler batches uploaded PDP pieces into aggregate roots, queues their
+// AddRoots jobs and re-queues roots whose job was lost (e.g. on restart)
+type PDPRootHandler struct {
+	logger         *slog.Logger
+	pdpPieces      *service.PDPPieceService
+	jobQueueClient jobq.JobQueueClient
+
+	// Configuration
+	settleDelay time.Duration        // How long to wait after upload before aggregating a piece
+	staleAfter  time.Duration        // How long before a 'pending' root is re-queued
+	policy      ipfs.AggregatePolicy // When a batch of pieces becomes a root
+}
+
+// PDPRootConfig holds configuration for the PDP root handler
+type PDPRootConfig struct {
+	SettleDelaySeconds  int `mapstructure:"settle_delay_seconds" default:"20"`
+	StaleMinutes        int `mapstructure:"stale_minutes" default:"30"`
+	BatchMaxSizeMiB     int `mapstructure:"batch_max_size_mib" default:"64"`
+	BatchMaxSubroots    int `mapstructure:"batch_max_subroots" default:"256"`
+	BatchMaxWaitMinutes int `mapstructure:"batch_max_wait_minutes" default:"10"`
+}
+
+// staleRootBatchSize caps the roots re-queued per run
+const staleRootBatchSize = 100
+
+// NewPDPRootHandler creates a new PDP root handler
+func NewPDPRootHandler(
+	logger *slog.Logger,
+	pdpPieces *service.PDPPieceService,
+	jobQueueClient jobq.JobQueueClient,
+	config PDPRootConfig,
+) *PDPRootHandler {
+	// Apply defaults if not set
+	if config.SettleDelaySeconds <= 0 {
+		config.SettleDelaySeconds = 20
+	}
+	if config.StaleMinutes <= 0 {
+		config.StaleMinutes = 30
+	}
+	if config.BatchMaxSizeMiB <= 0 {
+		config.BatchMaxSizeMiB = 64
+	}
+	if config.BatchMaxSubroots <= 0 {
+		config.BatchMaxSubroots = 256
+	}
+	if config.BatchMaxWaitMinutes < 0 {
+		config.BatchMaxWaitMinutes = 10
+	}
+
+	return &PDPRootHandler{
+		logger:         logger.With("cron_handler", "pdp_root"),
+		pdpPieces:      pdpPieces,
+		jobQueueClient: jobQueueClient,
+		settleDelay:    time.Duration(config.SettleDelaySeconds) * time.Second,
+		staleAfter:     time.Duration(config.StaleMinutes) * time.Minute,
+		policy: ipfs.AggregatePolicy{
+			MaxSize:     uint64(config.BatchMaxSizeMiB) << 20,
+			MaxSubroots: config.BatchMaxSubroots,
+			MaxWait:     time.Duration(config.BatchMaxWaitMinutes) * time.Minute,
+		},
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *PDPRootHandler) Execute(ctx context.Context) error {
+	if !h.pdpPieces.Enabled() {
+		return nil
+	}
+
+	newRoots, err := h.pdpPieces.AggregatePieces(ctx, h.settleDelay, h.policy)
+	if err != nil {
+		return fmt.Errorf("aggregate pdp pieces: %w", err)
+	}
+
+	staleRoots, err := h.pdpPieces.ClaimStaleRoots(ctx, h.staleAfter, staleRootBatchSize)
+	if err != nil {
+		return fmt.Errorf("claim stale pdp roots: %w", err)
+	}
+
+	if len(newRoots) == 0 && len(staleRoots) == 0 {
+		h.logger.Debug("No PDP roots to add")
+		return nil
+	}
+
+	// A root whose job fails to enqueue stays 'pending' and is picked up again once stale
+	queued := 0
+	for _, rootCID := range append(newRoots, staleRoots...) {
+		logger := h.logger.With("root_cid", rootCID)
+
+		job, err := queue.NewPDPAddRootJob(rootCID)
+		if err != nil {
+			logger.Error("Failed to create pdp add root job", "error", err)
+			continue
+		}
+
+		_, err = h.jobQueueClient.Enqueue(ctx, job)
+		if err != nil {
+			logger.Error("Failed to enqueue pdp add root job", "error", err)
+			continue
+		}
+		queued++
+	}
+
+	h.logger.Info("Queued PDP add root jobs",
+		"new_roots", len(newRoots),
+		"stale_roots", len(staleRoots),
+		"queued", queued,
+	)
+	return nil
+}
@@ -0,0 +1,40 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+)
+
+// ScraperPoolSyncHandler records the circuit breaker state of the scraper
+// pool for monitoring, and applies the quarantines operators clear
+type ScraperPoolSyncHandler struct {
+	logger                *slog.Logger
+	scraperAccountService *service.ScraperAccountService
+	scraperPool           *xscraper.ScraperPool
+}
+
+// NewScraperPoolSyncHandler creates a new scraper pool sync handler
+func NewScraperPoolSyncHandler(
+	logger *slog.Logger,
+	scraperAccountService *service.ScraperAccountService,
+	scraperPool *xscraper.ScraperPool,
+) *ScraperPoolSyncHandler {
+	return &ScraperPoolSyncHandler{
+		logger:                logger.With("cron_handler", "scraper_pool_sync"),
+		scraperAccountService: scraperAccountService,
+		scraperPool:           scraperPool,
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *ScraperPoolSyncHandler) Execute(ctx context.Context) error {
+	if err := h.scraperAccountService.SyncPool(ctx, h.scraperPool); err != nil {
+		return fmt.Errorf("sync scraper pool: %w", err)
+	}
+	h.logger.Debug("Synced scraper pool", "accounts", h.scraperPool.Len())
+	return nil
+}
@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+)
+
+// StorageRepairHandler re-adds content that a replica of the replicated storage
+// backend missed, e.g. because it was down when the content was written
+type StorageRepairHandler struct {
+	logger  *slog.Logger
+	storage ipfs.Storage
+
+	// Configuration
+	batchSize int // Maximum number of missing replicas repaired per run
+}
+
+// StorageRepairConfig holds configuration for the storage repair handler
+type StorageRepairConfig struct {
+	BatchSize int `mapstructure:"batch_size" default:"100"`
+}
+
+// NewStorageRepairHandler creates a new storage repair handler
+func NewStorageRepairHandler(
+	logger *slog.Logger,
+	storage ipfs.Storage,
+	config StorageRepairConfig,
+) *StorageRepairHandler {
+	// Apply defaults if not set
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	return &StorageRepairHandler{
+		logger:    logger.With("cron_handler", "storage_repair"),
+		storage:   storage,
+		batchSize: config.BatchSize,
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *StorageRepairHandler) Execute(ctx context.Context) error {
+	replicated, ok := h.storage.(ipfs.ReplicatedStorage)
+	if !ok {
+		return nil
+	}
+
+	result, err := replicated.Repair(ctx, h.batchSize)
+	if err != nil {
+		return fmt.Errorf("repair storage replicas: %w", err)
+	}
+	if result.Checked == 0 {
+		h.logger.Debug("No storage replicas to repair")
+		return nil
+	}
+
+	h.logger.Info("Repaired storage replicas",
+		"checked", result.Checked,
+		"repaired", result.Repaired,
+		"failed", result.Failed,
+	)
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/internal/sqlc_generated"
 	"github.com/ipfs-force-community/threadmirror/internal/task/queue"
 	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
 )
@@ -139,7 +140,7 @@ func (h *ThreadStatusCleanupHandler) handleStuckScrapingThreads(ctx context.Cont
 		resetCount++
 
 		// Re-enqueue thread scrape job
-		job, err := queue.NewThreadScrapeJob(thread.ID.String())
+		job, err := newThreadScrapeJob(thread)
 		if err != nil {
 			logger.Error("Failed to create thread scrape job", "error", err)
 			continue
@@ -180,7 +181,7 @@ func (h *ThreadStatusCleanupHandler) handleOldPendingThreads(ctx context.Context
 		)
 
 		// Re-enqueue thread scrape job
-		job, err := queue.NewThreadScrapeJob(thread.ID.String())
+		job, err := newThreadScrapeJob(thread)
 		if err != nil {
 			logger.Error("Failed to create thread scrape job for old pending thread", "error", err)
 			continue
@@ -234,7 +235,7 @@ func (h *ThreadStatusCleanupHandler) handleFailedThreadsRetry(ctx context.Contex
 		logger.Info("Reset failed thread to pending for retry")
 
 		// Re-enqueue thread scrape job
-		job, err := queue.NewThreadScrapeJob(thread.ID.String())
+		job, err := newThreadScrapeJob(thread)
 		if err != nil {
 			logger.Error("Failed to create thread scrape job for retry", "error", err)
 			continue
@@ -252,3 +253,12 @@ func (h *ThreadStatusCleanupHandler) handleFailedThreadsRetry(ctx context.Contex
 
 	return retriedCount, nil
 }
+
+// newThreadScrapeJob creates the job that scrapes thread again. Threads remember
+// the tweet they were created for; others are scraped by their ID.
+func newThreadScrapeJob(thread sqlc_generated.Thread) (*jobq.Job, error) {
+	if thread.TweetID != nil {
+		return queue.NewThreadScrapeJobInto(thread.ID.String(), *thread.TweetID)
+	}
+	return queue.NewThreadScrapeJob(thread.ID.String())
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+)
+
+// ThreadVerifyHandler reads archived threads back from storage and records
+// whether their content still matches their CID
+type ThreadVerifyHandler struct {
+	logger        *slog.Logger
+	threadService *service.ThreadService
+
+	// Configuration
+	batchSize    int           // Maximum number of threads verified per run
+	recheckAfter time.Duration // How long before a verified thread is checked again
+	queueRepair  bool          // Whether failed replicas are queued for repair
+}
+
+// ThreadVerifyConfig holds configuration for the thread verify handler
+type ThreadVerifyConfig struct {
+	BatchSize    int  `mapstructure:"batch_size" default:"50"`
+	RecheckHours int  `mapstructure:"recheck_hours" default:"168"`
+	QueueRepair  bool `mapstructure:"queue_repair" default:"false"`
+}
+
+// NewThreadVerifyHandler creates a new thread verify handler
+func NewThreadVerifyHandler(
+	logger *slog.Logger,
+	threadService *service.ThreadService,
+	config ThreadVerifyConfig,
+) *ThreadVerifyHandler {
+	// Apply defaults if not set
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.RecheckHours <= 0 {
+		config.RecheckHours = 168
+	}
+
+	return &ThreadVerifyHandler{
+		logger:        logger.With("cron_handler", "thread_verify"),
+		threadService: threadService,
+		batchSize:     config.BatchSize,
+		recheckAfter:  time.Duration(config.RecheckHours) * time.Hour,
+		queueRepair:   config.QueueRepair,
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *ThreadVerifyHandler) Execute(ctx context.Context) error {
+	result, err := h.threadService.VerifyThreads(ctx, h.batchSize, h.recheckAfter, h.queueRepair)
+	if err != nil {
+		return fmt.Errorf("verify threads: %w", err)
+	}
+	if result.Checked == 0 {
+		h.logger.Debug("No threads to verify")
+		return nil
+	}
+
+	h.logger.Info("Verified archived threads",
+		"checked", result.Checked,
+		"passed", result.Passed,
+		"mismatched", result.Mismatched,
+		"unreachable", result.Unreachable,
+		"repair_queued", result.RepairQueued,
+	)
+	return nil
+}
@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+)
+
+// TransparencyLogHandler appends newly archived thread CIDs to the
+// transparency log and publishes a signed tree head when the log grew
+type TransparencyLogHandler struct {
+	logger     *slog.Logger
+	logService *service.TransparencyLogService
+
+	// Configuration
+	batchSize int // Maximum number of entries appended per run
+}
+
+// TransparencyLogConfig holds configuration for the transparency log handler
+type TransparencyLogConfig struct {
+	BatchSize int `mapstructure:"batch_size" default:"1000"`
+}
+
+// NewTransparencyLogHandler creates a new transparency log handler
+func NewTransparencyLogHandler(
+	logger *slog.Logger,
+	logService *service.TransparencyLogService,
+	config TransparencyLogConfig,
+) *TransparencyLogHandler {
+	// Apply defaults if not set
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1000
+	}
+
+	return &TransparencyLogHandler{
+		logger:     logger.With("cron_handler", "transparency_log"),
+		logService: logService,
+		batchSize:  config.BatchSize,
+	}
+}
+
+// Execute implements common.CronTaskHandler
+func (h *TransparencyLogHandler) Execute(ctx context.Context) error {
+	appended, err := h.logService.Sequence(ctx, h.batchSize)
+	if err != nil {
+		return fmt.Errorf("sequence transparency log: %w", err)
+	}
+	if appended > 0 {
+		h.logger.Info("Appended archives to the transparency log", "count", appended)
+	}
+
+	head, err := h.logService.PublishTreeHead(ctx)
+	if err != nil {
+		return fmt.Errorf("publish tree head: %w", err)
+	}
+	if head == nil {
+		h.logger.Debug("No tree head to publish")
+		return nil
+	}
+
+	h.logger.Info("Published tree head",
+		"tree_size", head.TreeSize,
+		"root_hash", head.RootHash,
+		"cid", head.HeadCID,
+	)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/ipfs-force-community/threadmirror/internal/service"
@@ -15,12 +16,17 @@ import (
 
 const TypeProcessMention = "process_mention"
 
+// conversationKeyword in a mention asks for the conversation under the tweet,
+// replies by other users included, instead of the author's thread
+const conversationKeyword = "conversation"
+
 type MentionPayload struct {
 	Tweet *xscraper.Tweet `json:"tweet"`
 }
 
 type MentionHandler struct {
 	mentionService *service.MentionService
+	threadService  *service.ThreadService
 	scrapers       []*xscraper.XScraper
 	logger         *slog.Logger
 	jobQueueClient jobq.JobQueueClient
@@ -29,12 +35,14 @@ type MentionHandler struct {
 // NewMentionHandler constructs a MentionHandler.
 func NewMentionHandler(
 	mentionService *service.MentionService,
+	threadService *service.ThreadService,
 	scrapers []*xscraper.XScraper,
 	logger *slog.Logger,
 	jobQueueClient jobq.JobQueueClient,
 ) *MentionHandler {
 	return &MentionHandler{
 		mentionService: mentionService,
+		threadService:  threadService,
 		scrapers:       scrapers,
 		logger:         logger.With("job_handler", "mention"),
 		jobQueueClient: jobQueueClient,
@@ -90,13 +98,16 @@ func (w *MentionHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 	}
 
 	// Use InReplyToStatusID as the thread ID to scrape
-	threadID := mention.InReplyToStatusID
+	tweetID := mention.InReplyToStatusID
+	threadID := tweetID
+	conversation := wantsConversation(mention.Text)
 
 	logger := w.logger.With(
 		"job_type", j.Type,
 		"mention_user_id", mentionUserID,
 		"mention_id", mention.RestID,
-		"thread_id", threadID,
+		"tweet_id", tweetID,
+		"conversation", conversation,
 		"author_screen_name", mention.Author.ScreenName,
 	)
 
@@ -105,6 +116,18 @@ func (w *MentionHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 		"created_at", mention.CreatedAt.Format(time.RFC3339),
 	)
 
+	// Conversations have their own thread, shared by everyone asking for the
+	// tweet with the default limits
+	if conversation {
+		thread, err := w.threadService.GetOrCreateConversationThread(ctx, tweetID, xscraper.ConversationOptions{})
+		if err != nil {
+			logger.Error("Failed to create conversation thread", "error", err)
+			return fmt.Errorf("failed to create conversation thread: %w", err)
+		}
+		threadID = thread.ID
+	}
+	logger = logger.With("thread_id", threadID)
+
 	// Create mention record (this will create a pending thread and trigger ThreadScrapeJob)
 	_, err := w.mentionService.CreateMention(ctx, mentionUserID, threadID, &mention.RestID, mention.CreatedAt)
 	if err != nil {
@@ -119,7 +142,12 @@ func (w *MentionHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 	logger.Info("🤖 Created mention and thread records")
 
 	// Step 2: Create and enqueue thread scrape job (consistent with PostThreadScrape)
-	threadScrapeJob, err := NewThreadScrapeJob(threadID)
+	var threadScrapeJob *jobq.Job
+	if conversation {
+		threadScrapeJob, err = NewThreadScrapeJobInto(threadID, tweetID)
+	} else {
+		threadScrapeJob, err = NewThreadScrapeJob(threadID)
+	}
 	if err != nil {
 		logger.Error("Failed to create thread scrape job", "error", err)
 		return fmt.Errorf("failed to create thread scrape job: %w", err)
@@ -155,3 +183,14 @@ func (w *MentionHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 	)
 	return nil
 }
+
+// wantsConversation reports whether the mention text holds conversationKeyword,
+// with or without a leading '#'
+func wantsConversation(text string) bool {
+	for _, word := range strings.Fields(text) {
+		if strings.EqualFold(strings.TrimPrefix(word, "#"), conversationKeyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+package queue
+
+import "testing"
+
+func TestWantsConversation(t *testing.T) {
+	tests := map[string]bool{
+		"@threadmirror":                        false,
+		"@threadmirror conversation":           true,
+		"@threadmirror #Conversation please":   true,
+		"@threadmirror conversations":          false,
+		"@threadmirror save this conversation": true,
+	}
+	for text, want := range tests {
+		if got := wantsConversation(text); got != want {
+			t.Errorf("wantsConversation(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
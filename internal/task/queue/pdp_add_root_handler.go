@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ipfs-force-community/threadmirror/internal/config"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
+)
+
+const TypePDPAddRoot = "pdp_add_root"
+
+// pdpAddRootJobMaxRetry is how often the queue retries a single AddRoots job.
+// The root's attempts are also counted in the database, so a job re-queued by
+// the cron does not get an unlimited number of tries.
+const pdpAddRootJobMaxRetry = 5
+
+type PDPAddRootPayload struct {
+	RootCID string `json:"root_cid"`
+}
+
+type PDPAddRootHandler struct {
+	pdpPieces   *service.PDPPieceService
+	maxAttempts int
+	logger      *slog.Logger
+}
+
+// NewPDPAddRootHandler constructs a PDPAddRootHandler.
+func NewPDPAddRootHandler(
+	pdpPieces *service.PDPPieceService,
+	cronConfig *config.CronConfig,
+	logger *slog.Logger,
+) *PDPAddRootHandler {
+	maxAttempts := cronConfig.PDPRoot.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	return &PDPAddRootHandler{
+		pdpPieces:   pdpPieces,
+		maxAttempts: maxAttempts,
+		logger:      logger.With("job_handler", "pdp_add_root"),
+	}
+}
+
+// NewPDPAddRootJob creates a new job for adding an aggregate root to the PDP proof set.
+func NewPDPAddRootJob(rootCID string) (*jobq.Job, error) {
+	payload, err := json.Marshal(PDPAddRootPayload{
+		RootCID: rootCID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pdp add root payload: %w", err)
+	}
+
+	job := jobq.NewJob(TypePDPAddRoot, payload)
+	job.MaxRetry = pdpAddRootJobMaxRetry
+	return job, nil
+}
+
+// HandleJob implements the job.JobHandler interface.
+func (h *PDPAddRootHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
+	var payload PDPAddRootPayload
+	if err := json.Unmarshal(j.Payload, &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v", err)
+	}
+
+	if payload.RootCID == "" {
+		return fmt.Errorf("root CID is empty")
+	}
+
+	logger := h.logger.With("root_cid", payload.RootCID)
+
+	err := h.pdpPieces.AddRoot(ctx, payload.RootCID, h.maxAttempts)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, service.ErrPDPRootAddFailed):
+		// Out of attempts, retrying the job would not change anything
+		logger.Error("Giving up adding PDP root", "error", err)
+		return nil
+	case errors.Is(err, service.ErrNotFound):
+		logger.Warn("PDP root is not tracked, skipping")
+		return nil
+	default:
+		return err
+	}
+}
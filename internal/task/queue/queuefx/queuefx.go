@@ -13,16 +13,18 @@ var Module = fx.Module("queue",
 	fx.Provide(internalqueue.NewMentionHandler),
 	fx.Provide(internalqueue.NewReplyTweetHandler),
 	fx.Provide(internalqueue.NewThreadScrapeHandler),
+	fx.Provide(internalqueue.NewPDPAddRootHandler),
 	// Register lifecycle hooks for proper startup/shutdown
 	fx.Invoke(registerJobLifecycle),
 )
 
 // registerJobLifecycle sets up proper startup and shutdown hooks for job processing
-func registerJobLifecycle(lc fx.Lifecycle, registry jobq.JobHandlerRegistry, mentionHandler *internalqueue.MentionHandler, replyHandler *internalqueue.ReplyTweetHandler, threadScrapeHandler *internalqueue.ThreadScrapeHandler) {
+func registerJobLifecycle(lc fx.Lifecycle, registry jobq.JobHandlerRegistry, mentionHandler *internalqueue.MentionHandler, replyHandler *internalqueue.ReplyTweetHandler, threadScrapeHandler *internalqueue.ThreadScrapeHandler, pdpAddRootHandler *internalqueue.PDPAddRootHandler) {
 	lc.Append(fx.StartHook(func(ctx context.Context) error {
 		registry.RegisterHandler(internalqueue.TypeProcessMention, mentionHandler)
 		registry.RegisterHandler(internalqueue.TypeReplyTweet, replyHandler)
 		registry.RegisterHandler(internalqueue.TypeThreadScrape, threadScrapeHandler)
+		registry.RegisterHandler(internalqueue.TypePDPAddRoot, pdpAddRootHandler)
 		return nil
 	}))
 }
@@ -30,8 +30,9 @@ type ReplyTweetHandler struct {
 	mentionService       *service.MentionService
 	threadService        *service.ThreadService
 	processedMarkService *service.ProcessedMarkService
-	scrapers             []*xscraper.XScraper
+	scraperPool          *xscraper.ScraperPool
 	threadURLTemplate    string
+	mediaURLTemplate     string
 	enableImageReply     bool
 	screenshotScale      float64
 }
@@ -42,7 +43,7 @@ func NewReplyTweetHandler(
 	mentionService *service.MentionService,
 	threadService *service.ThreadService,
 	processedMarkService *service.ProcessedMarkService,
-	scrapers []*xscraper.XScraper,
+	scraperPool *xscraper.ScraperPool,
 	commonConfig *config.CommonConfig,
 	botConfig *config.BotConfig,
 ) *ReplyTweetHandler {
@@ -51,8 +52,9 @@ func NewReplyTweetHandler(
 		mentionService:       mentionService,
 		threadService:        threadService,
 		processedMarkService: processedMarkService,
-		scrapers:             scrapers,
+		scraperPool:          scraperPool,
 		threadURLTemplate:    commonConfig.ThreadURLTemplate,
+		mediaURLTemplate:     commonConfig.MediaURLTemplate,
 		enableImageReply:     botConfig.EnableImageReply,
 		screenshotScale:      botConfig.ScreenshotScale,
 	}
@@ -106,8 +108,7 @@ func (h *ReplyTweetHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 
 	var tweets []*xscraper.Tweet
 
-	pool := xscraper.NewScraperPool(h.scrapers)
-	searchRes, err := xscraper.TryWithResult(pool, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
+	searchRes, err := xscraper.TryWithResult(h.scraperPool, xscraper.EndpointSearchTimeline, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
 		return sc.SearchTweets(ctx, searchQuery, 1)
 	})
 	if err != nil {
@@ -124,9 +125,14 @@ func (h *ReplyTweetHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 
 		var buf []byte = nil
 
+		// Tell the user why a thread X will not serve cannot be archived
+		if thread.FailureReason != "" {
+			replyText = fmt.Sprintf("Sorry, this thread cannot be archived: %s.\n\n%s\n\n#threadmirror", unavailableReasonText(thread.FailureReason), threadURL)
+		}
+
 		// Generate image only if image reply is enabled
-		if h.enableImageReply {
-			html, err := comm.RenderThread(h.threadURLTemplate, mention.ThreadID, thread, logger)
+		if h.enableImageReply && thread.FailureReason == "" {
+			html, err := comm.RenderThread(h.threadURLTemplate, h.mediaURLTemplate, mention.ThreadID, thread, logger)
 			if err != nil {
 				return fmt.Errorf("render thread id %s: %w", mention.ThreadID, err)
 			}
@@ -143,7 +149,7 @@ func (h *ReplyTweetHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 		}
 
 		// Use fallback to upload media and create tweet
-		_, err = xscraper.TryWithResult(pool, func(sc *xscraper.XScraper) (*xscraper.Tweet, error) {
+		_, err = xscraper.TryWithResult(h.scraperPool, xscraper.EndpointCreateTweet, func(sc *xscraper.XScraper) (*xscraper.Tweet, error) {
 			mediaIDs := []string{}
 			if len(buf) > 0 {
 				// Upload the generated screenshot and obtain the media ID
@@ -178,3 +184,20 @@ func (h *ReplyTweetHandler) HandleJob(ctx context.Context, j *jobq.Job) error {
 	logger.Info("reply tweet for thread", "thread_id", mention.ThreadID)
 	return nil
 }
+
+// unavailableReasonText describes why X does not serve a tweet to the user
+// who asked for its thread
+func unavailableReasonText(reason xscraper.UnavailableReason) string {
+	switch reason {
+	case xscraper.ReasonDeleted:
+		return "the tweet was deleted"
+	case xscraper.ReasonProtected:
+		return "the author's tweets are protected"
+	case xscraper.ReasonSuspended:
+		return "the author's account is suspended"
+	case xscraper.ReasonAgeRestricted:
+		return "the tweet is age-restricted"
+	default:
+		return "X does not make the tweet available"
+	}
+}
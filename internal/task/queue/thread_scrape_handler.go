@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ipfs-force-community/threadmirror/internal/service"
 	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
@@ -17,12 +18,14 @@ const TypeThreadScrape = "thread_scrape"
 
 type ThreadScrapePayload struct {
 	TweetID string `json:"tweet_id"`
+	// ThreadID is the thread to archive into, the thread of TweetID if empty
+	ThreadID string `json:"thread_id,omitempty"`
 }
 
 type ThreadScrapeHandler struct {
 	mentionService *service.MentionService
 	threadService  *service.ThreadService
-	scrapers       []*xscraper.XScraper
+	scraperPool    *xscraper.ScraperPool
 	logger         *slog.Logger
 }
 
@@ -30,22 +33,36 @@ type ThreadScrapeHandler struct {
 func NewThreadScrapeHandler(
 	mentionService *service.MentionService,
 	threadService *service.ThreadService,
-	scrapers []*xscraper.XScraper,
+	scraperPool *xscraper.ScraperPool,
 	logger *slog.Logger,
 ) *ThreadScrapeHandler {
 	return &ThreadScrapeHandler{
 		mentionService: mentionService,
 		threadService:  threadService,
-		scrapers:       scrapers,
+		scraperPool:    scraperPool,
 		logger:         logger.With("job_handler", "thread_scrape"),
 	}
 }
 
 // NewThreadScrapeJob creates a new job for scraping a thread.
 func NewThreadScrapeJob(tweetID string) (*jobq.Job, error) {
-	payload, err := json.Marshal(ThreadScrapePayload{
+	return newThreadScrapeJob(ThreadScrapePayload{
 		TweetID: tweetID,
 	})
+}
+
+// NewThreadScrapeJobInto creates a job scraping tweetID into threadID, for
+// threads with their own ID: private threads and conversations. The thread's
+// mode decides whether the author's thread or the conversation is scraped.
+func NewThreadScrapeJobInto(threadID, tweetID string) (*jobq.Job, error) {
+	return newThreadScrapeJob(ThreadScrapePayload{
+		TweetID:  tweetID,
+		ThreadID: threadID,
+	})
+}
+
+func newThreadScrapeJob(p ThreadScrapePayload) (*jobq.Job, error) {
+	payload, err := json.Marshal(p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal thread scrape payload: %w", err)
 	}
@@ -64,13 +81,22 @@ func (h *ThreadScrapeHandler) HandleJob(ctx context.Context, j *jobq.Job) error
 		return fmt.Errorf("tweet ID is empty")
 	}
 
+	threadID := payload.ThreadID
+	if threadID == "" {
+		threadID = payload.TweetID
+	}
+
 	logger := h.logger.With(
 		"job_type", j.Type,
 		"tweet_id", payload.TweetID,
+		"thread_id", threadID,
 	)
 
+	// Jobs archive private threads too
+	ctx = service.WithSystemViewer(ctx)
+
 	// Check if thread already exists and determine action based on status
-	existingThread, err := h.threadService.GetThreadByID(ctx, payload.TweetID)
+	existingThread, err := h.threadService.GetThreadByID(ctx, threadID)
 	if err != nil && !errors.Is(err, service.ErrThreadNotFound) {
 		logger.Error("Failed to check existing thread", "error", err)
 		return fmt.Errorf("failed to check existing thread: %w", err)
@@ -105,18 +131,33 @@ func (h *ThreadScrapeHandler) HandleJob(ctx context.Context, j *jobq.Job) error
 	logger.Info("🤖 Starting thread scraping job")
 
 	// Update thread status to scraping with optimistic locking
-	err = h.threadService.UpdateThreadStatus(ctx, payload.TweetID, "scraping", existingThread.Version)
+	err = h.threadService.UpdateThreadStatus(ctx, threadID, "scraping", existingThread.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update thread status to scraping: %w", err)
 	}
 
-	// Use xscraper to get complete thread
-	tweets, err := h.getCompleteThreadFromTweetID(ctx, payload.TweetID)
+	// Use xscraper to get complete thread, or the conversation under the tweet
+	tweets, missing, provenance, err := h.scrapeTweets(ctx, payload.TweetID, existingThread.Conversation)
 	if err != nil {
 		logger.Error("Failed to get complete thread", "error", err)
 		if errors.Is(err, service.ErrThreadNotFound) {
 			return fmt.Errorf("thread not found: %w", err)
 		}
+		if reason, ok := xscraper.IsTweetUnavailable(err); ok {
+			// X will never serve the tweet: fail the thread with the reason;
+			// the error is permanent so the job is not retried either
+			if err := h.threadService.MarkThreadUnavailable(ctx, threadID, reason, existingThread.Version+1); err != nil {
+				logger.Error("Failed to mark thread unavailable", "error", err)
+			}
+			return fmt.Errorf("thread unavailable: %w", err)
+		}
+		if _, ok := jobq.RetryAfter(err); ok {
+			// The job is postponed until an account is ready; put the thread
+			// back to pending so the postponed job does not skip it
+			if err := h.threadService.UpdateThreadStatus(ctx, threadID, "pending", existingThread.Version+1); err != nil {
+				logger.Error("Failed to reset thread status to pending", "error", err)
+			}
+		}
 		return fmt.Errorf("failed to get complete thread: %w", err)
 	}
 
@@ -125,28 +166,32 @@ func (h *ThreadScrapeHandler) HandleJob(ctx context.Context, j *jobq.Job) error
 		return fmt.Errorf("no tweets found for thread %s", payload.TweetID)
 	}
 
-	// Filter out empty RestID tweets (deleted tweets)
-	tweets = lo.Filter(tweets, func(tweet *xscraper.Tweet, _ int) bool {
+	// Unavailable tweets come back as tombstones in missing; a tweet without
+	// an ID cannot be archived either
+	if valid := lo.Filter(tweets, func(tweet *xscraper.Tweet, _ int) bool {
 		return tweet.RestID != ""
-	})
+	}); len(valid) < len(tweets) {
+		logger.Warn("Dropping tweets without ID", "count", len(tweets)-len(valid))
+		tweets = valid
+	}
 
 	if len(tweets) < 1 {
 		logger.Error("No valid tweets found after filtering")
 		return fmt.Errorf("no valid tweets found for thread %s", payload.TweetID)
 	}
 
-	logger.Info("🤖 Successfully scraped tweets", "count", len(tweets))
+	logger.Info("🤖 Successfully scraped tweets", "count", len(tweets), "missing", len(missing))
 
 	// Get fresh thread version for final update
-	finalThread, err := h.threadService.GetThreadByID(ctx, payload.TweetID)
+	finalThread, err := h.threadService.GetThreadByID(ctx, threadID)
 	if err != nil {
 		return fmt.Errorf("failed to get thread for final update: %w", err)
 	}
 
 	// Update the existing thread with scraped data (status, author info, content)
-	err = h.threadService.UpdateThreadWithScrapedData(ctx, payload.TweetID, tweets, finalThread.Version)
+	err = h.threadService.UpdateThreadWithScrapedData(ctx, threadID, tweets, missing, finalThread.Version, provenance)
 	if err != nil {
-		_ = h.threadService.UpdateThreadStatus(ctx, payload.TweetID, "failed", finalThread.Version)
+		_ = h.threadService.UpdateThreadStatus(ctx, threadID, "failed", finalThread.Version)
 		logger.Error("Failed to update thread after scraping", "error", err)
 		return fmt.Errorf("failed to update thread after scraping: %w", err)
 	}
@@ -154,27 +199,36 @@ func (h *ThreadScrapeHandler) HandleJob(ctx context.Context, j *jobq.Job) error
 	logger.Info("🤖 Thread updated successfully with scraped data")
 
 	logger.Info("🤖 Thread scrape completed successfully",
-		"thread_id", payload.TweetID,
+		"thread_id", threadID,
 		"tweets_count", len(tweets),
 	)
 
 	return nil
 }
 
-// getCompleteThreadFromTweetID gets complete thread using xscraper
-func (h *ThreadScrapeHandler) getCompleteThreadFromTweetID(ctx context.Context, tweetID string) ([]*xscraper.Tweet, error) {
-	if len(h.scrapers) == 0 {
-		return nil, errors.New("no scrapers available")
-	}
-
-	pool := xscraper.NewScraperPool(h.scrapers)
-	tweets, err := xscraper.TryWithResult(pool, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
-		return xscraper.GetCompleteThread(ctx, sc, tweetID, 0)
+// scrapeTweets gets the complete thread using xscraper, or the conversation under
+// tweetID if conversation is set, together with the tweets of the thread X did
+// not serve and the account and time it was scraped with
+func (h *ThreadScrapeHandler) scrapeTweets(ctx context.Context, tweetID string, conversation *xscraper.ConversationOptions) ([]*xscraper.Tweet, []xscraper.Tombstone, service.ScrapeProvenance, error) {
+	var provenance service.ScrapeProvenance
+	var missing []xscraper.Tombstone
+	tweets, err := xscraper.TryWithResult(h.scraperPool, xscraper.EndpointTweetDetail, func(sc *xscraper.XScraper) ([]*xscraper.Tweet, error) {
+		var tweets []*xscraper.Tweet
+		var err error
+		if conversation != nil {
+			tweets, err = xscraper.GetConversation(ctx, sc, tweetID, *conversation)
+		} else {
+			tweets, missing, err = xscraper.GetCompleteThreadWithTombstones(ctx, sc, tweetID, 0)
+		}
+		if err == nil {
+			provenance = service.ScrapeProvenance{ScrapedAt: time.Now(), ScraperAccount: sc.LoginOpts.Username}
+		}
+		return tweets, err
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tweets: %w", err)
+		return nil, nil, service.ScrapeProvenance{}, fmt.Errorf("failed to get tweets: %w", err)
 	}
 
-	return tweets, nil
+	return tweets, missing, provenance, nil
 }
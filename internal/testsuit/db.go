@@ -178,6 +178,9 @@ func setupGinkgoContainerSuite() *ContainerTestSuite {
 	threadService := service.NewThreadService(
 		db,
 		ipfs.Storage(mockIPFS),
+		service.NewPDPPieceService(db, mockIPFS, slog.Default()),
+		nil,
+		nil,
 		llm.Model(mockLLM),
 		redisClient,
 		slog.Default(),
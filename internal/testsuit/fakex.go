@@ -0,0 +1,930 @@
+package testsuit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+)
+
+// fakeXRateLimit is the number of requests an account may make to each
+// GraphQL endpoint of FakeX in a window of fakeXRateWindow
+const (
+	fakeXRateLimit  = 500
+	fakeXRateWindow = 15 * time.Minute
+)
+
+// fakeXEpoch is the creation time of the first tweet of a FakeX; later tweets
+// are a minute apart so their order does not depend on the clock
+var fakeXEpoch = time.Date(2025, 5, 9, 0, 0, 0, 0, time.UTC)
+
+// FakeUser is a user of FakeX
+type FakeUser struct {
+	ID         string
+	ScreenName string
+	Name       string
+}
+
+// FakeTweet is a tweet of FakeX
+type FakeTweet struct {
+	ID        string
+	Author    *FakeUser
+	Text      string
+	CreatedAt time.Time
+	// InReplyTo is the ID of the tweet replied to, empty for a new conversation
+	InReplyTo string
+	// MediaIDs are the uploaded media the tweet was created with
+	MediaIDs []string
+}
+
+// FakeX is an in-process fake of the X endpoints XScraper uses: the login
+// flow, TweetDetail, TweetResultByRestId, SearchTimeline, CreateTweet and
+// media uploads, plus the profile images of its users. It is backed by an
+// in-memory graph of users and tweets that tests script with AddUser, Post
+// and Reply, and inspect with Tweet and Replies.
+//
+// Point a scraper at it with LoginOptions. Requests need the session of a
+// login to the fake; anything else is answered like X answers a logged out
+// client.
+type FakeX struct {
+	Server *httptest.Server
+	// transport is the loopback-only transport of the scrapers of LoginOptions
+	transport *http.Transport
+
+	mu       sync.Mutex
+	nextID   int64
+	users    map[string]*FakeUser // by lower-cased screen name
+	tweets   map[string]*FakeTweet
+	children map[string][]string // reply IDs by tweet ID, in creation order
+	// passwords of the users that can log in, by lower-cased screen name
+	passwords map[string]string
+	// sessions maps auth_token cookies to their user; flows maps flow tokens
+	// to the user identifier entered in them
+	sessions map[string]*FakeUser
+	flows    map[string]string
+	// uploads holds the bytes appended to each media ID
+	uploads map[string]int
+	// failures are the statuses to answer the next requests of an endpoint
+	// with; requests counts the requests of each session to each endpoint
+	failures map[xscraper.Endpoint][]int
+	requests map[string]int
+	resetAt  time.Time
+}
+
+// NewFakeX starts a FakeX that is closed when the test ends
+func NewFakeX(t *testing.T) *FakeX {
+	f := &FakeX{
+		nextID:    1920000000000000000,
+		users:     make(map[string]*FakeUser),
+		tweets:    make(map[string]*FakeTweet),
+		children:  make(map[string][]string),
+		passwords: make(map[string]string),
+		sessions:  make(map[string]*FakeUser),
+		flows:     make(map[string]string),
+		uploads:   make(map[string]int),
+		failures:  make(map[xscraper.Endpoint][]int),
+		requests:  make(map[string]int),
+		resetAt:   time.Now().Add(fakeXRateWindow),
+	}
+	f.transport = offlineTransport(t)
+	useFakeTransactionIDPairs(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /i/flow/login", f.handleFlowPage)
+	mux.HandleFunc("POST /1.1/guest/activate.json", f.handleGuestActivate)
+	mux.HandleFunc("GET /1.1/hashflags.json", f.handleHashflags)
+	mux.HandleFunc("POST /1.1/onboarding/task.json", f.handleOnboardingTask)
+	mux.HandleFunc("GET /i/api/graphql/{queryID}/TweetDetail", f.graphQL(xscraper.EndpointTweetDetail, f.handleTweetDetail))
+	mux.HandleFunc("GET /i/api/graphql/{queryID}/TweetResultByRestId", f.graphQL(xscraper.EndpointTweetResultByRestId, f.handleTweetResultByRestID))
+	mux.HandleFunc("GET /i/api/graphql/{queryID}/SearchTimeline", f.graphQL(xscraper.EndpointSearchTimeline, f.handleSearchTimeline))
+	mux.HandleFunc("POST /i/api/graphql/{queryID}/CreateTweet", f.graphQL(xscraper.EndpointCreateTweet, f.handleCreateTweet))
+	mux.HandleFunc("POST /2/media/upload/initialize", f.handleMediaInitialize)
+	mux.HandleFunc("POST /2/media/upload/{mediaID}/append", f.handleMediaAppend)
+	mux.HandleFunc("POST /2/media/upload/{mediaID}/finalize", f.handleMediaFinalize)
+	mux.HandleFunc("GET /profile_images/{userID}", f.handleProfileImage)
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Server.Close)
+	return f
+}
+
+// offlineTransport returns a transport that only dials loopback addresses, for
+// the scrapers of a test driving FakeX, so the test fails rather than quietly
+// reaching x.com or any other host
+func offlineTransport(t *testing.T) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			t.Errorf("test dialed %s outside FakeX", addr)
+			return nil, fmt.Errorf("offline test: dial %s refused", addr)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	t.Cleanup(transport.CloseIdleConnections)
+	return transport
+}
+
+// fakeXPairs counts the tests using the fake transaction ID pair. The pairs
+// are process-wide, so the ones in use before the first of those tests are
+// only restored once the last has ended.
+var fakeXPairs struct {
+	sync.Mutex
+	users int
+	prev  []xscraper.TransactionIDPair
+}
+
+// useFakeTransactionIDPairs installs a fixed transaction ID pair until the test
+// ends. The fake accepts any transaction ID; the pair spares the scraper the
+// remote pair list.
+func useFakeTransactionIDPairs(t *testing.T) {
+	fakeXPairs.Lock()
+	defer fakeXPairs.Unlock()
+	if fakeXPairs.users == 0 {
+		prev, err := xscraper.SetTransactionIDPairs([]xscraper.TransactionIDPair{{AnimationKey: "fake", Verification: "ZmFrZQ=="}})
+		if err != nil {
+			t.Fatalf("set transaction ID pairs: %v", err)
+		}
+		fakeXPairs.prev = prev
+	}
+	fakeXPairs.users++
+
+	t.Cleanup(func() {
+		fakeXPairs.Lock()
+		defer fakeXPairs.Unlock()
+		fakeXPairs.users--
+		if fakeXPairs.users == 0 {
+			if _, err := xscraper.SetTransactionIDPairs(fakeXPairs.prev); err != nil {
+				t.Errorf("restore transaction ID pairs: %v", err)
+			}
+			fakeXPairs.prev = nil
+		}
+	})
+}
+
+// LoginOptions returns the options of a scraper logging in to the fake as the
+// account of screenName, created with AddAccount
+func (f *FakeX) LoginOptions(screenName string) xscraper.LoginOptions {
+	f.mu.Lock()
+	password := f.passwords[strings.ToLower(screenName)]
+	f.mu.Unlock()
+
+	return xscraper.LoginOptions{
+		LoadCookies: func(context.Context) ([]*http.Cookie, error) { return nil, nil },
+		SaveCookies: func(context.Context, []*http.Cookie) error { return nil },
+		Username:    screenName,
+		Password:    password,
+		Email:       screenName + "@example.com",
+		// Media uploads go through the API; the fake does not check OAuth
+		APIKey:            "fake-api-key",
+		APIKeySecret:      "fake-api-key-secret",
+		AccessToken:       "fake-access-token",
+		AccessTokenSecret: "fake-access-token-secret",
+		BaseURL:           f.Server.URL,
+		APIBaseURL:        f.Server.URL,
+		Transport:         f.transport,
+	}
+}
+
+// AddUser adds a user that cannot log in
+func (f *FakeX) AddUser(screenName string) *FakeUser {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addUser(screenName)
+}
+
+// AddAccount adds a user that logs in with password
+func (f *FakeX) AddAccount(screenName, password string) *FakeUser {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.passwords[strings.ToLower(screenName)] = password
+	return f.addUser(screenName)
+}
+
+func (f *FakeX) addUser(screenName string) *FakeUser {
+	if user, ok := f.users[strings.ToLower(screenName)]; ok {
+		return user
+	}
+	user := &FakeUser{
+		ID:         strconv.Itoa(1000 + len(f.users)),
+		ScreenName: screenName,
+		Name:       strings.ToUpper(screenName[:1]) + screenName[1:],
+	}
+	f.users[strings.ToLower(screenName)] = user
+	return user
+}
+
+// Post adds a tweet starting a conversation
+func (f *FakeX) Post(author *FakeUser, text string) *FakeTweet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addTweet(author, text, "", nil)
+}
+
+// Reply adds a reply to the tweet to
+func (f *FakeX) Reply(author *FakeUser, to *FakeTweet, text string) *FakeTweet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addTweet(author, text, to.ID, nil)
+}
+
+func (f *FakeX) addTweet(author *FakeUser, text, inReplyTo string, mediaIDs []string) *FakeTweet {
+	n := len(f.tweets)
+	f.nextID += 1 << 22 // a snowflake a few milliseconds later
+	tweet := &FakeTweet{
+		ID:        strconv.FormatInt(f.nextID, 10),
+		Author:    author,
+		Text:      text,
+		CreatedAt: fakeXEpoch.Add(time.Duration(n) * time.Minute),
+		InReplyTo: inReplyTo,
+		MediaIDs:  mediaIDs,
+	}
+	f.tweets[tweet.ID] = tweet
+	if inReplyTo != "" {
+		f.children[inReplyTo] = append(f.children[inReplyTo], tweet.ID)
+	}
+	return tweet
+}
+
+// Tweet returns a copy of the tweet with the given ID
+func (f *FakeX) Tweet(id string) (FakeTweet, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tweet, ok := f.tweets[id]
+	if !ok {
+		return FakeTweet{}, false
+	}
+	return *tweet, true
+}
+
+// Replies returns copies of the replies to the tweet with the given ID, in
+// the order they were made
+func (f *FakeX) Replies(id string) []FakeTweet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	replies := make([]FakeTweet, 0, len(f.children[id]))
+	for _, childID := range f.children[id] {
+		replies = append(replies, *f.tweets[childID])
+	}
+	return replies
+}
+
+// FailNext answers the next request to endpoint with status, after the
+// failures already scheduled for it. 429 comes with the rate limit headers
+// of a used up limit that resets a second later.
+func (f *FakeX) FailNext(endpoint xscraper.Endpoint, status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[endpoint] = append(f.failures[endpoint], status)
+}
+
+// UploadedBytes returns how many bytes were appended to the upload of
+// mediaID, and whether it was initialized
+func (f *FakeX) UploadedBytes(mediaID string) (int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, ok := f.uploads[mediaID]
+	return n, ok
+}
+
+// ========================================
+// Login flow
+// ========================================
+
+func (f *FakeX) handleFlowPage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = io.WriteString(w, "<!DOCTYPE html><html><head><title>X</title></head><body></body></html>")
+}
+
+func (f *FakeX) handleGuestActivate(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"guest_token": randomToken(8)})
+}
+
+func (f *FakeX) handleHashflags(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []any{})
+}
+
+// handleOnboardingTask walks the login flow of XScraper: JS instrumentation,
+// user identifier, password, duplication check, success. The session cookies
+// are set once the password is accepted.
+func (f *FakeX) handleOnboardingTask(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		FlowToken     string           `json:"flow_token"`
+		SubtaskInputs []map[string]any `json:"subtask_inputs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 214, "Bad request")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if body.FlowToken == "" {
+		if r.URL.Query().Get("flow_name") != "login" {
+			writeAPIErrors(w, http.StatusBadRequest, 214, "Unknown flow")
+			return
+		}
+		f.writeSubtask(w, "", "LoginJsInstrumentationSubtask")
+		return
+	}
+	identifier, ok := f.flows[body.FlowToken]
+	if !ok {
+		writeAPIErrors(w, http.StatusBadRequest, 366, "flow name LoginFlow is currently not accessible")
+		return
+	}
+	delete(f.flows, body.FlowToken)
+	if len(body.SubtaskInputs) == 0 {
+		// After LoginSuccessSubtask the flow is over
+		writeJSON(w, http.StatusOK, map[string]any{"flow_token": body.FlowToken, "status": "success", "subtasks": []any{}})
+		return
+	}
+
+	input := body.SubtaskInputs[0]
+	switch input["subtask_id"] {
+	case "LoginJsInstrumentationSubtask":
+		f.writeSubtask(w, "", "LoginEnterUserIdentifierSSO")
+	case "LoginEnterUserIdentifierSSO":
+		var settings struct {
+			SettingResponses []struct {
+				ResponseData struct {
+					TextData struct {
+						Result string `json:"result"`
+					} `json:"text_data"`
+				} `json:"response_data"`
+			} `json:"setting_responses"`
+		}
+		remarshal(input["settings_list"], &settings)
+		if len(settings.SettingResponses) == 0 {
+			writeAPIErrors(w, http.StatusBadRequest, 214, "Missing user identifier")
+			return
+		}
+		identifier = strings.ToLower(settings.SettingResponses[0].ResponseData.TextData.Result)
+		if _, ok := f.passwords[identifier]; !ok {
+			writeAPIErrors(w, http.StatusBadRequest, 399, "Sorry, we could not find your account.")
+			return
+		}
+		f.writeSubtask(w, identifier, "LoginEnterPassword")
+	case "LoginEnterPassword":
+		var password struct {
+			Password string `json:"password"`
+		}
+		remarshal(input["enter_password"], &password)
+		if password.Password != f.passwords[identifier] {
+			writeAPIErrors(w, http.StatusBadRequest, 399, "Wrong password!")
+			return
+		}
+		f.writeSubtask(w, identifier, "AccountDuplicationCheck")
+	case "AccountDuplicationCheck":
+		authToken := randomToken(20)
+		f.sessions[authToken] = f.users[identifier]
+		http.SetCookie(w, &http.Cookie{Name: "auth_token", Value: authToken, Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "ct0", Value: csrfToken(authToken), Path: "/"})
+		f.writeSubtask(w, identifier, "LoginSuccessSubtask")
+	default:
+		writeAPIErrors(w, http.StatusBadRequest, 214, fmt.Sprintf("Unexpected subtask %v", input["subtask_id"]))
+	}
+}
+
+// writeSubtask starts the next step of a login flow of identifier
+func (f *FakeX) writeSubtask(w http.ResponseWriter, identifier, subtaskID string) {
+	flowToken := "g;" + randomToken(16)
+	f.flows[flowToken] = identifier
+	writeJSON(w, http.StatusOK, map[string]any{
+		"flow_token": flowToken,
+		"status":     "success",
+		"subtasks":   []map[string]any{{"subtask_id": subtaskID}},
+	})
+}
+
+// csrfToken returns the ct0 cookie of a session, which requests must repeat
+// in the X-Csrf-Token header
+func csrfToken(authToken string) string {
+	return "csrf" + authToken
+}
+
+// ========================================
+// GraphQL
+// ========================================
+
+// graphQL wraps a GraphQL handler with the checks X makes first: the session
+// and its CSRF token, scripted failures and the rate limit of the endpoint
+func (f *FakeX) graphQL(endpoint xscraper.Endpoint, handle func(w http.ResponseWriter, r *http.Request, viewer *FakeUser)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		cookie, err := r.Cookie("auth_token")
+		var viewer *FakeUser
+		if err == nil {
+			viewer = f.sessions[cookie.Value]
+		}
+		if viewer == nil || r.Header.Get("X-Csrf-Token") != csrfToken(cookie.Value) {
+			writeAPIErrors(w, http.StatusUnauthorized, 32, "Could not authenticate you.")
+			return
+		}
+
+		key := cookie.Value + " " + string(endpoint)
+		if time.Now().After(f.resetAt) {
+			f.requests = make(map[string]int)
+			f.resetAt = time.Now().Add(fakeXRateWindow)
+		}
+		f.requests[key]++
+		remaining := max(fakeXRateLimit-f.requests[key], 0)
+		status := 0
+		if failures := f.failures[endpoint]; len(failures) > 0 {
+			status, f.failures[endpoint] = failures[0], failures[1:]
+		}
+		resetAt := f.resetAt
+		if status == http.StatusTooManyRequests {
+			// A scripted limit resets right away, so tests do not wait the window out
+			remaining, resetAt = 0, time.Now().Add(time.Second)
+		}
+		w.Header().Set("X-Rate-Limit-Limit", strconv.Itoa(fakeXRateLimit))
+		w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		switch {
+		case status == http.StatusTooManyRequests || f.requests[key] > fakeXRateLimit:
+			writeAPIErrors(w, http.StatusTooManyRequests, 88, "Rate limit exceeded")
+		case status != 0:
+			writeAPIErrors(w, status, 0, http.StatusText(status))
+		default:
+			handle(w, r, viewer)
+		}
+	}
+}
+
+// graphQLVariables decodes the variables of a GET GraphQL request
+func graphQLVariables(r *http.Request, v any) error {
+	return json.Unmarshal([]byte(r.URL.Query().Get("variables")), v)
+}
+
+func (f *FakeX) handleTweetDetail(w http.ResponseWriter, r *http.Request, _ *FakeUser) {
+	var variables struct {
+		FocalTweetID string `json:"focalTweetId"`
+	}
+	if err := graphQLVariables(r, &variables); err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 214, "Bad variables")
+		return
+	}
+	focal, ok := f.tweets[variables.FocalTweetID]
+	if !ok {
+		writeJSON(w, http.StatusOK, graphQLErrors(144, "_Missing: No status found with that ID."))
+		return
+	}
+
+	// The ancestors of the focal tweet down from the root of the conversation,
+	// then a module for each reply followed by its author's own replies
+	var ancestors []any
+	for tweet := focal; tweet != nil; tweet = f.tweets[tweet.InReplyTo] {
+		ancestors = append(ancestors, f.tweetEntry(tweet))
+	}
+	slices.Reverse(ancestors)
+	entries := ancestors
+	for _, replyID := range f.children[focal.ID] {
+		entries = append(entries, f.conversationEntry(f.tweets[replyID]))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"threaded_conversation_with_injections_v2": map[string]any{
+				"instructions": []any{
+					map[string]any{"type": "TimelineAddEntries", "entries": entries},
+					// The whole conversation above the focal tweet is included
+					map[string]any{"type": "TimelineTerminateTimeline", "direction": "Top"},
+				},
+			},
+		},
+	})
+}
+
+func (f *FakeX) handleTweetResultByRestID(w http.ResponseWriter, r *http.Request, _ *FakeUser) {
+	var variables struct {
+		TweetID string `json:"tweetId"`
+	}
+	if err := graphQLVariables(r, &variables); err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 214, "Bad variables")
+		return
+	}
+	result := map[string]any{}
+	if tweet, ok := f.tweets[variables.TweetID]; ok {
+		result["result"] = f.tweetResult(tweet)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"tweetResult": result}})
+}
+
+func (f *FakeX) handleSearchTimeline(w http.ResponseWriter, r *http.Request, _ *FakeUser) {
+	var variables struct {
+		RawQuery string `json:"rawQuery"`
+		Count    int    `json:"count"`
+	}
+	if err := graphQLVariables(r, &variables); err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 214, "Bad variables")
+		return
+	}
+	query := parseSearchQuery(variables.RawQuery)
+
+	matches := make([]*FakeTweet, 0)
+	for _, tweet := range f.tweets {
+		if query.matches(tweet, f.tweets[tweet.InReplyTo]) {
+			matches = append(matches, tweet)
+		}
+	}
+	// Newest first
+	slices.SortFunc(matches, func(a, b *FakeTweet) int { return strings.Compare(b.ID, a.ID) })
+	if variables.Count > 0 && len(matches) > variables.Count {
+		matches = matches[:variables.Count]
+	}
+	entries := make([]any, 0, len(matches))
+	for _, tweet := range matches {
+		entries = append(entries, f.tweetEntry(tweet))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"search_by_raw_query": map[string]any{
+				"search_timeline": map[string]any{
+					"timeline": map[string]any{
+						"instructions": []any{
+							map[string]any{"type": "TimelineAddEntries", "entries": entries},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func (f *FakeX) handleCreateTweet(w http.ResponseWriter, r *http.Request, viewer *FakeUser) {
+	var body struct {
+		Variables struct {
+			TweetText string `json:"tweet_text"`
+			Reply     *struct {
+				InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+			} `json:"reply"`
+			Media struct {
+				MediaEntities []struct {
+					MediaID string `json:"media_id"`
+				} `json:"media_entities"`
+			} `json:"media"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 214, "Bad request")
+		return
+	}
+
+	inReplyTo := ""
+	if body.Variables.Reply != nil {
+		inReplyTo = body.Variables.Reply.InReplyToTweetID
+		if _, ok := f.tweets[inReplyTo]; !ok {
+			writeJSON(w, http.StatusOK, graphQLErrors(385, "You attempted to reply to a Tweet that is deleted or not visible to you."))
+			return
+		}
+	}
+	var mediaIDs []string
+	for _, media := range body.Variables.Media.MediaEntities {
+		if _, ok := f.uploads[media.MediaID]; !ok {
+			writeJSON(w, http.StatusOK, graphQLErrors(324, "Invalid media id "+media.MediaID))
+			return
+		}
+		mediaIDs = append(mediaIDs, media.MediaID)
+	}
+
+	tweet := f.addTweet(viewer, body.Variables.TweetText, inReplyTo, mediaIDs)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"create_tweet": map[string]any{
+				"tweet_results": map[string]any{"result": f.tweetResult(tweet)},
+			},
+		},
+	})
+}
+
+// tweetEntry returns the timeline entry of a tweet
+func (f *FakeX) tweetEntry(tweet *FakeTweet) map[string]any {
+	return map[string]any{
+		"entryId":   "tweet-" + tweet.ID,
+		"sortIndex": tweet.ID,
+		"content": map[string]any{
+			"entryType":   "TimelineTimelineItem",
+			"__typename":  "TimelineTimelineItem",
+			"itemContent": f.timelineTweet(tweet),
+		},
+	}
+}
+
+// conversationEntry returns the module of a reply under the focal tweet of a
+// TweetDetail, with the replies its author made to it in turn
+func (f *FakeX) conversationEntry(reply *FakeTweet) map[string]any {
+	entryID := "conversationthread-" + reply.ID
+	var items []any
+	for tweet := reply; tweet != nil; {
+		items = append(items, map[string]any{
+			"entryId": entryID + "-tweet-" + tweet.ID,
+			"item":    map[string]any{"itemContent": f.timelineTweet(tweet)},
+		})
+		next := (*FakeTweet)(nil)
+		for _, childID := range f.children[tweet.ID] {
+			if child := f.tweets[childID]; child.Author == reply.Author {
+				next = child
+				break
+			}
+		}
+		tweet = next
+	}
+	return map[string]any{
+		"entryId":   entryID,
+		"sortIndex": reply.ID,
+		"content": map[string]any{
+			"entryType":   "TimelineTimelineModule",
+			"__typename":  "TimelineTimelineModule",
+			"displayType": "VerticalConversation",
+			"items":       items,
+		},
+	}
+}
+
+func (f *FakeX) timelineTweet(tweet *FakeTweet) map[string]any {
+	return map[string]any{
+		"itemType":            "TimelineTweet",
+		"__typename":          "TimelineTweet",
+		"tweetDisplayType":    "Tweet",
+		"tweet_results":       map[string]any{"result": f.tweetResult(tweet)},
+		"hasModeratedReplies": false,
+	}
+}
+
+// mentionPattern matches the screen names mentioned in tweet text
+var mentionPattern = regexp.MustCompile(`@(\w{1,15})`)
+
+// tweetResult returns a tweet as X's GraphQL API serializes it
+func (f *FakeX) tweetResult(tweet *FakeTweet) map[string]any {
+	conversationID := tweet.ID
+	for parent := f.tweets[tweet.InReplyTo]; parent != nil; parent = f.tweets[parent.InReplyTo] {
+		conversationID = parent.ID
+	}
+
+	userMentions := []any{}
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(tweet.Text, -1) {
+		user, ok := f.users[strings.ToLower(tweet.Text[match[2]:match[3]])]
+		if !ok {
+			continue
+		}
+		start := len([]rune(tweet.Text[:match[0]]))
+		userMentions = append(userMentions, map[string]any{
+			"id_str":      user.ID,
+			"name":        user.Name,
+			"screen_name": user.ScreenName,
+			"indices":     []int{start, start + len([]rune(tweet.Text[match[0]:match[1]]))},
+		})
+	}
+
+	legacy := map[string]any{
+		"id_str":              tweet.ID,
+		"user_id_str":         tweet.Author.ID,
+		"full_text":           tweet.Text,
+		"created_at":          tweet.CreatedAt.Format(time.RubyDate),
+		"conversation_id_str": conversationID,
+		"display_text_range":  []int{0, len([]rune(tweet.Text))},
+		"entities": map[string]any{
+			"hashtags":      []any{},
+			"symbols":       []any{},
+			"urls":          []any{},
+			"user_mentions": userMentions,
+		},
+		"lang":            "en",
+		"reply_count":     len(f.children[tweet.ID]),
+		"retweet_count":   0,
+		"favorite_count":  0,
+		"quote_count":     0,
+		"bookmark_count":  0,
+		"is_quote_status": false,
+		"favorited":       false,
+		"retweeted":       false,
+		"bookmarked":      false,
+	}
+	if parent, ok := f.tweets[tweet.InReplyTo]; ok {
+		legacy["in_reply_to_status_id_str"] = parent.ID
+		legacy["in_reply_to_user_id_str"] = parent.Author.ID
+		legacy["in_reply_to_screen_name"] = parent.Author.ScreenName
+	}
+
+	return map[string]any{
+		"__typename": "Tweet",
+		"rest_id":    tweet.ID,
+		"core": map[string]any{
+			"user_results": map[string]any{"result": f.userResult(tweet.Author)},
+		},
+		"legacy":          legacy,
+		"is_translatable": false,
+		"source":          `<a href="https://x.com" rel="nofollow">Twitter Web App</a>`,
+		"views":           map[string]any{"state": "Enabled"},
+	}
+}
+
+func (f *FakeX) userResult(user *FakeUser) map[string]any {
+	createdAt := fakeXEpoch.AddDate(-1, 0, 0).Format(time.RubyDate)
+	return map[string]any{
+		"__typename":       "User",
+		"id":               "VXNlcjo" + user.ID,
+		"rest_id":          user.ID,
+		"is_blue_verified": false,
+		"core": map[string]any{
+			"created_at":  createdAt,
+			"name":        user.Name,
+			"screen_name": user.ScreenName,
+		},
+		"legacy": map[string]any{
+			"created_at":              createdAt,
+			"name":                    user.Name,
+			"screen_name":             user.ScreenName,
+			"description":             "",
+			"followers_count":         0,
+			"friends_count":           0,
+			"statuses_count":          0,
+			"verified":                false,
+			"profile_image_url_https": f.Server.URL + "/profile_images/" + user.ID + ".png",
+		},
+	}
+}
+
+// searchQuery is the part of X's search syntax the scraper uses: terms and
+// quoted phrases the text must contain, from:, to: and filter:replies
+type searchQuery struct {
+	terms   []string
+	from    string
+	to      string
+	replies bool
+}
+
+// searchTokenPattern splits a query into quoted phrases and words
+var searchTokenPattern = regexp.MustCompile(`"[^"]*"|\S+`)
+
+func parseSearchQuery(raw string) searchQuery {
+	var q searchQuery
+	for _, token := range searchTokenPattern.FindAllString(raw, -1) {
+		if !strings.HasPrefix(token, `"`) {
+			token = strings.Trim(token, "()")
+		}
+		switch {
+		case strings.HasPrefix(token, "from:"):
+			q.from = strings.ToLower(strings.TrimPrefix(token, "from:"))
+		case strings.HasPrefix(token, "to:"):
+			q.to = strings.ToLower(strings.TrimPrefix(token, "to:"))
+		case token == "filter:replies":
+			q.replies = true
+		case token != "":
+			q.terms = append(q.terms, strings.ToLower(strings.Trim(token, `"`)))
+		}
+	}
+	return q
+}
+
+func (q searchQuery) matches(tweet, parent *FakeTweet) bool {
+	if q.from != "" && strings.ToLower(tweet.Author.ScreenName) != q.from {
+		return false
+	}
+	if q.to != "" && (parent == nil || strings.ToLower(parent.Author.ScreenName) != q.to) {
+		return false
+	}
+	if q.replies && tweet.InReplyTo == "" {
+		return false
+	}
+	text := strings.ToLower(tweet.Text)
+	for _, term := range q.terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// ========================================
+// Media upload
+// ========================================
+
+func (f *FakeX) handleMediaInitialize(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		TotalBytes int    `json:"total_bytes"`
+		MediaType  string `json:"media_type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.TotalBytes <= 0 {
+		writeAPIErrors(w, http.StatusBadRequest, 0, "Invalid media upload")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID += 1 << 22
+	mediaID := strconv.FormatInt(f.nextID, 10)
+	f.uploads[mediaID] = 0
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{"id": mediaID, "media_key": "3_" + mediaID, "expires_after_secs": 86400},
+	})
+}
+
+func (f *FakeX) handleMediaAppend(w http.ResponseWriter, r *http.Request) {
+	mediaID := r.PathValue("mediaID")
+	file, _, err := r.FormFile("media")
+	if err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 0, "Missing media")
+		return
+	}
+	defer file.Close() // nolint:errcheck
+	n, err := io.Copy(io.Discard, file)
+	if err != nil {
+		writeAPIErrors(w, http.StatusBadRequest, 0, "Bad media")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.uploads[mediaID]; !ok {
+		writeAPIErrors(w, http.StatusBadRequest, 0, "Unknown media ID")
+		return
+	}
+	f.uploads[mediaID] += int(n)
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{"expires_at": time.Now().Add(24 * time.Hour).Unix()}})
+}
+
+func (f *FakeX) handleMediaFinalize(w http.ResponseWriter, r *http.Request) {
+	mediaID := r.PathValue("mediaID")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	size, ok := f.uploads[mediaID]
+	if !ok {
+		writeAPIErrors(w, http.StatusBadRequest, 0, "Unknown media ID")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": map[string]any{"id": mediaID, "media_key": "3_" + mediaID, "size": size, "expires_after_secs": 86400},
+	})
+}
+
+// fakeProfileImage is a 1x1 transparent PNG
+var fakeProfileImage, _ = hex.DecodeString("89504e470d0a1a0a0000000d4948445200000001000000010806000000" +
+	"1f15c4890000000d49444154789c6360000002000185d3f3a50000000049454e44ae426082")
+
+func (f *FakeX) handleProfileImage(w http.ResponseWriter, r *http.Request) {
+	if path.Ext(r.PathValue("userID")) != ".png" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	_, _ = w.Write(fakeProfileImage)
+}
+
+// ========================================
+// Helpers
+// ========================================
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIErrors answers with the error body of X's APIs
+func writeAPIErrors(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]any{"errors": []map[string]any{{"code": code, "message": message}}})
+}
+
+// graphQLErrors returns the body of a GraphQL request X answered with errors
+func graphQLErrors(code int, message string) map[string]any {
+	return map[string]any{"errors": []map[string]any{{"code": code, "message": message}}, "data": map[string]any{}}
+}
+
+// remarshal decodes the JSON value v, decoded into any, into target
+func remarshal(v any, target any) {
+	data, _ := json.Marshal(v)
+	_ = json.Unmarshal(data, target)
+}
+
+func randomToken(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,120 @@
+package testsuit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFakeX 通过真实的 XScraper 驱动 FakeX：登录、抓取线程、搜索提及、上传媒体并回复
+func TestFakeX(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFakeX(t)
+	fake.AddAccount("mirror_bot", "hunter2")
+	alice := fake.AddUser("alice")
+	bob := fake.AddUser("bob")
+
+	root := fake.Post(alice, "1/ a thread about fakes")
+	second := fake.Reply(alice, root, "2/ they keep tests offline")
+	third := fake.Reply(alice, second, "3/ the end")
+	mention := fake.Reply(bob, third, "@mirror_bot please save this")
+
+	x, err := xscraper.New(fake.LoginOptions("mirror_bot"), slog.Default())
+	require.NoError(t, err)
+
+	t.Run("Thread", func(t *testing.T) {
+		tweets, err := xscraper.GetCompleteThread(ctx, x, third.ID, 0)
+		require.NoError(t, err)
+		ids := make([]string, len(tweets))
+		for i, tweet := range tweets {
+			ids[i] = tweet.RestID
+			require.Equal(t, root.ID, tweet.ConversationID)
+			require.Equal(t, "alice", tweet.Author.ScreenName)
+		}
+		require.Equal(t, []string{root.ID, second.ID, third.ID}, ids)
+	})
+
+	t.Run("Mentions", func(t *testing.T) {
+		mentions, err := x.GetMentions(ctx, func(*xscraper.Tweet) bool { return true })
+		require.NoError(t, err)
+		require.Len(t, mentions, 1)
+		require.Equal(t, mention.ID, mentions[0].RestID)
+		require.True(t, mentions[0].IsReply)
+		require.Equal(t, third.ID, mentions[0].InReplyToStatusID)
+	})
+
+	t.Run("ReplyWithMedia", func(t *testing.T) {
+		image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 256)
+		upload, err := x.UploadMedia(ctx, bytes.NewReader(image), len(image))
+		require.NoError(t, err)
+		uploaded, ok := fake.UploadedBytes(upload.MediaID)
+		require.True(t, ok)
+		require.Equal(t, len(image), uploaded)
+
+		reply, err := x.CreateTweet(ctx, xscraper.NewTweet{
+			Text:             "saved",
+			MediaIDs:         []string{upload.MediaID},
+			InReplyToTweetId: &mention.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, mention.ID, reply.InReplyToStatusID)
+
+		replies := fake.Replies(mention.ID)
+		require.Len(t, replies, 1)
+		require.Equal(t, "mirror_bot", replies[0].Author.ScreenName)
+		require.Equal(t, []string{upload.MediaID}, replies[0].MediaIDs)
+	})
+
+	t.Run("FailNext", func(t *testing.T) {
+		fake.FailNext(xscraper.EndpointTweetDetail, http.StatusTooManyRequests)
+		_, err := x.GetTweetDetail(ctx, root.ID)
+		var berr *xscraper.BadRequestError
+		require.ErrorAs(t, err, &berr)
+		require.Equal(t, http.StatusTooManyRequests, berr.StatusCode)
+
+		// The failure is used up
+		tweets, err := x.GetTweetDetail(ctx, root.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, tweets)
+	})
+}
+
+func TestFakeXWrongPassword(t *testing.T) {
+	fake := NewFakeX(t)
+	fake.AddAccount("mirror_bot", "hunter2")
+	alice := fake.AddUser("alice")
+	root := fake.Post(alice, "hello")
+
+	opts := fake.LoginOptions("mirror_bot")
+	opts.Password = "wrong"
+	x, err := xscraper.New(opts, slog.Default())
+	require.NoError(t, err)
+
+	_, err = x.GetTweetDetail(context.Background(), root.ID)
+	require.ErrorContains(t, err, "Wrong password")
+}
+
+// TestFakeXLeavesGlobalsAlone 检查 FakeX 不改动进程级的 http.DefaultTransport，并在测试结束后恢复 transaction ID pairs
+func TestFakeXLeavesGlobalsAlone(t *testing.T) {
+	transport := http.DefaultTransport
+	sentinel := []xscraper.TransactionIDPair{{AnimationKey: "sentinel", Verification: "c2VudGluZWw="}}
+	prev, err := xscraper.SetTransactionIDPairs(sentinel)
+	require.NoError(t, err)
+
+	t.Run("FakeX", func(t *testing.T) {
+		fake := NewFakeX(t)
+		NewFakeX(t)
+		require.Same(t, transport, http.DefaultTransport)
+		require.NotNil(t, fake.LoginOptions("mirror_bot").Transport)
+	})
+
+	restored, err := xscraper.SetTransactionIDPairs(prev)
+	require.NoError(t, err)
+	require.Equal(t, "sentinel", restored[0].AnimationKey)
+	require.Same(t, transport, http.DefaultTransport)
+}
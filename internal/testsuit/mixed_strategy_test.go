@@ -36,6 +36,9 @@ func TestMixedStrategy_ThreadService(t *testing.T) {
 	threadService := service.NewThreadService(
 		suite.DB,
 		ipfs.Storage(mockIPFS),
+		service.NewPDPPieceService(suite.DB, mockIPFS, slog.Default()),
+		nil,
+		nil,
 		mockLLM,
 		suite.RedisClient,
 		slog.Default(),
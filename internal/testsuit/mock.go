@@ -159,6 +159,13 @@ func (m *MockXScraper) GetTweetDetail(ctx context.Context, id string) ([]*xscrap
 	return m.MockTweets, nil
 }
 
+func (m *MockXScraper) GetConversationPage(ctx context.Context, id, cursor string) (*xscraper.ConversationPage, error) {
+	if m.ShouldReturnError {
+		return nil, &xscraper.BadRequestError{StatusCode: 404, Body: "Tweet not found"}
+	}
+	return &xscraper.ConversationPage{Tweets: m.MockTweets}, nil
+}
+
 func (m *MockXScraper) GetTweetResultByRestId(ctx context.Context, id string) (*xscraper.Tweet, error) {
 	if m.ShouldReturnError {
 		return nil, &xscraper.BadRequestError{StatusCode: 404, Body: "Tweet not found"}
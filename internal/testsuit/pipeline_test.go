@@ -0,0 +1,126 @@
+package testsuit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ipfs-force-community/threadmirror/internal/config"
+	"github.com/ipfs-force-community/threadmirror/internal/service"
+	"github.com/ipfs-force-community/threadmirror/internal/task/cron"
+	"github.com/ipfs-force-community/threadmirror/internal/task/queue"
+	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
+	"github.com/ipfs-force-community/threadmirror/pkg/jobq"
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryJobQueue 记录入队的任务，由测试按顺序交给对应的 handler 执行
+type memoryJobQueue struct {
+	jobs []*jobq.Job
+}
+
+func (q *memoryJobQueue) Enqueue(_ context.Context, job *jobq.Job) (string, error) {
+	q.jobs = append(q.jobs, job)
+	return fmt.Sprintf("job-%d", len(q.jobs)), nil
+}
+
+// drain 依次执行队列中的任务（包括执行过程中新入队的任务），返回执行过的任务类型
+func (q *memoryJobQueue) drain(t *testing.T, handlers map[string]jobq.JobHandler) []string {
+	t.Helper()
+	var types []string
+	for len(q.jobs) > 0 {
+		job := q.jobs[0]
+		q.jobs = q.jobs[1:]
+		handler, ok := handlers[job.Type]
+		require.True(t, ok, "no handler for job type %s", job.Type)
+		require.NoError(t, handler.HandleJob(context.Background(), job), job.Type)
+		types = append(types, job.Type)
+	}
+	return types
+}
+
+// TestMentionPipeline 在 FakeX 上跑完整的提及流程：
+// 提及检查 → MentionHandler → ThreadScrapeHandler → ReplyTweetHandler
+func TestMentionPipeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("跳过需要testcontainers的测试")
+	}
+
+	suite := SetupContainerTestSuite(t)
+	defer suite.TearDown(t)
+	suite.ResetDatabase(t)
+	ctx := context.Background()
+	logger := slog.Default()
+
+	// 在 FakeX 上构造一个线程，以及请求机器人保存它的提及
+	fake := NewFakeX(t)
+	fake.AddAccount("mirror_bot", "hunter2")
+	alice := fake.AddUser("alice")
+	bob := fake.AddUser("bob")
+	root := fake.Post(alice, "1/ how the pipeline works")
+	second := fake.Reply(alice, root, "2/ mentions become jobs")
+	last := fake.Reply(alice, second, "3/ and the bot replies")
+	mention := fake.Reply(bob, last, "@mirror_bot save this please")
+
+	scraper, err := xscraper.New(fake.LoginOptions("mirror_bot"), logger)
+	require.NoError(t, err)
+	pool := xscraper.NewScraperPool([]*xscraper.XScraper{scraper}, xscraper.PoolConfig{}, logger)
+
+	// 真实数据库 + Mock 外部依赖
+	mockIPFS := &MockIPFSStorage{}
+	mockLLM := &MockLLM{}
+	mentionService := service.NewMentionService(suite.DB, mockLLM, ipfs.Storage(mockIPFS))
+	threadService := service.NewThreadService(
+		suite.DB,
+		ipfs.Storage(mockIPFS),
+		service.NewPDPPieceService(suite.DB, mockIPFS, logger),
+		nil,
+		nil,
+		mockLLM,
+		suite.RedisClient,
+		logger,
+	)
+	processedMarkService := service.NewProcessedMarkService(suite.DB)
+
+	jobs := &memoryJobQueue{}
+	handlers := map[string]jobq.JobHandler{
+		queue.TypeProcessMention: queue.NewMentionHandler(mentionService, threadService, pool.Scrapers(), logger, jobs),
+		queue.TypeThreadScrape:   queue.NewThreadScrapeHandler(mentionService, threadService, pool, logger),
+		queue.TypeReplyTweet: queue.NewReplyTweetHandler(logger, mentionService, threadService, processedMarkService, pool,
+			&config.CommonConfig{
+				ThreadURLTemplate: "https://threadmirror.example/thread/%s",
+				MediaURLTemplate:  "https://threadmirror.example/media/%s",
+			},
+			&config.BotConfig{},
+		),
+	}
+
+	checker := cron.NewMentionCheckHandler(logger, pool, jobs, cron.MentionCheckConfig{})
+	require.NoError(t, checker.Execute(ctx))
+	require.Len(t, jobs.jobs, 1, "the mention is found")
+
+	ran := jobs.drain(t, handlers)
+	require.Equal(t, []string{queue.TypeProcessMention, queue.TypeThreadScrape, queue.TypeReplyTweet}, ran)
+
+	// 线程已归档
+	thread, err := threadService.GetThreadByID(ctx, last.ID)
+	require.NoError(t, err)
+	require.Equal(t, "completed", thread.Status)
+	require.Equal(t, 3, thread.NumTweets)
+	require.Equal(t, "alice", thread.Author.ScreenName)
+
+	// 机器人在提及下回复了线程链接
+	replies := fake.Replies(mention.ID)
+	require.Len(t, replies, 1)
+	require.Equal(t, "mirror_bot", replies[0].Author.ScreenName)
+	require.True(t, strings.HasPrefix(replies[0].Text, "https://threadmirror.example/thread/"), replies[0].Text)
+	require.Contains(t, replies[0].Text, "#threadmirror")
+
+	// 再次检查提及不会重复回复
+	require.NoError(t, checker.Execute(ctx))
+	jobs.drain(t, handlers)
+	require.Len(t, fake.Replies(mention.ID), 1)
+}
@@ -8,7 +8,9 @@ import (
 	apifx "github.com/ipfs-force-community/threadmirror/internal/api/apifx"
 	"github.com/ipfs-force-community/threadmirror/internal/config"
 	servicefx "github.com/ipfs-force-community/threadmirror/internal/service/servicefx"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest/attestfx"
 	sqlfx "github.com/ipfs-force-community/threadmirror/pkg/database/sql/sqlfx"
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring/keyringfx"
 	logfx "github.com/ipfs-force-community/threadmirror/pkg/log/logfx"
 	"go.uber.org/fx"
 )
@@ -36,6 +38,10 @@ func NewTestServer(ctx context.Context, opts ...fx.Option) (*fx.App, error) {
 	baseModules := []fx.Option{
 		fx.NopLogger,
 		fx.Supply(serverCfg, dbCfg, botCfg), // Supply individual config structs
+		fx.Supply(&attestfx.Config{}),       // No signing key: attestations are skipped
+		attestfx.Module,
+		fx.Supply(&keyringfx.Config{}), // No master key: private archives are disabled
+		keyringfx.Module,
 		logfx.Module,
 		sqlfx.Module,
 		servicefx.Module,
@@ -104,6 +104,9 @@ func SetupContainerTestSuite(t *testing.T) *ContainerTestSuite {
 	threadService := service.NewThreadService(
 		db,
 		ipfs.Storage(mockIPFS),
+		service.NewPDPPieceService(db, mockIPFS, slog.Default()),
+		nil,
+		nil,
 		llm.Model(mockLLM),
 		redisClient,
 		slog.Default(),
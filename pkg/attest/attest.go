@@ -0,0 +1,221 @@
+// Package attest signs and verifies provenance attestations of archived threads.
+//
+// An attestation is a Statement wrapped in a DSSE envelope
+// (https://github.com/secure-systems-lab/dsse): the statement JSON is the
+// base64 payload, and each signature is an ECDSA P-256 / SHA-256 signature
+// over the DSSE pre-authentication encoding of the payload. Anyone holding the
+// public key can verify an envelope offline.
+package attest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// PayloadType is the DSSE payload type of an attestation envelope
+	PayloadType = "application/vnd.threadmirror.attestation+json"
+
+	// StatementType identifies the statement format
+	StatementType = "https://threadmirror.xyz/attestation/v1"
+)
+
+var (
+	// ErrInvalidSignature means no signature of an envelope verifies with the key
+	ErrInvalidSignature = errors.New("attestation signature is invalid")
+	// ErrUnsupportedPayload means the envelope does not hold a thread statement
+	ErrUnsupportedPayload = errors.New("unsupported attestation payload")
+)
+
+// Statement is what ThreadMirror attests about an archive: that it captured
+// the given tweets of a thread with a scraper account at a point in time and
+// stored them under the content CID
+type Statement struct {
+	Type            string    `json:"type"`
+	ThreadID        string    `json:"thread_id"`
+	TweetIDs        []string  `json:"tweet_ids"`
+	ScrapedAt       time.Time `json:"scraped_at"`
+	ScraperAccount  string    `json:"scraper_account"`
+	ContentCID      string    `json:"content_cid"`
+	SoftwareVersion string    `json:"software_version"`
+}
+
+// Envelope is a DSSE envelope holding a signed statement
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a DSSE signature
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Signer signs statements with an ECDSA P-256 key
+type Signer struct {
+	key   *ecdsa.PrivateKey
+	keyID string
+}
+
+// NewSigner creates a Signer from a P-256 private key
+func NewSigner(key *ecdsa.PrivateKey) (*Signer, error) {
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("attestation key must use the P-256 curve")
+	}
+	keyID, err := KeyID(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: key, keyID: keyID}, nil
+}
+
+// KeyID returns the ID of the signing key
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the public key to verify attestations with
+func (s *Signer) PublicKey() *ecdsa.PublicKey {
+	return &s.key.PublicKey
+}
+
+// Sign wraps statement in a signed envelope
+func (s *Signer) Sign(statement Statement) (*Envelope, error) {
+	statement.Type = StatementType
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal statement: %w", err)
+	}
+	return s.SignPayload(PayloadType, payload)
+}
+
+// SignPayload wraps an arbitrary payload of payloadType in a signed envelope
+func (s *Signer) SignPayload(payloadType string, payload []byte) (*Envelope, error) {
+	digest := sha256.Sum256(pae(payloadType, payload))
+	sig, err := ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign payload: %w", err)
+	}
+
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: s.keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks that envelope is signed by pub and returns its statement
+func Verify(envelope *Envelope, pub *ecdsa.PublicKey) (*Statement, error) {
+	payload, err := VerifyPayload(envelope, PayloadType, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	if statement.Type != StatementType {
+		return nil, fmt.Errorf("%w: statement type %q", ErrUnsupportedPayload, statement.Type)
+	}
+	return &statement, nil
+}
+
+// VerifyPayload checks that envelope holds a payload of payloadType signed by
+// pub and returns the payload
+func VerifyPayload(envelope *Envelope, payloadType string, pub *ecdsa.PublicKey) ([]byte, error) {
+	if envelope.PayloadType != payloadType {
+		return nil, fmt.Errorf("%w: payload type %q", ErrUnsupportedPayload, envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+
+	digest := sha256.Sum256(pae(envelope.PayloadType, payload))
+	for _, s := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return payload, nil
+		}
+	}
+	return nil, ErrInvalidSignature
+}
+
+// Statement decodes the statement of envelope without verifying it
+func (e *Envelope) Statement() (*Statement, error) {
+	payload, err := e.DecodePayload()
+	if err != nil {
+		return nil, err
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	return &statement, nil
+}
+
+// DecodePayload returns the payload of envelope without verifying it
+func (e *Envelope) DecodePayload() ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	return payload, nil
+}
+
+// pae is the DSSE pre-authentication encoding that signatures cover
+func pae(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// KeyID identifies a public key by the hex SHA-256 of its PKIX encoding
+func KeyID(pub *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MarshalPublicKey encodes pub as a PKIX PEM block
+func MarshalPublicKey(pub *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// ParsePublicKey parses a PKIX PEM encoded ECDSA public key
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse public key PEM")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not ECDSA")
+	}
+	return ecdsaPub, nil
+}
@@ -0,0 +1,94 @@
+package attest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestSignVerify(t *testing.T) {
+	signer := newTestSigner(t)
+	statement := Statement{
+		ThreadID:        "0197a6b4-3d4e-7c1a-9a47-5b8f3c2d1e0f",
+		TweetIDs:        []string{"1900000000000000001", "1900000000000000002"},
+		ScrapedAt:       time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		ScraperAccount:  "mirror_bot",
+		ContentCID:      "bafyreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy",
+		SoftwareVersion: "v1.2.3",
+	}
+
+	envelope, err := signer.Sign(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.Signatures) != 1 || envelope.Signatures[0].KeyID != signer.KeyID() {
+		t.Fatalf("expected one signature by %s, got %+v", signer.KeyID(), envelope.Signatures)
+	}
+
+	got, err := Verify(envelope, signer.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != StatementType || got.ThreadID != statement.ThreadID || got.ContentCID != statement.ContentCID ||
+		len(got.TweetIDs) != 2 || !got.ScrapedAt.Equal(statement.ScrapedAt) || got.ScraperAccount != statement.ScraperAccount {
+		t.Fatalf("unexpected statement %+v", got)
+	}
+
+	// The public key survives a PEM roundtrip
+	pem, err := MarshalPublicKey(signer.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKey([]byte(pem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(envelope, pub); err != nil {
+		t.Fatalf("expected envelope to verify with the parsed key, got %v", err)
+	}
+
+	// Another key does not verify the envelope
+	if _, err := Verify(envelope, newTestSigner(t).PublicKey()); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature with another key, got %v", err)
+	}
+
+	// Neither does a tampered payload
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := *envelope
+	tampered.Payload = base64.StdEncoding.EncodeToString(
+		bytes.Replace(payload, []byte("mirror_bot"), []byte("someone_else"), 1))
+	if _, err := Verify(&tampered, signer.PublicKey()); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature for tampered payload, got %v", err)
+	}
+}
+
+func TestNewSignerRequiresP256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSigner(key); err == nil {
+		t.Fatal("expected P-384 key to be rejected")
+	}
+}
@@ -0,0 +1,29 @@
+package attestfx
+
+import (
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
+	"go.uber.org/fx"
+)
+
+type Config struct {
+	// PrivateKey is a PEM encoded PKCS8 P-256 key or the path to it; attestations
+	// are disabled if empty
+	PrivateKey string
+}
+
+// Module provides the fx module for attestation signing
+var Module = fx.Module("attest",
+	fx.Provide(NewSigner),
+)
+
+// NewSigner creates the attestation signer, or returns nil if no key is configured
+func NewSigner(config *Config) (*attest.Signer, error) {
+	if config.PrivateKey == "" {
+		return nil, nil
+	}
+	key, err := attest.ParsePrivateKey(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return attest.NewSigner(key)
+}
@@ -0,0 +1,38 @@
+package attest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// ParsePrivateKey parses a PEM encoded PKCS8 ECDSA private key. privateKey
+// is either a path to the PEM file or the PEM itself, in which newlines may be
+// escaped as \n (as in .env files).
+func ParsePrivateKey(privateKey string) (*ecdsa.PrivateKey, error) {
+	var privateKeyBytes []byte
+	if data, err := os.ReadFile(privateKey); err == nil {
+		privateKeyBytes = data
+	} else {
+		privateKeyBytes = bytes.ReplaceAll([]byte(privateKey), []byte("\\n"), []byte("\n"))
+	}
+
+	block, _ := pem.Decode(privateKeyBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse private key PEM")
+	}
+
+	// Parse the private key
+	privKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %v", err)
+	}
+	ecdsaPrivKey, ok := privKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not ECDSA")
+	}
+	return ecdsaPrivKey, nil
+}
@@ -40,6 +40,20 @@ func Middleware(
 	}
 }
 
+// OptionalMiddleware identifies the caller of a public route when a valid
+// bearer token is sent, and lets anonymous or invalid requests through
+func OptionalMiddleware(v JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		if strings.HasPrefix(authorization, "Bearer ") {
+			if ai, err := v.Verify(strings.TrimPrefix(authorization, "Bearer ")); err == nil {
+				SetAuthInfo(c, ai)
+			}
+		}
+		c.Next()
+	}
+}
+
 func SetAuthInfo(c *gin.Context, ai *AuthInfo) {
 	c.Set(authInfoKey, ai)
 }
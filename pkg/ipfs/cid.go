@@ -0,0 +1,88 @@
+package ipfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	chunk "github.com/ipfs/boxo/chunker"
+	"github.com/ipfs/boxo/ipld/merkledag"
+	"github.com/ipfs/boxo/ipld/unixfs/importer/balanced"
+	"github.com/ipfs/boxo/ipld/unixfs/importer/helpers"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+// CID modes of the self-contained backends (fs, s3), naming the backend whose
+// CIDs they reproduce so references stay stable when switching backends
+const (
+	// CIDModeKubo gives content the UnixFS CID of `ipfs add` with default options
+	CIDModeKubo = "kubo"
+	// CIDModePDP gives content its piece CID (CommP)
+	CIDModePDP = "pdp"
+)
+
+// importUnixFS adds content to dag as a UnixFS file the way Kubo does by
+// default (CIDv0, 256KiB chunks, balanced layout) and returns the root node
+func importUnixFS(dag ipld.DAGService, content io.Reader) (ipld.Node, error) {
+	params := helpers.DagBuilderParams{
+		Dagserv:    dag,
+		Maxlinks:   helpers.DefaultLinksPerBlock,
+		CidBuilder: merkledag.V0CidPrefix(),
+	}
+	db, err := params.New(chunk.DefaultSplitter(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DAG builder: %w", err)
+	}
+	node, err := balanced.Layout(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import content: %w", err)
+	}
+	return node, nil
+}
+
+// computeCID returns the CID content gets in mode and seeks content back to the start
+func computeCID(content io.ReadSeeker, mode string) (cid.Cid, error) {
+	switch mode {
+	case CIDModePDP:
+		pieceCID, _, _, _, err := preparePiece(content)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("failed to prepare piece: %w", err)
+		}
+		return pieceCID, nil
+	case CIDModeKubo:
+		node, err := importUnixFS(discardDAG{}, content)
+		if err != nil {
+			return cid.Undef, err
+		}
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return cid.Undef, fmt.Errorf("failed to seek content: %w", err)
+		}
+		return node.Cid(), nil
+	default:
+		return cid.Undef, fmt.Errorf("unsupported CID mode %q", mode)
+	}
+}
+
+// discardDAG is a DAGService that only lets nodes be built, e.g. to compute a root CID
+type discardDAG struct{}
+
+var _ ipld.DAGService = discardDAG{}
+
+func (discardDAG) Get(ctx context.Context, c cid.Cid) (ipld.Node, error) {
+	return nil, ipld.ErrNotFound{Cid: c}
+}
+
+func (discardDAG) GetMany(ctx context.Context, cids []cid.Cid) <-chan *ipld.NodeOption {
+	ch := make(chan *ipld.NodeOption, len(cids))
+	for _, c := range cids {
+		ch <- &ipld.NodeOption{Err: ipld.ErrNotFound{Cid: c}}
+	}
+	close(ch)
+	return ch
+}
+
+func (discardDAG) Add(context.Context, ipld.Node) error        { return nil }
+func (discardDAG) AddMany(context.Context, []ipld.Node) error  { return nil }
+func (discardDAG) Remove(context.Context, cid.Cid) error       { return nil }
+func (discardDAG) RemoveMany(context.Context, []cid.Cid) error { return nil }
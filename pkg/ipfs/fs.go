@@ -0,0 +1,244 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/datastore/dshelp"
+	"github.com/ipfs/boxo/exchange/offline"
+	"github.com/ipfs/boxo/files"
+	"github.com/ipfs/boxo/ipld/merkledag"
+	unixfile "github.com/ipfs/boxo/ipld/unixfs/file"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+var _ BlockStorage = (*FSStorage)(nil)
+
+// FSStorage implements Storage on a local directory, for development and tests
+// without a Kubo node or PDP service.
+//
+// In kubo mode Add imports content as a UnixFS DAG with the defaults of
+// `ipfs add` (CIDv0, 256KiB chunks, balanced layout), so content gets the CID a
+// Kubo node would give it. In pdp mode content is stored whole under its piece
+// CID, as the PDP service would. Blocks are kept in files sharded by the
+// next-to-last two characters of their key, like the flatfs datastore of Kubo.
+type FSStorage struct {
+	mode   string
+	blocks *fsBlockstore
+	pieces *fsShardedDir
+	dag    ipld.DAGService
+}
+
+// NewFSStorage creates a FSStorage in dir, creating the directory if needed
+func NewFSStorage(dir, cidMode string) (*FSStorage, error) {
+	if cidMode != CIDModeKubo && cidMode != CIDModePDP {
+		return nil, fmt.Errorf("unsupported CID mode %q", cidMode)
+	}
+
+	bs := &fsBlockstore{dir: &fsShardedDir{root: filepath.Join(dir, "blocks")}}
+	pieces := &fsShardedDir{root: filepath.Join(dir, "pieces")}
+	for _, d := range []string{bs.dir.root, pieces.root} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	return &FSStorage{
+		mode:   cidMode,
+		blocks: bs,
+		pieces: pieces,
+		dag:    merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs))),
+	}, nil
+}
+
+// Add stores content and returns its CID
+func (f *FSStorage) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	if f.mode == CIDModePDP {
+		pieceCID, err := computeCID(content, CIDModePDP)
+		if err != nil {
+			return cid.Undef, err
+		}
+		if err := f.pieces.write(pieceCID, content); err != nil {
+			return cid.Undef, fmt.Errorf("failed to store piece: %w", err)
+		}
+		return pieceCID, nil
+	}
+
+	node, err := importUnixFS(f.dag, content)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return node.Cid(), nil
+}
+
+// Get retrieves content by CID, either a piece or a UnixFS file
+func (f *FSStorage) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	if c.Prefix().Codec == cid.FilCommitmentUnsealed {
+		r, err := f.pieces.open(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get piece %s: %w", c, err)
+		}
+		return r, nil
+	}
+
+	node, err := f.dag.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content %s: %w", c, err)
+	}
+	fnode, err := unixfile.NewUnixfsFile(ctx, f.dag, node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", c, err)
+	}
+	file, ok := fnode.(files.File)
+	if !ok {
+		return nil, fmt.Errorf("node is not a file")
+	}
+	return file, nil
+}
+
+// PutBlock stores a raw block
+func (f *FSStorage) PutBlock(ctx context.Context, block blocks.Block) error {
+	return f.blocks.Put(ctx, block)
+}
+
+// GetBlock retrieves a raw block
+func (f *FSStorage) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return f.blocks.Get(ctx, c)
+}
+
+// fsShardedDir stores one file per multihash, sharded into subdirectories
+type fsShardedDir struct {
+	root string
+}
+
+func (d *fsShardedDir) path(c cid.Cid) string {
+	// Same key and sharding as flatfs with next-to-last/2
+	key := dshelp.MultihashToDsKey(c.Hash()).String()[1:]
+	return filepath.Join(d.root, key[len(key)-3:len(key)-1], key+".data")
+}
+
+func (d *fsShardedDir) write(c cid.Cid, r io.Reader) error {
+	p := d.path(c)
+	if _, err := os.Stat(p); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so readers never see partial content
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (d *fsShardedDir) open(c cid.Cid) (*os.File, error) {
+	return os.Open(d.path(c))
+}
+
+// fsBlockstore is a blockstore.Blockstore on a fsShardedDir
+type fsBlockstore struct {
+	dir *fsShardedDir
+}
+
+var _ blockstore.Blockstore = (*fsBlockstore)(nil)
+
+func (b *fsBlockstore) DeleteBlock(ctx context.Context, c cid.Cid) error {
+	err := os.Remove(b.dir.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (b *fsBlockstore) Has(ctx context.Context, c cid.Cid) (bool, error) {
+	_, err := os.Stat(b.dir.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *fsBlockstore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	data, err := os.ReadFile(b.dir.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ipld.ErrNotFound{Cid: c}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return blocks.NewBlockWithCid(data, c)
+}
+
+func (b *fsBlockstore) GetSize(ctx context.Context, c cid.Cid) (int, error) {
+	info, err := os.Stat(b.dir.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return -1, ipld.ErrNotFound{Cid: c}
+	}
+	if err != nil {
+		return -1, err
+	}
+	return int(info.Size()), nil
+}
+
+func (b *fsBlockstore) Put(ctx context.Context, block blocks.Block) error {
+	return b.dir.write(block.Cid(), bytes.NewReader(block.RawData()))
+}
+
+func (b *fsBlockstore) PutMany(ctx context.Context, blks []blocks.Block) error {
+	for _, block := range blks {
+		if err := b.Put(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllKeysChan returns CIDv1 raw CIDs, as the codec is not kept
+func (b *fsBlockstore) AllKeysChan(ctx context.Context) (<-chan cid.Cid, error) {
+	ch := make(chan cid.Cid)
+	go func() {
+		defer close(ch)
+		_ = filepath.WalkDir(b.dir.root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".data") {
+				return nil
+			}
+			mh, err := dshelp.DsKeyToMultihash(datastore.NewKey(strings.TrimSuffix(d.Name(), ".data")))
+			if err != nil {
+				return nil
+			}
+			select {
+			case ch <- cid.NewCidV1(cid.Raw, mh):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return ch, nil
+}
+
+func (b *fsBlockstore) HashOnRead(enabled bool) {}
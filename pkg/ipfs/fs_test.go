@@ -0,0 +1,108 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+)
+
+func TestFSStorageKuboCIDs(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage, err := NewFSStorage(dir, CIDModeKubo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same CID as `echo "hello world" | ipfs add`
+	c, err := storage.Add(ctx, strings.NewReader("hello world\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o" {
+		t.Fatalf("unexpected CID %s", c)
+	}
+
+	// Content spanning several chunks
+	data := make([]byte, 1<<20+123)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	c, err = storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := storage.Get(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("content mismatch")
+	}
+
+	// Blocks are sharded by the next-to-last two characters of their key
+	block := blocks.NewBlock([]byte("block"))
+	if err := storage.PutBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	key := filepath.Base(storage.blocks.dir.path(block.Cid()))
+	key = strings.TrimSuffix(key, ".data")
+	if _, err := os.Stat(filepath.Join(dir, "blocks", key[len(key)-3:len(key)-1], key+".data")); err != nil {
+		t.Fatal(err)
+	}
+	gotBlock, err := storage.GetBlock(ctx, block.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotBlock.RawData(), block.RawData()) {
+		t.Fatal("block mismatch")
+	}
+}
+
+func TestFSStoragePieceCIDs(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewFSStorage(t.TempDir(), CIDModePDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("threadmirror "), 100)
+	expected, _, _, _, err := preparePiece(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(expected) || c.Prefix().Codec != cid.FilCommitmentUnsealed {
+		t.Fatalf("expected piece CID %s, got %s", expected, c)
+	}
+
+	rc, err := storage.Get(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close() // nolint:errcheck
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("content mismatch")
+	}
+}
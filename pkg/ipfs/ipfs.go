@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 
+	blocks "github.com/ipfs/go-block-format"
 	"github.com/ipfs/go-cid"
 )
 
@@ -14,3 +15,48 @@ type Storage interface {
 	// Get retrieves content from IPFS by CID
 	Get(ctx context.Context, cid cid.Cid) (io.ReadCloser, error)
 }
+
+// BlockStorage is implemented by backends that can store raw IPLD blocks
+// (e.g. DAG-CBOR nodes) in addition to files
+type BlockStorage interface {
+	// PutBlock stores a block under its CID
+	PutBlock(ctx context.Context, block blocks.Block) error
+	// GetBlock retrieves a raw block by CID
+	GetBlock(ctx context.Context, cid cid.Cid) (blocks.Block, error)
+}
+
+// DAGStorage is implemented by block storages that store the blocks of a DAG
+// together, e.g. the replicated storage, whose replicas without block storage
+// keep them in one CAR
+type DAGStorage interface {
+	BlockStorage
+	// PutDAG stores the blocks of the DAG rooted at root
+	PutDAG(ctx context.Context, root cid.Cid, blocks []blocks.Block) error
+}
+
+// Piece is a piece commitment (CommP) together with its padded size
+type Piece struct {
+	CID        cid.Cid
+	PaddedSize uint64
+}
+
+// PieceStorage is implemented by backends that store content as pieces and can
+// report the padded size needed to aggregate them (see AggregatePieces)
+type PieceStorage interface {
+	// AddPiece adds content and returns the resulting piece
+	AddPiece(ctx context.Context, content io.ReadSeeker) (Piece, error)
+}
+
+// ProofSetStorage is implemented by backends whose uploaded pieces only become
+// covered by storage proofs once they are added as roots of a proof set
+type ProofSetStorage interface {
+	// AddRoots adds roots to the proof set. Each root input has the format
+	// rootCID:subrootCID1+subrootCID2+...
+	AddRoots(ctx context.Context, extraDataHexStr string, rootInputs []string) error
+	// ProofSetID returns the ID of the proof set roots are added to
+	ProofSetID() uint64
+	// GetProofSet fetches the proving status and roots of the proof set
+	GetProofSet(ctx context.Context) (*ProofSetStatus, error)
+	// GetRoot fetches a single root of the proof set, or returns ErrRootNotFound
+	GetRoot(ctx context.Context, rootID uint64) (*RootStatus, error)
+}
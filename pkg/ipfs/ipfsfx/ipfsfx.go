@@ -1,8 +1,10 @@
 package ipfsfx
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/ipfs-force-community/threadmirror/pkg/ipfs"
 	"go.uber.org/fx"
@@ -55,11 +57,102 @@ func (p *PDPConfig) Validate() error {
 	return nil
 }
 
+// FSConfig represents configuration for the local filesystem backend
+type FSConfig struct {
+	Path string `json:"path" yaml:"path"`
+	// CIDMode is the backend whose CIDs are reproduced (kubo or pdp)
+	CIDMode string `json:"cid_mode" yaml:"cid_mode"`
+}
+
+func (f *FSConfig) GetBackend() string {
+	return "fs"
+}
+
+func (f *FSConfig) Validate() error {
+	if f.Path == "" {
+		return fmt.Errorf("path is required for fs backend")
+	}
+	if f.CIDMode != ipfs.CIDModeKubo && f.CIDMode != ipfs.CIDModePDP {
+		return fmt.Errorf("cid_mode must be kubo or pdp for fs backend")
+	}
+	return nil
+}
+
+// S3Config represents configuration for the S3 compatible object storage backend
+type S3Config struct {
+	Endpoint        string `json:"endpoint" yaml:"endpoint"`
+	Region          string `json:"region" yaml:"region"`
+	Bucket          string `json:"bucket" yaml:"bucket"`
+	AccessKeyID     string `json:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key" yaml:"secret_access_key"`
+	UseSSL          bool   `json:"use_ssl" yaml:"use_ssl"`
+	Prefix          string `json:"prefix" yaml:"prefix"`
+	// CIDMode is the backend whose CIDs are reproduced (kubo or pdp)
+	CIDMode string `json:"cid_mode" yaml:"cid_mode"`
+}
+
+func (s *S3Config) GetBackend() string {
+	return "s3"
+}
+
+func (s *S3Config) Validate() error {
+	if s.Endpoint == "" {
+		return fmt.Errorf("endpoint is required for s3 backend")
+	}
+	if s.Bucket == "" {
+		return fmt.Errorf("bucket is required for s3 backend")
+	}
+	if s.CIDMode != ipfs.CIDModeKubo && s.CIDMode != ipfs.CIDModePDP {
+		return fmt.Errorf("cid_mode must be kubo or pdp for s3 backend")
+	}
+	return nil
+}
+
+// ReplicatedConfig represents configuration for the replicated backend, which
+// writes to several of the other backends. The replicas are configured by their
+// own backend configs.
+type ReplicatedConfig struct {
+	Replicas    []string `json:"replicas" yaml:"replicas"`
+	WriteQuorum int      `json:"write_quorum" yaml:"write_quorum"`
+	// CIDMode is the backend whose CIDs content is known by (kubo or pdp)
+	CIDMode string `json:"cid_mode" yaml:"cid_mode"`
+}
+
+func (r *ReplicatedConfig) GetBackend() string {
+	return "replicated"
+}
+
+func (r *ReplicatedConfig) Validate() error {
+	if len(r.Replicas) == 0 {
+		return fmt.Errorf("replicas are required for replicated backend")
+	}
+	if r.WriteQuorum < 1 || r.WriteQuorum > len(r.Replicas) {
+		return fmt.Errorf("write_quorum must be between 1 and the number of replicas for replicated backend")
+	}
+	if r.CIDMode != ipfs.CIDModeKubo && r.CIDMode != ipfs.CIDModePDP {
+		return fmt.Errorf("cid_mode must be kubo or pdp for replicated backend")
+	}
+	seen := make(map[string]bool, len(r.Replicas))
+	for _, replica := range r.Replicas {
+		if replica == "replicated" {
+			return fmt.Errorf("replicated backend cannot be a replica")
+		}
+		if seen[replica] {
+			return fmt.Errorf("duplicate replica %s for replicated backend", replica)
+		}
+		seen[replica] = true
+	}
+	return nil
+}
+
 // Config represents the main configuration that wraps backend-specific configs
 type Config struct {
-	Backend string      `json:"backend" yaml:"backend"`
-	Kubo    *KuboConfig `json:"kubo,omitempty" yaml:"kubo,omitempty"`
-	PDP     *PDPConfig  `json:"pdp,omitempty" yaml:"pdp,omitempty"`
+	Backend    string            `json:"backend" yaml:"backend"`
+	Kubo       *KuboConfig       `json:"kubo,omitempty" yaml:"kubo,omitempty"`
+	PDP        *PDPConfig        `json:"pdp,omitempty" yaml:"pdp,omitempty"`
+	FS         *FSConfig         `json:"fs,omitempty" yaml:"fs,omitempty"`
+	S3         *S3Config         `json:"s3,omitempty" yaml:"s3,omitempty"`
+	Replicated *ReplicatedConfig `json:"replicated,omitempty" yaml:"replicated,omitempty"`
 }
 
 // GetBackendConfig returns the appropriate backend configuration
@@ -75,18 +168,75 @@ func (c *Config) GetBackendConfig() (BackendConfig, error) {
 			return nil, fmt.Errorf("pdp configuration is required when backend is 'pdp'")
 		}
 		return c.PDP, c.PDP.Validate()
+	case "fs":
+		if c.FS == nil {
+			return nil, fmt.Errorf("fs configuration is required when backend is 'fs'")
+		}
+		return c.FS, c.FS.Validate()
+	case "s3":
+		if c.S3 == nil {
+			return nil, fmt.Errorf("s3 configuration is required when backend is 's3'")
+		}
+		return c.S3, c.S3.Validate()
+	case "replicated":
+		if c.Replicated == nil {
+			return nil, fmt.Errorf("replicated configuration is required when backend is 'replicated'")
+		}
+		if err := c.Replicated.Validate(); err != nil {
+			return nil, err
+		}
+		for _, replica := range c.Replicated.Replicas {
+			if _, err := c.replicaConfig(replica).GetBackendConfig(); err != nil {
+				return nil, fmt.Errorf("replica %s: %w", replica, err)
+			}
+		}
+		return c.Replicated, nil
 	default:
-		return nil, fmt.Errorf("unsupported backend: %s, supported backends: kubo, pdp", c.Backend)
+		return nil, fmt.Errorf("unsupported backend: %s, supported backends: kubo, pdp, fs, s3, replicated", c.Backend)
 	}
 }
 
+// replicaConfig returns the config of a single replica of the replicated backend
+func (c *Config) replicaConfig(backend string) *Config {
+	replica := *c
+	replica.Backend = backend
+	replica.Replicated = nil
+	return &replica
+}
+
 // Module provides the fx module for ipfs
 var Module = fx.Module("ipfs",
 	fx.Provide(NewStorage),
 )
 
-// NewStorage creates a new IPFS Storage instance based on configuration
-func NewStorage(config *Config, logger *slog.Logger) (ipfs.Storage, error) {
+// NewStorage creates a new IPFS Storage instance based on configuration. index
+// is only used by the replicated backend.
+func NewStorage(config *Config, index ipfs.ReplicaIndex, logger *slog.Logger) (ipfs.Storage, error) {
+	backendConfig, err := config.GetBackendConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backend config: %w", err)
+	}
+
+	if replicatedConfig, ok := backendConfig.(*ReplicatedConfig); ok {
+		replicas := make([]ipfs.Replica, len(replicatedConfig.Replicas))
+		for i, name := range replicatedConfig.Replicas {
+			storage, err := newBackendStorage(config.replicaConfig(name), logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create replica %s: %w", name, err)
+			}
+			replicas[i] = ipfs.Replica{Name: name, Storage: storage}
+		}
+		if index == nil {
+			logger.Warn("No replica index, missing replicas will not be repaired after a restart")
+			index = ipfs.NewMemoryReplicaIndex()
+		}
+		return ipfs.NewReplicated(replicas, replicatedConfig.WriteQuorum, replicatedConfig.CIDMode, index, logger.With("storage", "replicated"))
+	}
+	return newBackendStorage(config, logger)
+}
+
+// newBackendStorage creates the storage of a single backend
+func newBackendStorage(config *Config, logger *slog.Logger) (ipfs.Storage, error) {
 	backendConfig, err := config.GetBackendConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get backend config: %w", err)
@@ -99,6 +249,23 @@ func NewStorage(config *Config, logger *slog.Logger) (ipfs.Storage, error) {
 	case "pdp":
 		pdpConfig := backendConfig.(*PDPConfig)
 		return ipfs.NewPDP(pdpConfig.ServiceURL, pdpConfig.ServiceName, pdpConfig.PrivateKey, pdpConfig.ProofSetID, logger)
+	case "fs":
+		fsConfig := backendConfig.(*FSConfig)
+		return ipfs.NewFSStorage(fsConfig.Path, fsConfig.CIDMode)
+	case "s3":
+		s3Config := backendConfig.(*S3Config)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return ipfs.NewS3Storage(ctx, ipfs.S3Options{
+			Endpoint:        s3Config.Endpoint,
+			Region:          s3Config.Region,
+			Bucket:          s3Config.Bucket,
+			AccessKeyID:     s3Config.AccessKeyID,
+			SecretAccessKey: s3Config.SecretAccessKey,
+			UseSSL:          s3Config.UseSSL,
+			Prefix:          s3Config.Prefix,
+			CIDMode:         s3Config.CIDMode,
+		})
 	default:
 		return nil, fmt.Errorf("unsupported backend: %s", backendConfig.GetBackend())
 	}
@@ -1,19 +1,24 @@
 package ipfs
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 
 	"github.com/ipfs/boxo/files"
 	"github.com/ipfs/boxo/path"
+	blocks "github.com/ipfs/go-block-format"
 	"github.com/ipfs/go-cid"
 	"github.com/ipfs/kubo/client/rpc"
 	iface "github.com/ipfs/kubo/core/coreiface"
 	"github.com/ipfs/kubo/core/coreiface/options"
 	"github.com/multiformats/go-multiaddr"
+	"github.com/multiformats/go-multicodec"
 )
 
+var _ BlockStorage = (*KuboStorage)(nil)
+
 // KuboStorage implements Storage interface using IPFS Kubo RPC client
 type KuboStorage struct {
 	client iface.CoreAPI
@@ -65,3 +70,35 @@ func (k *KuboStorage) Get(ctx context.Context, cid cid.Cid) (io.ReadCloser, erro
 
 	return file, nil
 }
+
+// PutBlock stores a raw block on the Kubo node and pins it
+func (k *KuboStorage) PutBlock(ctx context.Context, block blocks.Block) error {
+	prefix := block.Cid().Prefix()
+
+	stat, err := k.client.Block().Put(ctx, bytes.NewReader(block.RawData()),
+		options.Block.CidCodec(multicodec.Code(prefix.Codec).String()),
+		options.Block.Hash(prefix.MhType, prefix.MhLength),
+		options.Block.Pin(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put block to IPFS: %w", err)
+	}
+	if got := stat.Path().RootCid(); !got.Equals(block.Cid()) {
+		return fmt.Errorf("block CID mismatch: expected %s, got %s", block.Cid(), got)
+	}
+	return nil
+}
+
+// GetBlock retrieves a raw block from the Kubo node
+func (k *KuboStorage) GetBlock(ctx context.Context, cid cid.Cid) (blocks.Block, error) {
+	r, err := k.client.Block().Get(ctx, path.FromCid(cid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block from IPFS: %w", err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block: %w", err)
+	}
+	return blocks.NewBlockWithCid(data, cid)
+}
@@ -4,24 +4,28 @@ import (
 	"bytes"
 	"context"
 	"crypto/ecdsa"
-	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
-	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	commcid "github.com/filecoin-project/go-fil-commcid"
 	commp "github.com/filecoin-project/go-fil-commp-hashhash"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/ipfs-force-community/threadmirror/pkg/attest"
 	"github.com/ipfs/go-cid"
 )
 
+var (
+	_ PieceStorage    = (*PDP)(nil)
+	_ ProofSetStorage = (*PDP)(nil)
+)
+
 type PDP struct {
 	serviceURL  string
 	serviceName string
@@ -31,41 +35,35 @@ type PDP struct {
 }
 
 func NewPDP(serviceURL, serviceName, privateKey string, proofSetID uint64, logger *slog.Logger) (*PDP, error) {
-	var privateKeyBytes []byte
-	if data, err := os.ReadFile(privateKey); err == nil {
-		privateKeyBytes = data
-	} else {
-		privateKeyBytes = bytes.ReplaceAll([]byte(privateKey), []byte("\\n"), []byte("\n"))
-	}
-
-	block, _ := pem.Decode(privateKeyBytes)
-	if block == nil {
-		return nil, fmt.Errorf("failed to parse private key PEM")
-	}
-
-	// Parse the private key
-	privKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	ecdsaPrivKey, err := attest.ParsePrivateKey(privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %v", err)
-	}
-	ecdsaPrivKey, ok := privKey.(*ecdsa.PrivateKey)
-	if !ok {
-		return nil, fmt.Errorf("private key is not ECDSA")
+		return nil, err
 	}
 
 	return &PDP{serviceURL: serviceURL, serviceName: serviceName, privateKey: ecdsaPrivKey, proofSetID: proofSetID, logger: logger}, nil
 }
 
+// Add uploads content as a piece and returns its piece CID. The piece is not
+// covered by proofs until it is registered with AddRoots.
 func (p *PDP) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	piece, err := p.AddPiece(ctx, content)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return piece.CID, nil
+}
+
+// AddPiece uploads content as a piece and returns the piece CID and padded size
+func (p *PDP) AddPiece(ctx context.Context, content io.ReadSeeker) (Piece, error) {
 	jwtToken, err := createJWTToken(p.serviceName, p.privateKey)
 	if err != nil {
-		return cid.Undef, fmt.Errorf("failed to create JWT token: %v", err)
+		return Piece{}, fmt.Errorf("failed to create JWT token: %v", err)
 	}
 
 	// Compute CommP (PieceCID)
-	pieceCIDComputed, pieceSize, _, commpDigest, err := preparePiece(content)
+	pieceCIDComputed, pieceSize, paddedPieceSize, commpDigest, err := preparePiece(content)
 	if err != nil {
-		return cid.Undef, fmt.Errorf("failed to prepare piece: %v", err)
+		return Piece{}, fmt.Errorf("failed to prepare piece: %v", err)
 	}
 
 	// Prepare the check data
@@ -83,20 +81,13 @@ func (p *PDP) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
 
 	reqBody, err := json.Marshal(reqData)
 	if err != nil {
-		return cid.Undef, fmt.Errorf("failed to marshal request data: %v", err)
+		return Piece{}, fmt.Errorf("failed to marshal request data: %v", err)
 	}
 	if err := uploadOnePiece(ctx, http.DefaultClient, p.serviceURL, reqBody, jwtToken, content, pieceSize); err != nil {
-		return cid.Undef, fmt.Errorf("failed to upload piece: %v", err)
+		return Piece{}, fmt.Errorf("failed to upload piece: %v", err)
 	}
 
-	go func() {
-		time.Sleep(time.Second * 20)
-		if err := p.AddRoots(context.Background(), "", []string{fmt.Sprintf("%s:%s", pieceCIDComputed.String(), pieceCIDComputed.String())}); err != nil {
-			p.logger.Error("failed to add roots", "pieceCIDComputed", pieceCIDComputed.String(), "error", err)
-		}
-	}()
-
-	return pieceCIDComputed, nil
+	return Piece{CID: pieceCIDComputed, PaddedSize: paddedPieceSize}, nil
 }
 
 func (p *PDP) Get(ctx context.Context, cid cid.Cid) (io.ReadCloser, error) {
@@ -171,6 +162,12 @@ func (p *PDP) CreateProofSet(ctx context.Context, recordKeeper, extraDataHexStr
 	return nil
 }
 
+// RootInput returns the AddRoots input registering rootCID with the given subroots
+// (see AggregatePieces), in the format rootCID:subrootCID1+subrootCID2+...
+func RootInput(rootCID string, subrootCIDs ...string) string {
+	return rootCID + ":" + strings.Join(subrootCIDs, "+")
+}
+
 // rootInputs is Root CID and its subroots. Format: rootCID:subrootCID1+subrootCID2,...
 func (p *PDP) AddRoots(ctx context.Context, extraDataHexStr string, rootInputs []string) error {
 	// Validate extraData hex string and its decoded length
@@ -267,7 +264,116 @@ func (p *PDP) AddRoots(ctx context.Context, extraDataHexStr string, rootInputs [
 	return nil
 }
 
+// ProofSetStatus is the state of the configured proof set as reported by the PDP service
+type ProofSetStatus struct {
+	ID                 uint64 `json:"id"`
+	NextChallengeEpoch *int64 `json:"nextChallengeEpoch"`
+	// LastProvenEpoch is only reported by some PDP services
+	LastProvenEpoch *int64 `json:"lastProvenEpoch,omitempty"`
+	// Roots has one entry per subroot of every root in the proof set
+	Roots []ProofSetRoot `json:"roots"`
+}
+
+type ProofSetRoot struct {
+	RootID        uint64 `json:"rootId"`
+	RootCID       string `json:"rootCid"`
+	SubrootCID    string `json:"subrootCid"`
+	SubrootOffset int64  `json:"subrootOffset"`
+}
+
+// RootStatus is a single root of the proof set as reported by the PDP service
+type RootStatus struct {
+	RootID   uint64        `json:"rootId"`
+	RootCID  string        `json:"rootCid"`
+	Subroots []RootSubroot `json:"subroots"`
+}
+
+type RootSubroot struct {
+	SubrootCID    string `json:"subrootCid"`
+	SubrootOffset int64  `json:"subrootOffset"`
+}
+
+// ErrRootNotFound is returned by GetRoot if the proof set has no such root
+var ErrRootNotFound = errors.New("root not found in proof set")
+
+var errPDPNotFound = errors.New("not found")
+
+// ProofSetID returns the ID of the proof set roots are added to
+func (p *PDP) ProofSetID() uint64 {
+	return p.proofSetID
+}
+
+// GetProofSet fetches the status of the proof set, including all of its roots
+func (p *PDP) GetProofSet(ctx context.Context) (*ProofSetStatus, error) {
+	var status ProofSetStatus
+	if err := p.getJSON(ctx, fmt.Sprintf("%s/pdp/proof-sets/%d", p.serviceURL, p.proofSetID), &status); err != nil {
+		return nil, fmt.Errorf("failed to get proof set %d: %w", p.proofSetID, err)
+	}
+	return &status, nil
+}
+
+// GetRoot fetches the status of a single root of the proof set
+func (p *PDP) GetRoot(ctx context.Context, rootID uint64) (*RootStatus, error) {
+	var status RootStatus
+	if err := p.getJSON(ctx, fmt.Sprintf("%s/pdp/proof-sets/%d/roots/%d", p.serviceURL, p.proofSetID, rootID), &status); err != nil {
+		if errors.Is(err, errPDPNotFound) {
+			return nil, ErrRootNotFound
+		}
+		return nil, fmt.Errorf("failed to get root %d of proof set %d: %w", rootID, p.proofSetID, err)
+	}
+	return &status, nil
+}
+
+func (p *PDP) getJSON(ctx context.Context, url string, v any) error {
+	jwtToken, err := createJWTToken(p.serviceName, p.privateKey)
+	if err != nil {
+		return fmt.Errorf("failed to create JWT token: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return fmt.Errorf("failed to parse response: %v", err)
+		}
+		return nil
+	case http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return errPDPNotFound
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status code %d: %s", resp.StatusCode, string(body))
+	}
+}
+
 func preparePiece(r io.ReadSeeker) (pieceCIDComputed cid.Cid, pieceSize int64, paddedPieceSize uint64, digest []byte, err error) {
+	pieceCIDComputed, pieceSize, paddedPieceSize, digest, err = computePiece(r)
+	if err != nil {
+		return
+	}
+
+	// now compute sha256
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		err = fmt.Errorf("failed to seek file: %v", err)
+		return
+	}
+
+	return
+}
+
+// computePiece streams r through the commp calculator
+func computePiece(r io.Reader) (pieceCIDComputed cid.Cid, pieceSize int64, paddedPieceSize uint64, digest []byte, err error) {
 	// Create commp calculator
 	cp := &commp.Calc{}
 
@@ -291,13 +397,6 @@ func preparePiece(r io.ReadSeeker) (pieceCIDComputed cid.Cid, pieceSize int64, p
 		err = fmt.Errorf("failed to compute piece CID: %v", err)
 		return
 	}
-
-	// now compute sha256
-	if _, err = r.Seek(0, io.SeekStart); err != nil {
-		err = fmt.Errorf("failed to seek file: %v", err)
-		return
-	}
-
 	return
 }
 
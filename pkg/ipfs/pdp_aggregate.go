@@ -0,0 +1,139 @@
+package ipfs
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+	"sort"
+	"time"
+
+	commcid "github.com/filecoin-project/go-fil-commcid"
+)
+
+// SortPiecesForAggregate orders pieces largest first (stable), which keeps every
+// subroot naturally aligned so the aggregate needs no padding between them
+func SortPiecesForAggregate(pieces []Piece) {
+	sort.SliceStable(pieces, func(i, j int) bool {
+		return pieces[i].PaddedSize > pieces[j].PaddedSize
+	})
+}
+
+// AggregatePieces computes the piece commitment of pieces laid out one after
+// another in the given order, zero padded to the next power of two. This is the
+// root CID the PDP service expects for an AddRoots call with these pieces as
+// subroots. A single piece aggregates to itself.
+func AggregatePieces(pieces []Piece) (Piece, error) {
+	if len(pieces) == 0 {
+		return Piece{}, fmt.Errorf("no pieces to aggregate")
+	}
+
+	type node struct {
+		comm []byte
+		size uint64
+	}
+	var stack []node
+	reduce := func() {
+		for len(stack) >= 2 && stack[len(stack)-1].size == stack[len(stack)-2].size {
+			left, right := stack[len(stack)-2], stack[len(stack)-1]
+			stack = append(stack[:len(stack)-2], node{comm: hashPieceNodes(left.comm, right.comm), size: left.size * 2})
+		}
+	}
+	padTop := func() {
+		size := stack[len(stack)-1].size
+		stack = append(stack, node{comm: zeroPieceComm(size), size: size})
+		reduce()
+	}
+
+	for _, p := range pieces {
+		if p.PaddedSize < 128 || bits.OnesCount64(p.PaddedSize) != 1 {
+			return Piece{}, fmt.Errorf("invalid padded size %d for piece %s", p.PaddedSize, p.CID)
+		}
+		comm, err := commcid.CIDToDataCommitmentV1(p.CID)
+		if err != nil {
+			return Piece{}, fmt.Errorf("invalid piece CID %s: %w", p.CID, err)
+		}
+		// Subroots must start at an offset aligned to their own size
+		for len(stack) > 0 && stack[len(stack)-1].size < p.PaddedSize {
+			padTop()
+		}
+		stack = append(stack, node{comm: comm, size: p.PaddedSize})
+		reduce()
+	}
+	for len(stack) > 1 {
+		padTop()
+	}
+
+	c, err := commcid.DataCommitmentV1ToCID(stack[0].comm)
+	if err != nil {
+		return Piece{}, fmt.Errorf("failed to compute aggregate piece CID: %w", err)
+	}
+	return Piece{CID: c, PaddedSize: stack[0].size}, nil
+}
+
+// hashPieceNodes is the sha2-256-trunc254-padded node hash of the piece tree
+func hashPieceNodes(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(left)
+	h.Write(right)
+	out := h.Sum(nil)
+	out[31] &= 0x3F
+	return out
+}
+
+// zeroPieceComm returns the commitment of an all-zero piece of the given padded size
+func zeroPieceComm(paddedSize uint64) []byte {
+	comm := make([]byte, 32)
+	for size := uint64(32); size < paddedSize; size *= 2 {
+		comm = hashPieceNodes(comm, comm)
+	}
+	return comm
+}
+
+// AggregatePolicy decides when uploaded pieces are grouped into an aggregate root
+type AggregatePolicy struct {
+	// MaxSize is the target padded size of an aggregate; a batch is closed once
+	// adding the next piece would exceed it
+	MaxSize uint64
+	// MaxSubroots caps the number of pieces in one aggregate
+	MaxSubroots int
+	// MaxWait is how long a piece may wait for its batch to fill up before the
+	// partial batch is registered anyway
+	MaxWait time.Duration
+}
+
+// PendingPiece is an uploaded piece waiting for its aggregate root
+type PendingPiece struct {
+	Piece
+	UploadedAt time.Time
+}
+
+// PlanAggregates groups pieces (in upload order) into batches according to the
+// policy. Full batches are always returned; the trailing partial batch is only
+// returned once its oldest piece has waited MaxWait. Pieces not returned should
+// be planned again later.
+func PlanAggregates(pieces []PendingPiece, policy AggregatePolicy, now time.Time) [][]PendingPiece {
+	var (
+		batches [][]PendingPiece
+		cur     []PendingPiece
+		curSize uint64
+	)
+	closeBatch := func() {
+		batches = append(batches, cur)
+		cur, curSize = nil, 0
+	}
+
+	for _, p := range pieces {
+		if len(cur) > 0 && curSize+p.PaddedSize > policy.MaxSize {
+			closeBatch()
+		}
+		cur = append(cur, p)
+		curSize += p.PaddedSize
+		if curSize >= policy.MaxSize || (policy.MaxSubroots > 0 && len(cur) >= policy.MaxSubroots) {
+			closeBatch()
+		}
+	}
+	if len(cur) > 0 && now.Sub(cur[0].UploadedAt) >= policy.MaxWait {
+		closeBatch()
+	}
+	return batches
+}
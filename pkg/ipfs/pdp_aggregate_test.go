@@ -0,0 +1,140 @@
+package ipfs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	commcid "github.com/filecoin-project/go-fil-commcid"
+	commp "github.com/filecoin-project/go-fil-commp-hashhash"
+	"github.com/stretchr/testify/require"
+)
+
+func pieceOf(t *testing.T, data []byte) Piece {
+	t.Helper()
+	c, _, paddedSize, _, err := preparePiece(bytes.NewReader(data))
+	require.NoError(t, err)
+	return Piece{CID: c, PaddedSize: paddedSize}
+}
+
+func filled(n int, b byte) []byte {
+	return bytes.Repeat([]byte{b}, n)
+}
+
+func TestAggregatePiecesSingle(t *testing.T) {
+	p := pieceOf(t, filled(500, 1))
+	agg, err := AggregatePieces([]Piece{p})
+	require.NoError(t, err)
+	require.Equal(t, p, agg)
+}
+
+// The aggregate must equal the CommP of the subroot payloads laid out back to back
+func TestAggregatePiecesMatchesConcatenation(t *testing.T) {
+	// 127 unpadded bytes fill exactly 128 padded bytes
+	a := filled(254, 1) // 256 padded
+	b := filled(127, 2) // 128 padded
+	c := filled(127, 3) // 128 padded
+
+	pieces := []Piece{pieceOf(t, b), pieceOf(t, a), pieceOf(t, c)}
+	SortPiecesForAggregate(pieces)
+	require.Equal(t, []uint64{256, 128, 128}, []uint64{pieces[0].PaddedSize, pieces[1].PaddedSize, pieces[2].PaddedSize})
+	require.Equal(t, pieceOf(t, b).CID, pieces[1].CID, "sort must be stable")
+
+	agg, err := AggregatePieces(pieces)
+	require.NoError(t, err)
+	require.Equal(t, uint64(512), agg.PaddedSize)
+
+	cp := &commp.Calc{}
+	_, _ = cp.Write(append(append(append([]byte{}, a...), b...), c...))
+	digest, size, err := cp.Digest()
+	require.NoError(t, err)
+	expected, err := commcid.DataCommitmentV1ToCID(digest)
+	require.NoError(t, err)
+	require.Equal(t, expected, agg.CID)
+	require.Equal(t, size, agg.PaddedSize)
+}
+
+// Trailing space is zero padded up to the next power of two
+func TestAggregatePiecesPadsToPowerOfTwo(t *testing.T) {
+	a := filled(254, 1) // 256 padded
+	b := filled(127, 2) // 128 padded
+
+	agg, err := AggregatePieces([]Piece{pieceOf(t, a), pieceOf(t, b)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(512), agg.PaddedSize)
+
+	cp := &commp.Calc{}
+	_, _ = cp.Write(append(append(append([]byte{}, a...), b...), make([]byte, 127)...))
+	digest, _, err := cp.Digest()
+	require.NoError(t, err)
+	expected, err := commcid.DataCommitmentV1ToCID(digest)
+	require.NoError(t, err)
+	require.Equal(t, expected, agg.CID)
+}
+
+func TestAggregatePiecesRejectsInvalidSize(t *testing.T) {
+	p := pieceOf(t, filled(500, 1))
+	p.PaddedSize = 300
+	_, err := AggregatePieces([]Piece{p})
+	require.Error(t, err)
+}
+
+func TestRootInput(t *testing.T) {
+	require.Equal(t, "r:a+b", RootInput("r", "a", "b"))
+	require.Equal(t, "r:r", RootInput("r", "r"))
+}
+
+func TestPlanAggregates(t *testing.T) {
+	now := time.Now()
+	pending := func(size uint64, age time.Duration) PendingPiece {
+		return PendingPiece{Piece: Piece{PaddedSize: size}, UploadedAt: now.Add(-age)}
+	}
+	sizes := func(batches [][]PendingPiece) [][]uint64 {
+		var out [][]uint64
+		for _, b := range batches {
+			var s []uint64
+			for _, p := range b {
+				s = append(s, p.PaddedSize)
+			}
+			out = append(out, s)
+		}
+		return out
+	}
+	policy := AggregatePolicy{MaxSize: 1024, MaxSubroots: 3, MaxWait: time.Minute}
+
+	t.Run("partial batch waits", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{pending(128, time.Second), pending(256, 0)}, policy, now)
+		require.Empty(t, batches)
+	})
+
+	t.Run("partial batch flushed after max wait", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{pending(128, 2*time.Minute), pending(256, 0)}, policy, now)
+		require.Equal(t, [][]uint64{{128, 256}}, sizes(batches))
+	})
+
+	t.Run("size window", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{
+			pending(512, 0), pending(256, 0), pending(512, 0), pending(512, 0), pending(128, 0),
+		}, policy, now)
+		require.Equal(t, [][]uint64{{512, 256}, {512, 512}}, sizes(batches))
+	})
+
+	t.Run("subroot limit", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{
+			pending(128, 0), pending(128, 0), pending(128, 0), pending(128, 0),
+		}, policy, now)
+		require.Equal(t, [][]uint64{{128, 128, 128}}, sizes(batches))
+	})
+
+	t.Run("batch full at subroot limit", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{
+			pending(128, 0), pending(128, 0), pending(128, 0),
+		}, policy, now)
+		require.Equal(t, [][]uint64{{128, 128, 128}}, sizes(batches))
+	})
+
+	t.Run("oversized piece gets its own root", func(t *testing.T) {
+		batches := PlanAggregates([]PendingPiece{pending(128, 0), pending(4096, 0)}, policy, now)
+		require.Equal(t, [][]uint64{{128}, {4096}}, sizes(batches))
+	})
+}
@@ -0,0 +1,87 @@
+package ipfs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestPDP(t *testing.T, handler http.Handler) *PDP {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	pdp, err := NewPDP(server.URL, "test", string(keyPEM), 7, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pdp
+}
+
+func TestPDPGetProofSet(t *testing.T) {
+	pdp := newTestPDP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/pdp/proof-sets/7":
+			_, _ = w.Write([]byte(`{"id":7,"nextChallengeEpoch":1200,"roots":[
+				{"rootId":0,"rootCid":"root-a","subrootCid":"piece-1","subrootOffset":0},
+				{"rootId":0,"rootCid":"root-a","subrootCid":"piece-2","subrootOffset":256}]}`))
+		case "/pdp/proof-sets/7/roots/0":
+			_, _ = w.Write([]byte(`{"rootId":0,"rootCid":"root-a","subroots":[
+				{"subrootCid":"piece-1","subrootOffset":0},
+				{"subrootCid":"piece-2","subrootOffset":256}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	ctx := context.Background()
+
+	status, err := pdp.GetProofSet(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ID != 7 || status.NextChallengeEpoch == nil || *status.NextChallengeEpoch != 1200 {
+		t.Fatalf("unexpected proof set status %+v", status)
+	}
+	if status.LastProvenEpoch != nil {
+		t.Fatalf("expected no last proven epoch, got %d", *status.LastProvenEpoch)
+	}
+	if len(status.Roots) != 2 || status.Roots[1].SubrootCID != "piece-2" {
+		t.Fatalf("unexpected roots %+v", status.Roots)
+	}
+
+	root, err := pdp.GetRoot(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.RootCID != "root-a" || len(root.Subroots) != 2 {
+		t.Fatalf("unexpected root %+v", root)
+	}
+
+	if _, err := pdp.GetRoot(ctx, 1); !errors.Is(err, ErrRootNotFound) {
+		t.Fatalf("expected ErrRootNotFound, got %v", err)
+	}
+}
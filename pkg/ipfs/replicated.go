@@ -0,0 +1,823 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	carv2 "github.com/ipld/go-car/v2"
+	carstorage "github.com/ipld/go-car/v2/storage"
+)
+
+// Replica is a named backend of a replicated storage
+type Replica struct {
+	Name    string
+	Storage Storage
+}
+
+// ReplicaEntry records how a replica stores content that callers of a replicated
+// storage know by CID. A replica without an entry is assumed to store the content
+// under CID itself, so entries only exist for missing content and for replicas
+// that address content differently (e.g. PDP piece CIDs).
+type ReplicaEntry struct {
+	CID     cid.Cid
+	Replica string
+	// ReplicaCID is the CID of the content on the replica, cid.Undef while missing
+	ReplicaCID cid.Cid
+	// Block is set for content stored with PutBlock or PutDAG. Replicas that do
+	// not store blocks keep them in a CAR under ReplicaCID, one for all the
+	// blocks of a DAG.
+	Block bool
+	// Root is the root of the DAG a block was written with by PutDAG
+	Root cid.Cid
+	// LastError is the error of the last failed write or repair
+	LastError string
+	// Attempts counts failed repairs
+	Attempts int
+}
+
+// Missing reports whether the replica is missing the content
+func (e ReplicaEntry) Missing() bool {
+	return !e.ReplicaCID.Defined()
+}
+
+// ReplicaIndex persists the ReplicaEntry records of a replicated storage
+type ReplicaIndex interface {
+	// GetReplicas returns all entries for content c
+	GetReplicas(ctx context.Context, c cid.Cid) ([]ReplicaEntry, error)
+	// SetReplica creates or replaces the entry for entry.CID and entry.Replica
+	SetReplica(ctx context.Context, entry ReplicaEntry) error
+	// DeleteReplica removes the entry for content c on replica
+	DeleteReplica(ctx context.Context, c cid.Cid, replica string) error
+	// ListMissingReplicas returns up to limit missing entries, least recently updated first
+	ListMissingReplicas(ctx context.Context, limit int) ([]ReplicaEntry, error)
+}
+
+// RepairResult summarizes a ReplicatedStorage.Repair run
+type RepairResult struct {
+	Checked  int
+	Repaired int
+	Failed   int
+}
+
+// ReplicatedStorage writes content to several replicas and reads it from the
+// fastest healthy one
+type ReplicatedStorage interface {
+	Storage
+	// Replicas returns the replicas in configuration order
+	Replicas() []Replica
+	// ReplicaCID returns the CID content c has on replica, which differs from c
+	// on replicas addressing content differently (e.g. PDP piece CIDs), or
+	// cid.Undef if the replica is missing the content
+	ReplicaCID(ctx context.Context, c cid.Cid, replica string) (cid.Cid, error)
+	// WithReplicas returns a replicated storage sharing the index and replica
+	// health, with every replica storage replaced by wrap(replica)
+	WithReplicas(wrap func(Replica) Storage) ReplicatedStorage
+	// Repair re-adds up to limit pieces of content missing from a replica
+	Repair(ctx context.Context, limit int) (*RepairResult, error)
+	// CheckReplicas verifies the copy of content c (a block if block is set) on
+	// every replica that should have it and marks the unreachable or corrupt ones
+	// as missing, so that Repair copies the content over from an intact replica.
+	// It returns the names of the replicas marked.
+	CheckReplicas(ctx context.Context, c cid.Cid, block bool) ([]string, error)
+}
+
+// FindStorage returns storage, or the first of its replicas, implementing T
+func FindStorage[T any](storage Storage) (T, bool) {
+	if t, ok := storage.(T); ok {
+		return t, true
+	}
+	if r, ok := storage.(ReplicatedStorage); ok {
+		for _, replica := range r.Replicas() {
+			if t, ok := FindStorage[T](replica.Storage); ok {
+				return t, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+const (
+	// replicaLatencyWeight is the weight of a new sample in the latency average
+	replicaLatencyWeight = 0.2
+	// replicaBackoff is how long a replica is skipped for reads after a failure,
+	// doubling with every consecutive failure up to replicaMaxBackoff
+	replicaBackoff    = 10 * time.Second
+	replicaMaxBackoff = 5 * time.Minute
+)
+
+// replicaHealth tracks how fast and reliable a replica has been recently
+type replicaHealth struct {
+	mu        sync.Mutex
+	latency   time.Duration // moving average of successful operations
+	failures  int           // consecutive failures
+	downUntil time.Time
+}
+
+func (h *replicaHealth) observe(elapsed time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		h.failures++
+		backoff := replicaBackoff << min(h.failures-1, 10)
+		h.downUntil = time.Now().Add(min(backoff, replicaMaxBackoff))
+		return
+	}
+	h.failures = 0
+	h.downUntil = time.Time{}
+	if h.latency == 0 {
+		h.latency = elapsed
+	} else {
+		h.latency += time.Duration(replicaLatencyWeight * float64(elapsed-h.latency))
+	}
+}
+
+func (h *replicaHealth) status() (healthy bool, latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Now().After(h.downUntil), h.latency
+}
+
+type replica struct {
+	Replica
+	health *replicaHealth
+}
+
+// replicated implements ReplicatedStorage
+type replicated struct {
+	replicas []*replica
+	quorum   int
+	// cidMode is how content is keyed, whichever replicas store it
+	cidMode string
+	index   ReplicaIndex
+	logger  *slog.Logger
+}
+
+// replicatedBlocks is a replicated storage with at least one block storage
+// replica, so that blocks are read back as fast as content
+type replicatedBlocks struct {
+	*replicated
+}
+
+var (
+	_ ReplicatedStorage = (*replicated)(nil)
+	_ DAGStorage        = (*replicatedBlocks)(nil)
+)
+
+// NewReplicated creates a storage that writes content to all replicas and succeeds
+// once writeQuorum of them have it. Replicas that failed are recorded in index and
+// brought up to date by Repair. New content is keyed by the CID it has in cidMode
+// (CIDModeKubo or CIDModePDP), computed before it is written, so the key does not
+// depend on which replicas are up. Replicas storing it under another CID are
+// recorded in index.
+//
+// The storage implements DAGStorage if at least one replica implements
+// BlockStorage. Blocks are written to every replica, the others storing the
+// blocks of a DAG as one CAR with Add, and count towards writeQuorum like any
+// other write.
+func NewReplicated(replicas []Replica, writeQuorum int, cidMode string, index ReplicaIndex, logger *slog.Logger) (ReplicatedStorage, error) {
+	if len(replicas) == 0 {
+		return nil, fmt.Errorf("no replicas configured")
+	}
+	if writeQuorum < 1 || writeQuorum > len(replicas) {
+		return nil, fmt.Errorf("write quorum must be between 1 and %d, got %d", len(replicas), writeQuorum)
+	}
+	if cidMode != CIDModeKubo && cidMode != CIDModePDP {
+		return nil, fmt.Errorf("unsupported CID mode %q", cidMode)
+	}
+	seen := make(map[string]bool, len(replicas))
+	reps := make([]*replica, len(replicas))
+	for i, r := range replicas {
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate replica %q", r.Name)
+		}
+		seen[r.Name] = true
+		reps[i] = &replica{Replica: r, health: &replicaHealth{}}
+	}
+	return newReplicated(reps, writeQuorum, cidMode, index, logger), nil
+}
+
+func newReplicated(replicas []*replica, quorum int, cidMode string, index ReplicaIndex, logger *slog.Logger) ReplicatedStorage {
+	r := &replicated{replicas: replicas, quorum: quorum, cidMode: cidMode, index: index, logger: logger}
+	if len(r.blockReplicas()) > 0 {
+		return &replicatedBlocks{r}
+	}
+	return r
+}
+
+func (r *replicated) Replicas() []Replica {
+	replicas := make([]Replica, len(r.replicas))
+	for i, rep := range r.replicas {
+		replicas[i] = rep.Replica
+	}
+	return replicas
+}
+
+func (r *replicated) ReplicaCID(ctx context.Context, c cid.Cid, replica string) (cid.Cid, error) {
+	entries, err := r.index.GetReplicas(ctx, c)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to get replica entries: %w", err)
+	}
+	for _, e := range entries {
+		if e.Replica == replica {
+			return e.ReplicaCID, nil
+		}
+	}
+	return c, nil
+}
+
+func (r *replicated) WithReplicas(wrap func(Replica) Storage) ReplicatedStorage {
+	replicas := make([]*replica, len(r.replicas))
+	for i, rep := range r.replicas {
+		replicas[i] = &replica{
+			Replica: Replica{Name: rep.Name, Storage: wrap(rep.Replica)},
+			health:  rep.health,
+		}
+	}
+	return newReplicated(replicas, r.quorum, r.cidMode, r.index, r.logger)
+}
+
+// Add adds content to all replicas concurrently
+func (r *replicated) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	readers, err := splitReadSeeker(content, len(r.replicas)+1)
+	if err != nil {
+		return cid.Undef, err
+	}
+	key, err := computeCID(readers[len(r.replicas)], r.cidMode)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	results := make([]writeResult, len(r.replicas))
+	var wg sync.WaitGroup
+	for i, rep := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			c, err := rep.Storage.Add(ctx, readers[i])
+			rep.health.observe(time.Since(start), err)
+			results[i] = writeResult{replica: rep, cid: c, err: err}
+		}()
+	}
+	wg.Wait()
+
+	return key, r.commit(ctx, results, false, cid.Undef, key)
+}
+
+// Get reads content from the fastest healthy replica that has it, falling back
+// to the others
+func (r *replicated) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	var errs []error
+	for _, t := range r.readTargets(ctx, c, r.replicas) {
+		start := time.Now()
+		rc, err := t.replica.Storage.Get(ctx, t.cid)
+		t.replica.health.observe(time.Since(start), err)
+		if err == nil {
+			return rc, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t.replica.Name, err))
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no replica has %s", c)
+	}
+	return nil, fmt.Errorf("failed to get %s from replicas: %w", c, errors.Join(errs...))
+}
+
+// PutBlock stores a block on all replicas concurrently
+func (r *replicatedBlocks) PutBlock(ctx context.Context, block blocks.Block) error {
+	return r.putDAG(ctx, cid.Undef, []blocks.Block{block})
+}
+
+// PutDAG stores the blocks of a DAG on all replicas concurrently. Replicas
+// without block storage get one CAR holding all of them.
+func (r *replicatedBlocks) PutDAG(ctx context.Context, root cid.Cid, dag []blocks.Block) error {
+	return r.putDAG(ctx, root, dag)
+}
+
+func (r *replicatedBlocks) putDAG(ctx context.Context, root cid.Cid, dag []blocks.Block) error {
+	if len(dag) == 0 {
+		return nil
+	}
+	carRoot := root
+	if !carRoot.Defined() {
+		carRoot = dag[0].Cid()
+	}
+
+	results := make([]writeResult, len(r.replicas))
+	var wg sync.WaitGroup
+	for i, rep := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			c, err := rep.putBlocks(ctx, carRoot, dag)
+			rep.health.observe(time.Since(start), err)
+			results[i] = writeResult{replica: rep, cid: c, err: err}
+		}()
+	}
+	wg.Wait()
+
+	keys := make([]cid.Cid, len(dag))
+	for i, block := range dag {
+		keys[i] = block.Cid()
+	}
+	return r.commit(ctx, results, true, root, keys...)
+}
+
+// GetBlock reads a block from the fastest healthy replica that has it, falling
+// back to the others
+func (r *replicatedBlocks) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	return r.getBlock(ctx, c)
+}
+
+func (r *replicated) getBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	var errs []error
+	for _, t := range r.readTargets(ctx, c, r.replicas) {
+		start := time.Now()
+		block, err := t.replica.getBlock(ctx, c, t.cid)
+		t.replica.health.observe(time.Since(start), err)
+		if err == nil {
+			return block, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", t.replica.Name, err))
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no replica has block %s", c)
+	}
+	return nil, fmt.Errorf("failed to get block %s from replicas: %w", c, errors.Join(errs...))
+}
+
+// Repair re-adds content missing from a replica, reading it from the others.
+// The missing blocks of a DAG are written together, as one CAR on replicas
+// without block storage.
+func (r *replicated) Repair(ctx context.Context, limit int) (*RepairResult, error) {
+	missing, err := r.index.ListMissingReplicas(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missing replicas: %w", err)
+	}
+
+	result := &RepairResult{Checked: len(missing)}
+	type dagKey struct {
+		root    cid.Cid
+		replica string
+	}
+	var (
+		groups []dagKey
+		dags   = make(map[dagKey][]ReplicaEntry)
+	)
+	for _, entry := range missing {
+		target := r.replica(entry.Replica)
+		if target == nil {
+			// The replica has been removed from the configuration
+			if err := r.index.DeleteReplica(ctx, entry.CID, entry.Replica); err != nil {
+				return result, fmt.Errorf("failed to delete replica entry: %w", err)
+			}
+			continue
+		}
+		if !entry.Block {
+			replicaCID, err := r.repair(ctx, entry, target)
+			if err := r.recordRepair(ctx, result, entry, replicaCID, err); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		key := dagKey{root: entry.Root, replica: entry.Replica}
+		if !key.root.Defined() {
+			key.root = entry.CID
+		}
+		if _, ok := dags[key]; !ok {
+			groups = append(groups, key)
+		}
+		dags[key] = append(dags[key], entry)
+	}
+
+	for _, key := range groups {
+		entries := dags[key]
+		dag := make([]blocks.Block, 0, len(entries))
+		var errs []error
+		for _, entry := range entries {
+			block, err := r.getBlock(ctx, entry.CID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			dag = append(dag, block)
+		}
+		var (
+			replicaCID cid.Cid
+			err        = errors.Join(errs...)
+		)
+		if err == nil {
+			replicaCID, err = r.replica(key.replica).putBlocks(ctx, key.root, dag)
+		}
+		for _, entry := range entries {
+			if err := r.recordRepair(ctx, result, entry, replicaCID, err); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// recordRepair records the outcome of repairing entry. replicaCID is the CID
+// of the content on the replica, cid.Undef for blocks stored as such.
+func (r *replicated) recordRepair(ctx context.Context, result *RepairResult, entry ReplicaEntry, replicaCID cid.Cid, repairErr error) error {
+	logger := r.logger.With("cid", entry.CID, "replica", entry.Replica)
+	if repairErr != nil {
+		result.Failed++
+		entry.LastError = repairErr.Error()
+		entry.Attempts++
+		logger.Warn("failed to repair replica", "attempts", entry.Attempts, "error", repairErr)
+		if err := r.index.SetReplica(ctx, entry); err != nil {
+			return fmt.Errorf("failed to record repair failure: %w", err)
+		}
+		return nil
+	}
+
+	result.Repaired++
+	var err error
+	if !replicaCID.Defined() || replicaCID.Equals(entry.CID) {
+		err = r.index.DeleteReplica(ctx, entry.CID, entry.Replica)
+	} else {
+		err = r.index.SetReplica(ctx, ReplicaEntry{CID: entry.CID, Replica: entry.Replica, ReplicaCID: replicaCID, Block: entry.Block, Root: entry.Root})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record repaired replica: %w", err)
+	}
+	logger.Info("repaired replica", "replica_cid", replicaCID)
+	return nil
+}
+
+// CheckReplicas verifies content c on each replica and marks the failing ones as
+// missing, as long as one intact copy is left to repair them from
+func (r *replicated) CheckReplicas(ctx context.Context, c cid.Cid, block bool) ([]string, error) {
+	var (
+		failed []ReplicaEntry
+		intact int
+	)
+	for _, t := range r.readTargets(ctx, c, r.replicas) {
+		var err error
+		if block {
+			err = t.replica.verifyBlock(ctx, c, t.cid)
+		} else {
+			err = VerifyContent(ctx, t.replica.Storage, t.cid)
+		}
+		var verifyErr *VerifyError
+		switch {
+		case err == nil:
+			intact++
+		case errors.As(err, &verifyErr):
+			failed = append(failed, ReplicaEntry{CID: c, Replica: t.replica.Name, Block: block, Root: t.root, LastError: err.Error()})
+		default:
+			return nil, err
+		}
+	}
+	if len(failed) == 0 {
+		return nil, nil
+	}
+	if intact == 0 {
+		return nil, fmt.Errorf("no intact replica of %s to repair from", c)
+	}
+
+	marked := make([]string, 0, len(failed))
+	for _, entry := range failed {
+		if err := r.index.SetReplica(ctx, entry); err != nil {
+			return marked, fmt.Errorf("failed to mark replica %s missing: %w", entry.Replica, err)
+		}
+		r.logger.Warn("replica failed verification, content will be repaired", "cid", c, "replica", entry.Replica, "error", entry.LastError)
+		marked = append(marked, entry.Replica)
+	}
+	return marked, nil
+}
+
+// repair copies the content of entry from the other replicas to target and
+// returns its CID on target
+func (r *replicated) repair(ctx context.Context, entry ReplicaEntry, target *replica) (cid.Cid, error) {
+	rc, err := r.Get(ctx, entry.CID)
+	if err != nil {
+		return cid.Undef, err
+	}
+	defer rc.Close() // nolint:errcheck
+
+	// Storage.Add needs an io.ReadSeeker
+	tmp, err := os.CreateTemp("", "threadmirror-repair-*")
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+	defer tmp.Close()           // nolint:errcheck
+
+	if _, err := io.Copy(tmp, rc); err != nil {
+		return cid.Undef, fmt.Errorf("failed to read content: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return cid.Undef, fmt.Errorf("failed to seek temp file: %w", err)
+	}
+	return target.Storage.Add(ctx, tmp)
+}
+
+// putBlocks stores the blocks of the DAG rooted at root on the replica. It
+// returns cid.Undef on block storages, which keep every block under its own
+// CID, and the CID of the CAR holding all the blocks on the other replicas.
+func (rep *replica) putBlocks(ctx context.Context, root cid.Cid, dag []blocks.Block) (cid.Cid, error) {
+	if bs, ok := rep.Storage.(BlockStorage); ok {
+		for _, block := range dag {
+			if err := bs.PutBlock(ctx, block); err != nil {
+				return cid.Undef, err
+			}
+		}
+		return cid.Undef, nil
+	}
+
+	var car bytes.Buffer
+	w, err := carstorage.NewWritable(&car, []cid.Cid{root}, carv2.WriteAsCarV1(true))
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to create block CAR: %w", err)
+	}
+	for _, block := range dag {
+		if err := w.Put(ctx, block.Cid().KeyString(), block.RawData()); err != nil {
+			return cid.Undef, fmt.Errorf("failed to write block CAR: %w", err)
+		}
+	}
+	return rep.Storage.Add(ctx, bytes.NewReader(car.Bytes()))
+}
+
+// getBlock reads block c from the replica, where it is stored under replicaCID
+func (rep *replica) getBlock(ctx context.Context, c, replicaCID cid.Cid) (blocks.Block, error) {
+	if bs, ok := rep.Storage.(BlockStorage); ok {
+		return bs.GetBlock(ctx, c)
+	}
+
+	rc, err := rep.Storage.Get(ctx, replicaCID)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() // nolint:errcheck
+	br, err := carv2.NewBlockReader(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block CAR: %w", err)
+	}
+	for {
+		block, err := br.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("block CAR %s does not hold %s", replicaCID, c)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block CAR: %w", err)
+		}
+		if block.Cid().Equals(c) {
+			return block, nil
+		}
+	}
+}
+
+// verifyBlock reads block c from the replica and checks that it still hashes
+// to c. Failures are returned as a *VerifyError.
+func (rep *replica) verifyBlock(ctx context.Context, c, replicaCID cid.Cid) error {
+	block, err := rep.getBlock(ctx, c, replicaCID)
+	if err != nil {
+		return &VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ErrContentUnreachable, err)}
+	}
+	return VerifyBlock(block)
+}
+
+type writeResult struct {
+	replica *replica
+	// cid is the CID of the content on the replica, cid.Undef for blocks stored
+	// as such
+	cid cid.Cid
+	err error
+}
+
+// commit checks the write quorum and records the replicas that failed or store
+// the content of keys under a different CID. root is the root of the DAG
+// written with PutDAG.
+func (r *replicated) commit(ctx context.Context, results []writeResult, block bool, root cid.Cid, keys ...cid.Cid) error {
+	var (
+		ok   int
+		errs []error
+	)
+	for _, res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.replica.Name, res.err))
+			continue
+		}
+		ok++
+	}
+	if ok < r.quorum {
+		return fmt.Errorf("write quorum not reached (%d of %d replicas, need %d): %w", ok, len(results), r.quorum, errors.Join(errs...))
+	}
+
+	for _, res := range results {
+		for _, key := range keys {
+			entry := ReplicaEntry{CID: key, Replica: res.replica.Name, Block: block, Root: root}
+			switch {
+			case res.err != nil:
+				entry.LastError = res.err.Error()
+			case !res.cid.Defined(), res.cid.Equals(key):
+				continue
+			default:
+				entry.ReplicaCID = res.cid
+			}
+			if err := r.index.SetReplica(ctx, entry); err != nil {
+				return fmt.Errorf("failed to record replica %s: %w", res.replica.Name, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		logCID := keys[0]
+		if root.Defined() {
+			logCID = root
+		}
+		r.logger.Warn("replica writes failed, content will be repaired", "cid", logCID, "error", errors.Join(errs...))
+	}
+	return nil
+}
+
+type readTarget struct {
+	replica *replica
+	cid     cid.Cid
+	// root is the DAG root recorded for the content on the replica
+	root cid.Cid
+}
+
+// readTargets returns the replicas of candidates that have content c with its CID
+// there, healthy replicas first and faster ones before slower ones
+func (r *replicated) readTargets(ctx context.Context, c cid.Cid, candidates []*replica) []readTarget {
+	entries, err := r.index.GetReplicas(ctx, c)
+	if err != nil {
+		// Still worth trying every replica under c
+		r.logger.Warn("failed to get replica entries", "cid", c, "error", err)
+	}
+	byReplica := make(map[string]ReplicaEntry, len(entries))
+	for _, e := range entries {
+		byReplica[e.Replica] = e
+	}
+
+	type ranked struct {
+		readTarget
+		healthy bool
+		latency time.Duration
+	}
+	var targets []ranked
+	for _, rep := range candidates {
+		t := readTarget{replica: rep, cid: c}
+		if e, ok := byReplica[rep.Name]; ok {
+			if e.Missing() {
+				continue
+			}
+			t.cid = e.ReplicaCID
+			t.root = e.Root
+		}
+		healthy, latency := rep.health.status()
+		targets = append(targets, ranked{readTarget: t, healthy: healthy, latency: latency})
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		if targets[i].healthy != targets[j].healthy {
+			return targets[i].healthy
+		}
+		return targets[i].latency < targets[j].latency
+	})
+
+	out := make([]readTarget, len(targets))
+	for i, t := range targets {
+		out[i] = t.readTarget
+	}
+	return out
+}
+
+func (r *replicated) blockReplicas() []*replica {
+	var replicas []*replica
+	for _, rep := range r.replicas {
+		if _, ok := rep.Storage.(BlockStorage); ok {
+			replicas = append(replicas, rep)
+		}
+	}
+	return replicas
+}
+
+func (r *replicated) replica(name string) *replica {
+	for _, rep := range r.replicas {
+		if rep.Name == name {
+			return rep
+		}
+	}
+	return nil
+}
+
+// splitReadSeeker returns n independent readers over the rest of content
+func splitReadSeeker(content io.ReadSeeker, n int) ([]io.ReadSeeker, error) {
+	readers := make([]io.ReadSeeker, n)
+
+	if ra, ok := content.(io.ReaderAt); ok {
+		start, err := content.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seek content: %w", err)
+		}
+		end, err := content.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seek content: %w", err)
+		}
+		for i := range readers {
+			readers[i] = io.NewSectionReader(ra, start, end-start)
+		}
+		return readers, nil
+	}
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	for i := range readers {
+		readers[i] = bytes.NewReader(data)
+	}
+	return readers, nil
+}
+
+// MemoryReplicaIndex is an in-memory ReplicaIndex. Missing replicas are forgotten
+// on restart, so it is only meant for tests and one-off commands.
+type MemoryReplicaIndex struct {
+	mu      sync.Mutex
+	entries map[string]map[string]memoryReplicaEntry
+	clock   int64
+}
+
+type memoryReplicaEntry struct {
+	ReplicaEntry
+	updated int64
+}
+
+var _ ReplicaIndex = (*MemoryReplicaIndex)(nil)
+
+func NewMemoryReplicaIndex() *MemoryReplicaIndex {
+	return &MemoryReplicaIndex{entries: make(map[string]map[string]memoryReplicaEntry)}
+}
+
+func (m *MemoryReplicaIndex) GetReplicas(ctx context.Context, c cid.Cid) ([]ReplicaEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []ReplicaEntry
+	for _, e := range m.entries[c.KeyString()] {
+		entries = append(entries, e.ReplicaEntry)
+	}
+	return entries, nil
+}
+
+func (m *MemoryReplicaIndex) SetReplica(ctx context.Context, entry ReplicaEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := entry.CID.KeyString()
+	if m.entries[key] == nil {
+		m.entries[key] = make(map[string]memoryReplicaEntry)
+	}
+	m.clock++
+	m.entries[key][entry.Replica] = memoryReplicaEntry{ReplicaEntry: entry, updated: m.clock}
+	return nil
+}
+
+func (m *MemoryReplicaIndex) DeleteReplica(ctx context.Context, c cid.Cid, replica string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries[c.KeyString()], replica)
+	if len(m.entries[c.KeyString()]) == 0 {
+		delete(m.entries, c.KeyString())
+	}
+	return nil
+}
+
+func (m *MemoryReplicaIndex) ListMissingReplicas(ctx context.Context, limit int) ([]ReplicaEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var missing []memoryReplicaEntry
+	for _, byReplica := range m.entries {
+		for _, e := range byReplica {
+			if e.Missing() {
+				missing = append(missing, e)
+			}
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].updated < missing[j].updated })
+
+	entries := make([]ReplicaEntry, 0, min(limit, len(missing)))
+	for _, e := range missing[:min(limit, len(missing))] {
+		entries = append(entries, e.ReplicaEntry)
+	}
+	return entries, nil
+}
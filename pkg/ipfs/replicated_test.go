@@ -0,0 +1,406 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// memStorage is an in-memory Storage addressing content with prefix, or in
+// mode if set
+type memStorage struct {
+	mu     sync.Mutex
+	prefix cid.Prefix
+	mode   string
+	data   map[cid.Cid][]byte
+	down   bool
+}
+
+func newMemStorage(codec uint64) *memStorage {
+	return &memStorage{
+		prefix: cid.Prefix{Version: 1, Codec: codec, MhType: multihash.SHA2_256, MhLength: -1},
+		data:   make(map[cid.Cid][]byte),
+	}
+}
+
+// newKuboMemStorage returns a memStorage addressing content like Kubo
+func newKuboMemStorage() *memStorage {
+	m := newMemStorage(cid.Raw)
+	m.mode = CIDModeKubo
+	return m
+}
+
+var errDown = errors.New("replica down")
+
+func (m *memStorage) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return cid.Undef, errDown
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return cid.Undef, err
+	}
+	var c cid.Cid
+	if m.mode != "" {
+		c, err = computeCID(bytes.NewReader(data), m.mode)
+	} else {
+		c, err = m.prefix.Sum(data)
+	}
+	if err != nil {
+		return cid.Undef, err
+	}
+	m.data[c] = data
+	return c, nil
+}
+
+func (m *memStorage) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return nil, errDown
+	}
+	data, ok := m.data[c]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// memBlockStorage additionally stores blocks
+type memBlockStorage struct {
+	*memStorage
+}
+
+func (m memBlockStorage) PutBlock(ctx context.Context, block blocks.Block) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return errDown
+	}
+	m.data[block.Cid()] = block.RawData()
+	return nil
+}
+
+func (m memBlockStorage) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return nil, errDown
+	}
+	data, ok := m.data[c]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return blocks.NewBlockWithCid(data, c)
+}
+
+func (m *memStorage) setDown(down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = down
+}
+
+func readAll(t *testing.T, s Storage, c cid.Cid) string {
+	t.Helper()
+	rc, err := s.Get(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close() // nolint:errcheck
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestReplicatedQuorumAndRepair(t *testing.T) {
+	ctx := context.Background()
+	kubo := newKuboMemStorage()
+	pdp := newMemStorage(cid.FilCommitmentUnsealed) // addresses content differently
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"kubo", memBlockStorage{kubo}}, {"pdp", pdp}}, 1, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both replicas up: the CID is the kubo one, the pdp one is mapped
+	c, err := storage.Add(ctx, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := computeCID(strings.NewReader("hello"), CIDModeKubo); !c.Equals(want) {
+		t.Fatalf("expected the kubo CID %s, got %s", want, c)
+	}
+	kubo.setDown(true)
+	if got := readAll(t, storage, c); got != "hello" {
+		t.Fatalf("expected fallback read from pdp, got %q", got)
+	}
+	kubo.setDown(false)
+	if got, err := storage.ReplicaCID(ctx, c, "kubo"); err != nil || !got.Equals(c) {
+		t.Fatalf("expected %s on kubo, got %s: %v", c, got, err)
+	}
+	if got, err := storage.ReplicaCID(ctx, c, "pdp"); err != nil || got.Prefix().Codec != cid.FilCommitmentUnsealed {
+		t.Fatalf("expected the pdp CID, got %s: %v", got, err)
+	}
+
+	// One replica down: the write still reaches the quorum and is repaired later
+	pdp.setDown(true)
+	c2, err := storage.Add(ctx, strings.NewReader("world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, _ := index.ListMissingReplicas(ctx, 10)
+	if len(missing) != 1 || missing[0].Replica != "pdp" || !missing[0].CID.Equals(c2) {
+		t.Fatalf("expected pdp to be missing %s, got %+v", c2, missing)
+	}
+
+	result, err := storage.Repair(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed != 1 || result.Repaired != 0 {
+		t.Fatalf("expected repair to fail while pdp is down, got %+v", result)
+	}
+
+	pdp.setDown(false)
+	result, err = storage.Repair(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Repaired != 1 {
+		t.Fatalf("expected one repaired replica, got %+v", result)
+	}
+	if missing, _ := index.ListMissingReplicas(ctx, 10); len(missing) != 0 {
+		t.Fatalf("expected no missing replicas, got %+v", missing)
+	}
+	kubo.setDown(true)
+	if got := readAll(t, storage, c2); got != "world" {
+		t.Fatalf("expected repaired content on pdp, got %q", got)
+	}
+
+	// Both replicas down: the quorum is not reached
+	pdp.setDown(true)
+	if _, err := storage.Add(ctx, strings.NewReader("lost")); err == nil {
+		t.Fatal("expected write quorum error")
+	}
+}
+
+func TestReplicatedBlocks(t *testing.T) {
+	ctx := context.Background()
+	a, b := newMemStorage(cid.Raw), newMemStorage(cid.Raw)
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"a", memBlockStorage{a}}, {"b", memBlockStorage{b}}}, 2, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, ok := storage.(BlockStorage)
+	if !ok {
+		t.Fatal("expected block storage")
+	}
+
+	block := blocks.NewBlock([]byte("block"))
+	b.setDown(true)
+	if err := bs.PutBlock(ctx, block); err == nil {
+		t.Fatal("expected write quorum error")
+	}
+	b.setDown(false)
+	if err := bs.PutBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	got, err := bs.GetBlock(ctx, block.Cid())
+	if err != nil || !bytes.Equal(got.RawData(), block.RawData()) {
+		t.Fatalf("unexpected block %v: %v", got, err)
+	}
+
+	// Without any block replica there is no block storage
+	storage, err = NewReplicated([]Replica{{"a", a}, {"b", b}}, 2, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := storage.(BlockStorage); ok {
+		t.Fatal("expected no block storage")
+	}
+}
+
+func TestReplicatedBlocksOnContentReplica(t *testing.T) {
+	ctx := context.Background()
+	kubo, pdp := newMemStorage(cid.Raw), newMemStorage(cid.FilCommitmentUnsealed)
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"kubo", memBlockStorage{kubo}}, {"pdp", pdp}}, 2, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, ok := storage.(BlockStorage)
+	if !ok {
+		t.Fatal("expected block storage")
+	}
+	if _, ok := FindStorage[BlockStorage](storage); !ok {
+		t.Fatal("expected to find the block storage replica")
+	}
+
+	// The replica without block storage counts towards the quorum
+	block := blocks.NewBlock([]byte("thread"))
+	pdp.setDown(true)
+	if err := bs.PutBlock(ctx, block); err == nil {
+		t.Fatal("expected write quorum error")
+	}
+	pdp.setDown(false)
+	if err := bs.PutBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := index.GetReplicas(ctx, block.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Replica != "pdp" || !entries[0].Block || entries[0].Missing() {
+		t.Fatalf("expected the block CAR on pdp to be recorded, got %+v", entries)
+	}
+
+	// The block survives the block replica going down
+	kubo.setDown(true)
+	got, err := bs.GetBlock(ctx, block.Cid())
+	if err != nil || !bytes.Equal(got.RawData(), block.RawData()) {
+		t.Fatalf("unexpected block %v: %v", got, err)
+	}
+	if marked, err := storage.CheckReplicas(ctx, block.Cid(), true); err != nil || len(marked) != 1 || marked[0] != "kubo" {
+		t.Fatalf("expected kubo to be marked, got %v: %v", marked, err)
+	}
+
+	// and is repaired from the CAR
+	kubo.setDown(false)
+	delete(kubo.data, block.Cid())
+	result, err := storage.Repair(ctx, 10)
+	if err != nil || result.Repaired != 1 {
+		t.Fatalf("unexpected repair result %+v: %v", result, err)
+	}
+	if !bytes.Equal(kubo.data[block.Cid()], block.RawData()) {
+		t.Fatal("expected the block to be repaired on kubo")
+	}
+}
+
+// The key does not depend on which replicas are up
+func TestReplicatedKeyIndependentOfReplicas(t *testing.T) {
+	ctx := context.Background()
+	kubo, pdp := newKuboMemStorage(), newMemStorage(cid.FilCommitmentUnsealed)
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"pdp", pdp}, {"kubo", kubo}}, 1, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := computeCID(strings.NewReader("hello"), CIDModeKubo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubo.setDown(true)
+	c, err := storage.Add(ctx, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equals(want) {
+		t.Fatalf("expected the kubo CID %s while kubo is down, got %s", want, c)
+	}
+	if got, err := storage.ReplicaCID(ctx, c, "pdp"); err != nil || got.Prefix().Codec != cid.FilCommitmentUnsealed {
+		t.Fatalf("expected the pdp CID, got %s: %v", got, err)
+	}
+	if got, err := storage.ReplicaCID(ctx, c, "kubo"); err != nil || got.Defined() {
+		t.Fatalf("expected kubo to be missing, got %s: %v", got, err)
+	}
+	if got := readAll(t, storage, c); got != "hello" {
+		t.Fatalf("expected read from pdp, got %q", got)
+	}
+
+	kubo.setDown(false)
+	if result, err := storage.Repair(ctx, 10); err != nil || result.Repaired != 1 {
+		t.Fatalf("unexpected repair result %+v: %v", result, err)
+	}
+	if got, err := storage.ReplicaCID(ctx, c, "kubo"); err != nil || !got.Equals(c) {
+		t.Fatalf("expected %s on kubo, got %s: %v", c, got, err)
+	}
+
+	if _, err := NewReplicated([]Replica{{"kubo", kubo}}, 1, "", index, slog.Default()); err == nil {
+		t.Fatal("expected an error without a CID mode")
+	}
+}
+
+func TestReplicatedDAGOnContentReplica(t *testing.T) {
+	ctx := context.Background()
+	kubo, pdp := newMemStorage(cid.Raw), newMemStorage(cid.FilCommitmentUnsealed)
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"kubo", memBlockStorage{kubo}}, {"pdp", pdp}}, 1, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, ok := storage.(DAGStorage)
+	if !ok {
+		t.Fatal("expected DAG storage")
+	}
+
+	dag := []blocks.Block{blocks.NewBlock([]byte("tweet 1")), blocks.NewBlock([]byte("tweet 2")), blocks.NewBlock([]byte("root"))}
+	root := dag[2].Cid()
+	pieceCID := func(c cid.Cid) cid.Cid {
+		t.Helper()
+		got, err := storage.ReplicaCID(ctx, c, "pdp")
+		if err != nil || got.Prefix().Codec != cid.FilCommitmentUnsealed {
+			t.Fatalf("expected %s to be mapped to a pdp piece, got %s: %v", c, got, err)
+		}
+		return got
+	}
+
+	// pdp gets one CAR holding every block
+	if err := ds.PutDAG(ctx, root, dag); err != nil {
+		t.Fatal(err)
+	}
+	if len(pdp.data) != 1 {
+		t.Fatalf("expected one piece on pdp, got %d", len(pdp.data))
+	}
+	piece := pieceCID(root)
+	for _, block := range dag {
+		if got := pieceCID(block.Cid()); !got.Equals(piece) {
+			t.Fatalf("expected %s in piece %s, got %s", block.Cid(), piece, got)
+		}
+	}
+	kubo.setDown(true)
+	for _, block := range dag {
+		got, err := ds.GetBlock(ctx, block.Cid())
+		if err != nil || !bytes.Equal(got.RawData(), block.RawData()) {
+			t.Fatalf("unexpected block %v from the CAR: %v", got, err)
+		}
+	}
+	kubo.setDown(false)
+
+	// A DAG missing from pdp is repaired as one CAR too
+	other := []blocks.Block{blocks.NewBlock([]byte("tweet 3")), blocks.NewBlock([]byte("other root"))}
+	pdp.setDown(true)
+	if err := ds.PutDAG(ctx, other[1].Cid(), other); err != nil {
+		t.Fatal(err)
+	}
+	pdp.setDown(false)
+	result, err := storage.Repair(ctx, 10)
+	if err != nil || result.Repaired != 2 {
+		t.Fatalf("unexpected repair result %+v: %v", result, err)
+	}
+	if len(pdp.data) != 2 {
+		t.Fatalf("expected one more piece on pdp, got %d", len(pdp.data))
+	}
+	if !pieceCID(other[0].Cid()).Equals(pieceCID(other[1].Cid())) {
+		t.Fatal("expected the repaired blocks to share a piece")
+	}
+}
@@ -0,0 +1,280 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/boxo/blockservice"
+	"github.com/ipfs/boxo/blockstore"
+	"github.com/ipfs/boxo/exchange/offline"
+	"github.com/ipfs/boxo/files"
+	"github.com/ipfs/boxo/ipld/merkledag"
+	unixfile "github.com/ipfs/boxo/ipld/unixfs/file"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3MaxBlockSize bounds the objects GetBlock reads: anything larger is whole
+// content rather than a block (Kubo refuses blocks over 2MiB too)
+const s3MaxBlockSize = 2 << 20
+
+// errNotBlock means the object stored under a CID is not the block of that
+// CID, e.g. a piece
+var errNotBlock = errors.New("object is not a block")
+
+var _ BlockStorage = (*S3Storage)(nil)
+
+// S3Options configures a S3Storage
+type S3Options struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	// Prefix is prepended to every object key
+	Prefix string
+	// CIDMode is CIDModeKubo or CIDModePDP
+	CIDMode string
+}
+
+// S3Storage implements Storage on a S3 compatible object store (e.g. MinIO).
+// Objects are keyed by CID, computed as the Kubo (UnixFS) or PDP (CommP)
+// backend would, so the bucket can replace or mirror those backends without
+// changing any reference.
+//
+// In kubo mode Add imports content as a UnixFS DAG and stores every block as
+// an object, as FSStorage does, so GetBlock and DAG walks (e.g. CAR export)
+// see the same blocks a Kubo node would serve. In pdp mode content is stored
+// whole under its piece CID.
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+	mode   string
+	dag    ipld.DAGService
+}
+
+// NewS3Storage connects to the object store and creates the bucket if needed
+func NewS3Storage(ctx context.Context, opts S3Options) (*S3Storage, error) {
+	if opts.CIDMode != CIDModeKubo && opts.CIDMode != CIDModePDP {
+		return nil, fmt.Errorf("unsupported CID mode %q", opts.CIDMode)
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKeyID, opts.SecretAccessKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %s: %w", opts.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", opts.Bucket, err)
+		}
+	}
+
+	s := &S3Storage{client: client, bucket: opts.Bucket, prefix: opts.Prefix, mode: opts.CIDMode}
+	bs := &s3Blockstore{s: s}
+	s.dag = merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+	return s, nil
+}
+
+// Add stores content and returns its CID
+func (s *S3Storage) Add(ctx context.Context, content io.ReadSeeker) (cid.Cid, error) {
+	if s.mode == CIDModeKubo {
+		node, err := importUnixFS(s.dag, content)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("failed to add content to S3: %w", err)
+		}
+		return node.Cid(), nil
+	}
+
+	c, err := computeCID(content, s.mode)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to seek content: %w", err)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return cid.Undef, fmt.Errorf("failed to seek content: %w", err)
+	}
+
+	if err := s.put(ctx, c, content, size); err != nil {
+		return cid.Undef, fmt.Errorf("failed to add content to S3: %w", err)
+	}
+	return c, nil
+}
+
+// Get retrieves content by CID, either a piece or a UnixFS file
+func (s *S3Storage) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	if c.Prefix().Codec == cid.FilCommitmentUnsealed {
+		return s.getObject(ctx, c)
+	}
+
+	node, err := s.dag.Get(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content %s from S3: %w", c, err)
+	}
+	fnode, err := unixfile.NewUnixfsFile(ctx, s.dag, node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", c, err)
+	}
+	file, ok := fnode.(files.File)
+	if !ok {
+		return nil, fmt.Errorf("node is not a file")
+	}
+	return file, nil
+}
+
+func (s *S3Storage) getObject(ctx context.Context, c cid.Cid) (*minio.Object, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, s.key(c), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content from S3: %w", err)
+	}
+	// GetObject is lazy, make sure the object exists
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, fmt.Errorf("failed to get content from S3: %w", err)
+	}
+	return obj, nil
+}
+
+// PutBlock stores a raw block under its CID
+func (s *S3Storage) PutBlock(ctx context.Context, block blocks.Block) error {
+	data := block.RawData()
+	if err := s.put(ctx, block.Cid(), bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("failed to put block to S3: %w", err)
+	}
+	return nil
+}
+
+// GetBlock retrieves a raw block by CID. Objects that do not hash to the CID,
+// such as pieces, are rejected with errNotBlock rather than returned as the
+// block.
+func (s *S3Storage) GetBlock(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	if c.Prefix().Codec == cid.FilCommitmentUnsealed {
+		return nil, fmt.Errorf("%s: %w", c, errNotBlock)
+	}
+
+	obj, err := s.getObject(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close() // nolint:errcheck
+
+	info, err := obj.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block from S3: %w", err)
+	}
+	if info.Size > s3MaxBlockSize {
+		return nil, fmt.Errorf("%s: %w", c, errNotBlock)
+	}
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block: %w", err)
+	}
+
+	sum, err := c.Prefix().Sum(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash block: %w", err)
+	}
+	if !sum.Equals(c) {
+		return nil, fmt.Errorf("%s: %w", c, errNotBlock)
+	}
+	return blocks.NewBlockWithCid(data, c)
+}
+
+func (s *S3Storage) put(ctx context.Context, c cid.Cid, r io.Reader, size int64) error {
+	// Objects are immutable, skip content that is already stored
+	if _, err := s.client.StatObject(ctx, s.bucket, s.key(c), minio.StatObjectOptions{}); err == nil {
+		return nil
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, s.key(c), r, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return err
+}
+
+// isNoSuchKey reports whether err is S3 reporting a missing object
+func isNoSuchKey(err error) bool {
+	var resp minio.ErrorResponse
+	return errors.As(err, &resp) && resp.Code == "NoSuchKey"
+}
+
+func (s *S3Storage) key(c cid.Cid) string {
+	return s.prefix + c.String()
+}
+
+// s3Blockstore is a blockstore.Blockstore on the objects of a S3Storage
+type s3Blockstore struct {
+	s *S3Storage
+}
+
+var _ blockstore.Blockstore = (*s3Blockstore)(nil)
+
+func (b *s3Blockstore) DeleteBlock(ctx context.Context, c cid.Cid) error {
+	return b.s.client.RemoveObject(ctx, b.s.bucket, b.s.key(c), minio.RemoveObjectOptions{})
+}
+
+func (b *s3Blockstore) Has(ctx context.Context, c cid.Cid) (bool, error) {
+	_, err := b.s.client.StatObject(ctx, b.s.bucket, b.s.key(c), minio.StatObjectOptions{})
+	if isNoSuchKey(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b *s3Blockstore) Get(ctx context.Context, c cid.Cid) (blocks.Block, error) {
+	block, err := b.s.GetBlock(ctx, c)
+	if isNoSuchKey(err) {
+		return nil, ipld.ErrNotFound{Cid: c}
+	}
+	return block, err
+}
+
+func (b *s3Blockstore) GetSize(ctx context.Context, c cid.Cid) (int, error) {
+	info, err := b.s.client.StatObject(ctx, b.s.bucket, b.s.key(c), minio.StatObjectOptions{})
+	if isNoSuchKey(err) {
+		return -1, ipld.ErrNotFound{Cid: c}
+	}
+	if err != nil {
+		return -1, err
+	}
+	return int(info.Size), nil
+}
+
+func (b *s3Blockstore) Put(ctx context.Context, block blocks.Block) error {
+	return b.s.PutBlock(ctx, block)
+}
+
+func (b *s3Blockstore) PutMany(ctx context.Context, blks []blocks.Block) error {
+	for _, block := range blks {
+		if err := b.Put(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllKeysChan is not supported, the bucket holds pieces next to blocks
+func (b *s3Blockstore) AllKeysChan(ctx context.Context) (<-chan cid.Cid, error) {
+	return nil, fmt.Errorf("listing blocks is not supported by the s3 backend")
+}
+
+func (b *s3Blockstore) HashOnRead(enabled bool) {}
@@ -0,0 +1,261 @@
+package ipfs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/boxo/ipld/merkledag"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	carv2 "github.com/ipld/go-car/v2"
+	carstorage "github.com/ipld/go-car/v2/storage"
+)
+
+// fakeS3 is a minimal path-style S3 server keeping objects in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Bucket requests (exists, create) always succeed
+	if strings.Count(strings.Trim(r.URL.Path, "/"), "/") == 0 {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var body io.Reader = r.Body
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body = decodeAWSChunked(r.Body)
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAWSChunked strips the chunk headers of a streaming signed upload
+func decodeAWSChunked(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	var out bytes.Buffer
+	for {
+		header, err := br.ReadString('\n')
+		if err != nil {
+			break
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if err != nil || size == 0 {
+			break
+		}
+		if _, err := io.CopyN(&out, br, size); err != nil {
+			break
+		}
+		_, _ = br.ReadString('\n')
+	}
+	return &out
+}
+
+func newTestS3Storage(t *testing.T, server *httptest.Server, mode string) *S3Storage {
+	t.Helper()
+	storage, err := NewS3Storage(context.Background(), S3Options{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Region:          "us-east-1",
+		Bucket:          "threadmirror",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio123",
+		Prefix:          mode + "/",
+		CIDMode:         mode,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return storage
+}
+
+func TestS3Storage(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	for _, mode := range []string{CIDModeKubo, CIDModePDP} {
+		t.Run(mode, func(t *testing.T) {
+			storage := newTestS3Storage(t, server, mode)
+
+			data := []byte(strings.Repeat("hello world\n", 100))
+			expected, err := computeCID(bytes.NewReader(data), mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := storage.Add(ctx, bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !c.Equals(expected) {
+				t.Fatalf("expected CID %s, got %s", expected, c)
+			}
+
+			rc, err := storage.Get(ctx, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(rc)
+			_ = rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Fatal("content mismatch")
+			}
+
+			block := blocks.NewBlock([]byte(fmt.Sprintf("block %s", mode)))
+			if err := storage.PutBlock(ctx, block); err != nil {
+				t.Fatal(err)
+			}
+			gotBlock, err := storage.GetBlock(ctx, block.Cid())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(gotBlock.RawData(), block.RawData()) {
+				t.Fatal("block mismatch")
+			}
+
+			if _, err := storage.GetBlock(ctx, blocks.NewBlock([]byte("missing")).Cid()); err == nil {
+				t.Fatal("expected error for missing object")
+			}
+		})
+	}
+}
+
+// TestS3StorageCAR exports content spanning several chunks as a CAR from the
+// blocks of a S3Storage and checks every block and the content it rebuilds
+func TestS3StorageCAR(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+	storage := newTestS3Storage(t, server, CIDModeKubo)
+
+	data := make([]byte, 1<<20+123)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	root, err := storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var car bytes.Buffer
+	w, err := carstorage.NewWritable(&car, []cid.Cid{root}, carv2.WriteAsCarV1(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := []cid.Cid{root}
+	for len(queue) > 0 {
+		block, err := storage.GetBlock(ctx, queue[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		queue = queue[1:]
+		if err := w.Put(ctx, block.Cid().KeyString(), block.RawData()); err != nil {
+			t.Fatal(err)
+		}
+		node, err := merkledag.DecodeProtobufBlock(block)
+		if err != nil {
+			continue // raw leaf
+		}
+		for _, l := range node.Links() {
+			queue = append(queue, l.Cid)
+		}
+	}
+
+	// Rebuild the content from the CAR alone
+	r, err := carv2.NewBlockReader(&car)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := NewFSStorage(t.TempDir(), CIDModeKubo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		block, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := rebuilt.PutBlock(ctx, block); err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 6 {
+		t.Fatalf("expected the root and 5 chunks, got %d blocks", n)
+	}
+	rc, err := rebuilt.Get(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("content mismatch")
+	}
+}
+
+// TestS3StorageNotBlock checks an object that does not hash to its key is
+// never passed off as the block of that CID
+func TestS3StorageNotBlock(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+	storage := newTestS3Storage(t, server, CIDModeKubo)
+
+	block := blocks.NewBlock([]byte("a block"))
+	data := []byte("not the block")
+	if err := storage.put(ctx, block.Cid(), bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.GetBlock(ctx, block.Cid()); !errors.Is(err, errNotBlock) {
+		t.Fatalf("expected errNotBlock, got %v", err)
+	}
+}
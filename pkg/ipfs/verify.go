@@ -0,0 +1,144 @@
+package ipfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+)
+
+var (
+	// ErrContentUnreachable means content could not be read from storage
+	ErrContentUnreachable = errors.New("content unreachable")
+	// ErrCIDMismatch means content read from storage does not hash to its CID
+	ErrCIDMismatch = errors.New("content does not match its CID")
+	// ErrUnverifiableCID means the CID uses an addressing scheme that cannot be
+	// recomputed from the content alone
+	ErrUnverifiableCID = errors.New("CID cannot be recomputed from content")
+)
+
+// VerifyError reports content that failed verification
+type VerifyError struct {
+	CID cid.Cid
+	// Err wraps ErrContentUnreachable or ErrCIDMismatch
+	Err error
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("verify %s: %v", e.CID, e.Err)
+}
+
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// CanRecompute reports whether RecomputeCID supports the addressing scheme of c:
+// piece CIDs (CommP), CIDv0 UnixFS files as added by Kubo, and raw blocks
+func CanRecompute(c cid.Cid) bool {
+	prefix := c.Prefix()
+	switch prefix.Codec {
+	case cid.FilCommitmentUnsealed, cid.Raw:
+		return true
+	case cid.DagProtobuf:
+		return prefix.Version == 0
+	default:
+		return false
+	}
+}
+
+// RecomputeCID reads content and returns the CID it gets under the addressing
+// scheme of expected, so that comparing the two detects corruption
+func RecomputeCID(content io.Reader, expected cid.Cid) (cid.Cid, error) {
+	prefix := expected.Prefix()
+	switch {
+	case prefix.Codec == cid.FilCommitmentUnsealed:
+		pieceCID, _, _, _, err := computePiece(content)
+		if err != nil {
+			return cid.Undef, err
+		}
+		return pieceCID, nil
+	case prefix.Codec == cid.DagProtobuf && prefix.Version == 0:
+		node, err := importUnixFS(discardDAG{}, content)
+		if err != nil {
+			return cid.Undef, err
+		}
+		return node.Cid(), nil
+	case prefix.Codec == cid.Raw:
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("failed to read content: %w", err)
+		}
+		return prefix.Sum(data)
+	default:
+		return cid.Undef, fmt.Errorf("%w: %s", ErrUnverifiableCID, expected)
+	}
+}
+
+// VerifyContent reads content c from storage and checks that it still hashes
+// to c. Failures are returned as a *VerifyError.
+func VerifyContent(ctx context.Context, storage Storage, c cid.Cid) error {
+	if !CanRecompute(c) {
+		return fmt.Errorf("%w: %s", ErrUnverifiableCID, c)
+	}
+
+	rc, err := storage.Get(ctx, c)
+	if err != nil {
+		return &VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ErrContentUnreachable, err)}
+	}
+	defer rc.Close() // nolint:errcheck
+
+	// Errors reading the content mean it is unreachable, while content that
+	// cannot be hashed at all (e.g. too short for a piece) is corrupt
+	r := &readErrReader{r: rc}
+	got, err := RecomputeCID(r, c)
+	if err != nil {
+		if r.err != nil {
+			return &VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ErrContentUnreachable, err)}
+		}
+		return &VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ErrCIDMismatch, err)}
+	}
+	if !got.Equals(c) {
+		return &VerifyError{CID: c, Err: fmt.Errorf("%w: content hashes to %s", ErrCIDMismatch, got)}
+	}
+	return nil
+}
+
+// VerifyBlock checks that the data of block hashes to its CID. Failures are
+// returned as a *VerifyError.
+func VerifyBlock(block blocks.Block) error {
+	got, err := block.Cid().Prefix().Sum(block.RawData())
+	if err != nil {
+		return fmt.Errorf("failed to hash block %s: %w", block.Cid(), err)
+	}
+	if !got.Equals(block.Cid()) {
+		return &VerifyError{CID: block.Cid(), Err: fmt.Errorf("%w: block hashes to %s", ErrCIDMismatch, got)}
+	}
+	return nil
+}
+
+// VerifyStoredBlock reads block c from bs and checks that it still hashes to c.
+// Failures are returned as a *VerifyError.
+func VerifyStoredBlock(ctx context.Context, bs BlockStorage, c cid.Cid) error {
+	block, err := bs.GetBlock(ctx, c)
+	if err != nil {
+		return &VerifyError{CID: c, Err: fmt.Errorf("%w: %w", ErrContentUnreachable, err)}
+	}
+	return VerifyBlock(block)
+}
+
+// readErrReader remembers the first error other than io.EOF returned by r
+type readErrReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *readErrReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil && err != io.EOF && e.err == nil {
+		e.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,119 @@
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+)
+
+func TestVerifyContent(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("threadmirror "), 100)
+
+	for _, mode := range []string{CIDModeKubo, CIDModePDP} {
+		storage, err := NewFSStorage(t.TempDir(), mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := storage.Add(ctx, bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyContent(ctx, storage, c); err != nil {
+			t.Fatalf("%s: expected content to verify, got %v", mode, err)
+		}
+	}
+
+	// A corrupted piece no longer matches its piece CID
+	storage, err := NewFSStorage(t.TempDir(), CIDModePDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := storage.Add(ctx, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(storage.pieces.path(c), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = VerifyContent(ctx, storage, c)
+	var verifyErr *VerifyError
+	if !errors.As(err, &verifyErr) || !verifyErr.CID.Equals(c) || !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("expected CID mismatch for %s, got %v", c, err)
+	}
+
+	// Missing content is unreachable
+	if err := os.Remove(storage.pieces.path(c)); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyContent(ctx, storage, c); !errors.Is(err, ErrContentUnreachable) {
+		t.Fatalf("expected unreachable content, got %v", err)
+	}
+
+	// Blocks are verified by hashing their data
+	block := blocks.NewBlock([]byte("block"))
+	if err := VerifyBlock(block); err != nil {
+		t.Fatal(err)
+	}
+	bad, err := blocks.NewBlockWithCid([]byte("other"), block.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyBlock(bad); !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("expected block mismatch, got %v", err)
+	}
+}
+
+func TestReplicatedCheckReplicas(t *testing.T) {
+	ctx := context.Background()
+	first := newKuboMemStorage()
+	second := newKuboMemStorage()
+	index := NewMemoryReplicaIndex()
+
+	storage, err := NewReplicated([]Replica{{"first", first}, {"second", second}}, 2, CIDModeKubo, index, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := storage.Add(ctx, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	marked, err := storage.CheckReplicas(ctx, c, false)
+	if err != nil || len(marked) != 0 {
+		t.Fatalf("expected intact replicas, got %v, %v", marked, err)
+	}
+
+	// A corrupt copy is marked missing and repaired from the intact one
+	second.data[c] = []byte("corrupt")
+	marked, err = storage.CheckReplicas(ctx, c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marked) != 1 || marked[0] != "second" {
+		t.Fatalf("expected the second replica to be marked, got %v", marked)
+	}
+	result, err := storage.Repair(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Repaired != 1 {
+		t.Fatalf("expected one repair, got %+v", result)
+	}
+	if got := string(second.data[c]); got != "hello" {
+		t.Fatalf("expected repaired content, got %q", got)
+	}
+
+	// Nothing is marked without an intact copy to repair from
+	first.data[c] = []byte("corrupt")
+	second.data[c] = []byte("corrupt")
+	if _, err := storage.CheckReplicas(ctx, c, false); err == nil {
+		t.Fatal("expected an error without intact replicas")
+	}
+}
@@ -2,6 +2,7 @@ package asynqjobq
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -28,7 +29,14 @@ func NewAsynqClient(redisClient redis.UniversalClient, defaultOptions ...asynq.O
 // Enqueue enqueues a job to Asynq.
 func (c *AsynqClient) Enqueue(ctx context.Context, job *jobq.Job) (string, error) {
 	asynqTask := asynq.NewTask(job.Type, job.Payload)
-	taskInfo, err := c.EnqueueContext(ctx, asynqTask, c.defaultOptions...)
+	opts := append([]asynq.Option{}, c.defaultOptions...)
+	if job.MaxRetry > 0 {
+		opts = append(opts, asynq.MaxRetry(job.MaxRetry))
+	}
+	if job.Delay > 0 {
+		opts = append(opts, asynq.ProcessIn(job.Delay))
+	}
+	taskInfo, err := c.EnqueueContext(ctx, asynqTask, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -38,7 +46,9 @@ func (c *AsynqClient) Enqueue(ctx context.Context, job *jobq.Job) (string, error
 // AsynqServer implements job.JobQueueServer for Asynq.
 type AsynqServer struct {
 	*asynq.Server
-	mux    *asynq.ServeMux
+	mux *asynq.ServeMux
+	// client enqueues postponed jobs again
+	client *asynq.Client
 	logger *slog.Logger
 }
 
@@ -68,6 +78,7 @@ func NewAsynqServer(redisClient redis.UniversalClient, logger *slog.Logger) *Asy
 	return &AsynqServer{
 		Server: server,
 		mux:    mux,
+		client: asynq.NewClientFromRedisClient(redisClient),
 		logger: logger,
 	}
 }
@@ -77,14 +88,65 @@ func (s *AsynqServer) Start() error {
 	return s.Server.Start(s.mux)
 }
 
+// Shutdown stops the Asynq server.
+func (s *AsynqServer) Shutdown() {
+	s.Server.Shutdown()
+	if err := s.client.Close(); err != nil {
+		s.logger.Error("Failed to close asynq client", "error", err)
+	}
+}
+
 func (s *AsynqServer) RegisterHandler(jobType string, handler jobq.JobHandler) {
-	s.mux.HandleFunc(jobType, withLogging(s.logger, func(ctx context.Context, t *asynq.Task) error {
+	s.mux.HandleFunc(jobType, withSkipRetry(withRetryAfter(s.client, s.logger, withLogging(s.logger, func(ctx context.Context, t *asynq.Task) error {
 		jobJob := &jobq.Job{
 			Type:    t.Type(),
 			Payload: t.Payload(),
 		}
 		return handler.HandleJob(ctx, jobJob)
-	}))
+	}))))
+}
+
+// withSkipRetry is a middleware that archives jobs failing with a
+// jobq.PermanentError at once instead of retrying them.
+func withSkipRetry(handler asynq.HandlerFunc) asynq.HandlerFunc {
+	return func(ctx context.Context, task *asynq.Task) error {
+		err := handler(ctx, task)
+		if err != nil && jobq.IsPermanent(err) {
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+		return err
+	}
+}
+
+// withRetryAfter is a middleware that postpones jobs failing with a
+// jobq.RetryAfterError. The job is enqueued again to run after the delay and
+// the failed run is dropped, so waiting does not use up its retries.
+func withRetryAfter(client *asynq.Client, logger *slog.Logger, handler asynq.HandlerFunc) asynq.HandlerFunc {
+	return func(ctx context.Context, task *asynq.Task) error {
+		err := handler(ctx, task)
+		delay, ok := jobq.RetryAfter(err)
+		if !ok {
+			return err
+		}
+
+		opts := []asynq.Option{asynq.ProcessIn(delay)}
+		if maxRetry, ok := asynq.GetMaxRetry(ctx); ok {
+			opts = append(opts, asynq.MaxRetry(maxRetry))
+		}
+		if queue, ok := asynq.GetQueueName(ctx); ok {
+			opts = append(opts, asynq.Queue(queue))
+		}
+		if _, enqueueErr := client.EnqueueContext(ctx, asynq.NewTask(task.Type(), task.Payload()), opts...); enqueueErr != nil {
+			return fmt.Errorf("postpone job: %w (job error: %w)", enqueueErr, err)
+		}
+
+		logger.Info("Job postponed",
+			"job_type", task.Type(),
+			"delay", delay,
+			"error", err,
+		)
+		return nil
+	}
 }
 
 // withLogging is a middleware that wraps job handlers with logging.
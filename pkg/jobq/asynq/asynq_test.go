@@ -2,6 +2,7 @@ package asynqjobq
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"log/slog"
 
 	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 
@@ -65,3 +67,105 @@ func TestAsynqClientServerWithMiniredis(t *testing.T) {
 		t.Fatal("job handler was not called")
 	}
 }
+
+type retryAfterError struct{ delay time.Duration }
+
+func (e retryAfterError) Error() string             { return "rate limited" }
+func (e retryAfterError) RetryAfter() time.Duration { return e.delay }
+
+func TestAsynqServerPostponesRetryAfter(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	server := NewAsynqServer(redisClient, logger)
+	client := NewAsynqClient(redisClient)
+
+	jobType := "test_job"
+	jobPayload := []byte(`{"foo":"bar"}`)
+	handled := make(chan struct{}, 1)
+	server.RegisterHandler(jobType, JobHandlerFunc(func(ctx context.Context, job *jobq.Job) error {
+		handled <- struct{}{}
+		return fmt.Errorf("scrape: %w", retryAfterError{delay: time.Hour})
+	}))
+
+	go func() {
+		require.NoError(t, server.Start())
+	}()
+	defer server.Shutdown()
+
+	_, err := client.Enqueue(context.Background(), &jobq.Job{Type: jobType, Payload: jobPayload, MaxRetry: 3})
+	require.NoError(t, err)
+
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job handler was not called")
+	}
+
+	inspector := asynq.NewInspectorFromRedisClient(redisClient)
+	require.Eventually(t, func() bool {
+		scheduled, err := inspector.ListScheduledTasks("default")
+		return err == nil && len(scheduled) == 1
+	}, 2*time.Second, 50*time.Millisecond)
+
+	scheduled, err := inspector.ListScheduledTasks("default")
+	require.NoError(t, err)
+	task := scheduled[0]
+	require.Equal(t, jobType, task.Type)
+	require.Equal(t, jobPayload, task.Payload)
+	require.Equal(t, 3, task.MaxRetry)
+	require.Zero(t, task.Retried)
+	require.WithinDuration(t, time.Now().Add(time.Hour), task.NextProcessAt, time.Minute)
+
+	retries, err := inspector.ListRetryTasks("default")
+	require.NoError(t, err)
+	require.Empty(t, retries)
+}
+
+type permanentError struct{}
+
+func (permanentError) Error() string   { return "tweet deleted" }
+func (permanentError) Permanent() bool { return true }
+
+func TestAsynqServerSkipsRetryOfPermanentErrors(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	redisClient := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	server := NewAsynqServer(redisClient, logger)
+	client := NewAsynqClient(redisClient)
+
+	jobType := "test_job"
+	handled := make(chan struct{}, 1)
+	server.RegisterHandler(jobType, JobHandlerFunc(func(ctx context.Context, job *jobq.Job) error {
+		handled <- struct{}{}
+		return fmt.Errorf("scrape: %w", permanentError{})
+	}))
+
+	go func() {
+		require.NoError(t, server.Start())
+	}()
+	defer server.Shutdown()
+
+	_, err := client.Enqueue(context.Background(), &jobq.Job{Type: jobType, Payload: []byte(`{}`), MaxRetry: 3})
+	require.NoError(t, err)
+
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job handler was not called")
+	}
+
+	inspector := asynq.NewInspectorFromRedisClient(redisClient)
+	require.Eventually(t, func() bool {
+		archived, err := inspector.ListArchivedTasks("default")
+		return err == nil && len(archived) == 1
+	}, 2*time.Second, 50*time.Millisecond)
+
+	retries, err := inspector.ListRetryTasks("default")
+	require.NoError(t, err)
+	require.Empty(t, retries)
+}
@@ -2,12 +2,19 @@ package jobq
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
 // Job represents a generic job with a type and payload.
 type Job struct {
 	Type    string
 	Payload []byte
+
+	// MaxRetry overrides the queue's default retry limit when > 0.
+	MaxRetry int
+	// Delay postpones processing of the job when > 0.
+	Delay time.Duration
 }
 
 // NewJob creates a new generic job.
@@ -31,6 +38,37 @@ func (f JobHandlerFunc) HandleJob(ctx context.Context, job *Job) error {
 	return f(ctx, job)
 }
 
+// RetryAfterError is implemented by errors of jobs that cannot run yet, such as
+// when every account is rate limited. The queue postpones such jobs instead of
+// failing them.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// RetryAfter reports whether err asks for the job to run again later, and
+// after how long.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retry RetryAfterError
+	if !errors.As(err, &retry) {
+		return 0, false
+	}
+	return max(retry.RetryAfter(), 0), true
+}
+
+// PermanentError is implemented by errors of jobs that can never succeed,
+// such as scraping a deleted tweet. The queue does not retry such jobs.
+type PermanentError interface {
+	error
+	Permanent() bool
+}
+
+// IsPermanent reports whether err says running the job again cannot succeed.
+func IsPermanent(err error) bool {
+	var permanent PermanentError
+	return errors.As(err, &permanent) && permanent.Permanent()
+}
+
 // JobMiddleware defines a middleware for JobHandler.
 type JobMiddleware func(JobHandler) JobHandler
 
@@ -0,0 +1,139 @@
+// Package keyring encrypts private archives.
+//
+// Every archive is sealed with its own random data key (AES-256-GCM). The data
+// key is wrapped for the owning user with a key derived from the server master
+// key and the user ID (HKDF-SHA256), so a wrapped key only opens for the user it
+// was wrapped for.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of master and data keys
+const KeySize = 32
+
+// ErrDecrypt means sealed data or a wrapped key could not be opened, because it
+// is corrupt or belongs to another key or user
+var ErrDecrypt = errors.New("failed to decrypt")
+
+// Keyring wraps archive data keys with the server master key
+type Keyring struct {
+	master []byte
+}
+
+// New creates a Keyring from a 32 byte master key
+func New(master []byte) (*Keyring, error) {
+	if len(master) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(master))
+	}
+	return &Keyring{master: master}, nil
+}
+
+// ParseMasterKey decodes a hex or base64 encoded master key
+func ParseMasterKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil {
+		return key, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("master key must be hex or base64 encoded")
+	}
+	return key, nil
+}
+
+// NewDataKey returns a random data key for a new archive
+func NewDataKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return key, nil
+}
+
+// WrapKey encrypts dataKey for ownerID
+func (k *Keyring) WrapKey(ownerID string, dataKey []byte) ([]byte, error) {
+	kek, err := k.ownerKey(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return seal(kek, dataKey, []byte(ownerID))
+}
+
+// UnwrapKey decrypts a data key wrapped for ownerID
+func (k *Keyring) UnwrapKey(ownerID string, wrapped []byte) ([]byte, error) {
+	kek, err := k.ownerKey(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return open(kek, wrapped, []byte(ownerID))
+}
+
+// ownerKey derives the key encryption key of ownerID
+func (k *Keyring) ownerKey(ownerID string) ([]byte, error) {
+	if ownerID == "" {
+		return nil, fmt.Errorf("owner ID is required")
+	}
+	kek, err := hkdf.Key(sha256.New, k.master, nil, "threadmirror archive key "+ownerID, KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive owner key: %w", err)
+	}
+	return kek, nil
+}
+
+// Seal encrypts plaintext with dataKey. The result holds the nonce followed by
+// the ciphertext.
+func Seal(dataKey, plaintext []byte) ([]byte, error) {
+	return seal(dataKey, plaintext, nil)
+}
+
+// Open decrypts data sealed with dataKey
+func Open(dataKey, sealed []byte) ([]byte, error) {
+	return open(dataKey, sealed, nil)
+}
+
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("%w: data too short", ErrDecrypt)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
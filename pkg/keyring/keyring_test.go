@@ -0,0 +1,83 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWrapAndSeal(t *testing.T) {
+	kr, err := New(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey, err := NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped, err := kr.WrapKey("auth0|alice", dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unwrapped, err := kr.UnwrapKey("auth0|alice", wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(unwrapped, dataKey) {
+		t.Fatal("unwrapped key differs from the data key")
+	}
+
+	// A key wrapped for one user does not open for another
+	if _, err := kr.UnwrapKey("auth0|bob", wrapped); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected decrypt error for another owner, got %v", err)
+	}
+
+	// Nor with another master key
+	other, err := New(bytes.Repeat([]byte{8}, KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.UnwrapKey("auth0|alice", wrapped); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected decrypt error with another master key, got %v", err)
+	}
+
+	plaintext := []byte(`[{"rest_id":"1"}]`)
+	sealed, err := Seal(dataKey, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed data contains the plaintext")
+	}
+	opened, err := Open(dataKey, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("unexpected plaintext %q", opened)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := Open(dataKey, sealed); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("expected decrypt error for tampered data, got %v", err)
+	}
+}
+
+func TestParseMasterKey(t *testing.T) {
+	hexKey := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	fromHex, err := ParseMasterKey(hexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBase64, err := ParseMasterKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromHex, fromBase64) || len(fromHex) != KeySize {
+		t.Fatal("hex and base64 keys differ")
+	}
+	if _, err := New(fromHex[:16]); err == nil {
+		t.Fatal("expected short master key to be rejected")
+	}
+}
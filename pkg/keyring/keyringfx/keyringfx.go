@@ -0,0 +1,29 @@
+package keyringfx
+
+import (
+	"github.com/ipfs-force-community/threadmirror/pkg/keyring"
+	"go.uber.org/fx"
+)
+
+type Config struct {
+	// MasterKey is a hex or base64 encoded 32 byte key; private archives are
+	// disabled if empty
+	MasterKey string
+}
+
+// Module provides the fx module for private archive encryption
+var Module = fx.Module("keyring",
+	fx.Provide(NewKeyring),
+)
+
+// NewKeyring creates the archive keyring, or returns nil if no master key is configured
+func NewKeyring(config *Config) (*keyring.Keyring, error) {
+	if config.MasterKey == "" {
+		return nil, nil
+	}
+	master, err := keyring.ParseMasterKey(config.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+	return keyring.New(master)
+}
@@ -0,0 +1,195 @@
+// Package merkle implements the Merkle tree hashing, inclusion proofs and
+// consistency proofs of RFC 6962 (Certificate Transparency) over SHA-256.
+//
+// Trees are given as the leaf hashes of all their entries; callers keep the
+// log and hand the leaves of the tree size they want to work on.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// HashSize is the size of every hash in the tree
+const HashSize = sha256.Size
+
+// ErrInvalidProof means a proof does not verify against the given tree heads
+var ErrInvalidProof = errors.New("invalid merkle proof")
+
+// LeafHash returns the hash of a log entry, SHA-256(0x00 || data)
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// NodeHash returns the hash of an interior node, SHA-256(0x01 || left || right)
+func NodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// RootHash returns the Merkle tree hash of leaves
+func RootHash(leaves [][]byte) []byte {
+	n := len(leaves)
+	switch n {
+	case 0:
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	case 1:
+		return leaves[0]
+	}
+	k := split(n)
+	return NodeHash(RootHash(leaves[:k]), RootHash(leaves[k:]))
+}
+
+// InclusionProof returns the audit path of leaf index in the tree of leaves
+func InclusionProof(index int, leaves [][]byte) ([][]byte, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, fmt.Errorf("leaf index %d out of range for tree size %d", index, len(leaves))
+	}
+	return inclusionPath(index, leaves), nil
+}
+
+func inclusionPath(m int, leaves [][]byte) [][]byte {
+	n := len(leaves)
+	if n <= 1 {
+		return nil
+	}
+	k := split(n)
+	if m < k {
+		return append(inclusionPath(m, leaves[:k]), RootHash(leaves[k:]))
+	}
+	return append(inclusionPath(m-k, leaves[k:]), RootHash(leaves[:k]))
+}
+
+// ConsistencyProof returns the proof that the tree of the first size leaves
+// is a prefix of the tree of leaves
+func ConsistencyProof(size int, leaves [][]byte) ([][]byte, error) {
+	if size < 0 || size > len(leaves) {
+		return nil, fmt.Errorf("tree size %d out of range for tree size %d", size, len(leaves))
+	}
+	if size == 0 || size == len(leaves) {
+		return nil, nil
+	}
+	return subproof(size, leaves, true), nil
+}
+
+func subproof(m int, leaves [][]byte, complete bool) [][]byte {
+	n := len(leaves)
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{RootHash(leaves)}
+	}
+	k := split(n)
+	if m <= k {
+		return append(subproof(m, leaves[:k], complete), RootHash(leaves[k:]))
+	}
+	return append(subproof(m-k, leaves[k:], false), RootHash(leaves[:k]))
+}
+
+// VerifyInclusion checks that leafHash is leaf index of the tree of size with
+// the given root hash
+func VerifyInclusion(leafHash []byte, index, size int64, proof [][]byte, root []byte) error {
+	if index < 0 || index >= size {
+		return fmt.Errorf("%w: leaf index %d out of range for tree size %d", ErrInvalidProof, index, size)
+	}
+
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = NodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = NodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: proof too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("%w: root hash mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size1 with root1 is a prefix of
+// the tree of size2 with root2
+func VerifyConsistency(size1, size2 int64, root1, root2 []byte, proof [][]byte) error {
+	switch {
+	case size1 < 0 || size1 > size2:
+		return fmt.Errorf("%w: tree size %d is not within 0 and %d", ErrInvalidProof, size1, size2)
+	case size1 == size2:
+		if len(proof) != 0 || !bytes.Equal(root1, root2) {
+			return fmt.Errorf("%w: trees of equal size differ", ErrInvalidProof)
+		}
+		return nil
+	case size1 == 0:
+		if len(proof) != 0 {
+			return fmt.Errorf("%w: proof from the empty tree must be empty", ErrInvalidProof)
+		}
+		return nil
+	case len(proof) == 0:
+		return fmt.Errorf("%w: empty proof", ErrInvalidProof)
+	}
+
+	// A first tree that is a complete subtree is its own first proof node
+	if bits.OnesCount64(uint64(size1)) == 1 {
+		proof = append([][]byte{root1}, proof...)
+	}
+
+	fn, sn := size1-1, size2-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = NodeHash(c, fr)
+			sr = NodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = NodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: proof too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(fr, root1) || !bytes.Equal(sr, root2) {
+		return fmt.Errorf("%w: root hash mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// split returns the largest power of two smaller than n, for n > 1
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
@@ -0,0 +1,79 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testLeaves(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = LeafHash(fmt.Appendf(nil, "leaf %d", i))
+	}
+	return leaves
+}
+
+func TestRootHash(t *testing.T) {
+	// Empty tree hash from RFC 6962 test vectors
+	if got := hex.EncodeToString(RootHash(nil)); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("unexpected empty root %s", got)
+	}
+
+	leaves := testLeaves(3)
+	want := NodeHash(NodeHash(leaves[0], leaves[1]), leaves[2])
+	if !bytes.Equal(RootHash(leaves), want) {
+		t.Fatal("unexpected root of three leaves")
+	}
+}
+
+func TestInclusionProof(t *testing.T) {
+	leaves := testLeaves(20)
+	for size := 1; size <= len(leaves); size++ {
+		root := RootHash(leaves[:size])
+		for index := 0; index < size; index++ {
+			proof, err := InclusionProof(index, leaves[:size])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyInclusion(leaves[index], int64(index), int64(size), proof, root); err != nil {
+				t.Fatalf("leaf %d of %d: %v", index, size, err)
+			}
+			if size > 1 {
+				if err := VerifyInclusion(leaves[(index+1)%size], int64(index), int64(size), proof, root); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("leaf %d of %d: expected wrong leaf to fail, got %v", index, size, err)
+				}
+			}
+		}
+	}
+
+	if _, err := InclusionProof(3, leaves[:3]); err == nil {
+		t.Fatal("expected out of range leaf to fail")
+	}
+}
+
+func TestConsistencyProof(t *testing.T) {
+	leaves := testLeaves(20)
+	for size2 := 1; size2 <= len(leaves); size2++ {
+		root2 := RootHash(leaves[:size2])
+		for size1 := 0; size1 <= size2; size1++ {
+			root1 := RootHash(leaves[:size1])
+			proof, err := ConsistencyProof(size1, leaves[:size2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyConsistency(int64(size1), int64(size2), root1, root2, proof); err != nil {
+				t.Fatalf("%d to %d: %v", size1, size2, err)
+			}
+			if size1 > 0 && size1 < size2 {
+				// A rewritten history does not verify
+				forged := RootHash(append(testLeaves(size1-1), LeafHash([]byte("forged"))))
+				if err := VerifyConsistency(int64(size1), int64(size2), forged, root2, proof); !errors.Is(err, ErrInvalidProof) {
+					t.Fatalf("%d to %d: expected forged root to fail, got %v", size1, size2, err)
+				}
+			}
+		}
+	}
+}
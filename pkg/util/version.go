@@ -0,0 +1,45 @@
+package util
+
+import (
+	"runtime/debug"
+)
+
+// version can be set at build time with
+// -ldflags "-X github.com/ipfs-force-community/threadmirror/pkg/util.version=v1.2.3"
+var version string
+
+// Version returns the version of the running binary: the version set at build
+// time, the module version, or the VCS revision it was built from
+func Version() string {
+	if version != "" {
+		return version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision == "" {
+		return "devel"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified == "true" {
+		revision += "-dirty"
+	}
+	return "devel-" + revision
+}
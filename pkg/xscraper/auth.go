@@ -14,6 +14,11 @@ import (
 	"github.com/samber/lo"
 )
 
+// loginStepDelay is the pause between the steps of a login, 0.5s to 1.5s
+var loginStepDelay = func() time.Duration {
+	return time.Duration(500+rand.IntN(1000)) * time.Millisecond
+}
+
 type privLoginState int8
 
 const (
@@ -62,6 +67,31 @@ type LoginOptions struct {
 	APIKeySecret      string
 	AccessToken       string
 	AccessTokenSecret string
+
+	// Proxy is the URL of the proxy the account connects through, for both
+	// the web API and media uploads: http://, https://, socks5:// or
+	// socks5h://, with user:password@ for proxies that need authentication.
+	// Empty uses the proxy of the environment.
+	Proxy string
+	// RealHeader pins the browser fingerprint of the account; nil picks one of
+	// the built-in ones at random
+	RealHeader *RealHeader
+	// BaseURL and APIBaseURL replace BASE_URL and API_BASE_URL, to point the
+	// scraper at a fake X server in tests
+	BaseURL    string
+	APIBaseURL string
+	// Transport replaces the transport of the web API and media uploads, for
+	// example with a Cassette replaying recorded traffic in tests; Proxy does
+	// not apply to it
+	Transport http.RoundTripper
+
+	// TOTPSecret is the base32 two-factor authentication key of the account;
+	// codes generated from it answer the two-factor challenge
+	TOTPSecret string
+	// ChallengeCode supplies the codes of challenges the login cannot answer
+	// by itself: backup codes and emailed confirmation codes. Without it such
+	// challenges stop the login.
+	ChallengeCode ChallengeCodeFunc
 }
 
 func (x *XScraper) loadCookies(ctx context.Context) (bool, error) {
@@ -73,7 +103,7 @@ func (x *XScraper) loadCookies(ctx context.Context) (bool, error) {
 		x.loginMu.Lock()
 		defer x.loginMu.Unlock()
 
-		x.xPrivateApiClient.Jar.SetCookies(BASE_URL, cookies)
+		x.xPrivateApiClient.Jar.SetCookies(x.baseURL, cookies)
 		if csrfToken, ok := x.GetCookie("ct0"); ok {
 			x.csrfToken = csrfToken
 		}
@@ -90,7 +120,7 @@ func (x *XScraper) checkLoggedIn() bool {
 		return false
 	}
 
-	cookies := x.xPrivateApiClient.Jar.Cookies(BASE_URL)
+	cookies := x.xPrivateApiClient.Jar.Cookies(x.baseURL)
 	hasAuthToken := false
 	hasCsrfToken := false
 	for _, cookie := range cookies {
@@ -120,6 +150,8 @@ func (x *XScraper) ensureLoggedIn(ctx context.Context) error {
 	if err := authHandler.tryLogin(ctx); err != nil {
 		return err
 	}
+	// Requests after the login need the CSRF token it was given
+	x.csrfToken = authHandler.csrfToken
 	x.isLoggedIn = true
 	return nil
 }
@@ -149,7 +181,19 @@ func (a *authHandler) prepareRequest(req *http.Request) {
 	req.Header.Set("X-Twitter-Active-User", "yes")
 	req.Header.Set("2", "n")
 	req.Header.Set("Priority", "u=1, i")
-	req.Header.Set("X-Client-Transaction-Id", xClientTransactionID(req.Method, req.URL.Path))
+	a.scraper.setTransactionID(req)
+}
+
+// LoginChallengeError is returned when X stops a login at a step that needs
+// a person: DenyLoginSubtask, or a LoginAcid or LoginTwoFactorAuthChallenge
+// that neither the account's email and TOTP secret nor the codes from
+// LoginOptions.ChallengeCode satisfied
+type LoginChallengeError struct {
+	Subtask string
+}
+
+func (e *LoginChallengeError) Error() string {
+	return fmt.Sprintf("authentication error: %s", e.Subtask)
 }
 
 func (a *authHandler) tryLogin(ctx context.Context) error { //nolint:unparam
@@ -158,6 +202,10 @@ func (a *authHandler) tryLogin(ctx context.Context) error { //nolint:unparam
 		nextFlowToken string
 		nextSubtaskID string
 		err           error
+		// Times each challenge was asked; asked again means the answer was
+		// rejected
+		acidAttempts      int
+		twoFactorAttempts int
 	)
 
 	for {
@@ -199,13 +247,30 @@ func (a *authHandler) tryLogin(ctx context.Context) error { //nolint:unparam
 			}
 			nextState = subtaskIDToPrivLoginState(nextSubtaskID)
 		case privLoginStateLoginTwoFactorAuthChallenge:
-			nextFlowToken, nextSubtaskID, err = a.handleLoginTwoFactorAuthChallenge(ctx, nextFlowToken)
+			code, err := a.twoFactorCode(ctx, twoFactorAttempts)
+			if err != nil {
+				return err
+			}
+			twoFactorAttempts++
+			nextFlowToken, nextSubtaskID, err = a.handleLoginTwoFactorAuthChallenge(ctx, nextFlowToken, code)
 			if err != nil {
 				return err
 			}
 			nextState = subtaskIDToPrivLoginState(nextSubtaskID)
 		case privLoginStateLoginAcid:
-			nextFlowToken, nextSubtaskID, err = a.handleLoginAcid(ctx, nextFlowToken, &a.scraper.LoginOpts.Email)
+			text := a.scraper.LoginOpts.Email
+			if acidAttempts > 0 {
+				// Asked again after entering the email: X wants the code it emailed
+				if acidAttempts > 1 {
+					return &LoginChallengeError{Subtask: "LoginAcid"}
+				}
+				text, err = a.challengeCode(ctx, ChallengeEmailCode, "LoginAcid")
+				if err != nil {
+					return err
+				}
+			}
+			acidAttempts++
+			nextFlowToken, nextSubtaskID, err = a.handleLoginAcid(ctx, nextFlowToken, text)
 			if err != nil {
 				return err
 			}
@@ -217,21 +282,20 @@ func (a *authHandler) tryLogin(ctx context.Context) error { //nolint:unparam
 			}
 			nextState = privLoginStateLoggedIn
 		case privLoginStateDenyLoginSubtask:
-			return fmt.Errorf("authentication error: DenyLoginSubtask")
+			return &LoginChallengeError{Subtask: "DenyLoginSubtask"}
 		case privLoginStateLoggedIn:
-			err = a.scraper.LoginOpts.SaveCookies(ctx, a.scraper.xPrivateApiClient.Jar.Cookies(BASE_URL))
+			err = a.scraper.LoginOpts.SaveCookies(ctx, a.scraper.xPrivateApiClient.Jar.Cookies(a.scraper.baseURL))
 			return err
 
 		case privLoginStateUnknown:
 			return fmt.Errorf("unknown state")
 		}
-		// sleep 0.5s to 1.5s
-		time.Sleep(time.Duration(500+rand.IntN(1000)) * time.Millisecond)
+		time.Sleep(loginStepDelay())
 	}
 }
 
 func (a *authHandler) requestHashflags(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.x.com/1.1/hashflags.json", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.scraper.apiBaseURL.JoinPath("1.1/hashflags.json").String(), nil)
 	if err != nil {
 		return fmt.Errorf("create hashflags request: %w", err)
 	}
@@ -246,7 +310,7 @@ func (a *authHandler) requestHashflags(ctx context.Context) error {
 }
 
 func (a *authHandler) requestGuestToken(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.x.com/1.1/guest/activate.json", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.scraper.apiBaseURL.JoinPath("1.1/guest/activate.json").String(), nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -265,7 +329,7 @@ func (a *authHandler) requestGuestToken(ctx context.Context) (string, error) {
 
 func (a *authHandler) simulateXWebRequest(ctx context.Context) (err error) {
 	// access to https://x.com/i/flow/login to get cookies
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://x.com/i/flow/login", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.scraper.baseURL.JoinPath("i/flow/login").String(), nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -286,7 +350,7 @@ func (a *authHandler) simulateXWebRequest(ctx context.Context) (err error) {
 			{
 				Name:   "gt",
 				Value:  a.guestToken,
-				Domain: "." + BASE_URL.Host,
+				Domain: "." + a.scraper.baseURL.Hostname(),
 			},
 		})
 	}
@@ -427,22 +491,56 @@ func (a *authHandler) handleAccountDuplicationCheck(ctx context.Context, flowTok
 	})
 }
 
-func (a *authHandler) handleLoginTwoFactorAuthChallenge(ctx context.Context, flowToken string) (nextFlowToken, subtaskID string, err error) {
+// twoFactorCode returns the code answering the nth two-factor challenge of a
+// login: a TOTP code first if the account has a secret, then a code from the
+// operator
+func (a *authHandler) twoFactorCode(ctx context.Context, n int) (string, error) {
+	if secret := a.scraper.LoginOpts.TOTPSecret; secret != "" {
+		if n == 0 {
+			return GenerateTOTP(secret, time.Now())
+		}
+		n--
+	}
+	if n > 0 {
+		return "", &LoginChallengeError{Subtask: "LoginTwoFactorAuthChallenge"}
+	}
+	return a.challengeCode(ctx, ChallengeBackupCode, "LoginTwoFactorAuthChallenge")
+}
+
+// challengeCode asks the operator for the code of a challenge, failing with a
+// LoginChallengeError for subtask if there is no one to ask or no code
+func (a *authHandler) challengeCode(ctx context.Context, kind ChallengeKind, subtask string) (string, error) {
+	opts := a.scraper.LoginOpts
+	if opts.ChallengeCode == nil {
+		return "", &LoginChallengeError{Subtask: subtask}
+	}
+	code, err := opts.ChallengeCode(ctx, opts.Username, kind)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", &LoginChallengeError{Subtask: subtask}, err)
+	}
+	return code, nil
+}
+
+func (a *authHandler) handleLoginTwoFactorAuthChallenge(ctx context.Context, flowToken, code string) (nextFlowToken, subtaskID string, err error) {
 	return a.executeFlowTask(ctx, flowTaskRequest{
 		FlowToken: flowToken,
 		SubtaskInputs: []map[string]any{{
 			"subtask_id": "LoginTwoFactorAuthChallenge",
+			"enter_text": map[string]any{
+				"text": code,
+				"link": "next_link",
+			},
 		}},
 	})
 }
 
-func (a *authHandler) handleLoginAcid(ctx context.Context, flowToken string, email *string) (nextFlowToken, subtaskID string, err error) {
+func (a *authHandler) handleLoginAcid(ctx context.Context, flowToken, text string) (nextFlowToken, subtaskID string, err error) {
 	return a.executeFlowTask(ctx, flowTaskRequest{
 		FlowToken: flowToken,
 		SubtaskInputs: []map[string]any{{
 			"subtask_id": "LoginAcid",
 			"enter_text": map[string]any{
-				"text": email,
+				"text": text,
 				"link": "next_link",
 			},
 		}},
@@ -457,11 +555,9 @@ func (a *authHandler) handleLoginSuccessSubtask(ctx context.Context, flowToken s
 }
 
 func (a *authHandler) executeFlowTask(ctx context.Context, flowTaskRequest flowTaskRequest) (flowToken string, subtaskID string, err error) {
-	var onboardingTaskUrl string
+	onboardingTaskUrl := a.scraper.apiBaseURL.JoinPath("1.1/onboarding/task.json").String()
 	if flowTaskRequest.FlowToken == "" {
-		onboardingTaskUrl = "https://api.x.com/1.1/onboarding/task.json?flow_name=login"
-	} else {
-		onboardingTaskUrl = "https://api.x.com/1.1/onboarding/task.json"
+		onboardingTaskUrl += "?flow_name=login"
 	}
 
 	body, err := json.Marshal(flowTaskRequest)
@@ -0,0 +1,286 @@
+package xscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/samber/lo"
+)
+
+// Poll is a poll attached to a tweet
+type Poll struct {
+	Choices []PollChoice `json:"choices"`
+	// EndsAt is when voting closes
+	EndsAt          time.Time `json:"ends_at"`
+	DurationMinutes int       `json:"duration_minutes,omitempty"`
+	// Final is set once the poll has ended and its counts no longer change
+	Final bool `json:"final"`
+}
+
+// PollChoice is a choice of a poll and its votes
+type PollChoice struct {
+	Label    string `json:"label"`
+	Count    int    `json:"count"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// Votes returns the number of votes of all choices
+func (p *Poll) Votes() int {
+	var votes int
+	for _, c := range p.Choices {
+		votes += c.Count
+	}
+	return votes
+}
+
+// LinkCard is the preview X shows for a link in a tweet
+type LinkCard struct {
+	// Name is X's card type, such as summary_large_image or unified_card
+	Name string `json:"name"`
+	// URL is where the card leads, expanded from its t.co link when the
+	// tweet's entities have it
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Domain      string `json:"domain,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// Space is an audio Space shared in a tweet. The tweet only carries its ID.
+type Space struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Community is the community a tweet was posted in
+type Community struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// MemberCount is a live counter, archived with the stats of the tweet
+	MemberCount int    `json:"member_count,omitempty"`
+	URL         string `json:"url,omitempty"`
+	// AuthorRole is the role of the tweet's author in the community, such as
+	// Member or Moderator
+	AuthorRole string `json:"author_role,omitempty"`
+}
+
+// pollCardName matches the cards of polls: poll2choice_text_only up to
+// poll4choice_image
+var pollCardName = regexp.MustCompile(`^poll(\d)choice_(text_only|image)$`)
+
+// cardBindings are the binding values of a card by key
+type cardBindings map[string]generated.TweetCardLegacyBindingValueData
+
+func (b cardBindings) string(key string) string {
+	if v, ok := b[key]; ok && v.StringValue != nil {
+		return *v.StringValue
+	}
+	return ""
+}
+
+func (b cardBindings) int(key string) int {
+	n, _ := strconv.Atoi(b.string(key))
+	return n
+}
+
+func (b cardBindings) bool(key string) bool {
+	v, ok := b[key]
+	return ok && v.BooleanValue != nil && *v.BooleanValue
+}
+
+// image returns the URL of the first of keys holding an image
+func (b cardBindings) image(keys ...string) string {
+	for _, key := range keys {
+		if v, ok := b[key]; ok && v.ImageValue != nil && v.ImageValue.Url != "" {
+			return v.ImageValue.Url
+		}
+	}
+	return ""
+}
+
+// applyCard sets the poll, link card or Space of the card of a tweet
+func applyCard(tweet *Tweet, card *generated.TweetCard) {
+	if card == nil || card.Legacy == nil {
+		return
+	}
+	legacy := card.Legacy
+	bindings := make(cardBindings, len(legacy.BindingValues))
+	for _, v := range legacy.BindingValues {
+		bindings[v.Key] = v.Value
+	}
+
+	switch name := legacy.Name; {
+	case pollCardName.MatchString(name):
+		tweet.Poll = convertPoll(name, bindings)
+	case strings.HasSuffix(name, ":audiospace"):
+		if id := bindings.string("id"); id != "" {
+			tweet.Space = &Space{ID: id, URL: "https://x.com/i/spaces/" + id}
+		}
+	case name == "unified_card":
+		tweet.Card = convertUnifiedCard(bindings.string("unified_card"))
+	default:
+		tweet.Card = &LinkCard{
+			Name:        name,
+			URL:         lo.CoalesceOrEmpty(bindings.string("card_url"), legacy.Url),
+			Title:       bindings.string("title"),
+			Description: bindings.string("description"),
+			Domain:      lo.CoalesceOrEmpty(bindings.string("vanity_url"), bindings.string("domain")),
+			ImageURL: bindings.image(
+				"photo_image_full_size_original",
+				"summary_photo_image_original",
+				"thumbnail_image_original",
+				"player_image_original",
+			),
+		}
+	}
+	if tweet.Card != nil {
+		tweet.Card.URL = expandURL(tweet.Card.URL, tweet.Entities)
+	}
+}
+
+// convertPoll reads the choices of a poll card
+func convertPoll(name string, bindings cardBindings) *Poll {
+	n, _ := strconv.Atoi(pollCardName.FindStringSubmatch(name)[1])
+	poll := &Poll{
+		DurationMinutes: bindings.int("duration_minutes"),
+		Final:           bindings.bool("counts_are_final"),
+	}
+	if endsAt, err := time.Parse(time.RFC3339, bindings.string("end_datetime_utc")); err == nil {
+		poll.EndsAt = endsAt
+	}
+	for i := 1; i <= n; i++ {
+		key := fmt.Sprintf("choice%d", i)
+		label := bindings.string(key + "_label")
+		if label == "" {
+			continue
+		}
+		poll.Choices = append(poll.Choices, PollChoice{
+			Label:    label,
+			Count:    bindings.int(key + "_count"),
+			ImageURL: bindings.image(key+"_image_original", key+"_image"),
+		})
+	}
+	return poll
+}
+
+// unifiedCard is the part of the JSON of a unified_card binding used for
+// link previews
+type unifiedCard struct {
+	ComponentObjects map[string]struct {
+		Type string `json:"type"`
+		Data struct {
+			Title struct {
+				Content string `json:"content"`
+			} `json:"title"`
+			Subtitle struct {
+				Content string `json:"content"`
+			} `json:"subtitle"`
+			ID          string `json:"id"`
+			Destination string `json:"destination"`
+		} `json:"data"`
+	} `json:"component_objects"`
+	DestinationObjects map[string]struct {
+		Data struct {
+			URLData struct {
+				URL    string `json:"url"`
+				Vanity string `json:"vanity"`
+			} `json:"url_data"`
+		} `json:"data"`
+	} `json:"destination_objects"`
+	MediaEntities map[string]struct {
+		MediaURLHTTPS string `json:"media_url_https"`
+	} `json:"media_entities"`
+}
+
+// convertUnifiedCard reads the link preview of a unified card: the title of
+// its first details component, and the image of its first media component
+func convertUnifiedCard(raw string) *LinkCard {
+	var uc unifiedCard
+	if raw == "" || json.Unmarshal([]byte(raw), &uc) != nil {
+		return nil
+	}
+
+	card := &LinkCard{Name: "unified_card"}
+	keys := make([]string, 0, len(uc.ComponentObjects))
+	for key := range uc.ComponentObjects {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		component := uc.ComponentObjects[key]
+		switch component.Type {
+		case "details":
+			if card.Title != "" {
+				continue
+			}
+			card.Title = component.Data.Title.Content
+			card.Domain = component.Data.Subtitle.Content
+		case "media", "swipeable_media":
+			if card.ImageURL != "" {
+				continue
+			}
+			card.ImageURL = uc.MediaEntities[component.Data.ID].MediaURLHTTPS
+		default:
+			continue
+		}
+		if card.URL == "" {
+			if destination, ok := uc.DestinationObjects[component.Data.Destination]; ok {
+				card.URL = destination.Data.URLData.URL
+				card.Domain = lo.CoalesceOrEmpty(card.Domain, destination.Data.URLData.Vanity)
+			}
+		}
+	}
+	if card.Title == "" && card.URL == "" && card.ImageURL == "" {
+		return nil
+	}
+	return card
+}
+
+// convertCommunity returns the community a tweet was posted in
+func convertCommunity(genTweet *generated.Tweet) *Community {
+	var data *generated.CommunityData
+	var role string
+	switch {
+	case genTweet.CommunityResults != nil:
+		data = &genTweet.CommunityResults.Result
+	case genTweet.AuthorCommunityRelationship != nil:
+		data = &genTweet.AuthorCommunityRelationship.CommunityResults.Result
+	}
+	if rel := genTweet.AuthorCommunityRelationship; rel != nil && rel.Role != nil {
+		role = string(*rel.Role)
+	}
+	if data == nil || data.IdStr == "" {
+		return nil
+	}
+
+	community := &Community{
+		ID:          data.IdStr,
+		Name:        lo.FromPtr(data.Name),
+		Description: lo.FromPtr(data.Description),
+		AuthorRole:  role,
+	}
+	if data.MemberCount != nil {
+		community.MemberCount = *data.MemberCount
+	}
+	if data.Urls != nil {
+		community.URL = data.Urls.Permalink.Url
+	}
+	return community
+}
+
+// expandURL returns the expanded URL of a t.co link of the tweet
+func expandURL(url string, entities generated.Entities) string {
+	for _, u := range entities.Urls {
+		if u.Url == url && u.ExpandedUrl != nil {
+			return *u.ExpandedUrl
+		}
+	}
+	return url
+}
@@ -0,0 +1,120 @@
+package xscraper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/stretchr/testify/require"
+)
+
+// tweetWithCard converts a tweet with the given card and extra JSON fields
+func tweetWithCard(t *testing.T, card string, extra string) *Tweet {
+	t.Helper()
+	raw := `{"__typename": "Tweet", "rest_id": "1",
+		"legacy": {"full_text": "look https://t.co/abc", "created_at": "Wed Jan 03 12:00:00 +0000 2024",
+			"entities": {"hashtags": [], "symbols": [], "user_mentions": [],
+				"urls": [{"url": "https://t.co/abc", "expanded_url": "https://example.com/post", "display_url": "example.com/post", "indices": [5, 21]}]}}`
+	if card != "" {
+		raw += `, "card": ` + card
+	}
+	if extra != "" {
+		raw += `, ` + extra
+	}
+	raw += `}`
+
+	var genTweet generated.Tweet
+	require.NoError(t, json.Unmarshal([]byte(raw), &genTweet))
+	tweet, err := convertGeneratedTweetToTweet(&genTweet)
+	require.NoError(t, err)
+	return tweet
+}
+
+func TestConvertPoll(t *testing.T) {
+	tweet := tweetWithCard(t, `{"rest_id": "card://1", "legacy": {"name": "poll3choice_text_only", "url": "card://1", "binding_values": [
+		{"key": "choice1_label", "value": {"type": "STRING", "string_value": "tabs"}},
+		{"key": "choice1_count", "value": {"type": "STRING", "string_value": "12"}},
+		{"key": "choice2_label", "value": {"type": "STRING", "string_value": "spaces"}},
+		{"key": "choice2_count", "value": {"type": "STRING", "string_value": "30"}},
+		{"key": "choice3_label", "value": {"type": "STRING", "string_value": "both"}},
+		{"key": "choice3_count", "value": {"type": "STRING", "string_value": "0"}},
+		{"key": "end_datetime_utc", "value": {"type": "STRING", "string_value": "2024-01-04T12:00:00Z"}},
+		{"key": "duration_minutes", "value": {"type": "STRING", "string_value": "1440"}},
+		{"key": "counts_are_final", "value": {"type": "BOOLEAN", "boolean_value": true}}
+	]}}`, "")
+
+	require.Nil(t, tweet.Card)
+	require.Equal(t, &Poll{
+		Choices:         []PollChoice{{Label: "tabs", Count: 12}, {Label: "spaces", Count: 30}, {Label: "both"}},
+		EndsAt:          time.Date(2024, 1, 4, 12, 0, 0, 0, time.UTC),
+		DurationMinutes: 1440,
+		Final:           true,
+	}, tweet.Poll)
+	require.Equal(t, 42, tweet.Poll.Votes())
+}
+
+func TestConvertLinkCard(t *testing.T) {
+	tweet := tweetWithCard(t, `{"legacy": {"name": "summary_large_image", "url": "https://t.co/abc", "binding_values": [
+		{"key": "title", "value": {"type": "STRING", "string_value": "A post"}},
+		{"key": "description", "value": {"type": "STRING", "string_value": "About archives"}},
+		{"key": "vanity_url", "value": {"type": "STRING", "string_value": "example.com"}},
+		{"key": "card_url", "value": {"type": "STRING", "string_value": "https://t.co/abc"}},
+		{"key": "summary_photo_image_original", "value": {"type": "IMAGE", "image_value": {"url": "https://pbs.twimg.com/card_img/1/a.jpg", "width": 800, "height": 400}}}
+	]}}`, "")
+
+	require.Equal(t, &LinkCard{
+		Name:        "summary_large_image",
+		URL:         "https://example.com/post",
+		Title:       "A post",
+		Description: "About archives",
+		Domain:      "example.com",
+		ImageURL:    "https://pbs.twimg.com/card_img/1/a.jpg",
+	}, tweet.Card)
+	require.Contains(t, tweet.MediaURLs(), "https://pbs.twimg.com/card_img/1/a.jpg")
+}
+
+func TestConvertUnifiedCard(t *testing.T) {
+	unified, err := json.Marshal(`{"type": "image_website",
+		"component_objects": {
+			"details_1": {"type": "details", "data": {"title": {"content": "A post"}, "subtitle": {"content": "example.com"}, "destination": "browser_1"}},
+			"media_1": {"type": "media", "data": {"id": "3_1", "destination": "browser_1"}}},
+		"destination_objects": {"browser_1": {"type": "browser", "data": {"url_data": {"url": "https://example.com/post", "vanity": "example.com"}}}},
+		"media_entities": {"3_1": {"media_url_https": "https://pbs.twimg.com/media/card.jpg"}}}`)
+	require.NoError(t, err)
+	tweet := tweetWithCard(t, `{"legacy": {"name": "unified_card", "url": "https://t.co/abc", "binding_values": [
+		{"key": "unified_card", "value": {"type": "STRING", "string_value": `+string(unified)+`}}
+	]}}`, "")
+
+	require.Equal(t, &LinkCard{
+		Name:     "unified_card",
+		URL:      "https://example.com/post",
+		Title:    "A post",
+		Domain:   "example.com",
+		ImageURL: "https://pbs.twimg.com/media/card.jpg",
+	}, tweet.Card)
+}
+
+func TestConvertSpaceAndCommunity(t *testing.T) {
+	tweet := tweetWithCard(t, `{"legacy": {"name": "3691233323:audiospace", "url": "https://t.co/abc", "binding_values": [
+		{"key": "id", "value": {"type": "STRING", "string_value": "1ZkJzbdvLgyJv"}}
+	]}}`, `"author_community_relationship": {"role": "Moderator", "community_results": {"result": {
+		"__typename": "Community", "id_str": "300", "name": "Archivists", "member_count": 42,
+		"urls": {"permalink": {"url": "https://x.com/i/communities/300"}}}}}`)
+
+	require.Nil(t, tweet.Card)
+	require.Equal(t, &Space{ID: "1ZkJzbdvLgyJv", URL: "https://x.com/i/spaces/1ZkJzbdvLgyJv"}, tweet.Space)
+	require.Equal(t, &Community{
+		ID:          "300",
+		Name:        "Archivists",
+		MemberCount: 42,
+		URL:         "https://x.com/i/communities/300",
+		AuthorRole:  "Moderator",
+	}, tweet.Community)
+
+	plain := tweetWithCard(t, "", "")
+	require.Nil(t, plain.Poll)
+	require.Nil(t, plain.Card)
+	require.Nil(t, plain.Space)
+	require.Nil(t, plain.Community)
+}
@@ -0,0 +1,315 @@
+package xscraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CassetteMode is whether a Cassette records or replays
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and never touches the network
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests on and records them for Save
+	CassetteRecord
+)
+
+// scrubbed replaces secrets in recordings
+const scrubbed = "SCRUBBED"
+
+// scrubbedJSONKeys are the JSON fields whose values are secrets: login
+// inputs and the tokens of the login flow
+var scrubbedJSONKeys = map[string]bool{
+	"password":           true,
+	"flow_token":         true,
+	"guest_token":        true,
+	"auth_token":         true,
+	"ct0":                true,
+	"csrf_token":         true,
+	"oauth_token":        true,
+	"oauth_token_secret": true,
+}
+
+// Interaction is a request recorded by a Cassette and its response
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. Its headers, which carry the
+// cookies, bearer token and CSRF token, are not recorded.
+type CassetteRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response. Set-Cookie values are scrubbed.
+type CassetteResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+// Cassette is an http.RoundTripper that records the traffic of an XScraper to
+// a file once and replays it deterministically, so parsers can be tested
+// offline against real payloads. Set it as LoginOptions.Transport.
+//
+// Recordings are scrubbed of cookies, tokens and the CSRF token, and of the
+// secrets given to NewCassette, such as the password of the account. Replay
+// serves each recorded interaction once, in recording order, preferring an
+// exact match of method and URL and falling back to the same method and path,
+// as the variables of a query vary with cursors and IDs.
+type Cassette struct {
+	path      string
+	mode      CassetteMode
+	transport http.RoundTripper
+	secrets   []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewCassette opens the cassette at path. Replaying loads its interactions;
+// recording sends requests through transport, http.DefaultTransport if nil,
+// and replaces secrets in what it records.
+func NewCassette(path string, mode CassetteMode, transport http.RoundTripper, secrets ...string) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := &Cassette{path: path, mode: mode, transport: transport, secrets: secrets}
+	if mode == CassetteReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &c.interactions); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.interactions))
+		seedTransactionIDPairs()
+	}
+	return c, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.mode == CassetteReplay {
+		return c.replay(req)
+	}
+	return c.record(req)
+}
+
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	match := -1
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Request.Method != req.Method {
+			continue
+		}
+		if interaction.Request.URL == c.scrub(req.URL.String()) {
+			match = i
+			break
+		}
+		if match < 0 && samePath(interaction.Request.URL, req.URL.Scheme+"://"+req.URL.Host+req.URL.Path) {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("cassette %s: no recorded response for %s %s", c.path, req.Method, req.URL.Redacted())
+	}
+	c.used[match] = true
+
+	recorded := c.interactions[match].Response
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(decodeCassetteBody(recorded.Body))),
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
+
+// samePath reports whether a recorded URL has the given scheme, host and path
+func samePath(recorded, path string) bool {
+	recordedPath, _, _ := strings.Cut(recorded, "?")
+	return recordedPath == path
+}
+
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	for key, values := range header {
+		for i, v := range values {
+			values[i] = c.scrub(v)
+		}
+		if http.CanonicalHeaderKey(key) == "Set-Cookie" {
+			for i, v := range values {
+				values[i] = scrubCookie(v)
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: CassetteRequest{
+			Method: req.Method,
+			URL:    c.scrub(req.URL.String()),
+			Body:   c.encodeBody(reqBody),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       c.encodeBody(respBody),
+		},
+	})
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.interactions); err != nil {
+		return fmt.Errorf("marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create cassette directory: %w", err)
+	}
+	return os.WriteFile(c.path, buf.Bytes(), 0o644)
+}
+
+// Unused returns the recorded interactions that have not been replayed, to
+// check that a test made every request it recorded
+func (c *Cassette) Unused() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range c.interactions {
+		if !c.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// scrub replaces the secrets of the cassette in s
+func (c *Cassette) scrub(s string) string {
+	for _, secret := range c.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, scrubbed)
+		}
+	}
+	return s
+}
+
+// encodeBody returns a scrubbed body to record: JSON as is, so fixtures stay
+// readable, anything else as a JSON string
+func (c *Cassette) encodeBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		return json.RawMessage(c.scrub(string(marshalUnescaped(scrubJSON(v)))))
+	}
+	return marshalUnescaped(c.scrub(string(body)))
+}
+
+// marshalUnescaped marshals v without escaping HTML, which JSON values and
+// strings decoded from JSON always marshal without error
+func marshalUnescaped(v any) json.RawMessage {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// decodeCassetteBody returns the body recorded by encodeBody
+func decodeCassetteBody(raw json.RawMessage) []byte {
+	var s string
+	if len(raw) > 0 && raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+		return []byte(s)
+	}
+	return raw
+}
+
+// scrubJSON replaces the values of scrubbedJSONKeys in a decoded JSON value
+func scrubJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if _, ok := value.(string); ok && scrubbedJSONKeys[key] {
+				v[key] = scrubbed
+				continue
+			}
+			v[key] = scrubJSON(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = scrubJSON(value)
+		}
+	}
+	return v
+}
+
+// scrubCookie replaces the value of a Set-Cookie header, keeping the name and
+// attributes the cookie jar needs
+func scrubCookie(setCookie string) string {
+	nameValue, attrs, _ := strings.Cut(setCookie, ";")
+	name, _, ok := strings.Cut(nameValue, "=")
+	if !ok {
+		return setCookie
+	}
+	if attrs == "" {
+		return name + "=" + scrubbed
+	}
+	return name + "=" + scrubbed + ";" + attrs
+}
@@ -0,0 +1,242 @@
+package xscraper
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/xrate"
+	"github.com/stretchr/testify/require"
+)
+
+// cassetteTweetID is the tweet the TweetDetail cassette was recorded for
+const cassetteTweetID = "1920620861285093468"
+
+// cassetteCookies returns the cookies of a logged in account
+func cassetteCookies(context.Context) ([]*http.Cookie, error) {
+	return []*http.Cookie{
+		{Name: "auth_token", Value: "auth", Domain: "." + BASE_URL.Host},
+		{Name: "ct0", Value: "csrf", Domain: "." + BASE_URL.Host},
+	}, nil
+}
+
+// replayScraper returns a scraper replaying testdata/cassettes/name.json, and
+// checks on cleanup that every recorded request was made
+func replayScraper(t *testing.T, name string, opts LoginOptions) *XScraper {
+	t.Helper()
+	cassette, err := NewCassette(filepath.Join("testdata", "cassettes", name+".json"), CassetteReplay, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.Empty(t, cassette.Unused(), "requests recorded but not replayed") })
+
+	opts.Transport = cassette
+	if opts.LoadCookies == nil {
+		opts.LoadCookies = cassetteCookies
+	}
+	x, err := New(opts, slog.Default())
+	require.NoError(t, err)
+	x.rateLimiter = xrate.NewLimiter(xrate.Inf, 1)
+	return x
+}
+
+// requireTweets checks the fields every parsed tweet has
+func requireTweets(t *testing.T, tweets []*Tweet) {
+	t.Helper()
+	require.NotEmpty(t, tweets)
+	for _, tweet := range tweets {
+		require.NotEmpty(t, tweet.RestID)
+		require.NotEmpty(t, tweet.Text, tweet.RestID)
+		require.False(t, tweet.CreatedAt.IsZero(), tweet.RestID)
+		require.NotNil(t, tweet.Author, tweet.RestID)
+		require.NotEmpty(t, tweet.Author.ScreenName, tweet.RestID)
+	}
+}
+
+func TestCassetteTweetDetail(t *testing.T) {
+	x := replayScraper(t, "tweet_detail", LoginOptions{})
+
+	tweets, err := x.GetTweetDetail(context.Background(), cassetteTweetID)
+	require.NoError(t, err)
+	requireTweets(t, tweets)
+	require.Equal(t, cassetteTweetID, tweets[0].RestID)
+	for _, reply := range tweets[1:] {
+		require.Equal(t, cassetteTweetID, reply.ConversationID, reply.RestID)
+	}
+
+	limits := x.RateLimits()
+	require.Len(t, limits, 1)
+	require.Equal(t, EndpointTweetDetail, limits[0].Endpoint)
+	require.Positive(t, limits[0].Limit)
+}
+
+func TestCassetteSearchTimeline(t *testing.T) {
+	x := replayScraper(t, "search_timeline", LoginOptions{})
+
+	tweets, err := x.SearchTweets(context.Background(), "$ena", 20)
+	require.NoError(t, err)
+	requireTweets(t, tweets)
+}
+
+func TestCassetteMentions(t *testing.T) {
+	x := replayScraper(t, "mentions", LoginOptions{Username: "BTCdayu"})
+
+	tweets, err := x.GetMentions(context.Background(), func(*Tweet) bool { return true })
+	require.NoError(t, err)
+	requireTweets(t, tweets)
+	for _, tweet := range tweets {
+		require.NotEqual(t, "BTCdayu", tweet.Author.ScreenName)
+		require.Contains(t, tweet.Text, "@BTCdayu")
+	}
+}
+
+func TestCassetteCreateTweet(t *testing.T) {
+	x := replayScraper(t, "create_tweet", LoginOptions{})
+
+	replyTo := cassetteTweetID
+	tweet, err := x.CreateTweet(context.Background(), NewTweet{
+		Text:             "@BTCdayu 除了$hype $grass $tia 外，可能最值得fomo的机构币就是 $ena 。",
+		InReplyToTweetId: &replyTo,
+	})
+	require.NoError(t, err)
+	requireTweets(t, []*Tweet{tweet})
+	require.True(t, tweet.IsReply)
+	require.Equal(t, replyTo, tweet.InReplyToStatusID)
+}
+
+func TestCassetteLogin(t *testing.T) {
+	delay := loginStepDelay
+	loginStepDelay = func() time.Duration { return 0 }
+	t.Cleanup(func() { loginStepDelay = delay })
+
+	var saved []*http.Cookie
+	x := replayScraper(t, "login", LoginOptions{
+		Username:    "threadmirror_bot",
+		Email:       "bot@threadmirror.example",
+		Password:    "password",
+		LoadCookies: func(context.Context) ([]*http.Cookie, error) { return nil, nil },
+		SaveCookies: func(_ context.Context, cookies []*http.Cookie) error {
+			saved = cookies
+			return nil
+		},
+	})
+
+	require.NoError(t, x.ensureLoggedIn(context.Background()))
+	names := make([]string, len(saved))
+	var ct0 string
+	for i, cookie := range saved {
+		names[i] = cookie.Name
+		if cookie.Name == "ct0" {
+			ct0 = cookie.Value
+		}
+	}
+	require.Subset(t, names, []string{"auth_token", "ct0"})
+	require.True(t, x.checkLoggedIn())
+
+	// Requests after the login carry its CSRF token
+	req, err := http.NewRequest(http.MethodGet, BASE_URL.String(), nil)
+	require.NoError(t, err)
+	x.applyRealHeader(req)
+	require.Equal(t, ct0, req.Header.Get("X-Csrf-Token"))
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	upstream := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		require.Contains(t, string(body), "secret-password")
+		rec := httptest.NewRecorder()
+		http.SetCookie(rec, &http.Cookie{Name: "ct0", Value: "secret-csrf", Path: "/"})
+		rec.Header().Set("Content-Type", "application/json")
+		_, _ = rec.WriteString(`{"flow_token":"secret-flow","user":"bot","html":"<b>"}`)
+		return rec.Result(), nil
+	})
+
+	recorder, err := NewCassette(path, CassetteRecord, upstream, "secret-password")
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder}
+	req, err := http.NewRequest(http.MethodPost, "https://api.x.com/1.1/onboarding/task.json?flow_name=login",
+		strings.NewReader(`{"password":"secret-password","text":"secret-password"}`))
+	require.NoError(t, err)
+	req.Header.Set("Cookie", "auth_token=secret-auth")
+	req.Header.Set("X-Csrf-Token", "secret-csrf")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	live, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(live), "secret-flow", "the caller gets the response unscrubbed")
+	require.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret")
+	require.Contains(t, string(data), "ct0="+scrubbed)
+	require.Contains(t, string(data), `"html": "<b>"`)
+
+	player, err := NewCassette(path, CassetteReplay, nil)
+	require.NoError(t, err)
+	client = &http.Client{Transport: player}
+	resp, err = client.Post("https://api.x.com/1.1/onboarding/task.json", "application/json", nil)
+	require.NoError(t, err, "replay falls back to the path")
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, map[string]string{"flow_token": scrubbed, "user": "bot", "html": "<b>"}, body)
+	require.Equal(t, "ct0", resp.Cookies()[0].Name)
+	require.Empty(t, player.Unused())
+
+	_, err = client.Post("https://api.x.com/1.1/onboarding/task.json", "application/json", nil)
+	require.ErrorContains(t, err, "no recorded response", "each interaction replays once")
+}
+
+// TestRecordCassettes records the read-only cassettes again from the live
+// API, for when X changes its schema. Run it with RECORD_CASSETTES=1 and the
+// cookies of an account in cookies.json or TWITTER_COOKIES_PATH; the
+// CreateTweet and login cassettes are recorded by hand.
+func TestRecordCassettes(t *testing.T) {
+	if os.Getenv("RECORD_CASSETTES") == "" {
+		t.Skip("Skipping TestRecordCassettes: set RECORD_CASSETTES=1 to record from the live API")
+	}
+	cookiesPath := os.Getenv("TWITTER_COOKIES_PATH")
+	if cookiesPath == "" {
+		cookiesPath = "cookies.json"
+	}
+	cookiesBytes, err := os.ReadFile(cookiesPath)
+	require.NoError(t, err)
+	var cookies []*http.Cookie
+	require.NoError(t, json.Unmarshal(cookiesBytes, &cookies))
+	secrets := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		secrets = append(secrets, cookie.Value)
+	}
+
+	ctx := context.Background()
+	record := func(name string, opts LoginOptions, call func(x *XScraper) error) {
+		cassette, err := NewCassette(filepath.Join("testdata", "cassettes", name+".json"), CassetteRecord, nil, secrets...)
+		require.NoError(t, err)
+		opts.Transport = cassette
+		opts.LoadCookies = func(context.Context) ([]*http.Cookie, error) { return cookies, nil }
+		x, err := New(opts, slog.Default())
+		require.NoError(t, err)
+		require.NoError(t, call(x))
+		require.NoError(t, cassette.Save())
+	}
+
+	record("tweet_detail", LoginOptions{}, func(x *XScraper) error {
+		_, err := x.GetTweetDetail(ctx, cassetteTweetID)
+		return err
+	})
+	record("search_timeline", LoginOptions{}, func(x *XScraper) error {
+		_, err := x.SearchTweets(ctx, "$ena", 20)
+		return err
+	})
+	record("mentions", LoginOptions{Username: "BTCdayu"}, func(x *XScraper) error {
+		_, err := x.GetMentions(ctx, func(*Tweet) bool { return true })
+		return err
+	})
+}
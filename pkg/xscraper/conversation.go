@@ -0,0 +1,249 @@
+package xscraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/samber/lo"
+)
+
+// MaxConversationTweets bounds the size of a conversation however its options
+// are set
+const MaxConversationTweets = 1000
+
+// conversationMaxPages is the most TweetDetail pages read for the replies to
+// a single tweet
+const conversationMaxPages = 5
+
+// ConversationOptions limits the reply tree collected by GetConversation
+type ConversationOptions struct {
+	// MaxDepth is how many levels of replies below the root are collected
+	MaxDepth int `json:"max_depth"`
+	// MaxBreadth is the most replies collected under a single tweet
+	MaxBreadth int `json:"max_breadth"`
+	// MinLikes skips replies with fewer likes, and the replies under them
+	MinLikes int `json:"min_likes"`
+}
+
+// DefaultConversationOptions are used for options left at zero
+var DefaultConversationOptions = ConversationOptions{
+	MaxDepth:   3,
+	MaxBreadth: 20,
+}
+
+// MaxConversationOptions are the largest depth and breadth accepted
+var MaxConversationOptions = ConversationOptions{
+	MaxDepth:   10,
+	MaxBreadth: 100,
+}
+
+// Normalize fills in defaults for unset limits and caps them at
+// MaxConversationOptions
+func (o ConversationOptions) Normalize() ConversationOptions {
+	if o.MaxDepth <= 0 {
+		o.MaxDepth = DefaultConversationOptions.MaxDepth
+	}
+	if o.MaxBreadth <= 0 {
+		o.MaxBreadth = DefaultConversationOptions.MaxBreadth
+	}
+	o.MaxDepth = min(o.MaxDepth, MaxConversationOptions.MaxDepth)
+	o.MaxBreadth = min(o.MaxBreadth, MaxConversationOptions.MaxBreadth)
+	o.MinLikes = max(o.MinLikes, 0)
+	return o
+}
+
+// ConversationPage is one page of the TweetDetail timeline of a tweet
+type ConversationPage struct {
+	Tweets []*Tweet
+	// Tombstones are the tweets of the page X did not serve
+	Tombstones []*Tombstone
+	// Cursor continues the replies to the focal tweet; empty on the last page
+	Cursor string
+}
+
+// GetConversation collects the reply tree under rootID, including replies by
+// other users, within the limits of opts. The root comes first and every
+// reply comes after the tweet it replies to (InReplyToStatusID), level by
+// level.
+func GetConversation(ctx context.Context, scraper XScraperInterface, rootID string, opts ConversationOptions) ([]*Tweet, error) {
+	opts = opts.Normalize()
+
+	first, err := scraper.GetConversationPage(ctx, rootID, "")
+	if err != nil {
+		return nil, fmt.Errorf("get tweet %s: %w", rootID, err)
+	}
+	root, ok := lo.Find(first.Tweets, func(t *Tweet) bool { return t.RestID == rootID })
+	if !ok {
+		if tombstone, ok := lo.Find(first.Tombstones, func(t *Tombstone) bool { return t.TweetID == rootID }); ok {
+			return nil, tombstone.Err()
+		}
+		return nil, fmt.Errorf("tweet %s not found", rootID)
+	}
+
+	type node struct {
+		tweet *Tweet
+		depth int
+	}
+	tweets := []*Tweet{root}
+	seen := map[string]bool{rootID: true}
+	queue := []node{{tweet: root}}
+
+	for len(queue) > 0 && len(tweets) < MaxConversationTweets {
+		n := queue[0]
+		queue = queue[1:]
+		if n.depth >= opts.MaxDepth || n.tweet.Stats.ReplyCount == 0 {
+			continue
+		}
+
+		// The root's first page was already read to find it
+		var page *ConversationPage
+		if n.tweet == root {
+			page = first
+		}
+		replies, err := getReplies(ctx, scraper, n.tweet.RestID, page, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, reply := range replies {
+			if seen[reply.RestID] || len(tweets) >= MaxConversationTweets {
+				continue
+			}
+			seen[reply.RestID] = true
+			tweets = append(tweets, reply)
+			queue = append(queue, node{tweet: reply, depth: n.depth + 1})
+		}
+	}
+	return tweets, nil
+}
+
+// getReplies returns up to opts.MaxBreadth direct replies to parentID with at
+// least opts.MinLikes likes, starting from page if it is not nil
+func getReplies(ctx context.Context, scraper XScraperInterface, parentID string, page *ConversationPage, opts ConversationOptions) ([]*Tweet, error) {
+	var replies []*Tweet
+	seen := make(map[string]bool)
+	cursor := ""
+
+	for range conversationMaxPages {
+		if page == nil {
+			var err error
+			page, err = scraper.GetConversationPage(ctx, parentID, cursor)
+			if err != nil {
+				return nil, fmt.Errorf("get replies to %s: %w", parentID, err)
+			}
+		}
+
+		// Pages also hold the ancestors of the focal tweet and deeper replies;
+		// deeper replies are collected from their own parent's page
+		for _, tweet := range page.Tweets {
+			if tweet.RestID == "" || tweet.InReplyToStatusID != parentID || seen[tweet.RestID] {
+				continue
+			}
+			seen[tweet.RestID] = true
+			if tweet.Stats.FavoriteCount < opts.MinLikes {
+				continue
+			}
+			replies = append(replies, tweet)
+			if len(replies) >= opts.MaxBreadth {
+				return replies, nil
+			}
+		}
+
+		if page.Cursor == "" || page.Cursor == cursor {
+			break
+		}
+		cursor = page.Cursor
+		page = nil
+	}
+	return replies, nil
+}
+
+// GetConversationPage returns a page of the TweetDetail timeline of id: the
+// tweet with its ancestors and the first replies for the first page (empty
+// cursor), and further replies for the cursor of the previous page
+func (x *XScraper) GetConversationPage(ctx context.Context, id, cursor string) (*ConversationPage, error) {
+	p := &tweetDetailPageParams{GetTweetDetailParams: newTweetDetailParams(id), Cursor: cursor}
+
+	var resp generated.TweetDetailResponse
+	var berr *BadRequestError
+	err := x.GetGraphQL(ctx, "/i/api/graphql/xd_EMdYvB9hfZsZ6Idri0w/TweetDetail", p, &resp)
+	if err != nil {
+		if errors.As(err, &berr) && berr.StatusCode == http.StatusNotFound {
+			return &ConversationPage{}, nil
+		}
+		return nil, fmt.Errorf("failed to get tweet detail: %w", err)
+	}
+
+	if resp.Errors != nil && len(*resp.Errors) > 0 {
+		if err := unavailableFromErrors(id, *resp.Errors); err != nil {
+			return nil, err
+		}
+		msgs := lo.Map(*resp.Errors, func(e generated.ErrorResponse, _ int) string { return e.Message })
+		return nil, fmt.Errorf("tweet detail: %s", strings.Join(msgs, "; "))
+	}
+
+	return convertTimelineToConversationPage(resp.Data.ThreadedConversationWithInjectionsV2)
+}
+
+// tweetDetailPageParams adds the pagination cursor, which the generated
+// parameters lack, to the TweetDetail variables
+type tweetDetailPageParams struct {
+	*generated.GetTweetDetailParams
+	Cursor string
+}
+
+func (p *tweetDetailPageParams) Query() url.Values {
+	query := p.GetTweetDetailParams.Query()
+	if p.Cursor == "" {
+		return query
+	}
+
+	variables := make(map[string]any)
+	if err := json.Unmarshal([]byte(query.Get("variables")), &variables); err != nil {
+		return query
+	}
+	variables["cursor"] = p.Cursor
+	if js, err := json.Marshal(variables); err == nil {
+		query.Set("variables", string(js))
+	}
+	return query
+}
+
+// convertTimelineToConversationPage converts a TweetDetail timeline to its
+// tweets and the cursor loading more replies to the focal tweet
+func convertTimelineToConversationPage(timeline *generated.Timeline) (*ConversationPage, error) {
+	result, err := convertTimelineToTweets(timeline)
+	if err != nil {
+		return nil, err
+	}
+	page := &ConversationPage{Tweets: result.Tweets, Tombstones: result.Tombstones}
+
+	for _, instruction := range timeline.Instructions {
+		addEntries, err := instruction.AsTimelineAddEntries()
+		if err != nil {
+			continue
+		}
+		for _, entry := range addEntries.Entries {
+			// Cursors inside a module (ShowMore) expand a single reply chain;
+			// only entry-level cursors continue the replies to the focal tweet
+			item, err := entry.Content.AsTimelineTimelineItem()
+			if err != nil {
+				continue
+			}
+			cursor, err := item.ItemContent.AsTimelineTimelineCursor()
+			if err != nil || cursor.Value == "" {
+				continue
+			}
+			switch cursor.CursorType {
+			case generated.CursorTypeBottom, generated.CursorTypeShowMoreThreads, generated.CursorTypeShowMoreThreadsPrompt:
+				page.Cursor = cursor.Value
+			}
+		}
+	}
+	return page, nil
+}
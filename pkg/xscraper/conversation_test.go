@@ -0,0 +1,106 @@
+package xscraper
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+// conversationScraper serves TweetDetail pages from a fixed reply tree
+type conversationScraper struct {
+	XScraperInterface
+	pages map[string]*ConversationPage // keyed by focal ID and cursor
+	calls []string
+}
+
+func (s *conversationScraper) GetConversationPage(ctx context.Context, id, cursor string) (*ConversationPage, error) {
+	key := id + "/" + cursor
+	s.calls = append(s.calls, key)
+	if page, ok := s.pages[key]; ok {
+		return page, nil
+	}
+	return &ConversationPage{}, nil
+}
+
+func reply(id, parent string, likes, replies int) *Tweet {
+	return &Tweet{
+		RestID:            id,
+		InReplyToStatusID: parent,
+		IsReply:           parent != "",
+		Stats:             TweetStats{FavoriteCount: likes, ReplyCount: replies},
+	}
+}
+
+func TestGetConversation(t *testing.T) {
+	ancestor := reply("0", "", 0, 1)
+	root := reply("1", "0", 0, 3)
+	scraper := &conversationScraper{pages: map[string]*ConversationPage{
+		"1/": {
+			// Ancestors and nested replies come with the page too
+			Tweets: []*Tweet{ancestor, root, reply("2", "1", 10, 1), reply("5", "2", 10, 0), reply("3", "1", 1, 0)},
+			Cursor: "more",
+		},
+		"1/more": {Tweets: []*Tweet{reply("4", "1", 10, 1)}},
+		"2/":     {Tweets: []*Tweet{root, reply("2", "1", 10, 1), reply("5", "2", 10, 0)}},
+		"4/":     {Tweets: []*Tweet{reply("4", "1", 10, 1), reply("6", "4", 10, 1)}},
+		"6/":     {Tweets: []*Tweet{reply("6", "4", 10, 1), reply("7", "6", 10, 0)}},
+	}}
+
+	tweets, err := GetConversation(context.Background(), scraper, "1", ConversationOptions{MaxDepth: 2, MinLikes: 5})
+	require.NoError(t, err)
+	ids := lo.Map(tweets, func(t *Tweet, _ int) string { return t.RestID })
+	// 3 has too few likes and 7 is below the depth limit
+	require.Equal(t, []string{"1", "2", "4", "5", "6"}, ids)
+	// The root's first page is not fetched twice, and leaves are not fetched
+	require.Equal(t, []string{"1/", "1/more", "2/", "4/"}, scraper.calls)
+
+	tweets, err = GetConversation(context.Background(), scraper, "1", ConversationOptions{MaxDepth: 1, MaxBreadth: 2})
+	require.NoError(t, err)
+	ids = lo.Map(tweets, func(t *Tweet, _ int) string { return t.RestID })
+	require.Equal(t, []string{"1", "2", "3"}, ids)
+
+	_, err = GetConversation(context.Background(), scraper, "42", ConversationOptions{})
+	require.Error(t, err)
+}
+
+func TestConversationOptionsNormalize(t *testing.T) {
+	require.Equal(t, DefaultConversationOptions, ConversationOptions{}.Normalize())
+	require.Equal(t, ConversationOptions{MaxDepth: 10, MaxBreadth: 100, MinLikes: 3},
+		ConversationOptions{MaxDepth: 50, MaxBreadth: 1000, MinLikes: 3}.Normalize())
+}
+
+func TestTweetDetailPageParams(t *testing.T) {
+	p := &tweetDetailPageParams{GetTweetDetailParams: newTweetDetailParams("1")}
+	require.NotContains(t, p.Query().Get("variables"), "cursor")
+
+	p.Cursor = "abc"
+	var variables map[string]any
+	require.NoError(t, json.Unmarshal([]byte(p.Query().Get("variables")), &variables))
+	require.Equal(t, "abc", variables["cursor"])
+	require.Equal(t, "1", variables["focalTweetId"])
+}
+
+func TestConvertTimelineToConversationPage(t *testing.T) {
+	var timeline generated.Timeline
+	require.NoError(t, json.Unmarshal([]byte(`{"instructions": [{
+		"type": "TimelineAddEntries",
+		"entries": [
+			{"entryId": "cursor-showmore-1", "sortIndex": "2", "content": {
+				"entryType": "TimelineTimelineModule", "__typename": "TimelineTimelineModule", "displayType": "VerticalConversation",
+				"items": [{"entryId": "cursor-showmore-1-1", "item": {"itemContent": {
+					"itemType": "TimelineTimelineCursor", "__typename": "TimelineTimelineCursor", "cursorType": "ShowMore", "value": "nested"}}}]}},
+			{"entryId": "cursor-bottom-1", "sortIndex": "1", "content": {
+				"entryType": "TimelineTimelineItem", "__typename": "TimelineTimelineItem",
+				"itemContent": {"itemType": "TimelineTimelineCursor", "__typename": "TimelineTimelineCursor", "cursorType": "Bottom", "value": "next"}}}
+		]
+	}]}`), &timeline))
+
+	page, err := convertTimelineToConversationPage(&timeline)
+	require.NoError(t, err)
+	require.Empty(t, page.Tweets)
+	require.Equal(t, "next", page.Cursor)
+}
@@ -1,43 +1,43 @@
 package xscraper
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"time"
 )
 
-// ScraperPool holds a shuffled copy of scrapers for reuse
-type ScraperPool struct {
-	scrapers []*XScraper
-}
-
-// NewScraperPool creates a new pool with shuffled scrapers
-func NewScraperPool(scrapers []*XScraper) *ScraperPool {
-	shuffled := make([]*XScraper, len(scrapers))
-	copy(shuffled, scrapers)
-	rand.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
-
-	return &ScraperPool{
-		scrapers: shuffled,
-	}
-}
+// ErrNoScraperAvailable is returned when every account of the pool has an
+// open circuit or is quarantined
+var ErrNoScraperAvailable = errors.New("no scraper available")
 
-// TryWithResult tries an operation and returns result
-func TryWithResult[T any](pool *ScraperPool, op func(*XScraper) (T, error)) (T, error) {
-	return TryWithDelay(pool, op, time.Millisecond*300, time.Second*2)
+// TryWithResult tries an operation calling endpoint and returns result
+func TryWithResult[T any](pool *ScraperPool, endpoint Endpoint, op func(*XScraper) (T, error)) (T, error) {
+	return TryWithDelay(pool, endpoint, op, time.Millisecond*300, time.Second*2)
 }
 
-// TryWithDelay tries an operation with delay between attempts
-func TryWithDelay[T any](pool *ScraperPool, op func(*XScraper) (T, error), minDelay, maxDelay time.Duration) (T, error) {
+// TryWithDelay tries an operation calling endpoint with delay between
+// attempts, on the usable accounts of the pool that are ready soonest. The
+// outcome of every attempt is recorded in the pool. When no account can be
+// used within the MaxWait of the pool, or every attempt was rate limited, it
+// returns a RetryAfterError rather than waiting.
+func TryWithDelay[T any](pool *ScraperPool, endpoint Endpoint, op func(*XScraper) (T, error), minDelay, maxDelay time.Duration) (T, error) {
 	var zero T
-	if len(pool.scrapers) == 0 {
+	if pool.Len() == 0 {
 		return zero, fmt.Errorf("no scrapers available")
 	}
 
+	accounts, retryAt := pool.available(endpoint)
+	if len(accounts) == 0 {
+		if retryAt.IsZero() {
+			return zero, fmt.Errorf("%w: all accounts are quarantined", ErrNoScraperAvailable)
+		}
+		return zero, &RetryAfterError{At: retryAt, Err: ErrNoScraperAvailable}
+	}
+
 	var lastErr error
-	for _, scraper := range pool.scrapers {
+	rateLimited := true
+	for _, account := range accounts {
 		// Add delay if configured
 		if maxDelay > 0 {
 			delay := minDelay
@@ -48,12 +48,26 @@ func TryWithDelay[T any](pool *ScraperPool, op func(*XScraper) (T, error), minDe
 			time.Sleep(delay)
 		}
 
-		result, err := op(scraper)
+		result, err := op(account.scraper)
+		pool.report(account, err)
 		if err == nil {
 			return result, nil
 		}
 		lastErr = err
+		// A deleted tweet or suspended author is gone for every account; a
+		// protected or age-restricted tweet may be visible to another one
+		if errors.Is(err, ErrTweetDeleted) || errors.Is(err, ErrAccountSuspended) {
+			return zero, err
+		}
+		if class, _ := ClassifyError(err); class != ErrorClassRateLimited {
+			rateLimited = false
+		}
 	}
 
+	if rateLimited {
+		if accounts, retryAt := pool.available(endpoint); len(accounts) == 0 && !retryAt.IsZero() {
+			return zero, &RetryAfterError{At: retryAt, Err: fmt.Errorf("all scrapers rate limited: %w", lastErr)}
+		}
+	}
 	return zero, fmt.Errorf("all scrapers failed, last error: %w", lastErr)
 }
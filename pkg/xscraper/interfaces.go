@@ -11,6 +11,7 @@ type XScraperInterface interface {
 	// Tweet operations
 	GetTweets(ctx context.Context, id string) (*TweetsResult, error)
 	GetTweetDetail(ctx context.Context, id string) ([]*Tweet, error)
+	GetConversationPage(ctx context.Context, id, cursor string) (*ConversationPage, error)
 	GetTweetResultByRestId(ctx context.Context, id string) (*Tweet, error)
 	SearchTweets(ctx context.Context, query string, maxTweets int) ([]*Tweet, error)
 	CreateTweet(ctx context.Context, newTweet NewTweet) (*Tweet, error)
@@ -0,0 +1,362 @@
+package xscraper
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrorClass is the kind of failure of an account, as tracked by ScraperPool
+type ErrorClass string
+
+const (
+	// ErrorClassRateLimited is an HTTP 429 from X
+	ErrorClassRateLimited ErrorClass = "rate_limited"
+	// ErrorClassLocked is the lock screen X shows suspicious accounts
+	ErrorClassLocked ErrorClass = "locked"
+	// ErrorClassNotLoggedIn is a failed login
+	ErrorClassNotLoggedIn ErrorClass = "not_logged_in"
+	// ErrorClassChallenge is a login stopped at a step that needs a person
+	ErrorClassChallenge ErrorClass = "login_challenge"
+	// ErrorClassOther is any other error, such as a missing tweet, which says
+	// nothing about the account
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ClassifyError returns the class of an error returned by an operation of a
+// scraper, and false for errors that are not failures of the account, such
+// as a cancelled context or an unavailable tweet
+func ClassifyError(err error) (ErrorClass, bool) {
+	var berr *BadRequestError
+	var challenge *LoginChallengeError
+	var uerr *TweetUnavailableError
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "", false
+	case errors.As(err, &uerr):
+		return "", false
+	case errors.As(err, &challenge):
+		return ErrorClassChallenge, true
+	case errors.Is(err, ErrAccountLocked):
+		return ErrorClassLocked, true
+	case errors.As(err, &berr) && berr.StatusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited, true
+	case errors.Is(err, errNotLoggedIn):
+		return ErrorClassNotLoggedIn, true
+	default:
+		return ErrorClassOther, true
+	}
+}
+
+// CircuitState is the state of an account in a ScraperPool
+type CircuitState string
+
+const (
+	// CircuitClosed accounts are used
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen accounts are skipped until their cooldown ends
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen accounts are tried again after a cooldown; the next
+	// failure opens the circuit for twice as long
+	CircuitHalfOpen CircuitState = "half_open"
+	// CircuitQuarantined accounts are skipped until an operator clears them
+	CircuitQuarantined CircuitState = "quarantined"
+)
+
+// PoolConfig configures the circuit breaker of a ScraperPool
+type PoolConfig struct {
+	// FailureThreshold is how many failed logins in a row open the circuit of
+	// an account. Rate limits and lock screens open it at once.
+	FailureThreshold int
+	// BaseCooldown is how long a circuit first stays open; it doubles every
+	// time the circuit opens again before a success
+	BaseCooldown time.Duration
+	// MaxCooldown caps the cooldown
+	MaxCooldown time.Duration
+	// MaxWait is how long an operation may wait for the rate limit of an
+	// account. When no account is ready sooner, it fails with a
+	// RetryAfterError instead.
+	MaxWait time.Duration
+}
+
+// DefaultPoolConfig is used for PoolConfig fields left at zero
+var DefaultPoolConfig = PoolConfig{
+	FailureThreshold: 3,
+	BaseCooldown:     time.Minute,
+	MaxCooldown:      time.Hour,
+	MaxWait:          10 * time.Second,
+}
+
+// AccountStatus is the state of an account in a ScraperPool, for monitoring
+type AccountStatus struct {
+	Username string
+	State    CircuitState
+	// Failures counts the failures of the account by class since it was added
+	Failures map[ErrorClass]int
+	// ConsecutiveFailures counts the failures opening the circuit since the
+	// last success
+	ConsecutiveFailures int
+	// OpenUntil is the end of the cooldown of an open circuit
+	OpenUntil        time.Time
+	QuarantinedAt    time.Time
+	QuarantineReason string
+	LastError        string
+	LastErrorAt      time.Time
+	LastSuccessAt    time.Time
+	// RateLimits are the limits of the endpoints the account has called
+	RateLimits []EndpointLimit
+}
+
+// poolAccount is a scraper and the breaker state of its account
+type poolAccount struct {
+	scraper *XScraper
+
+	failures      map[ErrorClass]int
+	consecutive   int
+	trips         int // circuit openings since the last success
+	openUntil     time.Time
+	quarantinedAt time.Time
+	reason        string
+	lastError     string
+	lastErrorAt   time.Time
+	lastSuccessAt time.Time
+}
+
+func (a *poolAccount) state(now time.Time) CircuitState {
+	switch {
+	case !a.quarantinedAt.IsZero():
+		return CircuitQuarantined
+	case now.Before(a.openUntil):
+		return CircuitOpen
+	case a.trips > 0:
+		return CircuitHalfOpen
+	default:
+		return CircuitClosed
+	}
+}
+
+// ScraperPool shares the scrapers of all accounts between jobs. It tracks the
+// failures of every account, skips accounts whose circuit is open until their
+// cooldown ends, and quarantines accounts stopped at a login challenge until
+// an operator clears them.
+type ScraperPool struct {
+	mu       sync.Mutex
+	config   PoolConfig
+	accounts []*poolAccount
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewScraperPool creates a pool of scrapers
+func NewScraperPool(scrapers []*XScraper, config PoolConfig, logger *slog.Logger) *ScraperPool {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultPoolConfig.FailureThreshold
+	}
+	if config.BaseCooldown <= 0 {
+		config.BaseCooldown = DefaultPoolConfig.BaseCooldown
+	}
+	if config.MaxCooldown <= 0 {
+		config.MaxCooldown = DefaultPoolConfig.MaxCooldown
+	}
+	if config.MaxWait <= 0 {
+		config.MaxWait = DefaultPoolConfig.MaxWait
+	}
+
+	accounts := make([]*poolAccount, len(scrapers))
+	for i, scraper := range scrapers {
+		accounts[i] = &poolAccount{scraper: scraper, failures: make(map[ErrorClass]int)}
+	}
+	return &ScraperPool{config: config, accounts: accounts, now: time.Now, logger: logger.With("component", "scraper_pool")}
+}
+
+// Scrapers returns all scrapers of the pool, usable or not
+func (p *ScraperPool) Scrapers() []*XScraper {
+	scrapers := make([]*XScraper, len(p.accounts))
+	for i, account := range p.accounts {
+		scrapers[i] = account.scraper
+	}
+	return scrapers
+}
+
+// Len returns the number of accounts in the pool
+func (p *ScraperPool) Len() int {
+	return len(p.accounts)
+}
+
+// available returns the accounts that may be used for endpoint within
+// MaxWait, the ones ready soonest first and shuffled among those ready at the
+// same time. When there are none, retryAt is when the first account may be
+// used again, zero if every account is quarantined.
+func (p *ScraperPool) available(endpoint Endpoint) (accounts []*poolAccount, retryAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	readyAt := make(map[*poolAccount]time.Time, len(p.accounts))
+	for _, account := range p.accounts {
+		state := account.state(now)
+		if state == CircuitQuarantined {
+			continue
+		}
+		at := account.scraper.ReadyAt(endpoint, now)
+		if at.IsZero() {
+			continue
+		}
+		if state == CircuitOpen && account.openUntil.After(at) {
+			at = account.openUntil
+		}
+		if at.Before(now) {
+			at = now
+		}
+		if at.After(now.Add(p.config.MaxWait)) {
+			if retryAt.IsZero() || at.Before(retryAt) {
+				retryAt = at
+			}
+			continue
+		}
+		readyAt[account] = at
+		accounts = append(accounts, account)
+	}
+
+	rand.Shuffle(len(accounts), func(i, j int) {
+		accounts[i], accounts[j] = accounts[j], accounts[i]
+	})
+	slices.SortStableFunc(accounts, func(a, b *poolAccount) int {
+		return readyAt[a].Compare(readyAt[b])
+	})
+	if len(accounts) > 0 {
+		retryAt = time.Time{}
+	}
+	return accounts, retryAt
+}
+
+// report records the outcome of an operation of an account
+func (p *ScraperPool) report(account *poolAccount, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if err == nil {
+		account.consecutive = 0
+		account.trips = 0
+		account.openUntil = time.Time{}
+		account.lastSuccessAt = now
+		return
+	}
+
+	class, ok := ClassifyError(err)
+	if !ok {
+		return
+	}
+	account.failures[class]++
+	account.lastError = err.Error()
+	account.lastErrorAt = now
+
+	switch class {
+	case ErrorClassChallenge:
+		account.quarantinedAt = now
+		account.reason = err.Error()
+		p.logger.Error("scraper account quarantined", "username", account.scraper.LoginOpts.Username, "error", err)
+	case ErrorClassRateLimited, ErrorClassLocked:
+		p.trip(account, now)
+	case ErrorClassNotLoggedIn:
+		account.consecutive++
+		// A half-open circuit opens again on its first failure
+		if account.consecutive >= p.config.FailureThreshold || account.trips > 0 {
+			p.trip(account, now)
+		}
+	}
+}
+
+// trip opens the circuit of an account for an exponentially growing cooldown
+func (p *ScraperPool) trip(account *poolAccount, now time.Time) {
+	cooldown := p.config.BaseCooldown << min(account.trips, 30)
+	if cooldown <= 0 || cooldown > p.config.MaxCooldown {
+		cooldown = p.config.MaxCooldown
+	}
+	account.trips++
+	account.consecutive = 0
+	account.openUntil = now.Add(cooldown)
+	p.logger.Warn("scraper account circuit opened", "username", account.scraper.LoginOpts.Username,
+		"cooldown", cooldown, "error", account.lastError)
+}
+
+// find returns the account of username
+func (p *ScraperPool) find(username string) *poolAccount {
+	for _, account := range p.accounts {
+		if account.scraper.LoginOpts.Username == username {
+			return account
+		}
+	}
+	return nil
+}
+
+// Quarantine takes the account of username out of use until ClearQuarantine,
+// as if it had been stopped at a login challenge at the given time. It
+// reports whether the account is in the pool.
+func (p *ScraperPool) Quarantine(username, reason string, at time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	account := p.find(username)
+	if account == nil {
+		return false
+	}
+	account.quarantinedAt = at
+	account.reason = reason
+	return true
+}
+
+// ClearQuarantine puts a quarantined account back into use with a closed
+// circuit. It reports whether the account is in the pool.
+func (p *ScraperPool) ClearQuarantine(username string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	account := p.find(username)
+	if account == nil {
+		return false
+	}
+	account.quarantinedAt = time.Time{}
+	account.reason = ""
+	account.consecutive = 0
+	account.trips = 0
+	account.openUntil = time.Time{}
+	return true
+}
+
+// Snapshot returns the state of every account of the pool
+func (p *ScraperPool) Snapshot() []AccountStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	statuses := make([]AccountStatus, len(p.accounts))
+	for i, account := range p.accounts {
+		failures := make(map[ErrorClass]int, len(account.failures))
+		for class, n := range account.failures {
+			failures[class] = n
+		}
+		statuses[i] = AccountStatus{
+			Username:            account.scraper.LoginOpts.Username,
+			State:               account.state(now),
+			Failures:            failures,
+			ConsecutiveFailures: account.consecutive,
+			QuarantinedAt:       account.quarantinedAt,
+			QuarantineReason:    account.reason,
+			LastError:           account.lastError,
+			LastErrorAt:         account.lastErrorAt,
+			LastSuccessAt:       account.lastSuccessAt,
+			RateLimits:          account.scraper.rateLimits.snapshot(now),
+		}
+		if now.Before(account.openUntil) {
+			statuses[i].OpenUntil = account.openUntil
+		}
+	}
+	return statuses
+}
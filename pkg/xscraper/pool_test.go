@@ -0,0 +1,234 @@
+package xscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/xrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	for _, tc := range []struct {
+		err   error
+		class ErrorClass
+		ok    bool
+	}{
+		{nil, "", false},
+		{fmt.Errorf("get: %w", context.Canceled), "", false},
+		{&BadRequestError{StatusCode: http.StatusTooManyRequests}, ErrorClassRateLimited, true},
+		{fmt.Errorf("%w: %w", ErrAccountLocked, &BadRequestError{StatusCode: http.StatusForbidden}), ErrorClassLocked, true},
+		{fmt.Errorf("ensure login: %w: %w", errNotLoggedIn, &LoginChallengeError{Subtask: "DenyLoginSubtask"}), ErrorClassChallenge, true},
+		{fmt.Errorf("ensure login: %w: %w", errNotLoggedIn, errors.New("unknown state")), ErrorClassNotLoggedIn, true},
+		{&BadRequestError{StatusCode: http.StatusNotFound}, ErrorClassOther, true},
+		{fmt.Errorf("attempt 1 failed: %w", &TweetUnavailableError{TweetID: "1", Reason: ReasonDeleted}), "", false},
+	} {
+		class, ok := ClassifyError(tc.err)
+		require.Equal(t, tc.class, class, "%v", tc.err)
+		require.Equal(t, tc.ok, ok, "%v", tc.err)
+	}
+}
+
+func TestAPIErrorCodes(t *testing.T) {
+	require.Equal(t, []int{326}, apiErrorCodes(`{"errors":[{"code":326,"message":"locked"}]}`))
+	require.Empty(t, apiErrorCodes("not json"))
+}
+
+func newTestPool(usernames ...string) (*ScraperPool, *time.Time) {
+	scrapers := make([]*XScraper, len(usernames))
+	for i, username := range usernames {
+		scrapers[i] = &XScraper{LoginOpts: LoginOptions{Username: username}, rateLimiter: xrate.NewLimiter(xrate.Every(1500*time.Millisecond), 1)}
+	}
+	pool := NewScraperPool(scrapers, PoolConfig{FailureThreshold: 2, BaseCooldown: time.Minute, MaxCooldown: 3 * time.Minute}, slog.Default())
+	now := time.Unix(1_700_000_000, 0)
+	pool.now = func() time.Time { return now }
+	return pool, &now
+}
+
+func status(pool *ScraperPool, username string) AccountStatus {
+	for _, s := range pool.Snapshot() {
+		if s.Username == username {
+			return s
+		}
+	}
+	panic("no account " + username)
+}
+
+func TestScraperPoolCircuit(t *testing.T) {
+	pool, now := newTestPool("a")
+	account := pool.accounts[0]
+	rateLimited := &BadRequestError{StatusCode: http.StatusTooManyRequests}
+
+	// Errors that say nothing about the account do not open the circuit
+	pool.report(account, &BadRequestError{StatusCode: http.StatusNotFound})
+	pool.report(account, context.Canceled)
+	require.Equal(t, CircuitClosed, status(pool, "a").State)
+	require.Equal(t, map[ErrorClass]int{ErrorClassOther: 1}, status(pool, "a").Failures)
+
+	// Failed logins open it at the threshold
+	notLoggedIn := fmt.Errorf("ensure login: %w", errNotLoggedIn)
+	pool.report(account, notLoggedIn)
+	require.Equal(t, CircuitClosed, status(pool, "a").State)
+	pool.report(account, notLoggedIn)
+	require.Equal(t, CircuitOpen, status(pool, "a").State)
+	require.Equal(t, now.Add(time.Minute), status(pool, "a").OpenUntil)
+	accounts, retryAt := pool.available(EndpointAny)
+	require.Empty(t, accounts)
+	require.Equal(t, now.Add(time.Minute), retryAt)
+
+	// Half-open after the cooldown; the next failure doubles it
+	*now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, status(pool, "a").State)
+	accounts, _ = pool.available(EndpointAny)
+	require.Len(t, accounts, 1)
+	pool.report(account, rateLimited)
+	require.Equal(t, now.Add(2*time.Minute), status(pool, "a").OpenUntil)
+
+	// Capped at the maximum cooldown
+	*now = now.Add(2 * time.Minute)
+	pool.report(account, rateLimited)
+	require.Equal(t, now.Add(3*time.Minute), status(pool, "a").OpenUntil)
+
+	// A success closes it and resets the cooldown
+	*now = now.Add(3 * time.Minute)
+	pool.report(account, nil)
+	require.Equal(t, CircuitClosed, status(pool, "a").State)
+	require.Equal(t, *now, status(pool, "a").LastSuccessAt)
+	pool.report(account, rateLimited)
+	require.Equal(t, now.Add(time.Minute), status(pool, "a").OpenUntil)
+}
+
+func TestScraperPoolQuarantine(t *testing.T) {
+	pool, now := newTestPool("a", "b")
+	calls := map[string]int{}
+
+	challenge := fmt.Errorf("ensure login: %w: %w", errNotLoggedIn, &LoginChallengeError{Subtask: "LoginAcid"})
+	op := func(sc *XScraper) (string, error) {
+		calls[sc.LoginOpts.Username]++
+		if sc.LoginOpts.Username == "a" {
+			return "", challenge
+		}
+		return sc.LoginOpts.Username, nil
+	}
+
+	for range 5 {
+		result, err := TryWithDelay(pool, EndpointAny, op, 0, 0)
+		require.NoError(t, err)
+		require.Equal(t, "b", result)
+	}
+	// a is quarantined on its first challenge and not tried again
+	require.LessOrEqual(t, calls["a"], 1)
+	if calls["a"] == 1 {
+		s := status(pool, "a")
+		require.Equal(t, CircuitQuarantined, s.State)
+		require.Equal(t, *now, s.QuarantinedAt)
+		require.Contains(t, s.QuarantineReason, "LoginAcid")
+	}
+
+	// Quarantines last until cleared, however long
+	require.True(t, pool.Quarantine("a", "LoginAcid", *now))
+	require.True(t, pool.Quarantine("b", "DenyLoginSubtask", *now))
+	*now = now.Add(24 * time.Hour)
+	_, err := TryWithDelay(pool, EndpointAny, op, 0, 0)
+	require.ErrorIs(t, err, ErrNoScraperAvailable)
+
+	require.True(t, pool.ClearQuarantine("b"))
+	require.False(t, pool.ClearQuarantine("c"))
+	require.Equal(t, CircuitClosed, status(pool, "b").State)
+	result, err := TryWithDelay(pool, EndpointAny, op, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "b", result)
+}
+
+func TestTryWithDelayOpenCircuits(t *testing.T) {
+	pool, now := newTestPool("a")
+	_, err := TryWithDelay(pool, EndpointAny, func(*XScraper) (int, error) {
+		return 0, &BadRequestError{StatusCode: http.StatusTooManyRequests}
+	}, 0, 0)
+	require.Error(t, err)
+
+	_, err = TryWithDelay(pool, EndpointAny, func(*XScraper) (int, error) { return 1, nil }, 0, 0)
+	require.ErrorIs(t, err, ErrNoScraperAvailable)
+	require.Contains(t, err.Error(), now.Add(time.Minute).Format(time.RFC3339))
+	var retry *RetryAfterError
+	require.ErrorAs(t, err, &retry)
+	require.Equal(t, now.Add(time.Minute), retry.At)
+
+	empty := NewScraperPool(nil, PoolConfig{}, slog.Default())
+	_, err = TryWithDelay(empty, EndpointAny, func(*XScraper) (int, error) { return 1, nil }, 0, 0)
+	require.Error(t, err)
+}
+
+func rateLimitResponse(status, limit, remaining int, reset time.Time) *http.Response {
+	return &http.Response{StatusCode: status, Header: http.Header{
+		"X-Rate-Limit-Limit":     {strconv.Itoa(limit)},
+		"X-Rate-Limit-Remaining": {strconv.Itoa(remaining)},
+		"X-Rate-Limit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+	}}
+}
+
+func TestScraperPoolRateLimits(t *testing.T) {
+	pool, now := newTestPool("a", "b", "c")
+	a, b, c := pool.accounts[0], pool.accounts[1], pool.accounts[2]
+	usernames := func(accounts []*poolAccount) []string {
+		names := make([]string, len(accounts))
+		for i, account := range accounts {
+			names[i] = account.scraper.LoginOpts.Username
+		}
+		return names
+	}
+
+	// b waits a few seconds for its pacing; c used up TweetDetail
+	b.scraper.rateLimiter.ResetAt(now.Add(5*time.Second), 0)
+	c.scraper.rateLimits.update(EndpointTweetDetail, rateLimitResponse(http.StatusOK, 150, 0, now.Add(time.Hour)), *now)
+	require.Equal(t, now.Add(time.Hour), c.scraper.ReadyAt(EndpointTweetDetail, *now))
+	require.Equal(t, *now, c.scraper.ReadyAt(EndpointSearchTimeline, *now))
+
+	for range 10 {
+		accounts, _ := pool.available(EndpointTweetDetail)
+		require.Equal(t, []string{"a", "b"}, usernames(accounts))
+		accounts, _ = pool.available(EndpointSearchTimeline)
+		require.Len(t, accounts, 3)
+		require.Equal(t, "b", accounts[2].scraper.LoginOpts.Username)
+	}
+
+	// Every account exhausted: fail fast with the earliest reset
+	a.scraper.rateLimiter.ResetAt(now.Add(2*time.Hour), 0)
+	b.scraper.rateLimiter.ResetAt(now.Add(30*time.Minute), 0)
+	calls := 0
+	_, err := TryWithDelay(pool, EndpointTweetDetail, func(*XScraper) (int, error) {
+		calls++
+		return 1, nil
+	}, 0, 0)
+	var retry *RetryAfterError
+	require.ErrorAs(t, err, &retry)
+	require.ErrorIs(t, err, ErrNoScraperAvailable)
+	require.Equal(t, now.Add(30*time.Minute+1500*time.Millisecond), retry.At)
+	require.Zero(t, calls)
+
+	// A response with requests left clears the endpoint limit
+	c.scraper.rateLimits.update(EndpointTweetDetail, rateLimitResponse(http.StatusOK, 150, 10, now.Add(time.Hour)), *now)
+	result, err := TryWithDelay(pool, EndpointTweetDetail, func(sc *XScraper) (string, error) {
+		return sc.LoginOpts.Username, nil
+	}, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "c", result)
+}
+
+func TestTryWithDelayRateLimited(t *testing.T) {
+	pool, now := newTestPool("a", "b")
+	_, err := TryWithDelay(pool, EndpointSearchTimeline, func(*XScraper) (int, error) {
+		return 0, &BadRequestError{StatusCode: http.StatusTooManyRequests}
+	}, 0, 0)
+	var retry *RetryAfterError
+	require.ErrorAs(t, err, &retry)
+	require.Equal(t, now.Add(time.Minute), retry.At)
+	var berr *BadRequestError
+	require.ErrorAs(t, err, &berr)
+}
@@ -0,0 +1,180 @@
+package xscraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint names an X API endpoint with a rate limit of its own, the last
+// segment of its path. The limits of GraphQL endpoints are per account and
+// per operation, so they do not change with the query ID.
+type Endpoint string
+
+const (
+	EndpointTweetDetail         Endpoint = "TweetDetail"
+	EndpointTweetResultByRestId Endpoint = "TweetResultByRestId"
+	EndpointSearchTimeline      Endpoint = "SearchTimeline"
+	EndpointCreateTweet         Endpoint = "CreateTweet"
+	// EndpointAny is for operations without a single endpoint; only the
+	// pacing of the account is taken into account
+	EndpointAny Endpoint = ""
+)
+
+// endpointOf returns the endpoint of a request path
+func endpointOf(p string) Endpoint {
+	return Endpoint(path.Base(p))
+}
+
+// RetryAfterError is returned instead of waiting when no account of a
+// ScraperPool can be used before At. It implements RetryAfter, so a job
+// failing with it is postponed rather than retried at once.
+type RetryAfterError struct {
+	At  time.Time
+	Err error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.Err, e.At.Format(time.RFC3339))
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns how long to wait before trying again
+func (e *RetryAfterError) RetryAfter() time.Duration {
+	return time.Until(e.At)
+}
+
+// EndpointLimit is the rate limit of an endpoint for an account, as last
+// reported by X in the x-rate-limit-* headers of a response
+type EndpointLimit struct {
+	Endpoint Endpoint `json:"endpoint"`
+	// Limit is the number of requests allowed in a window
+	Limit int `json:"limit"`
+	// Remaining is the number of requests left until Reset
+	Remaining int `json:"remaining"`
+	// Reset is when the window ends and Remaining goes back to Limit
+	Reset     time.Time `json:"reset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// at returns the limit as of now, with the window reset if it has ended
+func (l EndpointLimit) at(now time.Time) EndpointLimit {
+	if !now.Before(l.Reset) {
+		l.Remaining = l.Limit
+	}
+	return l
+}
+
+// rateLimits is the registry of the endpoint limits of an account. The zero
+// value is empty and ready to use.
+type rateLimits struct {
+	mu     sync.Mutex
+	limits map[Endpoint]EndpointLimit
+}
+
+// update records the limit reported by a response of endpoint. It reports
+// whether the response had rate limit headers.
+func (r *rateLimits) update(endpoint Endpoint, resp *http.Response, now time.Time) bool {
+	limit, err := strconv.Atoi(resp.Header.Get("x-rate-limit-limit"))
+	if err != nil {
+		return false
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("x-rate-limit-remaining"))
+	if err != nil {
+		return false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("x-rate-limit-reset"), 10, 64)
+	if err != nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		remaining = 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.limits == nil {
+		r.limits = make(map[Endpoint]EndpointLimit)
+	}
+	r.limits[endpoint] = EndpointLimit{
+		Endpoint:  endpoint,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+		UpdatedAt: now,
+	}
+	return true
+}
+
+// readyAt returns when endpoint may be called again: now unless its limit
+// is used up, then the end of the window
+func (r *rateLimits) readyAt(endpoint Endpoint, now time.Time) time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit, ok := r.limits[endpoint]
+	if !ok || limit.at(now).Remaining > 0 {
+		return now
+	}
+	return limit.Reset
+}
+
+// snapshot returns the limits of all endpoints called so far, by endpoint
+func (r *rateLimits) snapshot(now time.Time) []EndpointLimit {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limits := make([]EndpointLimit, 0, len(r.limits))
+	for _, limit := range r.limits {
+		limits = append(limits, limit.at(now))
+	}
+	slices.SortFunc(limits, func(a, b EndpointLimit) int { return strings.Compare(string(a.Endpoint), string(b.Endpoint)) })
+	return limits
+}
+
+// ReadyAt returns when the scraper may next call endpoint without waiting:
+// once its pacing limiter has a token and the window of the endpoint, if its
+// limit is used up, has ended. EndpointAny only checks the pacing limiter.
+func (x *XScraper) ReadyAt(endpoint Endpoint, now time.Time) time.Time {
+	at := x.rateLimiter.ReadyAt(now)
+	if at.IsZero() || endpoint == EndpointAny {
+		return at
+	}
+	if reset := x.rateLimits.readyAt(endpoint, now); reset.After(at) {
+		return reset
+	}
+	return at
+}
+
+// RateLimits returns the rate limits of the endpoints the scraper has called,
+// as of now
+func (x *XScraper) RateLimits() []EndpointLimit {
+	return x.rateLimits.snapshot(time.Now())
+}
+
+// waitForEndpoint waits until the limit of endpoint allows a request
+func (x *XScraper) waitForEndpoint(ctx context.Context, endpoint Endpoint) error {
+	wait := time.Until(x.rateLimits.readyAt(endpoint, time.Now()))
+	if wait <= 0 {
+		return nil
+	}
+	if x.logger != nil {
+		x.logger.Warn("endpoint rate limit used up, waiting for reset", "username", x.LoginOpts.Username,
+			"endpoint", endpoint, "wait", wait)
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
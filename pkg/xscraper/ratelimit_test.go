@@ -0,0 +1,56 @@
+package xscraper
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/xrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointOf(t *testing.T) {
+	require.Equal(t, EndpointTweetDetail, endpointOf("/i/api/graphql/xd_EMdYvB9hfZsZ6Idri0w/TweetDetail"))
+	require.Equal(t, Endpoint("onboarding"), endpointOf("/1.1/onboarding"))
+}
+
+func TestRateLimits(t *testing.T) {
+	var limits rateLimits
+	now := time.Unix(1_700_000_000, 0)
+	reset := now.Add(15 * time.Minute)
+
+	require.Empty(t, limits.snapshot(now))
+	require.False(t, limits.update(EndpointSearchTimeline, &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, now))
+	require.Equal(t, now, limits.readyAt(EndpointSearchTimeline, now))
+
+	// Search used up; thread fetches are not held back by it
+	require.True(t, limits.update(EndpointSearchTimeline, rateLimitResponse(http.StatusOK, 50, 0, reset), now))
+	require.True(t, limits.update(EndpointTweetDetail, rateLimitResponse(http.StatusOK, 150, 149, reset), now))
+	require.Equal(t, reset, limits.readyAt(EndpointSearchTimeline, now))
+	require.Equal(t, now, limits.readyAt(EndpointTweetDetail, now))
+	require.Equal(t, []EndpointLimit{
+		{Endpoint: EndpointSearchTimeline, Limit: 50, Remaining: 0, Reset: reset, UpdatedAt: now},
+		{Endpoint: EndpointTweetDetail, Limit: 150, Remaining: 149, Reset: reset, UpdatedAt: now},
+	}, limits.snapshot(now))
+
+	// The window ends at the reset
+	require.Equal(t, reset, limits.readyAt(EndpointSearchTimeline, reset))
+	require.Equal(t, 50, limits.snapshot(reset)[0].Remaining)
+
+	// A 429 uses up the limit whatever the headers say
+	require.True(t, limits.update(EndpointTweetDetail, rateLimitResponse(http.StatusTooManyRequests, 150, 3, reset), now))
+	require.Equal(t, reset, limits.readyAt(EndpointTweetDetail, now))
+}
+
+func TestXScraperReadyAt(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	x := &XScraper{rateLimiter: xrate.NewLimiter(xrate.Every(1500*time.Millisecond), 1)}
+	x.rateLimits.update(EndpointSearchTimeline, rateLimitResponse(http.StatusOK, 50, 0, now.Add(time.Minute)), now)
+
+	require.Equal(t, now, x.ReadyAt(EndpointTweetDetail, now))
+	require.Equal(t, now.Add(time.Minute), x.ReadyAt(EndpointSearchTimeline, now))
+	require.Equal(t, now, x.ReadyAt(EndpointAny, now))
+
+	require.True(t, x.rateLimiter.AllowN(now, 1))
+	require.Equal(t, now.Add(1500*time.Millisecond), x.ReadyAt(EndpointTweetDetail, now))
+}
@@ -7,6 +7,8 @@ import (
 )
 
 type RealHeader struct {
+	// Name identifies the built-in fingerprints, see RealHeaderByName
+	Name                    string
 	SecChUa                 string
 	SecChUaMobile           string
 	SecUaPlatform           string
@@ -60,6 +62,7 @@ var (
 	realHeaders = []RealHeader{
 		// Chrome
 		{
+			Name:                    "chrome",
 			SecChUa:                 `"Google Chrome";v="137", "Chromium";v="137", "Not/A)Brand";v="24"`,
 			SecChUaMobile:           "?0",
 			SecUaPlatform:           `"Linux"`,
@@ -74,6 +77,7 @@ var (
 		},
 		// Chrome Fetch/XHR
 		{
+			Name:           "chrome-fetch",
 			SecChUa:        `"Google Chrome";v="137", "Chromium";v="137", "Not/A)Brand";v="24"`,
 			SecChUaMobile:  "?0",
 			SecUaPlatform:  `"Linux"`,
@@ -86,6 +90,7 @@ var (
 		},
 		// Edge
 		{
+			Name:                    "edge",
 			SecChUa:                 `"Microsoft Edge";v="137", "Chromium";v="137", "Not/A)Brand";v="24"`,
 			SecChUaMobile:           "?0",
 			SecUaPlatform:           `"Linux"`,
@@ -100,6 +105,7 @@ var (
 		},
 		// Edge Fetch/XHR
 		{
+			Name:           "edge-fetch",
 			SecChUa:        `"Microsoft Edge";v="137", "Chromium";v="137", "Not/A)Brand";v="24"`,
 			SecChUaMobile:  "?0",
 			SecUaPlatform:  `"Linux"`,
@@ -112,6 +118,7 @@ var (
 		},
 		// Firefox
 		{
+			Name:                    "firefox",
 			UserAgent:               "Mozilla/5.0 (X11; Linux x86_64; rv:139.0) Gecko/20100101 Firefox/139.0",
 			Accept:                  "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
 			AcceptLanguage:          "en-US,en;q=0.5",
@@ -123,6 +130,7 @@ var (
 		},
 		// Firefox Fetch
 		{
+			Name:           "firefox-fetch",
 			UserAgent:      "Mozilla/5.0 (X11; Linux x86_64; rv:139.0) Gecko/20100101 Firefox/139.0",
 			Accept:         "*/*",
 			AcceptLanguage: "en-US,en;q=0.5",
@@ -140,3 +148,17 @@ func RandRealHeader() RealHeader {
 
 	return realHeaders[rand.IntN(len(realHeaders))]
 }
+
+// RealHeaderByName returns the built-in fingerprint named name: chrome, edge
+// or firefox, with a -fetch suffix for the headers of a script request
+func RealHeaderByName(name string) (RealHeader, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, h := range realHeaders {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return RealHeader{}, false
+}
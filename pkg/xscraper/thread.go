@@ -3,12 +3,18 @@ package xscraper
 import (
 	"context"
 	"fmt"
-	"sort"
 )
 
 // GetCompleteThread 获取完整的推文串
 // 这是一个纯函数，会继续调用GetTweets直到IsComplete为true或达到最大尝试次数
 func GetCompleteThread(ctx context.Context, scraper XScraperInterface, tweetID string, maxAttempts int) ([]*Tweet, error) {
+	tweets, _, err := GetCompleteThreadWithTombstones(ctx, scraper, tweetID, maxAttempts)
+	return tweets, err
+}
+
+// GetCompleteThreadWithTombstones 与 GetCompleteThread 相同，同时返回推文串中缺失的推文
+// （已删除、受保护等），Position 为其在返回的推文中的位置
+func GetCompleteThreadWithTombstones(ctx context.Context, scraper XScraperInterface, tweetID string, maxAttempts int) ([]*Tweet, []Tombstone, error) {
 	if maxAttempts <= 0 {
 		maxAttempts = 10 // 默认最大尝试次数
 	}
@@ -17,12 +23,34 @@ func GetCompleteThread(ctx context.Context, scraper XScraperInterface, tweetID s
 	seenTweetIDs := make(map[string]bool) // 去重
 	attempts := 0
 
+	// 缺失的推文，以及其后面的推文的 ID（为空表示位于末尾），排序后用于计算位置
+	var tombstones []Tombstone
+	var anchors []string
+	seenTombstones := make(map[string]bool)
+	addTombstone := func(tombstone Tombstone, anchor string) {
+		key := tombstone.TweetID
+		if key == "" {
+			key = "before:" + anchor
+		}
+		if seenTombstones[key] {
+			return
+		}
+		seenTombstones[key] = true
+		tombstones = append(tombstones, tombstone)
+		anchors = append(anchors, anchor)
+	}
+
 	for attempts < maxAttempts {
 		attempts++
 
 		tweetsResult, err := scraper.GetTweets(ctx, tweetID)
 		if err != nil {
-			return nil, fmt.Errorf("attempt %d failed: %w", attempts, err)
+			// 父级推文不可用时，推文串从这里断开，记录缺失的推文并返回已获取的部分
+			if reason, ok := IsTweetUnavailable(err); ok && len(allTweets) > 0 {
+				addTombstone(Tombstone{TweetID: tweetID, Reason: reason, Message: unavailableMessage(err)}, oldestTweetID(allTweets))
+				break
+			}
+			return nil, nil, fmt.Errorf("attempt %d failed: %w", attempts, err)
 		}
 
 		// 截断并添加新推文（去重）- 合并逻辑
@@ -30,7 +58,8 @@ func GetCompleteThread(ctx context.Context, scraper XScraperInterface, tweetID s
 		if len(tweetsResult.Tweets) > 0 {
 			oldestTweet = tweetsResult.Tweets[0]
 		}
-		for _, tweet := range tweetsResult.Tweets {
+		focal := len(tweetsResult.Tweets) - 1
+		for i, tweet := range tweetsResult.Tweets {
 			// 添加新推文（去重）
 			if tweet.RestID != "" && !seenTweetIDs[tweet.RestID] {
 				allTweets = append(allTweets, tweet)
@@ -38,10 +67,26 @@ func GetCompleteThread(ctx context.Context, scraper XScraperInterface, tweetID s
 			}
 			// 如果找到目标推文，截断到这里
 			if tweet.RestID == tweetID {
+				focal = i
 				break
 			}
 		}
 
+		// 目标推文之前的墓碑属于推文串，之后的属于回复
+		for _, tombstone := range tweetsResult.Tombstones {
+			if tombstone.TweetID == tweetID {
+				return nil, nil, tombstone.Err()
+			}
+			if tombstone.Position > focal {
+				continue
+			}
+			anchor := ""
+			if tombstone.Position < len(tweetsResult.Tweets) {
+				anchor = tweetsResult.Tweets[tombstone.Position].RestID
+			}
+			addTombstone(*tombstone, anchor)
+		}
+
 		// 如果已完整，返回结果
 		if tweetsResult.IsComplete || len(tweetsResult.Tweets) == 0 {
 			break
@@ -63,10 +108,32 @@ func GetCompleteThread(ctx context.Context, scraper XScraperInterface, tweetID s
 		tweetID = oldestTweet.InReplyToStatusID
 	}
 
-	// 使用 RestID 升序排序后返回（RestID 越小代表越早的推文）
-	sort.Slice(allTweets, func(i, j int) bool {
-		return allTweets[i].RestID < allTweets[j].RestID
-	})
+	// 按 RestID 的数值升序排序后返回（ID 越小代表越早的推文；长度不同的 ID 不能按字符串比较）
+	SortSnowflake(allTweets)
+
+	// 墓碑的位置为其后面的推文在排序后的位置
+	index := make(map[string]int, len(allTweets))
+	for i, tweet := range allTweets {
+		index[tweet.RestID] = i
+	}
+	for i := range tombstones {
+		position, ok := index[anchors[i]]
+		if !ok {
+			position = len(allTweets)
+		}
+		tombstones[i].Position = position
+	}
+
+	return allTweets, tombstones, nil
+}
 
-	return allTweets, nil
+// oldestTweetID 返回 ID 最小的推文的 ID
+func oldestTweetID(tweets []*Tweet) string {
+	oldest := tweets[0]
+	for _, tweet := range tweets[1:] {
+		if CompareSnowflake(tweet.RestID, oldest.RestID) < 0 {
+			oldest = tweet
+		}
+	}
+	return oldest.RestID
 }
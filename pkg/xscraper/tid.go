@@ -24,6 +24,14 @@ var (
 	transactionIDPairs               = []TransactionIDPair{}
 	transactionIDPairsMu             sync.RWMutex
 	transactionIDPairsUpdateInterval = 30 * time.Minute // 每30分钟更新一次
+
+	// transactionIDPairsFetchMu serialises the first fetch, which happens on
+	// the first signed request rather than at init so importing the package
+	// needs no network
+	transactionIDPairsFetchMu       sync.Mutex
+	transactionIDPairsLastAttempt   time.Time
+	transactionIDPairsRetryInterval = time.Minute
+	transactionIDPairsUpdater       sync.Once
 )
 
 // fetchTransactionIDPairs 从远程获取并解析transaction ID pairs
@@ -68,6 +76,28 @@ func fetchTransactionIDPairs() error {
 	return nil
 }
 
+// SetTransactionIDPairs replaces the pairs transaction IDs are generated
+// from, for tests against a fake X server, which does not check them. It
+// returns the pairs replaced, to be restored when the test ends.
+func SetTransactionIDPairs(pairs []TransactionIDPair) ([]TransactionIDPair, error) {
+	for i := range pairs {
+		if pairs[i].VerificationBase64Decoded != nil {
+			continue
+		}
+		var err error
+		pairs[i].VerificationBase64Decoded, err = base64.StdEncoding.DecodeString(pairs[i].Verification)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 verification %s: %w", pairs[i].AnimationKey, err)
+		}
+	}
+
+	transactionIDPairsMu.Lock()
+	defer transactionIDPairsMu.Unlock()
+	prev := transactionIDPairs
+	transactionIDPairs = pairs
+	return prev, nil
+}
+
 // updateTransactionIDPairs 定时更新transaction ID pairs
 func updateTransactionIDPairs() {
 	ticker := time.NewTicker(transactionIDPairsUpdateInterval)
@@ -80,26 +110,57 @@ func updateTransactionIDPairs() {
 	}
 }
 
-func init() {
-	// 初始化时获取数据
-	if err := fetchTransactionIDPairs(); err != nil {
-		panic(err)
+// seedTransactionIDPairs installs a placeholder pair unless pairs are
+// already loaded, for cassette replay, where X never checks the transaction ID
+func seedTransactionIDPairs() {
+	transactionIDPairsMu.Lock()
+	defer transactionIDPairsMu.Unlock()
+	if len(transactionIDPairs) == 0 {
+		transactionIDPairs = []TransactionIDPair{{AnimationKey: "replay", VerificationBase64Decoded: []byte("replay")}}
 	}
-
-	// 启动定时更新goroutine
-	go updateTransactionIDPairs()
 }
 
-func xClientTransactionID(method, path string) string {
+// ensureTransactionIDPairs fetches the pairs on first use and starts the
+// periodic refresh. A failed fetch is retried after
+// transactionIDPairsRetryInterval rather than on every request
+func ensureTransactionIDPairs() error {
 	transactionIDPairsMu.RLock()
-	if len(transactionIDPairs) == 0 {
-		transactionIDPairsMu.RUnlock()
-		panic("no transaction ID pairs available")
+	loaded := len(transactionIDPairs) > 0
+	transactionIDPairsMu.RUnlock()
+	if loaded {
+		return nil
 	}
+
+	transactionIDPairsFetchMu.Lock()
+	defer transactionIDPairsFetchMu.Unlock()
+	transactionIDPairsMu.RLock()
+	loaded = len(transactionIDPairs) > 0
+	transactionIDPairsMu.RUnlock()
+	if loaded {
+		return nil
+	}
+	if time.Since(transactionIDPairsLastAttempt) < transactionIDPairsRetryInterval {
+		return fmt.Errorf("no transaction ID pairs available")
+	}
+	transactionIDPairsLastAttempt = time.Now()
+	if err := fetchTransactionIDPairs(); err != nil {
+		return err
+	}
+	transactionIDPairsUpdater.Do(func() { go updateTransactionIDPairs() })
+	return nil
+}
+
+// xClientTransactionID returns a transaction ID for the request, or an error
+// when no pairs could be loaded, in which case the header is left out
+func xClientTransactionID(method, path string) (string, error) {
+	if err := ensureTransactionIDPairs(); err != nil {
+		return "", err
+	}
+	transactionIDPairsMu.RLock()
 	pair := transactionIDPairs[rand.Intn(len(transactionIDPairs))]
 	transactionIDPairsMu.RUnlock()
 
-	return generateTransactionId(method, path, pair.VerificationBase64Decoded, pair.AnimationKey)
+	return generateTransactionId(method, path, pair.VerificationBase64Decoded, pair.AnimationKey), nil
 }
 
 // generateTransactionId generates a unique transaction ID.
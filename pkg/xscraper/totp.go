@@ -0,0 +1,85 @@
+package xscraper
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	totpStep   = 30 * time.Second
+	totpDigits = 6
+)
+
+// GenerateTOTP returns the RFC 6238 code of secret at t, as authenticator apps
+// compute it for X: HMAC-SHA1 over 30 second steps, 6 digits. secret is the
+// base32 key X shows when two-factor authentication is set up; spaces and
+// case are ignored.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", fmt.Errorf("decode TOTP secret: %w", err)
+	}
+	if len(key) == 0 {
+		return "", fmt.Errorf("empty TOTP secret")
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(totpStep/time.Second)))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, code%1_000_000), nil
+}
+
+// ChallengeKind is a login challenge answered with a code from the operator
+type ChallengeKind string
+
+const (
+	// ChallengeBackupCode is a two-factor challenge the account's TOTP secret,
+	// if any, did not satisfy; a backup code or a current code is expected
+	ChallengeBackupCode ChallengeKind = "backup_code"
+	// ChallengeEmailCode is the confirmation code X emails to the account
+	ChallengeEmailCode ChallengeKind = "email_code"
+)
+
+// ChallengeCodeFunc returns the code answering a login challenge of the
+// account of username. An error stops the login with a LoginChallengeError.
+type ChallengeCodeFunc func(ctx context.Context, username string, kind ChallengeKind) (string, error)
+
+// CommandChallengeCode returns a ChallengeCodeFunc running command with sh,
+// such as a script reading the code from a mailbox. The username and kind
+// are passed in the THREADMIRROR_USERNAME and THREADMIRROR_CHALLENGE
+// environment variables; the code is the first line of its output.
+func CommandChallengeCode(command string) ChallengeCodeFunc {
+	return func(ctx context.Context, username string, kind ChallengeKind) (string, error) {
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(os.Environ(),
+			"THREADMIRROR_USERNAME="+username,
+			"THREADMIRROR_CHALLENGE="+string(kind),
+		)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("run challenge code command: %w", err)
+		}
+		code, _, _ := strings.Cut(string(out), "\n")
+		code = strings.TrimSpace(code)
+		if code == "" {
+			return "", fmt.Errorf("challenge code command printed no code")
+		}
+		return code, nil
+	}
+}
@@ -0,0 +1,162 @@
+package xscraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/xrate"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	// RFC 6238 appendix B SHA1 vectors, last 6 of the 8 digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // "12345678901234567890"
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		code, err := GenerateTOTP(secret, time.Unix(tc.unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, tc.code, code, tc.unix)
+	}
+
+	code, err := GenerateTOTP("gezd gnbv gy3t qojq gezd gnbv gy3t qojq", time.Unix(59, 0))
+	require.NoError(t, err)
+	require.Equal(t, "287082", code)
+
+	_, err = GenerateTOTP("not base32!", time.Now())
+	require.Error(t, err)
+	_, err = New(LoginOptions{TOTPSecret: "1"}, slog.Default())
+	require.Error(t, err)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeLoginFlow serves the login flow of X, asking for the given subtasks
+// after the password and checking the text entered for each
+func fakeLoginFlow(t *testing.T, challenges []string, answers []string) http.RoundTripper {
+	step := 0
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		switch req.URL.Path {
+		case "/1.1/onboarding/task.json":
+			var body flowTaskRequest
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			next := "LoginEnterUserIdentifierSSO"
+			if len(body.SubtaskInputs) > 0 {
+				input := body.SubtaskInputs[0]
+				switch input["subtask_id"] {
+				case "LoginEnterUserIdentifierSSO":
+					next = "LoginEnterPassword"
+				case "LoginEnterPassword", "LoginTwoFactorAuthChallenge", "LoginAcid":
+					if input["subtask_id"] != "LoginEnterPassword" {
+						require.Equal(t, answers[step], input["enter_text"].(map[string]any)["text"])
+						step++
+					}
+					next = "LoginSuccessSubtask"
+					if step < len(challenges) {
+						next = challenges[step]
+					}
+				}
+			} else if body.FlowToken != "" {
+				http.SetCookie(rec, &http.Cookie{Name: "auth_token", Value: "token"})
+				next = ""
+			}
+			_ = json.NewEncoder(rec).Encode(map[string]any{
+				"flow_token": "flow",
+				"subtasks":   []map[string]any{{"subtask_id": next}},
+			})
+		case "/1.1/guest/activate.json":
+			_ = json.NewEncoder(rec).Encode(map[string]any{"guest_token": "guest"})
+		}
+		return rec.Result(), nil
+	})
+}
+
+func TestLoginChallenges(t *testing.T) {
+	delay := loginStepDelay
+	loginStepDelay = func() time.Duration { return 0 }
+	t.Cleanup(func() { loginStepDelay = delay })
+	seedTransactionIDPairs()
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	login := func(opts LoginOptions, transport http.RoundTripper) (bool, error) {
+		saved := false
+		opts.Username, opts.Email, opts.Password = "bot", "bot@example.com", "password"
+		opts.SaveCookies = func(context.Context, []*http.Cookie) error {
+			saved = true
+			return nil
+		}
+		x, err := New(opts, slog.Default())
+		require.NoError(t, err)
+		x.rateLimiter = xrate.NewLimiter(xrate.Inf, 1)
+		x.xPrivateApiClient.Transport = transport
+		err = (&authHandler{scraper: x}).tryLogin(context.Background())
+		return saved, err
+	}
+
+	var asked []ChallengeKind
+	operator := func(_ context.Context, username string, kind ChallengeKind) (string, error) {
+		require.Equal(t, "bot", username)
+		asked = append(asked, kind)
+		return string(kind) + "-1", nil
+	}
+
+	// TOTP accepted; stay clear of the end of a step
+	if time.Now().Unix()%30 == 29 {
+		time.Sleep(time.Second)
+	}
+	totp, err := GenerateTOTP(secret, time.Now())
+	require.NoError(t, err)
+	saved, err := login(LoginOptions{TOTPSecret: secret},
+		fakeLoginFlow(t, []string{"LoginTwoFactorAuthChallenge"}, []string{totp}))
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	// Backup code after no TOTP secret, then an emailed code
+	saved, err = login(LoginOptions{ChallengeCode: operator}, fakeLoginFlow(t,
+		[]string{"LoginTwoFactorAuthChallenge", "LoginAcid", "LoginAcid"},
+		[]string{"backup_code-1", "bot@example.com", "email_code-1"}))
+	require.NoError(t, err)
+	require.True(t, saved)
+	require.Equal(t, []ChallengeKind{ChallengeBackupCode, ChallengeEmailCode}, asked)
+
+	// Without an operator the emailed code stops the login
+	_, err = login(LoginOptions{}, fakeLoginFlow(t,
+		[]string{"LoginAcid", "LoginAcid"}, []string{"bot@example.com"}))
+	var challenge *LoginChallengeError
+	require.ErrorAs(t, err, &challenge)
+	require.Equal(t, "LoginAcid", challenge.Subtask)
+
+	// An operator error stops it too
+	_, err = login(LoginOptions{ChallengeCode: func(context.Context, string, ChallengeKind) (string, error) {
+		return "", errors.New("no code")
+	}}, fakeLoginFlow(t, []string{"LoginTwoFactorAuthChallenge"}, nil))
+	require.ErrorAs(t, err, &challenge)
+	require.Equal(t, "LoginTwoFactorAuthChallenge", challenge.Subtask)
+	require.ErrorContains(t, err, "no code")
+}
+
+func TestCommandChallengeCode(t *testing.T) {
+	code, err := CommandChallengeCode(`echo "$THREADMIRROR_CHALLENGE-$THREADMIRROR_USERNAME"; echo ignored`)(context.Background(), "bot", ChallengeEmailCode)
+	require.NoError(t, err)
+	require.Equal(t, "email_code-bot", code)
+
+	_, err = CommandChallengeCode("true")(context.Background(), "bot", ChallengeEmailCode)
+	require.Error(t, err)
+	_, err = CommandChallengeCode("exit 1")(context.Background(), "bot", ChallengeEmailCode)
+	require.Error(t, err)
+}
@@ -0,0 +1,189 @@
+package xscraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// CompareSnowflake compares two tweet IDs in the order the tweets were posted.
+// Tweet IDs are snowflakes, decimal numbers that grow with time, so they are
+// ordered by length first; comparing them as plain strings puts "9" after "10".
+func CompareSnowflake(a, b string) int {
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// SortSnowflake sorts tweets oldest first by their IDs
+func SortSnowflake(tweets []*Tweet) {
+	slices.SortStableFunc(tweets, func(a, b *Tweet) int { return CompareSnowflake(a.RestID, b.RestID) })
+}
+
+// ThreadNode is a tweet in a ThreadTree, or a gap where a tweet is missing
+type ThreadNode struct {
+	// ID is the tweet ID, also known for gaps from the replies to them
+	ID string `json:"id"`
+	// Tweet is nil for a gap
+	Tweet *Tweet `json:"tweet,omitempty"`
+	// Parent is the tweet this one replies to; nil for the root and for gaps,
+	// whose place in the tree is unknown
+	Parent *ThreadNode `json:"-"`
+	// Children are the replies to the tweet, oldest first
+	Children []*ThreadNode `json:"children,omitempty"`
+	// AuthorChain marks the canonical thread: the root and each following
+	// reply by the root's author to the previous tweet of the chain
+	AuthorChain bool `json:"author_chain"`
+}
+
+// IsGap reports whether the tweet is missing from the tree, most likely
+// because it was deleted
+func (n *ThreadNode) IsGap() bool {
+	return n.Tweet == nil
+}
+
+// ThreadTree is the reply tree of a thread or conversation, built from the
+// InReplyToStatusID of its tweets
+type ThreadTree struct {
+	// Root is the oldest tweet; nil for a tree without tweets. Tweets it
+	// replies to are outside the tree and are not gaps.
+	Root *ThreadNode
+	// Gaps are the tweets replied to inside the tree but missing from it,
+	// oldest first. The replies to a gap hang off it.
+	Gaps []*ThreadNode
+
+	nodes  map[string]*ThreadNode
+	tweets []*Tweet
+}
+
+// NewThreadTree builds the reply tree of tweets, in any order. Tweets without
+// an ID and repeated tweets are skipped. A tweet other than the root that
+// replies to nothing is kept as a reply to the root.
+func NewThreadTree(tweets []*Tweet) *ThreadTree {
+	t := &ThreadTree{nodes: make(map[string]*ThreadNode)}
+	for _, tweet := range tweets {
+		if tweet == nil || tweet.RestID == "" || t.nodes[tweet.RestID] != nil {
+			continue
+		}
+		t.nodes[tweet.RestID] = &ThreadNode{ID: tweet.RestID, Tweet: tweet}
+		t.tweets = append(t.tweets, tweet)
+	}
+	if len(t.tweets) == 0 {
+		return t
+	}
+	SortSnowflake(t.tweets)
+	t.Root = t.nodes[t.tweets[0].RestID]
+
+	for _, tweet := range t.tweets[1:] {
+		node := t.nodes[tweet.RestID]
+		parentID := tweet.InReplyToStatusID
+		parent := t.nodes[parentID]
+		switch {
+		case parentID == "":
+			parent = t.Root
+		case parent == nil:
+			parent = &ThreadNode{ID: parentID}
+			t.nodes[parentID] = parent
+			t.Gaps = append(t.Gaps, parent)
+		}
+		parent.Children = append(parent.Children, node)
+		if !parent.IsGap() {
+			node.Parent = parent
+		}
+	}
+	slices.SortFunc(t.Gaps, func(a, b *ThreadNode) int { return CompareSnowflake(a.ID, b.ID) })
+
+	t.markAuthorChain()
+	return t
+}
+
+// markAuthorChain marks the canonical author chain from the root. The chain
+// continues across a gap newer than its last tweet when the author replied to
+// the missing tweet, so a deleted tweet does not cut a thread short.
+func (t *ThreadTree) markAuthorChain() {
+	author := t.Root.Tweet.Author
+	tip := t.Root
+	tip.AuthorChain = true
+	for {
+		next := firstReplyBy(tip, author)
+		if next == nil {
+			for _, gap := range t.Gaps {
+				if gap.AuthorChain || CompareSnowflake(gap.ID, tip.ID) <= 0 {
+					continue
+				}
+				if next = firstReplyBy(gap, author); next != nil {
+					gap.AuthorChain = true
+					break
+				}
+			}
+		}
+		if next == nil {
+			return
+		}
+		next.AuthorChain = true
+		tip = next
+	}
+}
+
+// firstReplyBy returns the oldest reply to node by author
+func firstReplyBy(node *ThreadNode, author *User) *ThreadNode {
+	if author == nil {
+		return nil
+	}
+	for _, child := range node.Children {
+		if child.Tweet.Author != nil && child.Tweet.Author.RestID == author.RestID {
+			return child
+		}
+	}
+	return nil
+}
+
+// Node returns the node of a tweet or gap by ID
+func (t *ThreadTree) Node(id string) (*ThreadNode, bool) {
+	node, ok := t.nodes[id]
+	return node, ok
+}
+
+// Len returns the number of tweets in the tree, not counting gaps
+func (t *ThreadTree) Len() int {
+	return len(t.tweets)
+}
+
+// Tweets returns the tweets of the tree oldest first. Every reply comes after
+// the tweet it replies to.
+func (t *ThreadTree) Tweets() []*Tweet {
+	return t.tweets
+}
+
+// AuthorChain returns the tweets of the canonical author chain, oldest first
+func (t *ThreadTree) AuthorChain() []*Tweet {
+	var chain []*Tweet
+	for _, tweet := range t.tweets {
+		if t.nodes[tweet.RestID].AuthorChain {
+			chain = append(chain, tweet)
+		}
+	}
+	return chain
+}
+
+// Nodes returns the tree depth first, replies oldest first: the root and the
+// replies under it, then each gap and the replies under it
+func (t *ThreadTree) Nodes() []*ThreadNode {
+	if t.Root == nil {
+		return nil
+	}
+	nodes := make([]*ThreadNode, 0, len(t.nodes))
+	var walk func(n *ThreadNode)
+	walk = func(n *ThreadNode) {
+		nodes = append(nodes, n)
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(t.Root)
+	for _, gap := range t.Gaps {
+		walk(gap)
+	}
+	return nodes
+}
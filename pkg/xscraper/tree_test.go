@@ -0,0 +1,87 @@
+package xscraper
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSnowflake(t *testing.T) {
+	require.Equal(t, -1, CompareSnowflake("9", "10"))
+	require.Equal(t, 1, CompareSnowflake("1000000000000000001", "999999999999999999"))
+	require.Equal(t, 0, CompareSnowflake("42", "42"))
+
+	tweets := []*Tweet{{RestID: "100"}, {RestID: "99"}, {RestID: "1000"}, {RestID: "101"}}
+	SortSnowflake(tweets)
+	require.Equal(t, []string{"99", "100", "101", "1000"}, tweetIDs(tweets))
+}
+
+func tweetIDs(tweets []*Tweet) []string {
+	return lo.Map(tweets, func(t *Tweet, _ int) string { return t.RestID })
+}
+
+func nodeIDs(nodes []*ThreadNode) []string {
+	return lo.Map(nodes, func(n *ThreadNode, _ int) string { return n.ID })
+}
+
+func TestThreadTree(t *testing.T) {
+	alice := &User{RestID: "a"}
+	bob := &User{RestID: "b"}
+	by := func(tweet *Tweet, author *User) *Tweet {
+		tweet.Author = author
+		return tweet
+	}
+
+	// Alice's thread 98 -> 99 -> 100 -> (deleted 105) -> 110, with Bob
+	// replying to 99 and to the missing 105
+	tree := NewThreadTree([]*Tweet{
+		by(reply("110", "105", 0, 0), alice),
+		by(reply("100", "99", 0, 0), alice),
+		by(reply("98", "", 0, 0), alice),
+		by(reply("101", "99", 0, 0), bob),
+		by(reply("99", "98", 0, 0), alice),
+		by(reply("106", "105", 0, 0), bob),
+		by(reply("99", "98", 0, 0), alice),
+	})
+
+	require.Equal(t, 6, tree.Len())
+	require.Equal(t, []string{"98", "99", "100", "101", "106", "110"}, tweetIDs(tree.Tweets()))
+	require.Equal(t, "98", tree.Root.ID)
+	require.Nil(t, tree.Root.Parent)
+
+	require.Len(t, tree.Gaps, 1)
+	gap := tree.Gaps[0]
+	require.True(t, gap.IsGap())
+	require.Equal(t, "105", gap.ID)
+	require.Equal(t, []string{"106", "110"}, nodeIDs(gap.Children))
+
+	node, ok := tree.Node("110")
+	require.True(t, ok)
+	require.Nil(t, node.Parent)
+	node, ok = tree.Node("101")
+	require.True(t, ok)
+	require.Equal(t, "99", node.Parent.ID)
+
+	// The chain continues across the gap
+	require.Equal(t, []string{"98", "99", "100", "110"}, tweetIDs(tree.AuthorChain()))
+	require.True(t, gap.AuthorChain)
+
+	require.Equal(t, []string{"98", "99", "100", "101", "105", "106", "110"}, nodeIDs(tree.Nodes()))
+}
+
+func TestThreadTreeRootReply(t *testing.T) {
+	// A conversation under a reply: the tweet the root replies to is outside
+	// the tree rather than a gap
+	tree := NewThreadTree([]*Tweet{reply("10", "5", 0, 0), reply("11", "10", 0, 0), reply("12", "", 0, 0)})
+	require.Empty(t, tree.Gaps)
+	require.Equal(t, "10", tree.Root.ID)
+	require.Equal(t, []string{"11", "12"}, nodeIDs(tree.Root.Children))
+	// Without an author only the root is on the chain
+	require.Equal(t, []string{"10"}, tweetIDs(tree.AuthorChain()))
+
+	empty := NewThreadTree(nil)
+	require.Nil(t, empty.Root)
+	require.Empty(t, empty.Nodes())
+	require.Zero(t, empty.Len())
+}
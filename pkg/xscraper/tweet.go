@@ -68,6 +68,9 @@ func (x *XScraper) GetTweets(ctx context.Context, id string) (*TweetsResult, err
 
 	// Handle GraphQL-level errors
 	if resp.Errors != nil && len(*resp.Errors) > 0 {
+		if err := unavailableFromErrors(id, *resp.Errors); err != nil {
+			return nil, err
+		}
 		msgs := lo.Map(*resp.Errors, func(e generated.ErrorResponse, _ int) string { return e.Message })
 		return nil, fmt.Errorf("tweet detail: %s", strings.Join(msgs, "; "))
 	}
@@ -78,6 +81,9 @@ func (x *XScraper) GetTweets(ctx context.Context, id string) (*TweetsResult, err
 	}
 
 	if len(tweetsResult.Tweets) == 0 {
+		if tombstone, ok := lo.Find(tweetsResult.Tombstones, func(t *Tombstone) bool { return t.TweetID == id }); ok {
+			return nil, tombstone.Err()
+		}
 		return nil, fmt.Errorf("no tweet found")
 	}
 
@@ -87,7 +93,37 @@ func (x *XScraper) GetTweets(ctx context.Context, id string) (*TweetsResult, err
 // GetTweetDetail 调用 TweetDetail GraphQL接口，返回目标推文及其线程（可能包含多条推文）。
 // 参数仅需 tweet `id`，其余字段按 OpenAPI 默认/示例填充。
 func (x *XScraper) GetTweetDetail(ctx context.Context, id string) ([]*Tweet, error) {
-	p := generated.GetTweetDetailParams{}
+	p := newTweetDetailParams(id)
+
+	var resp generated.TweetDetailResponse
+	var berr *BadRequestError
+	err := x.GetGraphQL(ctx, "/i/api/graphql/xd_EMdYvB9hfZsZ6Idri0w/TweetDetail", p, &resp)
+	if err != nil {
+		if errors.As(err, &berr) && berr.StatusCode == http.StatusNotFound {
+			return []*Tweet{}, nil
+		}
+		return nil, fmt.Errorf("failed to get tweet detail: %w", err)
+	}
+
+	// Handle GraphQL-level errors
+	if resp.Errors != nil && len(*resp.Errors) > 0 {
+		if err := unavailableFromErrors(id, *resp.Errors); err != nil {
+			return nil, err
+		}
+		msgs := lo.Map(*resp.Errors, func(e generated.ErrorResponse, _ int) string { return e.Message })
+		return nil, fmt.Errorf("tweet detail: %s", strings.Join(msgs, "; "))
+	}
+
+	tweetsResult, err := convertTimelineToTweets(resp.Data.ThreadedConversationWithInjectionsV2)
+	if err != nil {
+		return nil, fmt.Errorf("convert tweet: %w", err)
+	}
+	return tweetsResult.Tweets, nil
+}
+
+// newTweetDetailParams returns the TweetDetail parameters focused on tweet id
+func newTweetDetailParams(id string) *generated.GetTweetDetailParams {
+	p := &generated.GetTweetDetailParams{}
 
 	// Variables
 	p.Variables.FocalTweetId = id
@@ -138,27 +174,7 @@ func (x *XScraper) GetTweetDetail(ctx context.Context, id string) ([]*Tweet, err
 	p.FieldToggles.WithGrokAnalyze = false
 	p.FieldToggles.WithDisallowedReplyControls = false
 
-	var resp generated.TweetDetailResponse
-	var berr *BadRequestError
-	err := x.GetGraphQL(ctx, "/i/api/graphql/xd_EMdYvB9hfZsZ6Idri0w/TweetDetail", &p, &resp)
-	if err != nil {
-		if errors.As(err, &berr) && berr.StatusCode == http.StatusNotFound {
-			return []*Tweet{}, nil
-		}
-		return nil, fmt.Errorf("failed to get tweet detail: %w", err)
-	}
-
-	// Handle GraphQL-level errors
-	if resp.Errors != nil && len(*resp.Errors) > 0 {
-		msgs := lo.Map(*resp.Errors, func(e generated.ErrorResponse, _ int) string { return e.Message })
-		return nil, fmt.Errorf("tweet detail: %s", strings.Join(msgs, "; "))
-	}
-
-	tweetsResult, err := convertTimelineToTweets(resp.Data.ThreadedConversationWithInjectionsV2)
-	if err != nil {
-		return nil, fmt.Errorf("convert tweet: %w", err)
-	}
-	return tweetsResult.Tweets, nil
+	return p
 }
 
 // GetTweetDetail returns the tweet with the given ID
@@ -212,6 +228,9 @@ func (x *XScraper) GetTweetResultByRestId(ctx context.Context, id string) (*Twee
 
 	// Handle GraphQL-level errors
 	if resp.Errors != nil && len(*resp.Errors) > 0 {
+		if err := unavailableFromErrors(id, *resp.Errors); err != nil {
+			return nil, err
+		}
 		msgs := lo.Map(*resp.Errors, func(e generated.ErrorResponse, _ int) string { return e.Message })
 		return nil, fmt.Errorf("tweet detail: %s", strings.Join(msgs, "; "))
 	}
@@ -219,6 +238,9 @@ func (x *XScraper) GetTweetResultByRestId(ctx context.Context, id string) (*Twee
 	if resp.Data.TweetResult == nil || resp.Data.TweetResult.Result == nil {
 		return nil, fmt.Errorf("no tweet found")
 	}
+	if tombstone, ok := tombstoneFromTweetUnion(id, resp.Data.TweetResult.Result); ok {
+		return nil, tombstone.Err()
+	}
 
 	genTweet, err := resp.Data.TweetResult.Result.AsTweet()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/samber/lo"
 )
 
 // isHashtagEqual compares two hashtags for equality based on text field
@@ -193,6 +194,8 @@ type User struct {
 	CreatedAt       time.Time `json:"created_at"`
 	Verified        bool      `json:"verified"`
 	IsBlueVerified  bool      `json:"is_blue_verified"`
+	// ProfileImageCID is the CID of the archived copy of ProfileImageURL, if any
+	ProfileImageCID string `json:"profile_image_cid,omitempty"`
 }
 
 // TweetStats represents tweet engagement statistics
@@ -230,6 +233,70 @@ type Tweet struct {
 	IsNoteTweet       bool                               `json:"is_note_tweet"`
 	RichText          *generated.NoteTweetResultRichText `json:"richtext,omitempty"`
 	DisplayTextRange  []int                              `json:"display_text_range,omitempty"`
+	ArchivedMedia     []ArchivedMedia                    `json:"archived_media,omitempty"`
+	Poll              *Poll                              `json:"poll,omitempty"`
+	Card              *LinkCard                          `json:"card,omitempty"`
+	Space             *Space                             `json:"space,omitempty"`
+	Community         *Community                         `json:"community,omitempty"`
+}
+
+// ArchivedMedia records a remote media file that has been copied into storage
+type ArchivedMedia struct {
+	URL         string `json:"url"`
+	CID         string `json:"cid"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size"`
+}
+
+// MediaURLs returns the URLs of all photos, video posters and mp4 video variants
+// attached to the tweet, and the images of its link card and poll, without
+// duplicates. HLS playlists are skipped because they only reference segments
+// that are not archived.
+func (t *Tweet) MediaURLs() []string {
+	seen := make(map[string]struct{})
+	var urls []string
+	add := func(u string) {
+		if u == "" {
+			return
+		}
+		if _, ok := seen[u]; ok {
+			return
+		}
+		seen[u] = struct{}{}
+		urls = append(urls, u)
+	}
+
+	for _, m := range lo.FromPtr(t.Entities.Media) {
+		add(m.MediaUrlHttps)
+		if m.VideoInfo == nil {
+			continue
+		}
+		for _, v := range m.VideoInfo.Variants {
+			if v.ContentType == "video/mp4" {
+				add(v.Url)
+			}
+		}
+	}
+	if t.Card != nil {
+		add(t.Card.ImageURL)
+	}
+	if t.Poll != nil {
+		for _, c := range t.Poll.Choices {
+			add(c.ImageURL)
+		}
+	}
+	return urls
+}
+
+// ArchivedCID returns the CID of the archived copy of the given media URL,
+// or an empty string if it has not been archived
+func (t *Tweet) ArchivedCID(url string) string {
+	for _, m := range t.ArchivedMedia {
+		if m.URL == url {
+			return m.CID
+		}
+	}
+	return ""
 }
 
 // GetDisplayableText returns the actual text that should be displayed to users,
@@ -273,6 +340,9 @@ func (t *Tweet) GetDisplayableText() string {
 type TweetsResult struct {
 	Tweets     []*Tweet `json:"tweets"`
 	IsComplete bool     `json:"is_complete"` // true if we've reached the beginning of the thread
+	// Tombstones are the tweets of the timeline X did not serve, positioned
+	// among Tweets
+	Tombstones []*Tombstone `json:"tombstones,omitempty"`
 }
 
 // convertTimelineToTweets converts a generated.Timeline to our TweetsResult struct
@@ -286,15 +356,44 @@ func convertTimelineToTweets(timeline *generated.Timeline) (*TweetsResult, error
 	}
 
 	var tweets []*Tweet
+	var tombstones []*Tombstone
 	isComplete := false
 
-	convertAndAppendTweet := func(timelineTweet *generated.TimelineTweet) error {
-		if timelineTweet.TweetResults.Result == nil {
+	addTombstone := func(tombstone *Tombstone) {
+		tombstone.Position = len(tweets)
+		tombstones = append(tombstones, tombstone)
+	}
+
+	convertAndAppendTweet := func(entryID string, itemContent generated.ItemContentUnion) error {
+		if typename, _ := itemContent.Discriminator(); typename == string(generated.TypeNameTimelineTombstone) {
+			if item, err := itemContent.AsTimelineTombstone(); err == nil {
+				addTombstone(tombstoneFromTimelineTombstone(tweetIDFromEntryID(entryID), item))
+			}
 			return nil
 		}
-		// Try to get Tweet from TweetUnion
-		tweetData, err := timelineTweet.TweetResults.Result.AsTweet()
+		timelineTweet, err := itemContent.AsTimelineTweet()
 		if err != nil {
+			return nil // Skip if not TimelineTweet
+		}
+		result := timelineTweet.TweetResults.Result
+		if result == nil {
+			return nil
+		}
+		if tombstone, ok := tombstoneFromTweetUnion(tweetIDFromEntryID(entryID), result); ok {
+			addTombstone(tombstone)
+			return nil
+		}
+
+		// Try to get Tweet from TweetUnion; tweets with limited actions come
+		// wrapped in their visibility results
+		var tweetData generated.Tweet
+		if typename, _ := result.Discriminator(); typename == string(generated.TypeNameTweetWithVisibilityResults) {
+			wrapped, err := result.AsTweetWithVisibilityResults()
+			if err != nil {
+				return nil
+			}
+			tweetData = wrapped.Tweet
+		} else if tweetData, err = result.AsTweet(); err != nil {
 			return nil
 		}
 
@@ -327,11 +426,7 @@ func convertTimelineToTweets(timeline *generated.Timeline) (*TweetsResult, error
 			// Try to get TimelineTimelineModule
 			if timelineModule, err := entry.Content.AsTimelineTimelineModule(); err == nil && timelineModule.Items != nil {
 				for _, item := range *timelineModule.Items {
-					timelineTweet, err := item.Item.ItemContent.AsTimelineTweet()
-					if err != nil {
-						continue // Skip if not TimelineTimelineItem
-					}
-					if err := convertAndAppendTweet(&timelineTweet); err != nil {
+					if err := convertAndAppendTweet(item.EntryId, item.Item.ItemContent); err != nil {
 						return nil, err
 					}
 				}
@@ -339,12 +434,7 @@ func convertTimelineToTweets(timeline *generated.Timeline) (*TweetsResult, error
 
 			// Try to get TimelineTimelineItem
 			if timelineItem, err := entry.Content.AsTimelineTimelineItem(); err == nil {
-				// Try to get TimelineTweet from ItemContent
-				timelineTweet, err := timelineItem.ItemContent.AsTimelineTweet()
-				if err != nil {
-					continue // Skip if not TimelineTweet
-				}
-				if err := convertAndAppendTweet(&timelineTweet); err != nil {
+				if err := convertAndAppendTweet(entry.EntryId, timelineItem.ItemContent); err != nil {
 					return nil, err
 				}
 			}
@@ -354,6 +444,7 @@ func convertTimelineToTweets(timeline *generated.Timeline) (*TweetsResult, error
 	return &TweetsResult{
 		Tweets:     tweets,
 		IsComplete: isComplete,
+		Tombstones: tombstones,
 	}, nil
 }
 
@@ -447,6 +538,10 @@ func convertGeneratedTweetToTweet(genTweet *generated.Tweet) (*Tweet, error) {
 		}
 	}
 
+	// Polls, link previews and Spaces are cards
+	applyCard(tweet, genTweet.Card)
+	tweet.Community = convertCommunity(genTweet)
+
 	// Handle quoted tweet if present
 	if genTweet.QuotedStatusResult != nil && genTweet.QuotedStatusResult.Result != nil {
 		quotedTweet, err := genTweet.QuotedStatusResult.Result.AsTweet()
@@ -338,3 +338,55 @@ func TestTimeParsingEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestTweetMediaURLs(t *testing.T) {
+	bitrate := 832000
+	media := []generated.Media{
+		{
+			IdStr:         "1",
+			MediaUrlHttps: "https://pbs.twimg.com/media/photo.jpg",
+		},
+		{
+			IdStr:         "2",
+			MediaUrlHttps: "https://pbs.twimg.com/ext_tw_video_thumb/poster.jpg",
+			VideoInfo: &generated.MediaVideoInfo{
+				Variants: []generated.MediaVideoInfoVariant{
+					{ContentType: "application/x-mpegURL", Url: "https://video.twimg.com/pl/playlist.m3u8"},
+					{ContentType: "video/mp4", Url: "https://video.twimg.com/vid/480x270/video.mp4", Bitrate: &bitrate},
+				},
+			},
+		},
+		{
+			IdStr:         "3",
+			MediaUrlHttps: "https://pbs.twimg.com/media/photo.jpg",
+		},
+	}
+	tweet := &Tweet{Entities: generated.Entities{Media: &media}}
+
+	want := []string{
+		"https://pbs.twimg.com/media/photo.jpg",
+		"https://pbs.twimg.com/ext_tw_video_thumb/poster.jpg",
+		"https://video.twimg.com/vid/480x270/video.mp4",
+	}
+	got := tweet.MediaURLs()
+	if len(got) != len(want) {
+		t.Fatalf("MediaURLs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MediaURLs()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if urls := (&Tweet{}).MediaURLs(); urls != nil {
+		t.Errorf("MediaURLs() on tweet without media = %v, want nil", urls)
+	}
+
+	tweet.ArchivedMedia = []ArchivedMedia{{URL: want[0], CID: "bafy-photo"}}
+	if c := tweet.ArchivedCID(want[0]); c != "bafy-photo" {
+		t.Errorf("ArchivedCID() = %v, want bafy-photo", c)
+	}
+	if c := tweet.ArchivedCID(want[1]); c != "" {
+		t.Errorf("ArchivedCID() for unarchived url = %v, want empty", c)
+	}
+}
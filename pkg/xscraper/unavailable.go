@@ -0,0 +1,237 @@
+package xscraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+)
+
+// UnavailableReason is why X does not serve a tweet
+type UnavailableReason string
+
+const (
+	ReasonDeleted       UnavailableReason = "deleted"
+	ReasonProtected     UnavailableReason = "protected"
+	ReasonSuspended     UnavailableReason = "suspended"
+	ReasonAgeRestricted UnavailableReason = "age_restricted"
+	// ReasonUnavailable covers the tombstones X gives no recognizable reason for
+	ReasonUnavailable UnavailableReason = "unavailable"
+)
+
+// Errors of tweets X does not serve, wrapped by TweetUnavailableError
+var (
+	ErrTweetDeleted       = errors.New("tweet deleted")
+	ErrTweetProtected     = errors.New("tweet protected")
+	ErrAccountSuspended   = errors.New("account suspended")
+	ErrTweetAgeRestricted = errors.New("tweet age-restricted")
+	ErrTweetUnavailable   = errors.New("tweet unavailable")
+)
+
+// Err returns the sentinel error of the reason
+func (r UnavailableReason) Err() error {
+	switch r {
+	case ReasonDeleted:
+		return ErrTweetDeleted
+	case ReasonProtected:
+		return ErrTweetProtected
+	case ReasonSuspended:
+		return ErrAccountSuspended
+	case ReasonAgeRestricted:
+		return ErrTweetAgeRestricted
+	default:
+		return ErrTweetUnavailable
+	}
+}
+
+// Tombstone records a tweet X replaced with a notice, and where in the
+// timeline it was
+type Tombstone struct {
+	// TweetID is the ID of the missing tweet, empty if X did not reveal it
+	TweetID string            `json:"tweet_id,omitempty"`
+	Reason  UnavailableReason `json:"reason"`
+	// Message is the notice X showed instead of the tweet
+	Message string `json:"message,omitempty"`
+	// Position is the number of tweets before the tombstone: in a TweetsResult
+	// the tweets of the result, in a thread the tweets of the thread
+	Position int `json:"position"`
+}
+
+// Err returns the error of fetching the missing tweet
+func (t *Tombstone) Err() error {
+	return &TweetUnavailableError{TweetID: t.TweetID, Reason: t.Reason, Message: t.Message}
+}
+
+// TweetUnavailableError is returned for a tweet X does not serve. It wraps
+// the sentinel error of its reason, such as ErrTweetDeleted. Fetching the
+// tweet again will not succeed, so jobs do not retry it.
+type TweetUnavailableError struct {
+	TweetID string
+	Reason  UnavailableReason
+	Message string
+}
+
+func (e *TweetUnavailableError) Error() string {
+	msg := fmt.Sprintf("tweet %s: %s", e.TweetID, e.Reason.Err())
+	if e.TweetID == "" {
+		msg = e.Reason.Err().Error()
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *TweetUnavailableError) Unwrap() error {
+	return e.Reason.Err()
+}
+
+// Permanent reports that the error does not go away by retrying
+func (e *TweetUnavailableError) Permanent() bool {
+	return true
+}
+
+// IsTweetUnavailable reports whether err is about a tweet X does not serve,
+// and returns its reason
+func IsTweetUnavailable(err error) (UnavailableReason, bool) {
+	var uerr *TweetUnavailableError
+	if !errors.As(err, &uerr) {
+		return "", false
+	}
+	return uerr.Reason, true
+}
+
+// X API error codes of unavailable tweets
+const (
+	errCodeNoStatusFound   = 144
+	errCodeUserSuspended   = 63
+	errCodeNotAuthorized   = 179
+	errCodeUserNotFound    = 50
+	errCodePageDoesntExist = 34
+)
+
+// unavailableFromErrors returns the error of the first GraphQL error about an
+// unavailable tweet, nil if there is none
+func unavailableFromErrors(tweetID string, errs []generated.ErrorResponse) error {
+	for _, e := range errs {
+		var reason UnavailableReason
+		switch e.Code {
+		case errCodeNoStatusFound, errCodePageDoesntExist, errCodeUserNotFound:
+			reason = ReasonDeleted
+		case errCodeUserSuspended:
+			reason = ReasonSuspended
+		case errCodeNotAuthorized:
+			reason = ReasonProtected
+		default:
+			continue
+		}
+		return &TweetUnavailableError{TweetID: tweetID, Reason: reason, Message: e.Message}
+	}
+	return nil
+}
+
+// tombstoneFromTweetUnion returns the tombstone of a tweet result that is
+// not a tweet, and false for tweets
+func tombstoneFromTweetUnion(tweetID string, result *generated.TweetUnion) (*Tombstone, bool) {
+	typename, _ := result.Discriminator()
+	switch typename {
+	case string(generated.TypeNameTweetTombstone):
+		raw, err := result.MarshalJSON()
+		if err != nil {
+			return nil, false
+		}
+		var tombstone struct {
+			Tombstone struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"tombstone"`
+		}
+		_ = json.Unmarshal(raw, &tombstone)
+		message := tombstone.Tombstone.Text.Text
+		return &Tombstone{TweetID: tweetID, Reason: reasonFromMessage(message), Message: message}, true
+	case string(generated.TypeNameTweetUnavailable):
+		unavailable, _ := result.AsTweetUnavailable()
+		reason := ""
+		if unavailable.Reason != nil {
+			reason = *unavailable.Reason
+		}
+		return &Tombstone{TweetID: tweetID, Reason: reasonFromUnavailable(reason), Message: reason}, true
+	default:
+		return nil, false
+	}
+}
+
+// tombstoneFromTimelineTombstone returns the tombstone of a TimelineTombstone
+// item
+func tombstoneFromTimelineTombstone(tweetID string, item generated.TimelineTombstone) *Tombstone {
+	var message string
+	if info := item.TombstoneInfo; info != nil {
+		switch {
+		case info.RichText != nil && info.RichText.Text != nil:
+			message = *info.RichText.Text
+		case info.Text != nil:
+			message = *info.Text
+		}
+	}
+	return &Tombstone{TweetID: tweetID, Reason: reasonFromMessage(message), Message: message}
+}
+
+// reasonFromMessage classifies the notice X shows instead of a tweet, such as
+// "This Post was deleted by the Post author."
+func reasonFromMessage(message string) UnavailableReason {
+	message = strings.ToLower(message)
+	switch {
+	case strings.Contains(message, "suspended"):
+		return ReasonSuspended
+	case strings.Contains(message, "deleted"), strings.Contains(message, "no longer exists"):
+		return ReasonDeleted
+	case strings.Contains(message, "limits who can view"), strings.Contains(message, "protected"):
+		return ReasonProtected
+	case strings.Contains(message, "age-restricted"), strings.Contains(message, "adult content"):
+		return ReasonAgeRestricted
+	default:
+		return ReasonUnavailable
+	}
+}
+
+// reasonFromUnavailable classifies the reason of a TweetUnavailable result
+func reasonFromUnavailable(reason string) UnavailableReason {
+	switch {
+	case reason == "Protected":
+		return ReasonProtected
+	case reason == "Suspended":
+		return ReasonSuspended
+	case reason == "Deleted":
+		return ReasonDeleted
+	case strings.HasPrefix(reason, "Nsfw"):
+		return ReasonAgeRestricted
+	default:
+		return ReasonUnavailable
+	}
+}
+
+// tweetIDFromEntryID returns the tweet ID at the end of a timeline entry ID,
+// as in "tweet-123" and "conversationthread-100-tweet-123"
+func tweetIDFromEntryID(entryID string) string {
+	i := strings.LastIndex(entryID, "tweet-")
+	if i < 0 {
+		return ""
+	}
+	id := entryID[i+len("tweet-"):]
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return ""
+	}
+	return id
+}
+
+// unavailableMessage returns the message X gave for an unavailable tweet
+func unavailableMessage(err error) string {
+	var uerr *TweetUnavailableError
+	if errors.As(err, &uerr) {
+		return uerr.Message
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package xscraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ipfs-force-community/threadmirror/pkg/xscraper/generated"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnavailableFromErrors(t *testing.T) {
+	err := unavailableFromErrors("1", []generated.ErrorResponse{
+		{Code: 214, Message: "Bad request"},
+		{Code: 144, Message: "_Missing: No status found with that ID."},
+	})
+	require.ErrorIs(t, err, ErrTweetDeleted)
+	reason, ok := IsTweetUnavailable(fmt.Errorf("attempt 1 failed: %w", err))
+	require.True(t, ok)
+	require.Equal(t, ReasonDeleted, reason)
+	require.Equal(t, "tweet 1: tweet deleted: _Missing: No status found with that ID.", err.Error())
+
+	require.ErrorIs(t, unavailableFromErrors("1", []generated.ErrorResponse{{Code: 63}}), ErrAccountSuspended)
+	require.ErrorIs(t, unavailableFromErrors("1", []generated.ErrorResponse{{Code: 179}}), ErrTweetProtected)
+	require.NoError(t, unavailableFromErrors("1", []generated.ErrorResponse{{Code: 214}}))
+
+	var permanent interface{ Permanent() bool }
+	require.True(t, errors.As(err, &permanent) && permanent.Permanent())
+}
+
+func TestReasonFromMessage(t *testing.T) {
+	for message, reason := range map[string]UnavailableReason{
+		"This Post was deleted by the Post author. Learn more":                                               ReasonDeleted,
+		"This Post is from a suspended account. Learn more":                                                  ReasonSuspended,
+		"You're unable to view this Post because this account owner limits who can view their Posts.":        ReasonProtected,
+		"This Post is from an account that no longer exists. Learn more":                                     ReasonDeleted,
+		"Age-restricted adult content. This content might not be appropriate for people under 18 years old.": ReasonAgeRestricted,
+		"This Post is unavailable.": ReasonUnavailable,
+	} {
+		require.Equal(t, reason, reasonFromMessage(message), message)
+	}
+}
+
+func TestConvertTimelineTombstones(t *testing.T) {
+	var timeline generated.Timeline
+	require.NoError(t, json.Unmarshal([]byte(`{"instructions": [{
+		"type": "TimelineAddEntries",
+		"entries": [
+			{"entryId": "tweet-100", "sortIndex": "3", "content": {
+				"entryType": "TimelineTimelineItem", "__typename": "TimelineTimelineItem",
+				"itemContent": {"itemType": "TimelineTombstone", "__typename": "TimelineTombstone",
+					"tombstoneInfo": {"text": "", "richText": {"text": "This Post was deleted by the Post author. Learn more"}}}}},
+			{"entryId": "conversationthread-1", "sortIndex": "2", "content": {
+				"entryType": "TimelineTimelineModule", "__typename": "TimelineTimelineModule", "displayType": "VerticalConversation",
+				"items": [
+					{"entryId": "conversationthread-1-tweet-200", "item": {"itemContent": {
+						"itemType": "TimelineTweet", "__typename": "TimelineTweet",
+						"tweet_results": {"result": {"__typename": "TweetUnavailable", "reason": "Protected"}}}}},
+					{"entryId": "conversationthread-1-tweet-300", "item": {"itemContent": {
+						"itemType": "TimelineTweet", "__typename": "TimelineTweet",
+						"tweet_results": {"result": {"__typename": "TweetTombstone",
+							"tombstone": {"__typename": "TextTombstone", "text": {"text": "This Post is from a suspended account. Learn more"}}}}}}}
+				]}}
+		]
+	}]}`), &timeline))
+
+	result, err := convertTimelineToTweets(&timeline)
+	require.NoError(t, err)
+	require.Empty(t, result.Tweets)
+	require.Equal(t, []*Tombstone{
+		{TweetID: "100", Reason: ReasonDeleted, Message: "This Post was deleted by the Post author. Learn more"},
+		{TweetID: "200", Reason: ReasonProtected, Message: "Protected"},
+		{TweetID: "300", Reason: ReasonSuspended, Message: "This Post is from a suspended account. Learn more"},
+	}, result.Tombstones)
+}
+
+// threadScraper serves GetTweets results from fixed pages, and errors for
+// the tweets in unavailable
+type threadScraper struct {
+	XScraperInterface
+	pages       map[string]*TweetsResult
+	unavailable map[string]error
+}
+
+func (s *threadScraper) GetTweets(ctx context.Context, id string) (*TweetsResult, error) {
+	if err, ok := s.unavailable[id]; ok {
+		return nil, err
+	}
+	if page, ok := s.pages[id]; ok {
+		return page, nil
+	}
+	return nil, fmt.Errorf("no tweet found")
+}
+
+func TestGetCompleteThreadWithTombstones(t *testing.T) {
+	scraper := &threadScraper{
+		pages: map[string]*TweetsResult{
+			// 3 is deleted between 2 and 4, and 6 is a deleted reply below 5
+			"5": {
+				Tweets:     []*Tweet{reply("2", "1", 0, 0), reply("4", "3", 0, 0), reply("5", "4", 0, 0)},
+				Tombstones: []*Tombstone{{TweetID: "3", Reason: ReasonDeleted, Position: 1}, {TweetID: "6", Reason: ReasonDeleted, Position: 3}},
+			},
+		},
+		unavailable: map[string]error{
+			"1":  &TweetUnavailableError{TweetID: "1", Reason: ReasonSuspended},
+			"42": &TweetUnavailableError{TweetID: "42", Reason: ReasonProtected},
+		},
+	}
+
+	tweets, missing, err := GetCompleteThreadWithTombstones(context.Background(), scraper, "5", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "4", "5"}, lo.Map(tweets, func(t *Tweet, _ int) string { return t.RestID }))
+	require.Equal(t, []Tombstone{
+		{TweetID: "3", Reason: ReasonDeleted, Position: 1},
+		{TweetID: "1", Reason: ReasonSuspended, Position: 0},
+	}, missing)
+
+	_, _, err = GetCompleteThreadWithTombstones(context.Background(), scraper, "42", 0)
+	require.ErrorIs(t, err, ErrTweetProtected)
+}
@@ -96,6 +96,35 @@ func (lim *Limiter) Tokens() float64 {
 	return lim.TokensAt(time.Now())
 }
 
+// ReadyAt returns the earliest time at or after t when a token is available,
+// without reserving it. It returns the zero time if no token will ever be
+// available.
+func (lim *Limiter) ReadyAt(t time.Time) time.Time {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+
+	if lim.limit == Inf {
+		return t
+	}
+	if lim.burst < 1 {
+		return time.Time{}
+	}
+	// No tokens accumulate before a reset in the future
+	from := t
+	if lim.last.After(from) {
+		from = lim.last
+	}
+	tokens := lim.advance(from)
+	if tokens >= 1 {
+		return from
+	}
+	wait := lim.limit.durationFromTokens(1 - tokens)
+	if wait == InfDuration {
+		return time.Time{}
+	}
+	return from.Add(wait)
+}
+
 // NewLimiter returns a new Limiter that allows events up to rate r and permits
 // bursts of at most b tokens.
 func NewLimiter(r Limit, b int) *Limiter {
@@ -673,3 +673,28 @@ func TestResetAt(t *testing.T) {
 		t.Errorf("After reset a future time, TokensAt should be gt 0")
 	}
 }
+
+func TestReadyAt(t *testing.T) {
+	now := time.Now()
+	lim := NewLimiter(Every(1*time.Second), 1)
+	if got := lim.ReadyAt(now); !got.Equal(now) {
+		t.Errorf("ReadyAt = %v, want %v with a full bucket", got, now)
+	}
+	if !lim.AllowN(now, 1) {
+		t.Fatalf("AllowN want true with a full bucket")
+	}
+	if got, want := lim.ReadyAt(now), now.Add(time.Second); !got.Equal(want) {
+		t.Errorf("ReadyAt = %v, want %v after using the token", got, want)
+	}
+	resetAt := now.Add(time.Hour)
+	lim.ResetAt(resetAt, 0)
+	if got, want := lim.ReadyAt(now), resetAt.Add(time.Second); !got.Equal(want) {
+		t.Errorf("ReadyAt = %v, want %v after a reset", got, want)
+	}
+	if got := NewLimiter(0, 1).ReadyAt(resetAt); !got.Equal(resetAt) {
+		t.Errorf("ReadyAt = %v, want %v for an unused zero limit", got, resetAt)
+	}
+	if got := NewLimiter(1, 0).ReadyAt(now); !got.IsZero() {
+		t.Errorf("ReadyAt = %v, want zero for a zero burst", got)
+	}
+}
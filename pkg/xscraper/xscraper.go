@@ -11,7 +11,6 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"strconv"
 	"sync"
 	"time"
 
@@ -25,10 +24,15 @@ import (
 // default http client timeout
 const DefaultClientTimeout = 10 * time.Second
 
+// gotwiClientTimeout is the timeout of media uploads, the default of gotwi
+const gotwiClientTimeout = 30 * time.Second
+
 var (
 	// Twitter Web App (GraphQL API)
 	TWITTER_WEB_APP_BEARER_TOKEN = "Bearer AAAAAAAAAAAAAAAAAAAAANRILgAAAAAAnNwIzUejRCOuH5E6I8xnZz4puTs%3D1Zv7ttfk8LF81IUq16cHjhLTvJu4FA33AGWWjCpTnA"
 	BASE_URL                     = lo.Must(url.Parse("https://x.com"))
+	// API_BASE_URL serves the REST API: the login flow and media uploads
+	API_BASE_URL = lo.Must(url.Parse("https://api.x.com"))
 )
 
 type XScraper struct {
@@ -37,6 +41,15 @@ type XScraper struct {
 	rateLimiter       *xrate.Limiter
 	logger            *slog.Logger
 
+	// rateLimits tracks the limits X reports per endpoint; rateLimiter only
+	// paces requests
+	rateLimits rateLimits
+
+	// baseURL and apiBaseURL are BASE_URL and API_BASE_URL unless
+	// LoginOptions overrides them
+	baseURL    *url.URL
+	apiBaseURL *url.URL
+
 	// Gotwi client for media upload functionality
 	gotwiClient *gotwi.Client
 
@@ -48,10 +61,41 @@ type XScraper struct {
 }
 
 func New(loginOpts LoginOptions, logger *slog.Logger) (*XScraper, error) {
+	transport, err := newTransport(loginOpts.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	if loginOpts.TOTPSecret != "" {
+		if _, err := GenerateTOTP(loginOpts.TOTPSecret, time.Now()); err != nil {
+			return nil, fmt.Errorf("invalid TOTP secret for %s: %w", loginOpts.Username, err)
+		}
+	}
+	baseURL, err := parseBaseURL(loginOpts.BaseURL, BASE_URL)
+	if err != nil {
+		return nil, err
+	}
+	apiBaseURL, err := parseBaseURL(loginOpts.APIBaseURL, API_BASE_URL)
+	if err != nil {
+		return nil, err
+	}
+	var webTransport http.RoundTripper = transport
+	if loginOpts.Transport != nil {
+		webTransport = loginOpts.Transport
+	}
+	realHeader := RandRealHeader()
+	if loginOpts.RealHeader != nil {
+		realHeader = *loginOpts.RealHeader
+	}
+
 	var gotwiClient *gotwi.Client
-	var err error
 	if loginOpts.APIKey != "" && loginOpts.APIKeySecret != "" {
+		// gotwi has the endpoints of api.x.com built in
+		uploadTransport := webTransport
+		if apiBaseURL.String() != API_BASE_URL.String() {
+			uploadTransport = &rebaseTransport{from: API_BASE_URL, to: apiBaseURL, transport: webTransport}
+		}
 		gotwiClient, err = gotwi.NewClient(&gotwi.NewClientInput{
+			HTTPClient:           &http.Client{Transport: uploadTransport, Timeout: gotwiClientTimeout},
 			AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
 			APIKey:               loginOpts.APIKey,
 			APIKeySecret:         loginOpts.APIKeySecret,
@@ -64,12 +108,15 @@ func New(loginOpts LoginOptions, logger *slog.Logger) (*XScraper, error) {
 	}
 
 	return &XScraper{
-		realHeader: RandRealHeader(),
+		realHeader: realHeader,
 		xPrivateApiClient: &http.Client{
-			Jar:     lo.Must(cookiejar.New(nil)),
-			Timeout: DefaultClientTimeout,
+			Jar:       lo.Must(cookiejar.New(nil)),
+			Transport: webTransport,
+			Timeout:   DefaultClientTimeout,
 		},
 		rateLimiter: xrate.NewLimiter(xrate.Every(1500*time.Millisecond), 1),
+		baseURL:     baseURL,
+		apiBaseURL:  apiBaseURL,
 
 		logger:    logger,
 		LoginOpts: loginOpts,
@@ -78,6 +125,63 @@ func New(loginOpts LoginOptions, logger *slog.Logger) (*XScraper, error) {
 	}, nil
 }
 
+// parseBaseURL returns the URL of a LoginOptions base URL option, def if the
+// option is empty
+func parseBaseURL(option string, def *url.URL) (*url.URL, error) {
+	if option == "" {
+		return def, nil
+	}
+	u, err := url.Parse(option)
+	if err != nil {
+		return nil, fmt.Errorf("parse base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q is not an http or https URL", option)
+	}
+	return u, nil
+}
+
+// rebaseTransport sends the requests for the host of from to the host of to
+type rebaseTransport struct {
+	from, to  *url.URL
+	transport http.RoundTripper
+}
+
+func (t *rebaseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.from.Host {
+		return t.transport.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.to.Scheme
+	req.URL.Host = t.to.Host
+	req.Host = ""
+	return t.transport.RoundTrip(req)
+}
+
+// newTransport returns the transport of an account connecting through proxy,
+// or through the proxy of the environment if proxy is empty
+func newTransport(proxy string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy == "" {
+		return transport, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		// The *url.Error would repeat the URL and its password
+		return nil, fmt.Errorf("parse proxy URL: %w", errors.Unwrap(err))
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", proxyURL.Redacted())
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return transport, nil
+}
+
 func (x *XScraper) Ready() bool {
 	return x.rateLimiter.Tokens() >= 1
 }
@@ -87,15 +191,28 @@ func (x *XScraper) WaitForReady(ctx context.Context) error {
 }
 
 func (x *XScraper) prepareRequest(req *http.Request) {
-	req.Header.Set("X-Client-Transaction-Id", xClientTransactionID(req.Method, req.URL.Path))
+	x.setTransactionID(req)
 	x.applyRealHeader(req)
 	// x.setCSRFToken(req)
 }
 
+// setTransactionID sets X-Client-Transaction-Id, leaving it out when the
+// transaction ID pairs are unavailable rather than failing the request
+func (x *XScraper) setTransactionID(req *http.Request) {
+	tid, err := xClientTransactionID(req.Method, req.URL.Path)
+	if err != nil {
+		if x.logger != nil {
+			x.logger.Warn("sending request without transaction ID", "error", err)
+		}
+		return
+	}
+	req.Header.Set("X-Client-Transaction-Id", tid)
+}
+
 func (x *XScraper) applyRealHeader(req *http.Request) {
 	x.realHeader.Apply(req)
 	if req.Header.Get("Referer") == "" {
-		req.Header.Set("Referer", "https://x.com/home")
+		req.Header.Set("Referer", x.baseURL.JoinPath("home").String())
 	}
 	if req.Header.Get("Authorization") == "" {
 		req.Header.Set("Authorization", TWITTER_WEB_APP_BEARER_TOKEN)
@@ -106,7 +223,7 @@ func (x *XScraper) applyRealHeader(req *http.Request) {
 }
 
 func (x *XScraper) GetCookie(name string) (string, bool) {
-	for _, cookie := range x.xPrivateApiClient.Jar.Cookies(BASE_URL) {
+	for _, cookie := range x.xPrivateApiClient.Jar.Cookies(x.baseURL) {
 		if cookie.Name == name {
 			return cookie.Value, true
 		}
@@ -115,17 +232,21 @@ func (x *XScraper) GetCookie(name string) (string, bool) {
 }
 
 func (x *XScraper) SetCookies(cs []*http.Cookie) {
-	cookies := x.xPrivateApiClient.Jar.Cookies(BASE_URL)
+	cookies := x.xPrivateApiClient.Jar.Cookies(x.baseURL)
 	for _, c := range cs {
 		if c.Domain == "" {
-			c.Domain = "." + BASE_URL.Host
+			c.Domain = "." + x.baseURL.Hostname()
 		}
 		cookies = append(cookies, c)
 	}
-	x.xPrivateApiClient.Jar.SetCookies(BASE_URL, cookies)
+	x.xPrivateApiClient.Jar.SetCookies(x.baseURL, cookies)
 }
 
 func (x *XScraper) do(req *http.Request) (resp *http.Response, err error) {
+	endpoint := endpointOf(req.URL.Path)
+	if err := x.waitForEndpoint(req.Context(), endpoint); err != nil {
+		return nil, fmt.Errorf("wait for %s rate limit: %w", endpoint, err)
+	}
 	if err := x.WaitForReady(req.Context()); err != nil {
 		return nil, fmt.Errorf("wait for ready: %w", err)
 	}
@@ -133,14 +254,7 @@ func (x *XScraper) do(req *http.Request) (resp *http.Response, err error) {
 	if err != nil {
 		return
 	}
-	limit, err := strconv.Atoi(resp.Header.Get("x-rate-limit-limit"))
-	if err == nil {
-		limit = 0
-	}
-	reset, err := strconv.Atoi(resp.Header.Get("x-rate-limit-reset"))
-	if err == nil {
-		x.rateLimiter.ResetAt(time.Unix(int64(reset), 0), limit)
-	}
+	x.rateLimits.update(endpoint, resp, time.Now())
 	return resp, nil
 }
 
@@ -191,6 +305,13 @@ func (x *XScraper) PostGraphQL(ctx context.Context, endpoint string, req any, ta
 
 var errNotLoggedIn = errors.New("not logged in")
 
+// ErrAccountLocked is returned when X shows the account a lock screen
+// (error 326) instead of serving the request
+var ErrAccountLocked = errors.New("account locked")
+
+// errCodeAccountLocked is the X API error code of the lock screen
+const errCodeAccountLocked = 326
+
 func (x *XScraper) DoGraphQL(ctx context.Context, method, endpoint string, params interface{ Query() url.Values }, reqBody any, target any) error {
 	// 初始化登录（只执行一次）
 	x.initLoginOnce.Do(func() {
@@ -206,7 +327,7 @@ func (x *XScraper) DoGraphQL(ctx context.Context, method, endpoint string, param
 	for {
 		// 检查登录状态并尝试登录
 		if err := x.ensureLoggedIn(ctx); err != nil {
-			return fmt.Errorf("ensure login: %w", err)
+			return fmt.Errorf("ensure login: %w: %w", errNotLoggedIn, err)
 		}
 
 		// 执行实际请求
@@ -222,7 +343,7 @@ func (x *XScraper) DoGraphQL(ctx context.Context, method, endpoint string, param
 }
 
 func (x *XScraper) doGraphQL(ctx context.Context, method, endpoint string, params interface{ Query() url.Values }, reqBody any, target any) error {
-	req, err := http.NewRequestWithContext(ctx, method, BASE_URL.JoinPath(endpoint).String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, x.baseURL.JoinPath(endpoint).String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -246,9 +367,29 @@ func (x *XScraper) doGraphQL(ctx context.Context, method, endpoint string, param
 	var berr *BadRequestError
 	if err != nil {
 		if errors.As(err, &berr) && (berr.StatusCode == http.StatusUnauthorized || berr.StatusCode == http.StatusForbidden) {
+			if lo.Contains(apiErrorCodes(berr.Body), errCodeAccountLocked) {
+				return fmt.Errorf("%w: %w", ErrAccountLocked, err)
+			}
 			return errNotLoggedIn
 		}
 		return err
 	}
 	return nil
 }
+
+// apiErrorCodes returns the codes of the errors in an X API error body
+func apiErrorCodes(body string) []int {
+	var resp struct {
+		Errors []struct {
+			Code int `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil
+	}
+	codes := make([]int, len(resp.Errors))
+	for i, e := range resp.Errors {
+		codes[i] = e.Code
+	}
+	return codes
+}
@@ -19,4 +19,7 @@ var Module = fx.Module("xscraper",
 		}
 		return scrapers, nil
 	}),
+	fx.Provide(func(scrapers []*xscraper.XScraper, config *xscraper.PoolConfig, logger *slog.Logger) *xscraper.ScraperPool {
+		return xscraper.NewScraperPool(scrapers, *config, logger)
+	}),
 )
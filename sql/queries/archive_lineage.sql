@@ -0,0 +1,20 @@
+-- Archive lineage queries
+
+-- name: CreateArchiveLineage :exec
+INSERT INTO archive_lineage (thread_id, old_cid, new_cid, from_version, to_version)
+VALUES (@thread_id, @old_cid, @new_cid, @from_version, @to_version);
+
+-- name: ListArchiveLineage :many
+-- Migrations of a thread's archive, oldest first
+SELECT * FROM archive_lineage
+WHERE thread_id = @thread_id
+ORDER BY id;
+
+-- name: ListThreadsToMigrate :many
+-- Completed threads after after_id, in ID order, for paging through every archive
+SELECT * FROM thread
+WHERE status = 'completed'
+  AND cid <> ''
+  AND id > @after_id
+ORDER BY id
+LIMIT @limit_;
@@ -0,0 +1,129 @@
+-- PDP piece queries
+
+-- name: GetPDPPiece :one
+SELECT * FROM pdp_piece WHERE piece_cid = @piece_cid;
+
+-- name: CreatePDPPiece :exec
+-- A piece whose root failed before is re-uploaded on the next archive, give it a fresh start
+INSERT INTO pdp_piece (piece_cid, padded_size) VALUES (@piece_cid, @padded_size)
+ON CONFLICT (piece_cid) DO UPDATE SET
+    padded_size = EXCLUDED.padded_size,
+    state = 'uploaded',
+    root_cid = NULL,
+    subroot_index = NULL,
+    updated_at = NOW()
+WHERE pdp_piece.state = 'failed';
+
+-- name: ListSettledPDPPieces :many
+SELECT * FROM pdp_piece
+WHERE state = 'uploaded'
+  AND updated_at < @settled_before
+ORDER BY created_at
+LIMIT @limit_
+FOR UPDATE SKIP LOCKED;
+
+-- name: AssignPDPPieceToRoot :exec
+UPDATE pdp_piece SET
+    state = 'root_pending',
+    root_cid = @root_cid,
+    subroot_index = @subroot_index,
+    updated_at = NOW()
+WHERE piece_cid = @piece_cid;
+
+-- name: ListPDPRootSubroots :many
+SELECT * FROM pdp_piece
+WHERE root_cid = @root_cid
+ORDER BY subroot_index;
+
+-- name: SetPDPRootPiecesState :exec
+UPDATE pdp_piece SET
+    state = @state,
+    updated_at = NOW()
+WHERE root_cid = @root_cid;
+
+-- PDP root queries
+
+-- name: GetPDPRoot :one
+SELECT * FROM pdp_root WHERE root_cid = @root_cid;
+
+-- name: CreatePDPRoot :exec
+INSERT INTO pdp_root (root_cid, padded_size, num_subroots) VALUES (@root_cid, @padded_size, @num_subroots)
+ON CONFLICT (root_cid) DO UPDATE SET
+    state = 'pending',
+    attempts = 0,
+    last_error = NULL,
+    updated_at = NOW()
+WHERE pdp_root.state = 'failed';
+
+-- name: ClaimStalePDPRoots :many
+UPDATE pdp_root SET
+    updated_at = NOW()
+WHERE root_cid IN (
+    SELECT r.root_cid FROM pdp_root r
+    WHERE r.state = 'pending'
+      AND r.updated_at < @stale_before
+    ORDER BY r.created_at
+    LIMIT @limit_
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkPDPRootAdded :exec
+UPDATE pdp_root SET
+    state = 'added',
+    last_error = NULL,
+    added_at = NOW(),
+    proof_set_id = @proof_set_id,
+    updated_at = NOW()
+WHERE root_cid = @root_cid;
+
+-- name: RecordPDPRootFailure :one
+UPDATE pdp_root SET
+    attempts = attempts + 1,
+    last_error = @last_error,
+    state = CASE WHEN attempts + 1 >= @max_attempts::int THEN 'failed'::pdp_root_state ELSE state END,
+    updated_at = NOW()
+WHERE root_cid = @root_cid
+RETURNING *;
+
+-- name: ListAddedPDPRoots :many
+SELECT * FROM pdp_root
+WHERE state = 'added'
+  AND proof_set_id = @proof_set_id
+ORDER BY added_at;
+
+-- name: UpdatePDPRootProofStatus :exec
+UPDATE pdp_root SET
+    root_id = @root_id,
+    last_proven_epoch = @last_proven_epoch,
+    proof_failures = @proof_failures,
+    last_proof_error = @last_proof_error,
+    proof_checked_at = NOW(),
+    updated_at = NOW()
+WHERE root_cid = @root_cid;
+
+-- PDP proof set queries
+
+-- name: GetPDPProofSet :one
+SELECT * FROM pdp_proof_set WHERE id = @id;
+
+-- name: UpsertPDPProofSet :exec
+INSERT INTO pdp_proof_set (
+    id, next_challenge_epoch, last_proven_epoch, last_proven_at, last_error, checked_at
+) VALUES (
+    @id, @next_challenge_epoch, @last_proven_epoch, @last_proven_at, NULL, NOW()
+)
+ON CONFLICT (id) DO UPDATE SET
+    next_challenge_epoch = EXCLUDED.next_challenge_epoch,
+    last_proven_epoch = EXCLUDED.last_proven_epoch,
+    last_proven_at = EXCLUDED.last_proven_at,
+    last_error = NULL,
+    checked_at = NOW(),
+    updated_at = NOW();
+
+-- name: RecordPDPProofSetError :exec
+INSERT INTO pdp_proof_set (id, last_error, checked_at) VALUES (@id, @last_error, NOW())
+ON CONFLICT (id) DO UPDATE SET
+    last_error = EXCLUDED.last_error,
+    checked_at = NOW(),
+    updated_at = NOW();
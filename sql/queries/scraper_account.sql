@@ -0,0 +1,38 @@
+-- Scraper account queries
+
+-- name: ListScraperAccounts :many
+SELECT * FROM scraper_account
+ORDER BY username;
+
+-- name: UpsertScraperAccount :exec
+-- Quarantine columns are written as the pool has them; clearing them is left
+-- to ClearScraperQuarantine
+INSERT INTO scraper_account (
+    username, state, failures, consecutive_failures, open_until,
+    last_error, last_error_at, last_success_at, rate_limits, quarantined_at, quarantine_reason
+)
+VALUES (
+    @username, @state, @failures, @consecutive_failures, @open_until,
+    @last_error, @last_error_at, @last_success_at, @rate_limits, @quarantined_at, @quarantine_reason
+)
+ON CONFLICT (username) DO UPDATE SET
+    state = EXCLUDED.state,
+    failures = EXCLUDED.failures,
+    consecutive_failures = EXCLUDED.consecutive_failures,
+    open_until = EXCLUDED.open_until,
+    last_error = EXCLUDED.last_error,
+    last_error_at = EXCLUDED.last_error_at,
+    last_success_at = EXCLUDED.last_success_at,
+    rate_limits = EXCLUDED.rate_limits,
+    quarantined_at = EXCLUDED.quarantined_at,
+    quarantine_reason = EXCLUDED.quarantine_reason,
+    updated_at = NOW();
+
+-- name: ClearScraperQuarantine :execrows
+UPDATE scraper_account
+SET state = 'closed',
+    quarantined_at = NULL,
+    quarantine_reason = NULL,
+    quarantine_cleared_at = NOW()
+WHERE username = @username
+  AND quarantined_at IS NOT NULL;
@@ -0,0 +1,26 @@
+-- Storage replica queries
+
+-- name: ListStorageReplicas :many
+SELECT * FROM storage_replica
+WHERE cid = @cid;
+
+-- name: UpsertStorageReplica :exec
+INSERT INTO storage_replica (cid, replica, replica_cid, is_block, dag_root, last_error, repair_attempts)
+VALUES (@cid, @replica, @replica_cid, @is_block, @dag_root, @last_error, @repair_attempts)
+ON CONFLICT (cid, replica) DO UPDATE SET
+    replica_cid = EXCLUDED.replica_cid,
+    is_block = EXCLUDED.is_block,
+    dag_root = EXCLUDED.dag_root,
+    last_error = EXCLUDED.last_error,
+    repair_attempts = EXCLUDED.repair_attempts,
+    updated_at = NOW();
+
+-- name: DeleteStorageReplica :exec
+DELETE FROM storage_replica
+WHERE cid = @cid AND replica = @replica;
+
+-- name: ListMissingStorageReplicas :many
+SELECT * FROM storage_replica
+WHERE replica_cid IS NULL
+ORDER BY updated_at
+LIMIT @limit_;
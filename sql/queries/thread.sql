@@ -6,12 +6,33 @@ SELECT * FROM thread WHERE id = @thread_id;
 -- name: CreateThread :one
 INSERT INTO thread (
     id, summary, cid, num_tweets, status, retry_count, version,
-    author_id, author_name, author_screen_name, author_profile_image_url
+    author_id, author_name, author_screen_name, author_profile_image_url, mode, tweet_id
 ) VALUES (
     @id, @summary, @cid, @num_tweets, @status, @retry_count, @version,
-    @author_id, @author_name, @author_screen_name, @author_profile_image_url
+    @author_id, @author_name, @author_screen_name, @author_profile_image_url, @mode, @tweet_id
 ) RETURNING *;
 
+-- name: CreatePrivateThread :one
+INSERT INTO thread (id, summary, cid, status, visibility, owner_id, tweet_id, mode, conversation_options)
+VALUES (@id, '', '', 'pending', 'private', @owner_id, @tweet_id, @mode, @conversation_options)
+RETURNING *;
+
+-- name: GetPrivateThreadByOwnerAndTweet :one
+SELECT * FROM thread
+WHERE visibility = 'private' AND owner_id = @owner_id AND tweet_id = @tweet_id AND mode = @mode
+LIMIT 1;
+
+-- name: CreateConversationThread :one
+INSERT INTO thread (id, summary, cid, status, tweet_id, mode, conversation_options)
+VALUES (@id, '', '', 'pending', @tweet_id, 'conversation', @conversation_options)
+RETURNING *;
+
+-- name: GetConversationThreadByTweetAndOptions :one
+SELECT * FROM thread
+WHERE visibility = 'public' AND mode = 'conversation' AND tweet_id = @tweet_id
+  AND conversation_options = @conversation_options
+LIMIT 1;
+
 -- name: UpdateThreadComplete :exec
 UPDATE thread SET
     summary = @summary,
@@ -24,6 +45,27 @@ UPDATE thread SET
     author_name = @author_name,
     author_screen_name = @author_screen_name,
     author_profile_image_url = @author_profile_image_url,
+    stats = @stats,
+    missing_tweets = @missing_tweets,
+    failure_reason = NULL,
+    updated_at = NOW()
+WHERE id = @id AND version = @expected_version;
+
+-- name: UpdateThreadUnavailable :exec
+-- Fails a thread whose tweet X will never serve
+UPDATE thread SET
+    status = 'failed',
+    failure_reason = @failure_reason,
+    version = version + 1,
+    updated_at = NOW()
+WHERE id = @thread_id AND version = @current_version;
+
+-- name: UpdateThreadArchive :execrows
+-- Points a thread at a rewritten archive of the same content
+UPDATE thread SET
+    cid = @cid,
+    stats = @stats,
+    version = version + 1,
     updated_at = NOW()
 WHERE id = @id AND version = @expected_version;
 
@@ -60,6 +102,7 @@ FOR UPDATE;
 -- name: GetFailedThreadsForRetry :many
 SELECT * FROM thread 
 WHERE status = 'failed' 
+  AND failure_reason IS NULL
   AND updated_at < @cutoff_time 
   AND retry_count < @max_retries
 FOR UPDATE;
@@ -0,0 +1,14 @@
+-- Thread attestation queries
+
+-- name: UpsertThreadAttestation :exec
+INSERT INTO thread_attestation (thread_id, content_cid, attestation_cid, key_id, scraped_at)
+VALUES (@thread_id, @content_cid, @attestation_cid, @key_id, @scraped_at)
+ON CONFLICT (thread_id, content_cid) DO UPDATE SET
+    attestation_cid = EXCLUDED.attestation_cid,
+    key_id = EXCLUDED.key_id,
+    scraped_at = EXCLUDED.scraped_at,
+    updated_at = NOW();
+
+-- name: GetThreadAttestation :one
+SELECT * FROM thread_attestation
+WHERE thread_id = @thread_id AND content_cid = @content_cid;
@@ -0,0 +1,8 @@
+-- Thread key queries
+
+-- name: CreateThreadKey :exec
+INSERT INTO thread_key (thread_id, owner_id, wrapped_key)
+VALUES (@thread_id, @owner_id, @wrapped_key);
+
+-- name: GetThreadKey :one
+SELECT * FROM thread_key WHERE thread_id = @thread_id;
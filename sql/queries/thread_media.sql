@@ -0,0 +1,11 @@
+-- Thread media queries
+
+-- name: AddThreadMedia :exec
+INSERT INTO thread_media (thread_id, cid)
+SELECT @thread_id::uuid, unnest(@cids::text[])
+ON CONFLICT (thread_id, cid) DO NOTHING;
+
+-- name: ListThreadsByMedia :many
+SELECT * FROM thread
+WHERE status = 'completed'
+  AND id IN (SELECT thread_id FROM thread_media WHERE thread_media.cid = @cid);
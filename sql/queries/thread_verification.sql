@@ -0,0 +1,43 @@
+-- Thread verification queries
+
+-- name: ListThreadsToVerify :many
+-- Completed threads that were never checked come first, then the ones checked
+-- longest ago
+SELECT t.id, t.cid, t.visibility
+FROM thread t
+LEFT JOIN thread_verification v ON v.thread_id = t.id
+WHERE t.status = 'completed'
+  AND t.cid <> ''
+  AND (v.checked_at IS NULL OR v.checked_at < @checked_before)
+ORDER BY v.checked_at NULLS FIRST, t.created_at
+LIMIT @limit_;
+
+-- name: UpsertThreadVerification :exec
+INSERT INTO thread_verification (thread_id, cid, status, failed_cid, last_error, consecutive_failures, checked_at, repair_queued_at)
+VALUES (
+    @thread_id, @cid, @status, @failed_cid, @last_error,
+    CASE WHEN @status::thread_verification_status = 'pass' THEN 0 ELSE 1 END,
+    NOW(), @repair_queued_at
+)
+ON CONFLICT (thread_id) DO UPDATE SET
+    cid = EXCLUDED.cid,
+    status = EXCLUDED.status,
+    failed_cid = EXCLUDED.failed_cid,
+    last_error = EXCLUDED.last_error,
+    consecutive_failures = CASE
+        WHEN EXCLUDED.status = 'pass' THEN 0
+        ELSE thread_verification.consecutive_failures + 1
+    END,
+    checked_at = EXCLUDED.checked_at,
+    repair_queued_at = COALESCE(EXCLUDED.repair_queued_at, thread_verification.repair_queued_at),
+    updated_at = NOW();
+
+-- name: ListFailedThreadVerifications :many
+SELECT * FROM thread_verification
+WHERE status <> 'pass'
+ORDER BY checked_at DESC
+LIMIT @limit_ OFFSET @offset_;
+
+-- name: CountFailedThreadVerifications :one
+SELECT COUNT(*) FROM thread_verification
+WHERE status <> 'pass';
@@ -0,0 +1,54 @@
+-- Transparency log queries
+
+-- name: LockTransparencyLog :exec
+-- Serializes appends for the rest of the transaction
+SELECT pg_advisory_xact_lock(hashtext('transparency_log'));
+
+-- name: ListThreadsToSequence :many
+-- Completed public threads whose current CID is not in the log yet, oldest
+-- first. Private archives stay out of the public log.
+SELECT t.id, t.cid
+FROM thread t
+WHERE t.status = 'completed'
+  AND t.visibility = 'public'
+  AND t.cid <> ''
+  AND NOT EXISTS (
+    SELECT 1 FROM transparency_log_leaf l
+    WHERE l.thread_id = t.id AND l.cid = t.cid
+  )
+ORDER BY t.updated_at, t.id
+LIMIT @limit_;
+
+-- name: GetTransparencyLogSize :one
+SELECT COALESCE(MAX(leaf_index) + 1, 0)::BIGINT AS tree_size
+FROM transparency_log_leaf;
+
+-- name: InsertTransparencyLogLeaf :exec
+INSERT INTO transparency_log_leaf (leaf_index, thread_id, cid, entry, leaf_hash)
+VALUES (@leaf_index, @thread_id, @cid, @entry, @leaf_hash);
+
+-- name: ListTransparencyLogLeafHashes :many
+SELECT leaf_hash
+FROM transparency_log_leaf
+WHERE leaf_index < @tree_size
+ORDER BY leaf_index;
+
+-- name: GetTransparencyLogLeafByCID :one
+-- First entry of a CID; the same content may be logged for several threads
+SELECT * FROM transparency_log_leaf
+WHERE cid = @cid
+ORDER BY leaf_index
+LIMIT 1;
+
+-- name: InsertTransparencyLogHead :exec
+INSERT INTO transparency_log_head (tree_size, root_hash, head_cid, key_id, signed_at)
+VALUES (@tree_size, @root_hash, @head_cid, @key_id, @signed_at);
+
+-- name: GetLatestTransparencyLogHead :one
+SELECT * FROM transparency_log_head
+ORDER BY tree_size DESC
+LIMIT 1;
+
+-- name: GetTransparencyLogHead :one
+SELECT * FROM transparency_log_head
+WHERE tree_size = @tree_size;
@@ -0,0 +1,20 @@
+-- Archive lineage table
+-- Records every archive that `threadmirror archive migrate` rewrote into a
+-- newer schema, so an old CID can still be traced to the archive replacing it.
+
+CREATE TABLE IF NOT EXISTS archive_lineage (
+    id           BIGSERIAL PRIMARY KEY,
+    thread_id    UUID NOT NULL REFERENCES thread(id) ON DELETE CASCADE,
+    old_cid      TEXT NOT NULL,
+    new_cid      TEXT NOT NULL,
+    -- Archive schema versions (see internal/archive)
+    from_version INTEGER NOT NULL,
+    to_version   INTEGER NOT NULL,
+
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_archive_lineage_thread_id ON archive_lineage(thread_id);
+CREATE INDEX IF NOT EXISTS idx_archive_lineage_old_cid ON archive_lineage(old_cid);
+CREATE INDEX IF NOT EXISTS idx_archive_lineage_new_cid ON archive_lineage(new_cid);
@@ -9,3 +9,21 @@ CREATE EXTENSION IF NOT EXISTS moddatetime;
 
 -- Thread status enum
 CREATE TYPE thread_status AS ENUM ('pending', 'scraping', 'completed', 'failed');
+
+-- Thread visibility enum
+CREATE TYPE thread_visibility AS ENUM ('public', 'private');
+
+-- Thread mode enum: the author's thread, or the whole reply tree
+CREATE TYPE thread_mode AS ENUM ('thread', 'conversation');
+
+-- PDP piece state enum
+CREATE TYPE pdp_piece_state AS ENUM ('uploaded', 'root_pending', 'root_added', 'failed');
+
+-- PDP root state enum
+CREATE TYPE pdp_root_state AS ENUM ('pending', 'added', 'failed');
+
+-- Thread verification status enum
+CREATE TYPE thread_verification_status AS ENUM ('pass', 'mismatch', 'unreachable');
+
+-- Scraper account circuit state enum
+CREATE TYPE scraper_circuit_state AS ENUM ('closed', 'open', 'half_open', 'quarantined');
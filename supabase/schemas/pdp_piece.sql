@@ -0,0 +1,85 @@
+-- PDP piece table
+-- Tracks every piece uploaded to the PDP service until it is covered by a proof set root
+
+CREATE TABLE IF NOT EXISTS pdp_piece (
+    piece_cid     TEXT PRIMARY KEY,
+    padded_size   BIGINT NOT NULL,
+
+    -- uploaded: waiting for an aggregate root, root_pending: AddRoots job queued,
+    -- root_added: covered by PDP proofs, failed: its root gave up after max attempts
+    state         pdp_piece_state NOT NULL DEFAULT 'uploaded',
+
+    -- Aggregate root the piece is a subroot of, and its position in that root
+    root_cid      TEXT,
+    subroot_index INTEGER,
+
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_pdp_piece_updated_at
+    BEFORE UPDATE ON pdp_piece
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_pdp_piece_state_updated_at ON pdp_piece(state, updated_at);
+CREATE INDEX IF NOT EXISTS idx_pdp_piece_root_cid ON pdp_piece(root_cid, subroot_index);
+
+-- PDP root table
+-- Aggregate roots added to the proof set, each made of one or more pdp_piece subroots
+
+CREATE TABLE IF NOT EXISTS pdp_root (
+    root_cid      TEXT PRIMARY KEY,
+    padded_size   BIGINT NOT NULL,
+    num_subroots  INTEGER NOT NULL,
+
+    state         pdp_root_state NOT NULL DEFAULT 'pending',
+
+    -- AddRoots attempt tracking
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    last_error    TEXT,
+
+    added_at      TIMESTAMPTZ,
+
+    -- Proof status, filled in by the proof check cron once the root is added
+    proof_set_id      BIGINT,
+    root_id           BIGINT,
+    last_proven_epoch BIGINT,
+    proof_failures    INTEGER NOT NULL DEFAULT 0,
+    last_proof_error  TEXT,
+    proof_checked_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_pdp_root_updated_at
+    BEFORE UPDATE ON pdp_root
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_pdp_root_state_updated_at ON pdp_root(state, updated_at);
+
+-- PDP proof set table
+-- Last observed proving status of each proof set
+
+CREATE TABLE IF NOT EXISTS pdp_proof_set (
+    id                   BIGINT PRIMARY KEY,
+    next_challenge_epoch BIGINT,
+    last_proven_epoch    BIGINT,
+    -- When the last proof was first observed
+    last_proven_at       TIMESTAMPTZ,
+    last_error           TEXT,
+    checked_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_pdp_proof_set_updated_at
+    BEFORE UPDATE ON pdp_proof_set
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
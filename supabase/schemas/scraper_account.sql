@@ -0,0 +1,37 @@
+-- Scraper account table
+-- Circuit breaker state of each X account the bot scrapes with, synced from the
+-- bot's scraper pool for monitoring. Quarantines are kept here so they survive
+-- restarts until an operator clears them.
+
+CREATE TABLE IF NOT EXISTS scraper_account (
+    username              TEXT PRIMARY KEY,
+
+    state                 scraper_circuit_state NOT NULL DEFAULT 'closed',
+    -- Failures by error class since the bot started
+    failures              JSONB,
+    -- Failures opening the circuit since the last success
+    consecutive_failures  INTEGER NOT NULL DEFAULT 0,
+    -- End of the cooldown of an open circuit
+    open_until            TIMESTAMPTZ,
+    last_error            TEXT,
+    last_error_at         TIMESTAMPTZ,
+    last_success_at       TIMESTAMPTZ,
+    -- Rate limits of the endpoints the account has called, as last reported
+    -- by X
+    rate_limits           JSONB,
+
+    -- Set when a login stopped at a challenge needing a person
+    quarantined_at        TIMESTAMPTZ,
+    quarantine_reason     TEXT,
+    -- When an operator last cleared the quarantine
+    quarantine_cleared_at TIMESTAMPTZ,
+
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_scraper_account_updated_at
+    BEFORE UPDATE ON scraper_account
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
@@ -0,0 +1,35 @@
+-- Storage replica table
+-- Content of the replicated IPFS backend that a replica is missing, or stores under
+-- a different CID than the one handed out. Replicas without a row store the
+-- content under cid itself.
+
+CREATE TABLE IF NOT EXISTS storage_replica (
+    cid             TEXT NOT NULL,
+    replica         TEXT NOT NULL,
+
+    -- CID of the content on the replica, NULL while the replica is missing it
+    replica_cid     TEXT,
+    -- Stored as a raw block rather than a file
+    is_block        BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Root of the DAG the block was written with, whose blocks a replica without
+    -- block storage keeps in one CAR
+    dag_root        TEXT,
+
+    -- Repair tracking
+    last_error      TEXT,
+    repair_attempts INTEGER NOT NULL DEFAULT 0,
+
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (cid, replica)
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_storage_replica_updated_at
+    BEFORE UPDATE ON storage_replica
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_storage_replica_missing ON storage_replica(updated_at) WHERE replica_cid IS NULL;
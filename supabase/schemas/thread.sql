@@ -21,6 +21,31 @@ CREATE TABLE IF NOT EXISTS thread (
     author_name              TEXT,
     author_screen_name       TEXT,
     author_profile_image_url TEXT,
+
+    -- Private archives are encrypted and only readable by their owner
+    visibility               thread_visibility NOT NULL DEFAULT 'public',
+    owner_id                 TEXT,
+    -- Source tweet of a private archive or a conversation; public threads
+    -- are keyed by it
+    tweet_id                 TEXT,
+
+    -- A conversation archives the reply tree under tweet_id rather than the
+    -- author's thread, within the limits in conversation_options
+    -- (xscraper.ConversationOptions). Public conversations are keyed by
+    -- tweet_id and their normalized options
+    mode                     thread_mode NOT NULL DEFAULT 'thread',
+    conversation_options     JSONB,
+
+    -- Engagement counters of the last scrape (archive.Stats). They are kept out
+    -- of the archive so that unchanged content keeps its CID.
+    stats                    JSONB,
+
+    -- Tweets of the thread X did not serve, with why and where they were
+    -- (xscraper.Tombstone)
+    missing_tweets           JSONB,
+    -- Why a failed thread can never be scraped (xscraper.UnavailableReason),
+    -- such as its tweet being deleted; such threads are not retried
+    failure_reason           TEXT,
     
     created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW()
@@ -38,3 +63,5 @@ CREATE INDEX IF NOT EXISTS idx_thread_author_id ON thread(author_id);
 CREATE INDEX IF NOT EXISTS idx_thread_created_at ON thread(created_at);
 CREATE INDEX IF NOT EXISTS idx_thread_updated_at ON thread(updated_at);
 CREATE INDEX IF NOT EXISTS idx_thread_retry_count ON thread(retry_count);
+CREATE INDEX IF NOT EXISTS idx_thread_owner_id ON thread(owner_id) WHERE owner_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_thread_conversation_tweet_id ON thread(tweet_id) WHERE mode = 'conversation';
@@ -0,0 +1,25 @@
+-- Thread attestation table
+-- Signed provenance attestations of archived threads. The attestation itself is
+-- stored in IPFS next to the content; one is kept per archived version.
+
+CREATE TABLE IF NOT EXISTS thread_attestation (
+    thread_id       UUID NOT NULL REFERENCES thread(id) ON DELETE CASCADE,
+    -- Archive CID the attestation is about
+    content_cid     TEXT NOT NULL,
+    -- CID of the signed DSSE envelope
+    attestation_cid TEXT NOT NULL,
+    -- ID of the signing key (hex SHA-256 of its PKIX encoding)
+    key_id          TEXT NOT NULL,
+    scraped_at      TIMESTAMPTZ NOT NULL,
+
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (thread_id, content_cid)
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_thread_attestation_updated_at
+    BEFORE UPDATE ON thread_attestation
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
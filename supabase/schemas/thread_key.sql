@@ -0,0 +1,11 @@
+-- Thread key table
+-- Data keys of private archives, wrapped for the owning user with a key derived
+-- from the server master key.
+
+CREATE TABLE IF NOT EXISTS thread_key (
+    thread_id   UUID PRIMARY KEY REFERENCES thread(id) ON DELETE CASCADE,
+    owner_id    TEXT NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,16 @@
+-- Thread media table
+-- CIDs of the media and avatars archived with a thread. /media/{cid} only
+-- serves CIDs recorded here for a thread the caller may view, so storage is
+-- never an open gateway to arbitrary content.
+
+CREATE TABLE IF NOT EXISTS thread_media (
+    thread_id  UUID NOT NULL REFERENCES thread(id) ON DELETE CASCADE,
+    cid        TEXT NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (thread_id, cid)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_thread_media_cid ON thread_media(cid);
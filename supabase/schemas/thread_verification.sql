@@ -0,0 +1,35 @@
+-- Thread verification table
+-- Result of the last integrity check of each completed thread: its content (and
+-- archived media) is read back from storage and must still hash to its CID
+
+CREATE TABLE IF NOT EXISTS thread_verification (
+    thread_id            UUID PRIMARY KEY REFERENCES thread(id) ON DELETE CASCADE,
+    -- Thread CID the check ran against
+    cid                  TEXT NOT NULL,
+
+    -- pass: all content matched, mismatch: content was corrupt,
+    -- unreachable: content could not be read back
+    status               thread_verification_status NOT NULL,
+    -- Content that failed the check (the thread itself or one of its media)
+    failed_cid           TEXT,
+    last_error           TEXT,
+    -- Checks failed in a row, reset by a passing check
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+
+    checked_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- When corrupt replicas of the failed content were last queued for repair
+    repair_queued_at     TIMESTAMPTZ,
+
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add updated_at trigger
+CREATE OR REPLACE TRIGGER set_thread_verification_updated_at
+    BEFORE UPDATE ON thread_verification
+    FOR EACH ROW
+    EXECUTE FUNCTION moddatetime('updated_at');
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_thread_verification_checked_at ON thread_verification(checked_at);
+CREATE INDEX IF NOT EXISTS idx_thread_verification_failed ON thread_verification(checked_at) WHERE status <> 'pass';
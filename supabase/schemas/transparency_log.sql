@@ -0,0 +1,31 @@
+-- Transparency log tables
+-- Append-only Merkle log (RFC 6962) of every archived thread CID. Leaves are
+-- never updated or deleted; tree heads are signed and stored in IPFS.
+
+CREATE TABLE IF NOT EXISTS transparency_log_leaf (
+    -- Position of the entry in the log, dense from 0
+    leaf_index  BIGINT PRIMARY KEY,
+    thread_id   UUID NOT NULL,
+    cid         TEXT NOT NULL,
+    -- Exact bytes of the log entry the leaf hash is computed over
+    entry       BYTEA NOT NULL,
+    -- SHA-256(0x00 || entry)
+    leaf_hash   BYTEA NOT NULL,
+
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    UNIQUE (thread_id, cid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transparency_log_leaf_cid ON transparency_log_leaf(cid);
+
+CREATE TABLE IF NOT EXISTS transparency_log_head (
+    tree_size   BIGINT PRIMARY KEY,
+    root_hash   BYTEA NOT NULL,
+    -- CID of the signed DSSE envelope of the tree head
+    head_cid    TEXT NOT NULL,
+    key_id      TEXT NOT NULL,
+    signed_at   TIMESTAMPTZ NOT NULL,
+
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);